@@ -0,0 +1,131 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 개인 API 토큰 관련 스크립트
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// APITokenPrefix 는 개인 API 토큰 앞에 붙는 문자열이다. 이 문자열로 시작하지 않는 토큰(예전 사용자 Token)은 사용할 수 없다.
+const APITokenPrefix = "bgt_"
+
+// APIScopes 는 API 토큰에 줄 수 있는 API 범위 리스트이다.
+var APIScopes = []APIScope{
+	{ID: "user", Name: "사용자"},
+	{ID: "artist", Name: "아티스트"},
+	{ID: "timelog", Name: "타임로그"},
+	{ID: "project", Name: "결산 프로젝트"},
+	{ID: "budget", Name: "예산"},
+	{ID: "setting", Name: "설정"},
+}
+
+// apiScopeRoutes 는 restAPI URL별로 필요한 API 범위이다.
+var apiScopeRoutes = map[string]string{
+	"/api/rmuser":                     "user",
+	"/api/addartistvfx":               "artist",
+	"/api/addartistcm":                "artist",
+	"/api/rmartist":                   "artist",
+	"/api/sgartist":                   "artist",
+	"/api/shotgunevent/humanuser/new": "artist",
+	"/api/checkmonthlystatus":         "timelog",
+	"/api/updatetimelog":              "timelog",
+	"/api/rmtimelogbyid":              "timelog",
+	"/api/rmtimelogbyproject":         "timelog",
+	"/api/resettimelog":               "timelog",
+	"/api/rmproject":                  "project",
+	"/api/monthlyPurchaseCost":        "project",
+	"/api/setMonthlyPurchaseCost":     "project",
+	"/api/monthlyPayment":             "project",
+	"/api/setMonthlyPayment":          "project",
+	"/api/updateprojects":             "project",
+	"/api/rmvendor":                   "project",
+	"/api/shotgunevent/project/new":   "project",
+	"/api/rmbgproject":                "budget",
+	"/api/rmclient":                   "budget",
+	"/api/rmratecard":                 "budget",
+	"/api/vfxteams":                   "setting",
+	"/api/totalteams":                 "setting",
+}
+
+// newAPITokenFunc 함수는 새 API 토큰을 만들어 토큰 정보와 토큰 원문을 반환하는 함수이다.
+func newAPITokenFunc(userID string, name string, scopes []string, expiresAt time.Time) (APIToken, string, error) {
+	id, err := newSessionIDFunc()
+	if err != nil {
+		return APIToken{}, "", err
+	}
+	b := make([]byte, 20)
+	_, err = rand.Read(b)
+	if err != nil {
+		return APIToken{}, "", err
+	}
+	secret := APITokenPrefix + hex.EncodeToString(b)
+	t := APIToken{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		Hash:      hashAPITokenFunc(secret),
+		Prefix:    secret[:len(APITokenPrefix)+6],
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	return t, secret, nil
+}
+
+// hashAPITokenFunc 함수는 API 토큰 원문의 SHA-256 해시값을 반환하는 함수이다.
+func hashAPITokenFunc(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkAPITokenFunc 함수는 API 토큰이 만료되지 않았고 URL에 필요한 API 범위를 가졌는지 확인하는 함수이다.
+func checkAPITokenFunc(t APIToken, path string, now time.Time) error {
+	if !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt) {
+		return errors.New("만료된 API 토큰입니다")
+	}
+	scope, ok := apiScopeRoutes[path]
+	if !ok || !checkStringInListFunc(scope, t.Scopes) {
+		return errors.New("API 토큰에 이 API를 사용할 권한이 없습니다")
+	}
+	return nil
+}
+
+// getUserFromAPITokenFunc 함수는 Authorization 헤더의 개인 API 토큰으로 사용자 정보를 가져오는 함수이다.
+// 만료 시간과 API 범위를 확인하고 마지막 사용 시간을 저장한다. 예전 사용자 Token은 API 범위가 없으므로 더 이상 사용할 수 없다.
+func getUserFromAPITokenFunc(r *http.Request, client *mongo.Client) (User, error) {
+	auth := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(auth) != 2 || auth[0] != "Basic" {
+		return User{}, errors.New("Authorization failed")
+	}
+	if !strings.HasPrefix(auth[1], APITokenPrefix) {
+		return User{}, errors.New("사용자 Token은 더 이상 restAPI에 사용할 수 없습니다. 프로필 페이지에서 API Token을 만들어 사용해주세요")
+	}
+
+	t, err := getAPITokenByHashFunc(client, hashAPITokenFunc(auth[1]))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return User{}, errors.New("Authorization failed")
+		}
+		return User{}, err
+	}
+	now := time.Now()
+	err = checkAPITokenFunc(t, r.URL.Path, now)
+	if err != nil {
+		return User{}, err
+	}
+	err = setAPITokenLastUsedFunc(client, t.ID, now)
+	if err != nil {
+		return User{}, err
+	}
+	return getUserFunc(client, t.UserID)
+}
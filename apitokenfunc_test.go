@@ -0,0 +1,47 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 개인 API 토큰 테스트 스크립트
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// 만든 API 토큰의 해시값과 앞부분이 올바른지 테스트하기 위한 함수
+func Test_newAPIToken(t *testing.T) {
+	token, secret, err := newAPITokenFunc("kim", "daemon", []string{"timelog"}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret, APITokenPrefix) || !strings.HasPrefix(secret, token.Prefix) {
+		t.Fatalf("Test_newAPIToken(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", secret, APITokenPrefix, token.Prefix)
+	}
+	if token.Hash != hashAPITokenFunc(secret) || token.Hash == secret {
+		t.Fatalf("Test_newAPIToken(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", secret, hashAPITokenFunc(secret), token.Hash)
+	}
+}
+
+// API 토큰의 만료 시간과 API 범위를 확인하는지 테스트하기 위한 함수
+func Test_checkAPIToken(t *testing.T) {
+	now := time.Date(2020, 12, 1, 12, 0, 0, 0, time.Local)
+	cases := []struct {
+		token APIToken
+		path  string
+		want  bool
+	}{
+		{token: APIToken{Scopes: []string{"timelog"}}, path: "/api/updatetimelog", want: true},
+		{token: APIToken{Scopes: []string{"timelog"}}, path: "/api/rmproject", want: false},
+		{token: APIToken{Scopes: []string{"timelog"}}, path: "/api/unknown", want: false},
+		{token: APIToken{Scopes: []string{"timelog"}, ExpiresAt: now.Add(time.Hour)}, path: "/api/updatetimelog", want: true},
+		{token: APIToken{Scopes: []string{"timelog"}, ExpiresAt: now}, path: "/api/updatetimelog", want: false},
+	}
+	for _, c := range cases {
+		got := checkAPITokenFunc(c.token, c.path, now) == nil
+		if got != c.want {
+			t.Fatalf("Test_checkAPIToken(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.token, c.path, c.want, got)
+		}
+	}
+}
@@ -446,3 +446,39 @@ func averageWageByTeamsFunc(task string, teams []string) (float64, error) {
 
 	return math.Round(averageCost), nil
 }
+
+// addArtistTeamHistoryFunc 함수는 아티스트의 팀이 바뀌었으면 바뀐 날짜(date)에 이전 팀을 기록하는 함수이다.
+// 기존 기록은 유지하고, 같은 날 여러 번 바뀌면 그 날 전까지의 팀을 유지한다.
+func addArtistTeamHistoryFunc(before Artist, after *Artist, date string) {
+	history := make(map[string]string)
+	for d, team := range before.TeamHistory {
+		history[d] = team
+	}
+	for d, team := range after.TeamHistory {
+		history[d] = team
+	}
+	if before.Team != "" && before.Team != after.Team {
+		if _, ok := history[date]; !ok {
+			history[date] = before.Team
+		}
+	}
+	if len(history) == 0 {
+		after.TeamHistory = nil
+		return
+	}
+	after.TeamHistory = history
+}
+
+// getArtistTeamOnDateFunc 함수는 팀 변경 기록으로 date(2020-03-01) 당시 아티스트의 팀을 반환하는 함수이다.
+// date 이후 가장 먼저 팀이 바뀐 날짜의 이전 팀이 당시 팀이고, date 이후에 바뀐 적이 없으면 현재 팀이다.
+func getArtistTeamOnDateFunc(artist Artist, date string) string {
+	team := artist.Team
+	next := ""
+	for d, t := range artist.TeamHistory {
+		if d > date && (next == "" || d < next) {
+			next = d
+			team = t
+		}
+	}
+	return team
+}
@@ -0,0 +1,64 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 아티스트 관련 테스트 스크립트
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 아티스트의 팀이 바뀌면 이전 팀을 기록하는지 테스트하기 위한 함수
+func Test_addArtistTeamHistory(t *testing.T) {
+	cases := []struct {
+		before Artist
+		after  Artist
+		want   map[string]string
+	}{{
+		before: Artist{Team: "FX"},
+		after:  Artist{Team: "FX"},
+		want:   nil,
+	}, {
+		before: Artist{Team: "FX"},
+		after:  Artist{Team: "Comp"},
+		want:   map[string]string{"2020-03-15": "FX"},
+	}, {
+		// 폼으로 새로 만든 아티스트 정보에도 기존 기록을 유지한다.
+		before: Artist{Team: "Comp", TeamHistory: map[string]string{"2020-01-10": "MatchMove"}},
+		after:  Artist{Team: "FX"},
+		want:   map[string]string{"2020-01-10": "MatchMove", "2020-03-15": "Comp"},
+	}, {
+		// 같은 날 다시 바뀌면 그 날 전까지의 팀을 유지한다.
+		before: Artist{Team: "Comp", TeamHistory: map[string]string{"2020-03-15": "FX"}},
+		after:  Artist{Team: "Lighting"},
+		want:   map[string]string{"2020-03-15": "FX"},
+	}}
+	for _, c := range cases {
+		after := c.after
+		addArtistTeamHistoryFunc(c.before, &after, "2020-03-15")
+		if !reflect.DeepEqual(after.TeamHistory, c.want) {
+			t.Fatalf("Test_addArtistTeamHistory(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", c.before, c.after, c.want, after.TeamHistory)
+		}
+	}
+}
+
+// 날짜에 해당하는 아티스트의 팀을 찾는지 테스트하기 위한 함수
+func Test_getArtistTeamOnDate(t *testing.T) {
+	artist := Artist{Team: "Comp", TeamHistory: map[string]string{"2020-03-15": "FX", "2020-01-10": "MatchMove"}}
+	cases := []struct {
+		date string
+		want string
+	}{
+		{date: "2019-12-01", want: "MatchMove"},
+		{date: "2020-02-01", want: "FX"},
+		{date: "2020-03-15", want: "Comp"}, // 바뀐 날부터는 새 팀이다.
+		{date: "2020-04-01", want: "Comp"},
+	}
+	for _, c := range cases {
+		got := getArtistTeamOnDateFunc(artist, c.date)
+		if got != c.want {
+			t.Fatalf("Test_getArtistTeamOnDate(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.date, c.want, got)
+		}
+	}
+}
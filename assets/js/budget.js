@@ -10,14 +10,9 @@ function setRmUserModalFunc(id) {
 
 // rmUserFunc 함수는 del 버튼을 클릭하면 확인창을 띄우고 ok를 클릭하면 restAPI를 이용하여 유저를 삭제하는 함수이다.
 function rmUserFunc(id) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url: `/api/rmuser?id=${id}`,
         type: "delete",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function() {
             alert("사용자가 삭제되었습니다.")
@@ -94,7 +89,6 @@ function updatePasswordPageBlankCheckFunc() {
 /* 아티스트 관련 함수 */
 // addArtistVFXFunc 함수는 modal-addartistvfx에서 ADD 버튼을 클릭하면 restAPI를 이용하여 아티스트를 추가하는 함수이다.
 function addArtistVFXFunc(id, salary, startday, endday) {
-    let token = document.getElementById("token").value;
     if (id == "") {
         alert("아티스트 Shotgun ID를 입력해주세요")
         return false
@@ -124,9 +118,6 @@ function addArtistVFXFunc(id, salary, startday, endday) {
     $.ajax({
         url: `/api/addartistvfx?id=${id}&salary=${salary}&startday=${startday}&endday=${endday}&change=${change.checked}&changedate=${changedate}&changesalary=${changesalary}`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             alert("아티스트가 추가되었습니다.")
@@ -140,7 +131,6 @@ function addArtistVFXFunc(id, salary, startday, endday) {
 
 // addArtistCMFunc 함수는 modal-addartistcm에서 ADD 버튼을 클릭하면 restAPI를 이용하여 아티스트를 추가하는 함수이다.
 function addArtistCMFunc(id, team, name, salary, startday, endday) {
-    let token = document.getElementById("token").value;
     if (id == "") {
         alert("아티스트 ID를 입력해주세요")
         return false
@@ -177,9 +167,6 @@ function addArtistCMFunc(id, team, name, salary, startday, endday) {
     $.ajax({
         url: `/api/addartistcm?id=${id}&team=${team}&name=${name}&salary=${salary}&startday=${startday}&endday=${endday}&change=${change.checked}&changedate=${changedate}&changesalary=${changesalary}`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             alert("아티스트가 추가되었습니다.")
@@ -240,14 +227,9 @@ function setRmArtistModalFunc(id, team, name) {
 
 // rmArtistFunc 함수는 restAPI를 이용하여 아티스트를 삭제하는 함수이다.
 function rmArtistFunc(id) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url: `/api/rmartist?id=${id}`,
         type: "delete",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             alert("아티스트가 삭제되었습니다.")
@@ -261,14 +243,9 @@ function rmArtistFunc(id) {
 
 // getSGArtistFunc 함수는 VFX팀 아티스트 edit 페이지에서 Shotgun 정보를 불러와 페이지를 업데이트하는 함수이다.
 function getSGArtistFunc(id) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url: `/api/sgartist?id=${id}`,
         type: "get",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         async: false,
         success: function(data) {
@@ -400,17 +377,12 @@ function sortCheckTableFunc(tableID) {
 
 // changeVFXTeamComboFunc 함수는 VFX팀 타임로그 페이지의 검색바에서 부서를 선택했을 때 팀 콤보박스에 해당 부서의 팀만 보여주도록 수정해주는 함수이다.
 function changeVFXTeamComboFunc(dept) {
-    let token = document.getElementById("token").value;
-
     if (dept == "") {
         dept = "all"
     }
     $.ajax({
         url:`/api/vfxteams?dept=${dept}`,
         type: "get",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         async: false,
         success: function(data) {
@@ -435,17 +407,12 @@ function changeVFXTeamComboFunc(dept) {
 
 //changeTotalTeamComboFunc 함수는 누계 타임로그 페이지의 검색바에서 부서를 선택했을 때 팀 콤보박스에 해당 부서의 팀만 보여주도록 수정해주는 함수이다.
 function changeTotalTeamComboFunc(dept) {
-    let token = document.getElementById("token").value;
-
     if (dept == "") {
         dept = "all"
     }
     $.ajax({
         url:`/api/totalteams?dept=${dept}`,
         type: "get",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         async:false,
         success: function(data) {
@@ -476,14 +443,9 @@ function changeTimelogCMExcelURIFunc() {
 
 // checkMonthlyStatusFunc 함수는 update 버튼을 눌렀을 때 월별 결산 상태에 맞게 modal창을 띄우는 함수이다.
 function checkMonthlyStatusFunc() {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url:`/api/checkmonthlystatus`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType:"json",
         async:false,
         success: function(data) {
@@ -515,14 +477,9 @@ $("#modal-updatetimelog-withlastmonth").on('shown.bs.modal', function(){
 
 // updateTimelogFunc 함수는 타임로그를 업데이트하는 함수이다.
 function updateTimelogFunc(status) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url:`/api/updatetimelog?status=${status}`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             if (data.Status == true) {
@@ -760,14 +717,9 @@ function setRmTimelogByIDModalFunc(idList) {
 
 // rmTimelogByIDFunc 함수는 restAPI를 이용하여 입력받은 ID가 작성한 타임로그를 삭제하는 함수이다.
 function rmTimelogByIDFunc(idList) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url:`/api/rmtimelogbyid?id=${idList}`,
         type: "delete",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function() {
             alert("삭제되었습니다")
@@ -786,14 +738,9 @@ function setRmTimelogByProjectModalFunc(projectList) {
 
 // rmTimelogByProjectFunc 함수는 restAPI를 이용하여 입력받은 프로젝트에 작성한 타임로그를 삭제하는 함수이다.
 function rmTimelogByProjectFunc(projectList) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url:`/api/rmtimelogbyproject?project=${projectList}`,
         type: "delete",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function() {
             alert("삭제되었습니다")
@@ -812,14 +759,9 @@ $("#modal-resettimelog").on("shown.bs.modal", function(){
 
 // resetTimelogFunc 함수는 restAPI를 이용하여 타임로그를 리셋하는 함수이다.
 function resetTimelogFunc() {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url:`/api/resettimelog`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function() {
             alert("리셋되었습니다")
@@ -1178,14 +1120,9 @@ function setRmProjectModalFunc(id, name) {
 
 // rmProjectFunc 함수는 restAPI를 이용하여 프로젝트를 삭제하는 함수이다.
 function rmProjectFunc(id) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url: `/api/rmproject?id=${id}`,
         type: "delete",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             alert("프로젝트가 삭제되었습니다.")
@@ -1199,14 +1136,9 @@ function rmProjectFunc(id) {
 
 // setMonthlyPurchaseCostModalFunc 함수는 ... 버튼을 클릭하면 프로젝트 ID, 날짜, 구매 내역을 modal 창에 보여주는 함수이다.
 function setMonthlyPurchaseCostModalFunc(projectID, date) {
-    let token = document.getElementById("token").value;
-    
     $.ajax({
         url: `/api/monthlyPurchaseCost?id=${projectID}&date=${date}`,
         type: "get",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             let parent = document.getElementById("purchaseCost");
@@ -1290,7 +1222,6 @@ function addPurchaseCostFunc() {
 
 // setMonthlyPurchaseCostFunc 함수는 modal-setMonthlyPurchaseCost에서 Update 버튼을 클릭하면 restAPI를 이용하여 월별 구매 내역을 업데이트하는 함수이다.
 function setMonthlyPurchaseCostFunc() {
-    let token = document.getElementById("token").value;
     let id = document.getElementById("projectID").value;
     let date = document.getElementById("date").value;
     let num = document.getElementById("purchaseCostNum").value;
@@ -1321,9 +1252,6 @@ function setMonthlyPurchaseCostFunc() {
     $.ajax({
         url: `/api/setMonthlyPurchaseCost?id=${id}&date=${date}&${url.join("&")}`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             alert("구매 내역이 저장되었습니다.");
@@ -1368,14 +1296,9 @@ function addPaymentFunc() {
 
 // setMonthlyPaymentModalFunc 함수는 ... 버튼을 클릭하면 프로젝트 ID, 날짜, 매출 내역을 modal 창에 보여주는 함수이다.
 function setMonthlyPaymentModalFunc(projectID, date) {
-    let token = document.getElementById("token").value;
-    
     $.ajax({
         url: `/api/monthlyPayment?id=${projectID}&date=${date}`,
         type: "get",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             let parent = document.getElementById("modal-setMonthlyPayment-payment");
@@ -1560,7 +1483,6 @@ function delMonthlyPaymentFunc(index) {
 
 // setMonthlyPaymentFunc 함수는 modal-setMonthlyPayment에서 Update 버튼을 클릭하면 restAPI를 이용하여 월별 매출 내역을 업데이트하는 함수이다.
 function setMonthlyPaymentFunc() {
-    let token = document.getElementById("token").value;
     let id = document.getElementById("modal-setMonthlyPayment-projectID").value;
     let date = document.getElementById("modal-setMonthlyPayment-date").value;
     let num = document.getElementById("modal-setMonthlyPayment-paymentNum").value;
@@ -1672,9 +1594,6 @@ function setMonthlyPaymentFunc() {
     $.ajax({
         url: `/api/setMonthlyPayment?id=${id}&date=${date}&${url.join("&")}`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             alert("매출 내역이 저장되었습니다.");
@@ -1693,15 +1612,11 @@ function setMonthlyPaymentFunc() {
 
 // updateProjectsFunc 함수는 샷건에서 프로젝트를 업데이트하는 함수이다.
 function updateProjectsFunc() {
-    let token = document.getElementById("token").value;
     $("#modal-updateprojects").modal("show");
 
     $.ajax({
         url: `/api/updateprojects`,
         type: "post",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         success: function(){
             $("#modal-updateprojects").modal("hide");
             window.location.reload()
@@ -1988,13 +1903,9 @@ function setRmVendorModalFunc(id, project, name) {
 
 // rmVendorFunc 함수는 Vendor를 삭제하는 함수이다.
 function rmVendorFunc(id, project, name) {
-    let token = document.getElementById("token").value;
     $.ajax({
         url: `/api/rmvendor?id=${id}&project=${project}&name=${name}`,
         type: "delete",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         success: function() {
             alert("Vendor가 삭제되었습니다.")
             location.reload();  // 페이지 새로고침
@@ -2166,6 +2077,15 @@ function addTeamSettingHeadFunc(teams) {
                 <input type="text" name="head${childNum}" id="head${childNum}" class="form-control">
                 <small class="form-text text-muted">예산에서 사용될 본부를 입력해주세요.</small>
             </div>
+            <div class="form-group">
+                <label class="text-muted" for="head${childNum}-approver-production">결재자(프로덕션)</label>
+                <input type="text" name="head${childNum}-approver-production" id="head${childNum}-approver-production" class="form-control">
+            </div>
+            <div class="form-group">
+                <label class="text-muted" for="head${childNum}-approver-management">결재자(매니지먼트)</label>
+                <input type="text" name="head${childNum}-approver-management" id="head${childNum}-approver-management" class="form-control">
+                <small class="form-text text-muted">예산안을 승인할 사용자 ID를 띄어쓰기로 구분하여 입력해주세요.</small>
+            </div>
         </div>
         <div class="col-10">
             <div id="head${childNum}-dept"> 
@@ -2201,14 +2121,9 @@ function setRmBGProjectModalFunc(id, name) {
 
 // rmBGProjectFunc 함수는 restAPI를 이용하여 예산 프로젝트를 삭제하는 함수이다.
 function rmBGProjectFunc(id) {
-    let token = document.getElementById("token").value;
-
     $.ajax({
         url: `/api/rmbgproject?id=${id}`,
         type: "delete",
-        headers: {
-            "Authorization": "Basic " + token,
-        },
         dataType: "json",
         success: function(data) {
             alert("프로젝트가 삭제되었습니다.")
@@ -2937,4 +2852,119 @@ $(document).on('keyup','input[inputmode=numeric]', function(event){
         range.moveEnd("character", end);
         range.select();
     }
-});
\ No newline at end of file
+});
+// checkClientPageFunc 함수는 클라이언트 추가, 수정 페이지에서 필수 정보를 입력했는지 확인하는 함수이다.
+function checkClientPageFunc() {
+    if (document.getElementById("name").value.trim() == "") {
+        alert("이름을 적어주세요")
+        return false
+    }
+    let emailPattern = /^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$/;
+    let contactNum = document.getElementById("contactNum").value;
+    for (let i = 0; i < contactNum; i++) {
+        let email = document.getElementById(`contactemail${i}`).value.trim();
+        if (email != "" && emailPattern.test(email) == false) {
+            alert(`${i + 1}번째 연락처의 이메일 형식이 올바르지 않습니다`)
+            return false
+        }
+    }
+    return true
+}
+
+// addClientContactFunc 함수는 클라이언트 추가, 수정 페이지에서 연락처 입력칸을 추가하는 함수이다.
+function addClientContactFunc() {
+    let childNum = document.getElementById("addcontact").childElementCount;
+    let e = document.createElement("div");
+    let html = `
+    <div class="row pt-2">
+        <div class="col">
+            <div class="form-group pb-2">
+                <label class="text-muted">이름</label>
+                <input type="text" class="form-control" id="contactname${childNum}" name="contactname${childNum}">
+            </div>
+        </div>
+        <div class="col">
+            <div class="form-group pb-2">
+                <label class="text-muted">직책</label>
+                <input type="text" class="form-control" id="contactposition${childNum}" name="contactposition${childNum}">
+            </div>
+        </div>
+        <div class="col">
+            <div class="form-group pb-2">
+                <label class="text-muted">전화번호</label>
+                <input type="text" class="form-control" id="contactphone${childNum}" name="contactphone${childNum}">
+            </div>
+        </div>
+        <div class="col">
+            <div class="form-group pb-2">
+                <label class="text-muted">이메일</label>
+                <input type="text" class="form-control" id="contactemail${childNum}" name="contactemail${childNum}">
+            </div>
+        </div>
+    </div>
+    `
+    e.innerHTML = html;
+    document.getElementById("addcontact").appendChild(e);
+    document.getElementById("contactNum").value = document.getElementById("addcontact").childElementCount;
+}
+
+// setRmClientModalFunc 함수는 클라이언트 삭제 버튼을 클릭하면 ID, 이름을 받아 modal 창에 보여주는 함수이다.
+function setRmClientModalFunc(id, name) {
+    document.getElementById("modal-rmclient-id").value = id;
+    document.getElementById("modal-rmclient-name").value = name;
+}
+
+// rmClientFunc 함수는 restAPI를 이용하여 클라이언트를 삭제하는 함수이다.
+function rmClientFunc(id) {
+    $.ajax({
+        url: `/api/rmclient?id=${id}`,
+        type: "delete",
+        dataType: "json",
+        success: function(data) {
+            alert("클라이언트가 삭제되었습니다.")
+            location.reload();  // 페이지 새로고침
+        },
+        error: function(request, status, error) {
+            alert(`code: ${request.status}\nstatus: ${status}\nmsg: ${request.responseText}\nerror: ${error}`);
+        }
+    })
+}
+
+// setRmRateCardModalFunc 함수는 단가표 삭제 버튼을 클릭하면 ID, 이름을 받아 modal 창에 보여주는 함수이다.
+function setRmRateCardModalFunc(id, name) {
+    document.getElementById("modal-rmratecard-id").value = id;
+    document.getElementById("modal-rmratecard-name").value = name;
+}
+
+// rmRateCardFunc 함수는 restAPI를 이용하여 단가표를 삭제하는 함수이다.
+function rmRateCardFunc(id) {
+    $.ajax({
+        url: `/api/rmratecard?id=${id}`,
+        type: "delete",
+        dataType: "json",
+        success: function(data) {
+            alert("단가표가 삭제되었습니다.")
+            location.reload();  // 페이지 새로고침
+        },
+        error: function(request, status, error) {
+            alert(`code: ${request.status}\nstatus: ${status}\nmsg: ${request.responseText}\nerror: ${error}`);
+        }
+    })
+}
+
+// addEpisodeFunc 함수는 에피소드 페이지에서 에피소드 입력줄을 추가하는 함수이다.
+function addEpisodeFunc() {
+    let tbody = document.getElementById("episodes");
+    let num = tbody.childElementCount;
+    let e = document.createElement("tr");
+    e.innerHTML = `
+        <td class="border-top-gray"><input type="text" class="form-control form-control-sm" name="episode${num}-name" placeholder="EP01"></td>
+        <td class="border-top-gray"><input type="date" class="form-control form-control-sm" name="episode${num}-airdate" max="9999-12-31"></td>
+        <td class="border-top-gray"><input type="date" class="form-control form-control-sm" name="episode${num}-deliverydate" max="9999-12-31"></td>
+        <td class="border-top-gray"><input type="text" class="form-control form-control-sm text-right" name="episode${num}-cuts"></td>
+        <td class="border-top-gray"><input type="text" class="form-control form-control-sm text-right" name="episode${num}-budget"></td>
+        <td class="border-top-gray"></td>
+    `;
+    tbody.appendChild(e);
+    document.getElementById("episodenum").value = tbody.childElementCount;
+}
@@ -0,0 +1,50 @@
+// 프로젝트 결산 프로그램
+//
+// Description : CSRF 토큰을 POST form과 ajax 요청에 넣는 스크립트
+
+// getCSRFTokenFunc 함수는 쿠키에 저장된 CSRF 토큰을 가져오는 함수이다.
+function getCSRFTokenFunc() {
+    let match = document.cookie.match(/(?:^|;\s*)CSRFToken=([^;]*)/);
+    return match ? decodeURIComponent(match[1]) : "";
+}
+
+// addCSRFTokenFunc 함수는 POST form에 CSRF 토큰 hidden input을 넣는 함수이다. 이미 있으면 값만 바꾼다.
+function addCSRFTokenFunc(form) {
+    if (!(form instanceof HTMLFormElement) || form.method.toLowerCase() !== "post") {
+        return;
+    }
+    let input = form.querySelector("input[name='csrf_token']");
+    if (!input) {
+        input = document.createElement("input");
+        input.type = "hidden";
+        input.name = "csrf_token";
+        form.appendChild(input);
+    }
+    input.value = getCSRFTokenFunc();
+}
+
+// 페이지의 모든 POST form에 토큰을 넣고, 나중에 만들어진 form은 submit할 때 넣는다.
+document.addEventListener("DOMContentLoaded", function() {
+    Array.prototype.forEach.call(document.forms, addCSRFTokenFunc);
+});
+document.addEventListener("submit", function(event) {
+    addCSRFTokenFunc(event.target);
+}, true);
+
+// 같은 서버로 보내는 ajax 요청(Dropzone 업로드 포함)에는 X-CSRF-Token 헤더를 넣는다.
+// restAPI는 GET 요청도 세션 쿠키로 인증하므로 요청 방식과 상관없이 헤더를 넣는다.
+(function() {
+    let open = XMLHttpRequest.prototype.open;
+    let send = XMLHttpRequest.prototype.send;
+    XMLHttpRequest.prototype.open = function(method, url) {
+        let target = new URL(url, window.location.href);
+        this.csrfRequired = target.origin === window.location.origin;
+        return open.apply(this, arguments);
+    };
+    XMLHttpRequest.prototype.send = function() {
+        if (this.csrfRequired) {
+            this.setRequestHeader("X-CSRF-Token", getCSRFTokenFunc());
+        }
+        return send.apply(this, arguments);
+    };
+})();
@@ -0,0 +1,344 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 감사 로그(동작, 대상, 수정 전후 값) 관련 스크립트
+
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/user"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 로그의 동작 값
+const (
+	LogActionCreate = "create" // 추가
+	LogActionUpdate = "update" // 수정
+	LogActionDelete = "delete" // 삭제
+	LogActionView   = "view"   // 민감한 정보 조회
+	LogActionExport = "export" // 다운로드
+	LogActionImport = "import" // 엑셀 업로드, 임포트
+	LogActionSync   = "sync"   // Shotgun 동기화
+	LogActionLogin  = "login"  // 로그인, 계정 잠금
+)
+
+// 로그의 대상 종류 값
+const (
+	LogEntityUser          = "user"
+	LogEntityRole          = "role"
+	LogEntitySession       = "session"
+	LogEntityAPIToken      = "apitoken"
+	LogEntityAdminSetting  = "adminsetting"
+	LogEntityArtist        = "artist"
+	LogEntityProject       = "project"
+	LogEntityBGProject     = "bgproject"
+	LogEntityTeamSetting   = "teamsetting"
+	LogEntityClient        = "client"
+	LogEntityVendor        = "vendor"
+	LogEntityRateCard      = "ratecard"
+	LogEntityTimelog       = "timelog"
+	LogEntityMonthlyStatus = "monthlystatus"
+	LogEntityLog           = "log"
+)
+
+// LogRedacted 는 로그에 남기지 않는 암호화된 값 대신 저장하는 문자열이다.
+const LogRedacted = "(암호화됨)"
+
+// LogActions 는 로그 페이지에서 검색할 수 있는 동작 리스트이다.
+var LogActions = []LogOption{
+	{ID: LogActionCreate, Name: "추가"},
+	{ID: LogActionUpdate, Name: "수정"},
+	{ID: LogActionDelete, Name: "삭제"},
+	{ID: LogActionView, Name: "조회"},
+	{ID: LogActionExport, Name: "다운로드"},
+	{ID: LogActionImport, Name: "임포트"},
+	{ID: LogActionSync, Name: "동기화"},
+	{ID: LogActionLogin, Name: "로그인"},
+}
+
+// LogEntityTypes 는 로그 페이지에서 검색할 수 있는 대상 종류 리스트이다.
+var LogEntityTypes = []LogOption{
+	{ID: LogEntityUser, Name: "유저"},
+	{ID: LogEntityRole, Name: "역할"},
+	{ID: LogEntitySession, Name: "로그인 세션"},
+	{ID: LogEntityAPIToken, Name: "API 토큰"},
+	{ID: LogEntityAdminSetting, Name: "AdminSetting"},
+	{ID: LogEntityArtist, Name: "아티스트"},
+	{ID: LogEntityProject, Name: "프로젝트"},
+	{ID: LogEntityBGProject, Name: "예산 프로젝트"},
+	{ID: LogEntityTeamSetting, Name: "예산 팀세팅"},
+	{ID: LogEntityClient, Name: "클라이언트"},
+	{ID: LogEntityVendor, Name: "벤더"},
+	{ID: LogEntityRateCard, Name: "단가표"},
+	{ID: LogEntityTimelog, Name: "타임로그"},
+	{ID: LogEntityMonthlyStatus, Name: "월 결산"},
+	{ID: LogEntityLog, Name: "로그"},
+}
+
+// logSensitiveFields 는 값을 로그에 남기지 않는 필드 이름이다. 이름이 Password로 끝나는 필드도 남기지 않는다.
+var logSensitiveFields = map[string]bool{
+	"Token":      true,
+	"SignKey":    true,
+	"TOTPSecret": true,
+	"Hash":       true,
+}
+
+// regexEncrypted 는 encryptAES256Func 함수로 암호화된 값(AES 블록 단위의 hex 문자열)과 일치하는 정규표현식이다.
+var regexEncrypted = regexp.MustCompile(`^(?:[0-9a-f]{32})+$`)
+
+// logOptionNameFunc 함수는 로그 검색 항목 ID에 해당하는 이름을 반환하는 함수이다. 없으면 ID를 그대로 반환한다.
+func logOptionNameFunc(options []LogOption, id string) string {
+	for _, o := range options {
+		if o.ID == id {
+			return o.Name
+		}
+	}
+	return id
+}
+
+// cmdActorFunc 함수는 CLI 명령으로 남기는 로그의 유저 ID를 반환하는 함수이다.
+func cmdActorFunc() string {
+	u, err := user.Current()
+	if err != nil {
+		return "cli"
+	}
+	return "cli:" + u.Username
+}
+
+// LogServiceActor 는 정기적으로 도는 서비스가 남기는 로그의 유저 ID이다.
+const LogServiceActor = "service"
+
+// apiActorFunc 함수는 restAPI 요청을 보낸 사용자 ID를 반환하는 함수이다.
+// permissionMiddlewareFunc에서 Authorization 헤더의 토큰으로 확인해 리퀘스트 context에 넣은 사용자를 사용하고, 확인한 사용자가 없으면 빈 문자열을 반환한다.
+func apiActorFunc(r *http.Request) string {
+	u, ok := requestUserFunc(r)
+	if !ok {
+		return ""
+	}
+	return u.ID
+}
+
+// redactLogValueFunc 함수는 로그에 남길 수 없는 값을 가리는 함수이다.
+// 민감한 필드이거나 암호화된 값처럼 보이면 LogRedacted를 반환한다.
+func redactLogValueFunc(field, value string) string {
+	if value == "" {
+		return value
+	}
+	name := field
+	if i := strings.LastIndex(name, "."); i != -1 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "["); i != -1 {
+		name = name[:i]
+	}
+	if logSensitiveFields[name] || strings.HasSuffix(name, "Password") || regexEncrypted.MatchString(value) {
+		return LogRedacted
+	}
+	return value
+}
+
+// flattenEntityFunc 함수는 자료구조의 값을 "필드 이름: 값" 형태로 펼치는 함수이다.
+// 구조체는 Field.SubField, 맵은 Field[key], 구조체 슬라이스는 Field[0] 형태의 이름을 사용한다.
+func flattenEntityFunc(prefix string, v reflect.Value, result map[string]string) {
+	if !v.IsValid() {
+		result[prefix] = ""
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			result[prefix] = ""
+			return
+		}
+		flattenEntityFunc(prefix, v.Elem(), result)
+		return
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			result[prefix] = ""
+		} else {
+			result[prefix] = t.Format(time.RFC3339)
+		}
+		return
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok && v.Kind() != reflect.Struct && v.Kind() != reflect.Map && v.Kind() != reflect.Slice {
+		result[prefix] = s.String()
+		return
+	}
+	join := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" { // unexported 필드
+				continue
+			}
+			flattenEntityFunc(join(f.Name), v.Field(i), result)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			flattenEntityFunc(fmt.Sprintf("%s[%v]", prefix, k.Interface()), v.MapIndex(k), result)
+		}
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Ptr, reflect.Interface:
+			for i := 0; i < v.Len(); i++ {
+				flattenEntityFunc(fmt.Sprintf("%s[%d]", prefix, i), v.Index(i), result)
+			}
+		default:
+			var values []string
+			for i := 0; i < v.Len(); i++ {
+				values = append(values, fmt.Sprint(v.Index(i).Interface()))
+			}
+			result[prefix] = strings.Join(values, ",")
+		}
+	default:
+		result[prefix] = fmt.Sprint(v.Interface())
+	}
+}
+
+// entitySnapshot 은 자료구조의 값을 펼쳐서 복사해둔 것이다.
+type entitySnapshot map[string]string
+
+// snapshotEntityFunc 함수는 자료구조의 현재 값을 복사해두는 함수이다.
+// 맵이나 슬라이스의 값을 직접 바꾸는 경우 구조체를 복사해도 수정 전 값이 남지 않으므로, 수정하기 전에 이 함수로 복사해둔다.
+func snapshotEntityFunc(v interface{}) entitySnapshot {
+	result := make(entitySnapshot)
+	flattenEntityFunc("", reflect.ValueOf(v), result)
+	return result
+}
+
+// diffEntityFunc 함수는 같은 자료구조의 수정 전후 값을 비교해서 바뀐 항목 리스트를 반환하는 함수이다. 자료구조 대신 snapshotEntityFunc 함수로 복사해둔 값을 넣을 수 있다.
+// 암호화된 값은 같은 값이면 암호문도 같기 때문에 바뀌었는지는 알 수 있지만, 값은 LogRedacted로 가려서 반환한다.
+func diffEntityFunc(before, after interface{}) []LogChange {
+	b, ok := before.(entitySnapshot)
+	if !ok {
+		b = snapshotEntityFunc(before)
+	}
+	a, ok := after.(entitySnapshot)
+	if !ok {
+		a = snapshotEntityFunc(after)
+	}
+
+	var fields []string
+	for k := range b {
+		fields = append(fields, k)
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []LogChange
+	for _, f := range fields {
+		if b[f] == a[f] {
+			continue
+		}
+		changes = append(changes, LogChange{
+			Field:  f,
+			Before: redactLogValueFunc(f, b[f]),
+			After:  redactLogValueFunc(f, a[f]),
+		})
+	}
+	return changes
+}
+
+// writeLogsCSVFunc 함수는 로그를 CSV 형식으로 쓰는 함수이다. 바뀐 항목이 여러 개인 로그는 항목마다 한 줄씩 쓴다.
+// 엑셀에서 한글이 깨지지 않도록 UTF-8 BOM을 먼저 쓰고, 사용자가 입력한 값이 수식으로 실행되지 않도록 각 칸을 escapeCSVCellsFunc로 바꾼다.
+func writeLogsCSVFunc(w io.Writer, logs []Log) error {
+	_, err := io.WriteString(w, "\ufeff")
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	err = cw.Write([]string{"시간", "사용자 ID", "IP", "동작", "대상 종류", "대상 ID", "항목", "수정 전", "수정 후", "로그 내용"})
+	if err != nil {
+		return err
+	}
+	for _, l := range logs {
+		row := []string{
+			l.CreatedAt.Format("2006-01-02 15:04:05"),
+			l.UserID,
+			l.IP,
+			logOptionNameFunc(LogActions, l.Action),
+			logOptionNameFunc(LogEntityTypes, l.EntityType),
+			l.EntityID,
+		}
+		if len(l.Changes) == 0 {
+			err = cw.Write(escapeCSVCellsFunc(append(row, "", "", "", l.Content)))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, c := range l.Changes {
+			err = cw.Write(escapeCSVCellsFunc(append(append([]string{}, row...), c.Field, c.Before, c.After, l.Content)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeCSVCellsFunc 함수는 엑셀에서 CSV 파일을 열 때 수식으로 실행되지 않도록 =, +, -, @, 탭, 캐리지 리턴으로 시작하는 값 앞에 '를 붙이는 함수이다.
+func escapeCSVCellsFunc(row []string) []string {
+	for i, cell := range row {
+		if cell != "" && strings.ContainsAny(cell[:1], "=+-@\t\r") {
+			row[i] = "'" + cell
+		}
+	}
+	return row
+}
+
+// logFilterFromQueryFunc 함수는 로그 페이지의 URL 쿼리를 로그 검색 조건으로 바꾸는 함수이다.
+// 날짜는 한국 시간 기준 2006-01-02 형식이고, 끝 날짜는 그 날의 로그까지 포함한다.
+func logFilterFromQueryFunc(q url.Values) (LogFilter, error) {
+	f := LogFilter{
+		UserID:     strings.TrimSpace(q.Get("userid")),
+		EntityType: q.Get("entitytype"),
+		EntityID:   strings.TrimSpace(q.Get("entityid")),
+		Action:     q.Get("action"),
+		Keyword:    strings.TrimSpace(q.Get("keyword")),
+	}
+	kst := time.FixedZone("KST", 9*60*60)
+	if q.Get("start") != "" {
+		start, err := time.ParseInLocation("2006-01-02", q.Get("start"), kst)
+		if err != nil {
+			return f, errors.New("시작 날짜는 2006-01-02 형식으로 입력해주세요")
+		}
+		f.Start = start.UTC()
+	}
+	if q.Get("end") != "" {
+		end, err := time.ParseInLocation("2006-01-02", q.Get("end"), kst)
+		if err != nil {
+			return f, errors.New("끝 날짜는 2006-01-02 형식으로 입력해주세요")
+		}
+		f.End = end.AddDate(0, 0, 1).UTC()
+	}
+	if !f.Start.IsZero() && !f.End.IsZero() && !f.Start.Before(f.End) {
+		return f, errors.New("시작 날짜가 끝 날짜보다 늦습니다")
+	}
+	return f, nil
+}
@@ -0,0 +1,204 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 감사 로그 테스트 스크립트
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 수정 전후 자료구조를 비교해서 바뀐 항목을 올바르게 반환하는지 테스트하기 위한 함수
+func Test_diffEntity(t *testing.T) {
+	before := Project{
+		ID:   "KIJ",
+		Name: "킹덤",
+		SMMonthlyPayment: map[string][]Payment{
+			"2020-03": {{Type: "계약금", Date: "2020-03-10", Expenses: strings.Repeat("ab", 16)}},
+		},
+	}
+	snapshot := snapshotEntityFunc(before)
+	after := before
+	after.SMMonthlyPayment["2020-03"] = []Payment{{Type: "계약금", Date: "2020-03-15", Expenses: strings.Repeat("cd", 16)}}
+
+	cases := []struct {
+		before interface{}
+		after  interface{}
+		want   []LogChange
+	}{{
+		before: snapshot,
+		after:  after,
+		want: []LogChange{
+			{Field: "SMMonthlyPayment[2020-03][0].Date", Before: "2020-03-10", After: "2020-03-15"},
+			{Field: "SMMonthlyPayment[2020-03][0].Expenses", Before: LogRedacted, After: LogRedacted},
+		},
+	}, {
+		before: User{ID: "kim", Team: "RND", Password: "old"},
+		after:  User{ID: "kim", Team: "PIPELINE", Password: "new"},
+		want: []LogChange{
+			{Field: "Password", Before: LogRedacted, After: LogRedacted},
+			{Field: "Team", Before: "RND", After: "PIPELINE"},
+		},
+	}, {
+		before: Vendor{},
+		after:  Vendor{Name: "덱스터", Tasks: []string{"comp", "fx"}},
+		want: []LogChange{
+			{Field: "Name", Before: "", After: "덱스터"},
+			{Field: "Tasks", Before: "", After: "comp,fx"},
+		},
+	}, {
+		before: Vendor{Name: "덱스터"},
+		after:  Vendor{Name: "덱스터"},
+		want:   nil,
+	}}
+	for _, c := range cases {
+		got := diffEntityFunc(c.before, c.after)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Test_diffEntity(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.before, c.after, c.want, got)
+		}
+	}
+}
+
+// 로그 페이지의 URL 쿼리를 올바른 검색 조건으로 바꾸는지 테스트하기 위한 함수
+func Test_logFilterFromQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  LogFilter
+		err   bool
+	}{{
+		query: "userid=kim&entitytype=project&entityid=KIJ&action=update",
+		want:  LogFilter{UserID: "kim", EntityType: "project", EntityID: "KIJ", Action: "update"},
+	}, {
+		query: "keyword=+3월+매출+",
+		want:  LogFilter{Keyword: "3월 매출"},
+	}, {
+		query: "start=2020-03-01&end=2020-03-31",
+		want: LogFilter{
+			Start: time.Date(2020, 2, 29, 15, 0, 0, 0, time.UTC),
+			End:   time.Date(2020, 3, 31, 15, 0, 0, 0, time.UTC),
+		},
+	}, {
+		query: "start=2020-03-01&end=2020-03-01",
+		want: LogFilter{
+			Start: time.Date(2020, 2, 29, 15, 0, 0, 0, time.UTC),
+			End:   time.Date(2020, 3, 1, 15, 0, 0, 0, time.UTC),
+		},
+	}, {
+		query: "start=2020-03-31&end=2020-03-01",
+		err:   true,
+	}, {
+		query: "start=20200301",
+		err:   true,
+	}}
+	for _, c := range cases {
+		q, err := url.ParseQuery(c.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := logFilterFromQueryFunc(q)
+		if c.err {
+			if err == nil {
+				t.Fatalf("Test_logFilterFromQuery(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.query, "에러", got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Fatalf("Test_logFilterFromQuery(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.query, c.want, got)
+		}
+	}
+}
+
+// 바뀐 항목마다 CSV 한 줄씩 쓰는지 테스트하기 위한 함수
+func Test_writeLogsCSV(t *testing.T) {
+	logs := []Log{{
+		UserID:     "kim",
+		CreatedAt:  time.Date(2020, 3, 2, 9, 0, 0, 0, time.UTC),
+		Content:    "프로젝트 KIJ가 수정되었습니다.",
+		Action:     LogActionUpdate,
+		EntityType: LogEntityProject,
+		EntityID:   "KIJ",
+		Changes: []LogChange{
+			{Field: "Name", Before: "킹덤", After: "킹덤2"},
+			{Field: "IsFinished", Before: "false", After: "true"},
+		},
+		IP: "10.0.0.1",
+	}, {
+		UserID:    "lee",
+		CreatedAt: time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC),
+		Content:   "=1+1",
+	}}
+	var buf bytes.Buffer
+	err := writeLogsCSVFunc(&buf, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 { // 헤더 + 바뀐 항목 2줄 + 항목이 없는 로그 1줄
+		t.Fatalf("Test_writeLogsCSV(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", logs, 4, len(records))
+	}
+	if records[2][6] != "IsFinished" || records[2][8] != "true" || records[3][6] != "" {
+		t.Fatalf("Test_writeLogsCSV(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", logs, "IsFinished, true", records)
+	}
+	if records[3][9] != "'=1+1" { // 수식으로 실행되지 않도록 '를 붙인다.
+		t.Fatalf("Test_writeLogsCSV(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", logs, "'=1+1", records[3][9])
+	}
+}
+
+// CSV 값이 수식으로 실행되지 않도록 바꾸는지 테스트하기 위한 함수
+func Test_escapeCSVCells(t *testing.T) {
+	cases := []struct {
+		cell string
+		want string
+	}{
+		{cell: "=HYPERLINK(\"http://example.com\")", want: "'=HYPERLINK(\"http://example.com\")"},
+		{cell: "+1+2", want: "'+1+2"},
+		{cell: "-2+3", want: "'-2+3"},
+		{cell: "@SUM(A1)", want: "'@SUM(A1)"},
+		{cell: "\t=1", want: "'\t=1"},
+		{cell: "킹덤=2", want: "킹덤=2"},
+		{cell: "", want: ""},
+	}
+	for _, c := range cases {
+		got := escapeCSVCellsFunc([]string{c.cell})[0]
+		if got != c.want {
+			t.Fatalf("Test_escapeCSVCells(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.cell, c.want, got)
+		}
+	}
+}
+
+// restAPI 로그의 사용자를 미들웨어에서 확인한 사용자로 남기는지 테스트하기 위한 함수
+func Test_apiActor(t *testing.T) {
+	cases := []struct {
+		user *User
+		want string
+	}{
+		{user: &User{ID: "kim"}, want: "kim"},
+		{user: nil, want: ""}, // 세션 쿠키가 있어도 사용하지 않는다.
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodDelete, "/api/rmartist?id=lee", nil)
+		r.AddCookie(&http.Cookie{Name: "SessionToken", Value: "token"})
+		if c.user != nil {
+			r = r.WithContext(context.WithValue(r.Context(), userContextKey, *c.user))
+		}
+		got := apiActorFunc(r)
+		if got != c.want {
+			t.Fatalf("Test_apiActor(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.user, c.want, got)
+		}
+	}
+}
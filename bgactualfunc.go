@@ -0,0 +1,269 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 대비 실제 비용 비교 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// newBGActualFunc 함수는 예산과 실제 비용으로 차이와 차이 비율을 계산하여 BGActual을 만드는 함수이다.
+func newBGActualFunc(head string, dept string, budget int, actual int) BGActual {
+	a := BGActual{
+		Headquarter: head,
+		Department:  dept,
+		Budget:      budget,
+		Actual:      actual,
+		Variance:    actual - budget,
+	}
+	if budget != 0 {
+		a.VarianceRatio = strconv.FormatFloat(math.Round(float64(a.Variance)/float64(budget)*1000)/10, 'f', 1, 64)
+	}
+	return a
+}
+
+// getBGDeptOfTeamFunc 함수는 예산 팀세팅에서 팀이 속한 본부와 부서를 찾는 함수이다.
+// Controls에 속한 팀은 Management 부서로 분류하고, 찾지 못하면 빈 문자열을 반환한다.
+func getBGDeptOfTeamFunc(ts BGTeamSetting, team string) (string, string) {
+	if team == "" {
+		return "", ""
+	}
+	for _, head := range ts.Headquarters {
+		for _, dept := range ts.Departments[head] {
+			for _, part := range dept.Parts {
+				for _, task := range part.Tasks {
+					if checkStringInListFunc(team, ts.Teams[task]) {
+						return head, dept.Name
+					}
+				}
+			}
+		}
+		for _, control := range ts.Controls[head] {
+			for _, part := range control.Parts {
+				if checkStringInListFunc(team, part.Teams) {
+					return head, BGDeptManagement
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// calBGActualFunc 함수는 결산 프로젝트와 연결된 예산 프로젝트의 메인 예산안을 비교하여 본부, 부서별 예산과 실제 비용을 계산하는 함수이다.
+// 실제 인건비는 타임로그로 계산하고 타임로그 당시 아티스트의 팀으로 본부, 부서를 나눈다. 외주비와 진행비는 계약 결정액에 외주비율과 진행비율을 곱한 값을 예산으로 사용한다.
+func calBGActualFunc(client *mongo.Client, project Project, bgp BGProject) ([]BGActual, error) {
+	typedata, ok := bgp.TypeData[bgp.MainType]
+	if !ok {
+		return nil, fmt.Errorf("%s 예산 프로젝트에 메인 예산안이 없습니다", bgp.ID)
+	}
+	ts := typedata.TeamSetting
+	if len(ts.Headquarters) == 0 { // 예산안에 팀세팅이 저장되어 있지 않으면 현재 팀세팅을 사용한다.
+		var err error
+		ts, err = getBGTeamSettingFunc(client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 본부, 부서별 예산
+	budget := make(map[string]map[string]int)
+	for _, lc := range typedata.LaborCosts {
+		if budget[lc.Headquarter] == nil {
+			budget[lc.Headquarter] = make(map[string]int)
+		}
+		for dept, cost := range lc.DepartmentCost {
+			costInt, err := decryptToIntFunc(cost)
+			if err != nil {
+				return nil, err
+			}
+			budget[lc.Headquarter][dept] += costInt
+		}
+		management, err := decryptToIntFunc(lc.Management)
+		if err != nil {
+			return nil, err
+		}
+		budget[lc.Headquarter][BGDeptManagement] += management
+	}
+
+	// 타임로그로 본부, 부서별 실제 인건비를 계산한다.
+	actual := make(map[string]map[string]float64)
+	dates, err := getDatesFunc(project.StartDate, project.SMEndDate)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := getBGTeamSettingVersionsFunc(client)
+	if err != nil {
+		return nil, err
+	}
+	artists := make(map[string]Artist)
+	for _, date := range dates {
+		day := date + "-01" // 타임로그는 월 단위이므로 그 달 1일을 기준으로 팀과 팀세팅을 찾는다.
+		year, err := strconv.Atoi(strings.Split(date, "-")[0])
+		if err != nil {
+			return nil, err
+		}
+		month, err := strconv.Atoi(strings.Split(date, "-")[1])
+		if err != nil {
+			return nil, err
+		}
+		for _, head := range []string{"VFX", "CM"} {
+			var timelogs []Timelog
+			if head == "VFX" {
+				timelogs, err = getTimelogOfTheProjectVFXFunc(client, year, month, project.ID)
+			} else {
+				timelogs, err = getTimelogOfTheProjectCMFunc(client, year, month, project.ID)
+			}
+			if err != nil {
+				if err == mongo.ErrNoDocuments {
+					continue
+				}
+				return nil, err
+			}
+			for _, t := range timelogs {
+				artist, ok := artists[t.UserID]
+				if !ok {
+					artist, err = getArtistFunc(client, t.UserID)
+					if err != nil {
+						if err == mongo.ErrNoDocuments {
+							continue
+						}
+						return nil, err
+					}
+					artists[t.UserID] = artist
+				}
+				hourlyWage := 0.0
+				if artist.Salary[strconv.Itoa(year)] != "" {
+					hourlyWage, err = hourlyWageFunc(artist, year, month) // 시급 계산
+					if err != nil {
+						return nil, err
+					}
+				}
+				duration := math.Round(t.Duration/60*10) / 10
+
+				// 현재 팀이 아니라 타임로그 당시의 팀으로 분류한다.
+				team := getArtistTeamOnDateFunc(artist, day)
+				h, dept := getBGDeptOfTeamFunc(ts, team)
+				if h == "" { // 예산안 팀세팅에 없는 팀은 타임로그 당시에 사용하던 팀세팅에서 찾는다.
+					if old, ok := getBGTeamSettingOnDateFunc(versions, day); ok {
+						h, dept = getBGDeptOfTeamFunc(old, team)
+					}
+				}
+				if h == "" { // 팀세팅에 없는 팀은 타임로그 본부의 ETC로 분류한다.
+					h = head
+					dept = BGDeptETC
+				}
+				if actual[h] == nil {
+					actual[h] = make(map[string]float64)
+				}
+				actual[h][dept] += duration * hourlyWage
+			}
+		}
+	}
+
+	// 팀세팅의 본부, 부서 순서대로 정리하고 팀세팅에 없는 항목은 뒤에 붙인다.
+	heads := append([]string{}, ts.Headquarters...)
+	var extraHeads []string
+	for head := range budget {
+		if !checkStringInListFunc(head, heads) && !checkStringInListFunc(head, extraHeads) {
+			extraHeads = append(extraHeads, head)
+		}
+	}
+	for head := range actual {
+		if !checkStringInListFunc(head, heads) && !checkStringInListFunc(head, extraHeads) {
+			extraHeads = append(extraHeads, head)
+		}
+	}
+	sort.Strings(extraHeads)
+	heads = append(heads, extraHeads...)
+
+	var results []BGActual
+	for _, head := range heads {
+		var depts []string
+		for _, dept := range ts.Departments[head] {
+			depts = append(depts, dept.Name)
+		}
+		var extraDepts []string
+		for dept := range budget[head] {
+			if !checkStringInListFunc(dept, depts) && !checkStringInListFunc(dept, extraDepts) {
+				extraDepts = append(extraDepts, dept)
+			}
+		}
+		for dept := range actual[head] {
+			if !checkStringInListFunc(dept, depts) && !checkStringInListFunc(dept, extraDepts) {
+				extraDepts = append(extraDepts, dept)
+			}
+		}
+		sort.Strings(extraDepts)
+		depts = append(depts, extraDepts...)
+
+		for _, dept := range depts {
+			b := budget[head][dept]
+			a := int(math.Round(actual[head][dept]))
+			if !checkStringInListFunc(dept, extraDepts) || b != 0 || a != 0 {
+				results = append(results, newBGActualFunc(head, dept, b, a))
+			}
+		}
+	}
+
+	// 외주비, 진행비 예산은 계약 결정액(없으면 제안 견적)에 비율을 곱해 계산한다.
+	contract, err := decryptToIntFunc(typedata.Decision)
+	if err != nil {
+		return nil, err
+	}
+	if contract == 0 {
+		contract, err = decryptToIntFunc(typedata.Proposal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	vendors, err := searchVendorFunc(client, "project:"+project.ID)
+	if err != nil {
+		return nil, err
+	}
+	vendorSum := 0
+	for _, v := range vendors {
+		expenses, err := decryptToIntFunc(v.Expenses)
+		if err != nil {
+			return nil, err
+		}
+		vendorSum += expenses
+	}
+	vendorBudget := int(math.Round(float64(contract) * typedata.VendorRatio / 100))
+	results = append(results, newBGActualFunc(BGHeadVendor, "", vendorBudget, vendorSum))
+
+	progressSum := 0
+	if project.IsFinished && project.FinishedCost.ProgressCost != "" {
+		progressSum, err = decryptToIntFunc(project.FinishedCost.ProgressCost)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		progressSum, err = getTotalProgressCostFunc(project)
+		if err != nil {
+			return nil, err
+		}
+	}
+	progressBudget := int(math.Round(float64(contract) * typedata.ProgressRatio / 100))
+	results = append(results, newBGActualFunc(BGHeadProgress, "", progressBudget, progressSum))
+
+	return results, nil
+}
+
+// calBGActualTotalFunc 함수는 예산 대비 실제 비용 항목들의 합계를 계산하는 함수이다.
+func calBGActualTotalFunc(items []BGActual) BGActual {
+	budget := 0
+	actual := 0
+	for _, item := range items {
+		budget += item.Budget
+		actual += item.Actual
+	}
+	return newBGActualFunc(BGHeadTotal, "", budget, actual)
+}
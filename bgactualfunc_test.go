@@ -0,0 +1,58 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 대비 실제 비용 비교 테스트 스크립트
+
+package main
+
+import "testing"
+
+// 팀이 예산 팀세팅의 어느 본부, 부서에 속하는지 찾는지 테스트하기 위한 함수
+func Test_getBGDeptOfTeam(t *testing.T) {
+	ts := BGTeamSetting{
+		Headquarters: []string{"VFX", "CM"},
+		Departments: map[string][]BGDept{
+			"VFX": {{Name: "3D+FX", Parts: []BGPart{{Name: "MatchMove", Tasks: []string{"MM"}}}}, {Name: "COMP", Parts: []BGPart{{Name: "Comp", Tasks: []string{"comp"}}}}},
+			"CM":  {{Name: "CM", Parts: []BGPart{{Name: "CM_Matte", Tasks: []string{"CM_Matte"}}}}},
+		},
+		Controls: map[string][]BGControl{
+			"VFX": {{Name: "SUP+PROD", Parts: []BGControlPart{{Name: "Supervisor", Teams: []string{"Supervisor"}}}}},
+		},
+		Teams: map[string][]string{"MM": {"MatchMove"}, "comp": {"Comp1", "Comp2"}, "CM_Matte": {"cm_Matte"}},
+	}
+	cases := []struct {
+		team string
+		head string
+		dept string
+	}{
+		{team: "Comp2", head: "VFX", dept: "COMP"},
+		{team: "cm_Matte", head: "CM", dept: "CM"},
+		{team: "Supervisor", head: "VFX", dept: BGDeptManagement},
+		{team: "RND", head: "", dept: ""},
+		{team: "", head: "", dept: ""},
+	}
+	for _, c := range cases {
+		head, dept := getBGDeptOfTeamFunc(ts, c.team)
+		if head != c.head || dept != c.dept {
+			t.Fatalf("Test_getBGDeptOfTeam(): 입력 값: %v, 원하는 값: %v %v, 얻은 값: %v %v\n", c.team, c.head, c.dept, head, dept)
+		}
+	}
+}
+
+// 예산 대비 차이와 차이 비율이 올바르게 계산되는지 테스트하기 위한 함수
+func Test_newBGActual(t *testing.T) {
+	cases := []struct {
+		budget int
+		actual int
+		want   string
+	}{
+		{budget: 1000, actual: 1200, want: "20.0"},
+		{budget: 3000, actual: 2000, want: "-33.3"},
+		{budget: 0, actual: 500, want: ""}, // 예산이 없으면 비율을 계산하지 않는다.
+	}
+	for _, c := range cases {
+		got := newBGActualFunc("VFX", "COMP", c.budget, c.actual)
+		if got.VarianceRatio != c.want || got.Variance != c.actual-c.budget {
+			t.Fatalf("Test_newBGActual(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", c.budget, c.actual, c.want, got.VarianceRatio)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산안 결재 관련 스크립트
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// getBGApprovalStatusFunc 함수는 예산안의 결재 상태를 반환하는 함수이다. 결재 상태가 없는 예산안은 작성중으로 본다.
+func getBGApprovalStatusFunc(typedata BGTypeData) string {
+	if typedata.ApprovalStatus == "" {
+		return BGApprovalDraft
+	}
+	return typedata.ApprovalStatus
+}
+
+// checkBGTypeEditableFunc 함수는 예산안을 수정할 수 있는지 확인하는 함수이다. 작성중인 예산안만 수정할 수 있다.
+func checkBGTypeEditableFunc(bgtype string, typedata BGTypeData) error {
+	if getBGApprovalStatusFunc(typedata) != BGApprovalDraft {
+		return fmt.Errorf("%s 예산안은 결재가 진행중이거나 승인되어 수정할 수 없습니다", bgtype)
+	}
+	return nil
+}
+
+// checkBGTypeFormEditedFunc 함수는 예산 프로젝트 수정 폼에서 index번째 예산안의 값을 보냈는지 확인하는 함수이다.
+// 수정할 수 없는 예산안은 ID와 이름만 보내므로 다른 값이 있으면 수정하려고 한 것이다.
+func checkBGTypeFormEditedFunc(form url.Values, index int) bool {
+	prefix := fmt.Sprintf("type%d-", index)
+	for key := range form {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if key == prefix+"bgtypeid" || key == prefix+"bgtype" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// getBGStageApproversFunc 함수는 결재 상태에서 승인해야 하는 본부별 결재자를 반환하는 함수이다.
+// 결재자가 설정되지 않은 본부는 제외한다.
+func getBGStageApproversFunc(ts BGTeamSetting, stage string) map[string][]string {
+	result := make(map[string][]string)
+	for _, head := range ts.Headquarters {
+		approver := ts.Approvers[head]
+		var users []string
+		switch stage {
+		case BGApprovalSubmitted:
+			users = approver.Production
+		case BGApprovalHeadApproved:
+			users = approver.Management
+		}
+		if len(users) != 0 {
+			result[head] = users
+		}
+	}
+	return result
+}
+
+// getBGPendingHeadsFunc 함수는 현재 결재 상태에서 아직 승인하지 않은 본부 리스트를 반환하는 함수이다.
+// 마지막 결재 요청 이후의 승인만 인정한다.
+func getBGPendingHeadsFunc(ts BGTeamSetting, typedata BGTypeData) []string {
+	stage := getBGApprovalStatusFunc(typedata)
+	approved := make(map[string]bool)
+	for _, a := range typedata.Approvals {
+		switch {
+		case a.Action == BGApprovalActionSubmit:
+			approved = make(map[string]bool)
+		case a.Action == BGApprovalActionApprove && a.Stage == stage:
+			approved[a.Headquarter] = true
+		}
+	}
+
+	var pending []string
+	for _, head := range ts.Headquarters {
+		if _, ok := getBGStageApproversFunc(ts, stage)[head]; ok && !approved[head] {
+			pending = append(pending, head)
+		}
+	}
+	return pending
+}
+
+// getBGSubmitterFunc 함수는 예산안의 마지막 결재 요청자 ID를 반환하는 함수이다.
+func getBGSubmitterFunc(typedata BGTypeData) string {
+	for i := len(typedata.Approvals) - 1; i >= 0; i-- {
+		if typedata.Approvals[i].Action == BGApprovalActionSubmit {
+			return typedata.Approvals[i].UserID
+		}
+	}
+	return ""
+}
+
+// getBGApprovalRecipientsFunc 함수는 결재 작업 후 알림을 받아야 하는 사용자 ID 리스트를 반환하는 함수이다.
+// 다음에 승인해야 하는 결재자와 결재 요청자가 알림을 받는다.
+func getBGApprovalRecipientsFunc(ts BGTeamSetting, typedata BGTypeData) []string {
+	var result []string
+	approvers := getBGStageApproversFunc(ts, getBGApprovalStatusFunc(typedata))
+	for _, head := range getBGPendingHeadsFunc(ts, typedata) {
+		for _, user := range approvers[head] {
+			if !checkStringInListFunc(user, result) {
+				result = append(result, user)
+			}
+		}
+	}
+	submitter := getBGSubmitterFunc(typedata)
+	if submitter != "" && !checkStringInListFunc(submitter, result) {
+		result = append(result, submitter)
+	}
+	return result
+}
+
+// applyBGApprovalFunc 함수는 예산안에 결재 작업을 적용하는 함수이다.
+// 결재 상태는 작성중 -> 결재 요청 -> 프로덕션 헤드 승인 -> 매니지먼트 승인 -> 확정 순서로 바뀌고, 반려하거나 요청을 취소하면 작성중으로 돌아간다.
+// manage는 결재 관리(manageapproval) 권한이 있는지 여부로, 다른 사람의 요청 취소, 확정, 다시 작성에 사용한다.
+func applyBGApprovalFunc(ts BGTeamSetting, typedata *BGTypeData, action string, userID string, manage bool, comment string) error {
+	stage := getBGApprovalStatusFunc(*typedata)
+	approval := BGApproval{
+		Action:      action,
+		Stage:       stage,
+		UserID:      userID,
+		Comment:     comment,
+		CreatedTime: time.Now().Format(time.RFC3339),
+	}
+
+	switch action {
+	case BGApprovalActionSubmit:
+		if stage != BGApprovalDraft {
+			return errors.New("작성중인 예산안만 결재를 요청할 수 있습니다")
+		}
+		if len(getBGStageApproversFunc(ts, BGApprovalSubmitted)) == 0 {
+			return errors.New("프로덕션 헤드 결재자가 설정되지 않았습니다. 예산 팀세팅에서 본부별 결재자를 설정해주세요")
+		}
+		if len(getBGStageApproversFunc(ts, BGApprovalHeadApproved)) == 0 {
+			return errors.New("매니지먼트 결재자가 설정되지 않았습니다. 예산 팀세팅에서 본부별 결재자를 설정해주세요")
+		}
+		typedata.Approvals = append(typedata.Approvals, approval)
+		typedata.ApprovalStatus = BGApprovalSubmitted
+	case BGApprovalActionApprove:
+		if stage != BGApprovalSubmitted && stage != BGApprovalHeadApproved {
+			return errors.New("결재가 요청된 예산안만 승인할 수 있습니다")
+		}
+		// 사용자가 결재자인 본부 중에서 아직 승인하지 않은 본부를 모두 승인한다.
+		approvers := getBGStageApproversFunc(ts, stage)
+		var heads []string
+		for _, head := range getBGPendingHeadsFunc(ts, *typedata) {
+			if checkStringInListFunc(userID, approvers[head]) {
+				heads = append(heads, head)
+			}
+		}
+		if len(heads) == 0 {
+			return errors.New("승인할 수 있는 결재자가 아닙니다")
+		}
+		for _, head := range heads {
+			approval.Headquarter = head
+			typedata.Approvals = append(typedata.Approvals, approval)
+		}
+		// 모든 본부가 승인하면 다음 결재 상태로 넘어간다.
+		if len(getBGPendingHeadsFunc(ts, *typedata)) == 0 {
+			if stage == BGApprovalSubmitted {
+				typedata.ApprovalStatus = BGApprovalHeadApproved
+			} else {
+				typedata.ApprovalStatus = BGApprovalApproved
+			}
+		}
+	case BGApprovalActionReject:
+		if stage != BGApprovalSubmitted && stage != BGApprovalHeadApproved {
+			return errors.New("결재가 요청된 예산안만 반려할 수 있습니다")
+		}
+		if comment == "" {
+			return errors.New("반려 사유를 입력해주세요")
+		}
+		approvers := getBGStageApproversFunc(ts, stage)
+		for _, head := range ts.Headquarters {
+			if checkStringInListFunc(userID, approvers[head]) {
+				approval.Headquarter = head
+				break
+			}
+		}
+		if approval.Headquarter == "" {
+			return errors.New("반려할 수 있는 결재자가 아닙니다")
+		}
+		typedata.Approvals = append(typedata.Approvals, approval)
+		typedata.ApprovalStatus = BGApprovalDraft
+	case BGApprovalActionWithdraw:
+		if stage != BGApprovalSubmitted && stage != BGApprovalHeadApproved {
+			return errors.New("결재가 요청된 예산안만 요청을 취소할 수 있습니다")
+		}
+		if !manage && getBGSubmitterFunc(*typedata) != userID {
+			return errors.New("결재 요청자만 요청을 취소할 수 있습니다")
+		}
+		typedata.Approvals = append(typedata.Approvals, approval)
+		typedata.ApprovalStatus = BGApprovalDraft
+	case BGApprovalActionLock:
+		if stage != BGApprovalApproved {
+			return errors.New("승인된 예산안만 확정할 수 있습니다")
+		}
+		if !manage {
+			isManagement := false
+			for _, users := range getBGStageApproversFunc(ts, BGApprovalHeadApproved) {
+				if checkStringInListFunc(userID, users) {
+					isManagement = true
+					break
+				}
+			}
+			if !isManagement {
+				return errors.New("매니지먼트 결재자 또는 결재 관리 권한이 있는 사용자만 예산안을 확정할 수 있습니다")
+			}
+		}
+		typedata.Approvals = append(typedata.Approvals, approval)
+		typedata.ApprovalStatus = BGApprovalLocked
+	case BGApprovalActionReopen:
+		if stage != BGApprovalApproved {
+			return errors.New("확정되지 않은 승인된 예산안만 다시 작성할 수 있습니다")
+		}
+		if !manage {
+			return errors.New("결재 관리 권한이 있는 사용자만 승인된 예산안을 다시 작성중으로 변경할 수 있습니다")
+		}
+		if comment == "" {
+			return errors.New("다시 작성하는 사유를 입력해주세요")
+		}
+		typedata.Approvals = append(typedata.Approvals, approval)
+		typedata.ApprovalStatus = BGApprovalDraft
+	case BGApprovalActionComment:
+		if comment == "" {
+			return errors.New("코멘트를 입력해주세요")
+		}
+		typedata.Approvals = append(typedata.Approvals, approval)
+	default:
+		return fmt.Errorf("지원하지 않는 결재 작업입니다: %s", action)
+	}
+	return nil
+}
+
+// addBGApprovalNotificationFunc 함수는 예산안 결재 작업이 있을 때 구독한 사용자들과 다음 결재자, 결재 요청자에게 알림을 추가하는 함수이다.
+func addBGApprovalNotificationFunc(client *mongo.Client, bgp BGProject, bgtype string, ts BGTeamSetting, userID string, content string) error {
+	typedata := bgp.TypeData[bgtype]
+	type Recipe struct {
+		ID      string // 예산 프로젝트 ID
+		Name    string // 예산 프로젝트 이름
+		BGType  string // 예산안 이름
+		Status  string // 결재 상태
+		UserID  string // 결재 작업을 한 사용자 ID
+		Content string // 결재 내용
+		Time    string // 결재 작업 시간
+	}
+	rcp := Recipe{
+		ID:      bgp.ID,
+		Name:    bgp.Name,
+		BGType:  bgtype,
+		Status:  getBGApprovalStatusFunc(typedata),
+		UserID:  userID,
+		Content: content,
+		Time:    time.Now().Format("2006-01-02 15:04"),
+	}
+	subject := "[BUDGET] " + bgp.Name + " " + bgtype + " 예산안 결재 알람"
+
+	// 채팅 webhook은 구독과 상관없이 admin setting에 설정된 이벤트면 보낸다.
+	err := addWebhookNotificationByTemplateFunc(client, EventBudgetApproval, subject, "mail-budgetapproval", rcp)
+	if err != nil {
+		return err
+	}
+
+	// 구독한 사용자와 함께 구독 여부와 상관없이 다음 결재자와 결재 요청자에게 알린다.
+	to, err := getSubscribersFunc(client, EventBudgetApproval, bgp.ID)
+	if err != nil {
+		return err
+	}
+	for _, user := range getBGApprovalRecipientsFunc(ts, typedata) {
+		if user != userID && !checkStringInListFunc(user, to) {
+			to = append(to, user)
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	return addNotificationByTemplateFunc(client, EventBudgetApproval, to, subject, "mail-budgetapproval", rcp)
+}
@@ -0,0 +1,99 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산안 결재 테스트 스크립트
+
+package main
+
+import (
+	"net/url"
+	"testing"
+)
+
+// 예산안 결재 상태가 결재 작업에 따라 바뀌는지 테스트하기 위한 함수
+func Test_applyBGApproval(t *testing.T) {
+	ts := BGTeamSetting{
+		Headquarters: []string{"VFX", "CM"},
+		Approvers: map[string]BGApprover{
+			"VFX": {Production: []string{"vfxprod"}, Management: []string{"mng"}},
+			"CM":  {Production: []string{"cmprod"}, Management: []string{"mng"}},
+		},
+	}
+	cases := []struct {
+		action  string
+		userID  string
+		manage  bool
+		comment string
+		want    string // 결재 작업 후 상태, 에러가 나야 하면 빈 문자열
+	}{
+		{action: BGApprovalActionApprove, userID: "vfxprod", want: ""},
+		{action: BGApprovalActionSubmit, userID: "artist", want: BGApprovalSubmitted},
+		{action: BGApprovalActionApprove, userID: "mng", want: ""},
+		{action: BGApprovalActionApprove, userID: "vfxprod", want: BGApprovalSubmitted},
+		{action: BGApprovalActionApprove, userID: "vfxprod", want: ""},
+		{action: BGApprovalActionReject, userID: "cmprod", want: ""},
+		{action: BGApprovalActionReject, userID: "cmprod", comment: "bid 재검토", want: BGApprovalDraft},
+		{action: BGApprovalActionSubmit, userID: "artist", want: BGApprovalSubmitted},
+		{action: BGApprovalActionApprove, userID: "vfxprod", want: BGApprovalSubmitted},
+		{action: BGApprovalActionApprove, userID: "cmprod", want: BGApprovalHeadApproved},
+		{action: BGApprovalActionWithdraw, userID: "other", want: ""},
+		{action: BGApprovalActionComment, userID: "other", comment: "확인 부탁드립니다", want: BGApprovalHeadApproved},
+		{action: BGApprovalActionApprove, userID: "mng", want: BGApprovalApproved},
+		{action: BGApprovalActionReopen, userID: "mng", comment: "변경", want: ""},
+		{action: BGApprovalActionLock, userID: "mng", want: BGApprovalLocked},
+		{action: BGApprovalActionReopen, userID: "admin", manage: true, comment: "변경", want: ""},
+	}
+	typedata := BGTypeData{}
+	for _, c := range cases {
+		err := applyBGApprovalFunc(ts, &typedata, c.action, c.userID, c.manage, c.comment)
+		if c.want == "" {
+			if err == nil {
+				t.Fatalf("Test_applyBGApproval(): 입력 값: %v %v, 원하는 값: 에러, 얻은 값: %v\n", c.action, c.userID, getBGApprovalStatusFunc(typedata))
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test_applyBGApproval(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", c.action, c.userID, c.want, err)
+		}
+		if getBGApprovalStatusFunc(typedata) != c.want {
+			t.Fatalf("Test_applyBGApproval(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", c.action, c.userID, c.want, getBGApprovalStatusFunc(typedata))
+		}
+	}
+	if checkBGTypeEditableFunc("A안", typedata) == nil {
+		t.Fatalf("Test_applyBGApproval(): 확정된 예산안을 수정할 수 있습니다\n")
+	}
+}
+
+// 결재자가 설정되지 않았을 때 결재를 요청할 수 없는지 테스트하기 위한 함수
+func Test_applyBGApprovalWithoutApprovers(t *testing.T) {
+	ts := BGTeamSetting{
+		Headquarters: []string{"VFX"},
+		Approvers: map[string]BGApprover{
+			"VFX": {Production: []string{"vfxprod"}},
+		},
+	}
+	typedata := BGTypeData{}
+	err := applyBGApprovalFunc(ts, &typedata, BGApprovalActionSubmit, "artist", false, "")
+	if err == nil {
+		t.Fatalf("Test_applyBGApprovalWithoutApprovers(): 입력 값: %v, 원하는 값: 에러, 얻은 값: %v\n", ts.Approvers, typedata.ApprovalStatus)
+	}
+}
+
+// 수정할 수 없는 예산안의 값을 폼으로 보냈는지 확인하는지 테스트하기 위한 함수
+func Test_checkBGTypeFormEdited(t *testing.T) {
+	cases := []struct {
+		form  url.Values
+		index int
+		want  bool
+	}{
+		{form: url.Values{"type1-bgtypeid": {"abc"}, "type1-bgtype": {"A안"}, "type0-bgproposal": {"100"}}, index: 1, want: false},
+		{form: url.Values{"type1-bgtypeid": {"abc"}, "type1-bgtype": {"A안"}, "type1-bgproposal": {"100"}}, index: 1, want: true},
+		{form: url.Values{"type1-bgtypeid": {"abc"}, "type1-bgmaintypestatus": {"true"}}, index: 1, want: true},
+		{form: url.Values{"type1-bgtypeid": {"abc"}, "type10-bgproposal": {"100"}}, index: 1, want: false},
+	}
+	for _, c := range cases {
+		got := checkBGTypeFormEditedFunc(c.form, c.index)
+		if got != c.want {
+			t.Fatalf("Test_checkBGTypeFormEdited(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.form, c.index, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 프로젝트 인력 수급 계획 관련 스크립트
+
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// getWeekdaysFunc 함수는 입력받은 연월(2006-01)의 평일 수를 반환하는 함수이다.
+func getWeekdaysFunc(date string) (int, error) {
+	firstDate, err := time.Parse("2006-01", date)
+	if err != nil {
+		return 0, err
+	}
+	weekdays := 0
+	for d := firstDate; d.Month() == firstDate.Month(); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			weekdays++
+		}
+	}
+	return weekdays, nil
+}
+
+// getCapacityTeamFunc 함수는 예산 팀세팅에서 사용하는 아티스트의 팀 이름을 반환하는 함수이다.
+// CM 아티스트는 팀 이름 앞에 CM_ 을 붙인다.
+func getCapacityTeamFunc(artist Artist) string {
+	if artist.Team == "" {
+		return ""
+	}
+	if strings.HasPrefix(strings.ToLower(artist.ID), "cm") {
+		return "CM_" + artist.Team
+	}
+	return artist.Team
+}
+
+// calArtistHeadcountFunc 함수는 입력받은 연월(2006-01)에 아티스트가 근무한 날의 비율을 계산하는 함수이다.
+// 한 달을 모두 근무하면 1, 월 중간에 입사하거나 퇴사하면 근무한 날만큼의 비율을 반환한다.
+func calArtistHeadcountFunc(artist Artist, date string) (float64, error) {
+	if artist.StartDay == "" { // 입사일이 없는 경우
+		return 0, nil
+	}
+	firstDate, err := time.Parse("2006-01", date)
+	if err != nil {
+		return 0, err
+	}
+	lastDate := firstDate.AddDate(0, 1, -1)
+
+	start, err := time.Parse("2006-01-02", artist.StartDay)
+	if err != nil {
+		return 0, err
+	}
+	if start.Before(firstDate) {
+		start = firstDate
+	}
+	end := lastDate
+	if artist.EndDay != "" { // 퇴사일 또는 퇴사 예정일이 있는 경우
+		endDay, err := time.Parse("2006-01-02", artist.EndDay)
+		if err != nil {
+			return 0, err
+		}
+		if endDay.Before(end) {
+			end = endDay
+		}
+	}
+	if end.Before(start) {
+		return 0, nil
+	}
+	days := end.Sub(start).Hours()/24 + 1
+	return days / float64(lastDate.Day()), nil
+}
+
+// spreadMandaysFunc 함수는 manday를 기간 동안 균등하게 나누는 함수이다.
+func spreadMandaysFunc(mandays float64, dates []string) map[string]float64 {
+	result := make(map[string]float64)
+	if len(dates) == 0 {
+		return result
+	}
+	for _, date := range dates {
+		result[date] += mandays / float64(len(dates))
+	}
+	return result
+}
+
+// addBGCapacityDemandFunc 함수는 예산 프로젝트의 메인 예산안에서 팀별 월별 필요 manday를 계산하여 더하는 함수이다.
+// 샷, 어셋 bid는 태스크에 해당하는 팀들에게 똑같이 나누어 예산 프로젝트 기간 동안 균등하게 배정한다.
+// 슈퍼바이저, 프로덕션, 매니지먼트는 시작월부터 기간만큼 근무일수 * 비율을 배정한다.
+// userTeams는 아티스트 ID별 팀 이름이고, 팀을 찾지 못한 항목은 빈 문자열 팀으로 모은다.
+func addBGCapacityDemandFunc(demand map[string]map[string]float64, projects map[string][]string, bgp BGProject, ts BGTeamSetting, userTeams map[string]string) error {
+	typedata, ok := bgp.TypeData[bgp.MainType]
+	if !ok || bgp.StartDate == "" || bgp.EndDate == "" {
+		return nil
+	}
+	dates, err := getDatesFunc(bgp.StartDate, bgp.EndDate)
+	if err != nil {
+		return err
+	}
+
+	add := func(team string, date string, mandays float64) {
+		if mandays == 0 {
+			return
+		}
+		if demand[team] == nil {
+			demand[team] = make(map[string]float64)
+		}
+		demand[team][date] += mandays
+		if !checkStringInListFunc(bgp.ID, projects[team]) {
+			projects[team] = append(projects[team], bgp.ID)
+		}
+	}
+
+	totalBid := make(map[string]float64)
+	for _, list := range [][]BGShotAsset{typedata.ShotList, typedata.AssetList} {
+		for task, bid := range calTotalBidFunc(list) {
+			totalBid[task] += bid
+		}
+	}
+	for task, bid := range totalBid {
+		teams := ts.Teams[task]
+		if len(teams) == 0 {
+			teams = []string{""}
+		}
+		for _, team := range teams {
+			for date, mandays := range spreadMandaysFunc(bid/float64(len(teams)), dates) {
+				add(team, date, mandays)
+			}
+		}
+	}
+
+	var managements []BGManagement
+	managements = append(managements, typedata.Supervisors...)
+	managements = append(managements, typedata.Production...)
+	managements = append(managements, typedata.Management...)
+	for _, m := range managements {
+		period := dates
+		if m.Period > 0 && m.Period < len(dates) {
+			period = dates[:m.Period]
+		}
+		for _, date := range period {
+			weekdays, err := getWeekdaysFunc(date)
+			if err != nil {
+				return err
+			}
+			add(userTeams[m.UserID], date, float64(weekdays)*m.Ratio/100)
+		}
+	}
+	return nil
+}
+
+// newBGCapacitiesFunc 함수는 팀별 필요 manday와 아티스트 정보로 월별 배정률을 계산하는 함수이다.
+// 팀세팅의 팀 순서대로 정리하고, 팀세팅에 없는 팀은 이름순으로 뒤에, 팀을 찾지 못한 항목은 마지막에 붙인다.
+func newBGCapacitiesFunc(dates []string, teams []string, demand map[string]map[string]float64, projects map[string][]string, artists []Artist) ([]BGCapacity, error) {
+	headcount := make(map[string]map[string]float64)
+	for _, artist := range artists {
+		team := getCapacityTeamFunc(artist)
+		if team == "" {
+			continue
+		}
+		for _, date := range dates {
+			h, err := calArtistHeadcountFunc(artist, date)
+			if err != nil {
+				return nil, err
+			}
+			if h == 0 {
+				continue
+			}
+			if headcount[team] == nil {
+				headcount[team] = make(map[string]float64)
+			}
+			headcount[team][date] += h
+		}
+	}
+
+	names := append([]string{}, teams...)
+	var extra []string
+	for team := range demand {
+		if team != "" && !checkStringInListFunc(team, names) && !checkStringInListFunc(team, extra) {
+			extra = append(extra, team)
+		}
+	}
+	sort.Strings(extra)
+	names = append(names, extra...)
+	if _, ok := demand[""]; ok {
+		names = append(names, "")
+	}
+
+	var results []BGCapacity
+	for _, team := range names {
+		c := BGCapacity{
+			Team:      team,
+			Projects:  projects[team],
+			Demand:    make(map[string]float64),
+			Headcount: make(map[string]float64),
+			Capacity:  make(map[string]float64),
+			Balance:   make(map[string]float64),
+			Ratio:     make(map[string]float64),
+		}
+		sort.Strings(c.Projects)
+		for _, date := range dates {
+			weekdays, err := getWeekdaysFunc(date)
+			if err != nil {
+				return nil, err
+			}
+			c.Demand[date] = math.Round(demand[team][date]*10) / 10
+			c.Headcount[date] = math.Round(headcount[team][date]*10) / 10
+			c.Capacity[date] = math.Round(headcount[team][date]*float64(weekdays)*10) / 10
+			c.Balance[date] = math.Round((c.Capacity[date]-c.Demand[date])*10) / 10
+			if c.Capacity[date] != 0 {
+				c.Ratio[date] = math.Round(c.Demand[date]/c.Capacity[date]*1000) / 10
+			}
+		}
+		results = append(results, c)
+	}
+	return results, nil
+}
+
+// getBGTeamsFunc 함수는 예산 팀세팅의 본부, 부서, 태스크 순서대로 팀 리스트를 반환하는 함수이다.
+// 슈퍼바이저, 프로덕션, 매니지먼트 팀은 각 본부의 마지막에 붙인다.
+func getBGTeamsFunc(ts BGTeamSetting) []string {
+	var teams []string
+	for _, head := range ts.Headquarters {
+		for _, dept := range ts.Departments[head] {
+			for _, part := range dept.Parts {
+				for _, task := range part.Tasks {
+					for _, team := range ts.Teams[task] {
+						if !checkStringInListFunc(team, teams) {
+							teams = append(teams, team)
+						}
+					}
+				}
+			}
+		}
+		for _, control := range ts.Controls[head] {
+			for _, part := range control.Parts {
+				for _, team := range part.Teams {
+					if !checkStringInListFunc(team, teams) {
+						teams = append(teams, team)
+					}
+				}
+			}
+		}
+	}
+	return teams
+}
@@ -0,0 +1,95 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 프로젝트 인력 수급 계획 테스트 스크립트
+
+package main
+
+import "testing"
+
+// 입사일과 퇴사일을 반영하여 월별 인원을 계산하는지 테스트하기 위한 함수
+func Test_calArtistHeadcount(t *testing.T) {
+	cases := []struct {
+		artist Artist
+		date   string
+		want   float64
+	}{
+		{artist: Artist{StartDay: "2020-01-01"}, date: "2021-04", want: 1},
+		{artist: Artist{StartDay: "2021-04-16"}, date: "2021-04", want: 0.5},
+		{artist: Artist{StartDay: "2021-05-01"}, date: "2021-04", want: 0},
+		{artist: Artist{StartDay: "2020-01-01", EndDay: "2021-04-15", Resination: true}, date: "2021-04", want: 0.5},
+		{artist: Artist{StartDay: "2020-01-01", EndDay: "2021-03-31", Resination: true}, date: "2021-04", want: 0},
+		{artist: Artist{}, date: "2021-04", want: 0},
+	}
+	for _, c := range cases {
+		got, err := calArtistHeadcountFunc(c.artist, c.date)
+		if err != nil {
+			t.Fatalf("Test_calArtistHeadcount(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.artist, c.want, err)
+		}
+		if got != c.want {
+			t.Fatalf("Test_calArtistHeadcount(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.artist, c.want, got)
+		}
+	}
+}
+
+// bid와 매니지먼트 비율을 팀별 월별로 배정하고 가용 인력과 비교하는지 테스트하기 위한 함수
+func Test_newBGCapacities(t *testing.T) {
+	ts := BGTeamSetting{
+		Teams: map[string][]string{
+			"comp": {"Comp"},
+			"mm":   {"MatchMove", "Layout"},
+		},
+	}
+	bgp := BGProject{
+		ID:        "TEST",
+		StartDate: "2021-03",
+		EndDate:   "2021-04",
+		MainType:  "A안",
+		TypeData: map[string]BGTypeData{
+			"A안": {
+				ShotList: []BGShotAsset{
+					{Name: "s0010_c0010", Manday: map[string]float64{"comp": 30, "mm": 8}},
+					{Name: "s0010_c0020", Manday: map[string]float64{"comp": 14, "fx": 6}},
+				},
+				Supervisors: []BGManagement{{UserID: "sup", Period: 1, Ratio: 50}},
+			},
+		},
+	}
+	demand := make(map[string]map[string]float64)
+	projects := make(map[string][]string)
+	err := addBGCapacityDemandFunc(demand, projects, bgp, ts, map[string]string{"sup": "Comp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artists := []Artist{
+		{ID: "1", Team: "Comp", StartDay: "2020-01-01"},
+		{ID: "cm1", Team: "Comp", StartDay: "2020-01-01"}, // CM_Comp 팀
+	}
+	dates := []string{"2021-03", "2021-04"}
+	got, err := newBGCapacitiesFunc(dates, []string{"MatchMove", "Comp"}, demand, projects, artists)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 팀세팅 순서, 팀세팅에 없는 팀, 미지정 순서로 정리된다.
+	var teams []string
+	for _, c := range got {
+		teams = append(teams, c.Team)
+	}
+	want := []string{"MatchMove", "Comp", "Layout", ""}
+	if listToStringFunc(teams, true) != listToStringFunc(want, true) {
+		t.Fatalf("Test_newBGCapacities(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", demand, want, teams)
+	}
+
+	// 2021-03: comp bid 44 / 2 + 슈퍼바이저 평일 23일 * 50% = 33.5, 가용 1명 * 23일
+	comp := got[1]
+	if comp.Demand["2021-03"] != 33.5 || comp.Capacity["2021-03"] != 23 || comp.Balance["2021-03"] != -10.5 || comp.Ratio["2021-03"] != 145.7 {
+		t.Fatalf("Test_newBGCapacities(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "Comp 2021-03", "33.5 23 -10.5 145.7", comp)
+	}
+	if comp.Demand["2021-04"] != 22 || comp.Balance["2021-04"] != 0 {
+		t.Fatalf("Test_newBGCapacities(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "Comp 2021-04", "22 0", comp)
+	}
+	if got[0].Demand["2021-03"] != 2 || got[0].Capacity["2021-03"] != 0 || got[3].Demand["2021-04"] != 3 {
+		t.Fatalf("Test_newBGCapacities(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", demand, "MatchMove 2, 미지정 3", got)
+	}
+}
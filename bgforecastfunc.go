@@ -0,0 +1,114 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 최종 비용 예측(EAC) 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// calBGForecastFunc 함수는 예산과 현재까지의 실제 비용으로 남은 비용과 최종 비용을 예측하는 함수이다.
+// 완료된 컷 비율만큼 bid를 소진했다고 보고 bid 1 manday당 실제 인건비로 남은 bid의 인건비를 계산한다.
+// 완료된 컷이 없으면 예산안 인건비의 manday당 단가를 사용한다.
+func calBGForecastFunc(f *BGForecast) {
+	f.CompleteRatio = 0
+	if f.WorkingCuts > 0 {
+		f.CompleteRatio = math.Min(float64(f.CompletedCuts)/float64(f.WorkingCuts), 1)
+	}
+	earnedMandays := f.TotalMandays * f.CompleteRatio
+	f.RemainingMandays = f.TotalMandays - earnedMandays
+
+	f.BurnRate = 0
+	if earnedMandays > 0 {
+		f.BurnRate = int(math.Round(float64(f.ActualLabor) / earnedMandays))
+	} else if f.TotalMandays > 0 {
+		f.BurnRate = int(math.Round(float64(f.BudgetLabor) / f.TotalMandays))
+	}
+	f.RemainingLabor = int(math.Round(f.RemainingMandays * float64(f.BurnRate)))
+
+	f.RemainingExpense = 0
+	if f.BudgetExpense > f.ActualExpense {
+		f.RemainingExpense = f.BudgetExpense - f.ActualExpense
+	}
+	f.RemainingVendor = 0
+	if f.VendorContract > f.VendorPaid {
+		f.RemainingVendor = f.VendorContract - f.VendorPaid
+	}
+
+	f.EAC = f.ActualLabor + f.RemainingLabor + f.ActualExpense + f.RemainingExpense + f.VendorPaid + f.RemainingVendor
+	f.Margin = f.Decision - f.EAC
+	f.MarginRatio = ""
+	if f.Decision != 0 {
+		f.MarginRatio = strconv.FormatFloat(math.Round(float64(f.Margin)/float64(f.Decision)*1000)/10, 'f', 1, 64)
+	}
+	f.OverBudget = f.EAC > f.Decision
+}
+
+// getBGForecastFunc 함수는 프로젝트에 연결된 예산 프로젝트의 메인 예산안으로 최종 비용을 예측하는 함수이다.
+// 실제 인건비, 진행비 + 구매비, 지출된 외주비는 결산 페이지에서 계산한 합계를 받아서 사용한다.
+func getBGForecastFunc(client *mongo.Client, project Project, vendors []Vendor, actualLabor int, actualExpense int, vendorPaid int) (BGForecast, error) {
+	f := BGForecast{
+		WorkingCuts:   project.WorkingCuts,
+		CompletedCuts: project.CompletedCuts,
+		ActualLabor:   actualLabor,
+		ActualExpense: actualExpense,
+		VendorPaid:    vendorPaid,
+	}
+	bgp, err := getBGProjectFunc(client, project.BGProjectID)
+	if err != nil {
+		return f, err
+	}
+	typedata, ok := bgp.TypeData[bgp.MainType]
+	if !ok {
+		return f, fmt.Errorf("%s 예산 프로젝트에 메인 예산안이 없습니다", bgp.ID)
+	}
+
+	// 계약 결정액이 없으면 제안 견적을 사용한다.
+	f.Decision, err = decryptToIntFunc(typedata.Decision)
+	if err != nil {
+		return f, err
+	}
+	if f.Decision == 0 {
+		f.Decision, err = decryptToIntFunc(typedata.Proposal)
+		if err != nil {
+			return f, err
+		}
+	}
+	f.BudgetExpense = int(math.Round(float64(f.Decision) * typedata.ProgressRatio / 100))
+
+	for _, lc := range typedata.LaborCosts {
+		for _, cost := range lc.DepartmentCost {
+			costInt, err := decryptToIntFunc(cost)
+			if err != nil {
+				return f, err
+			}
+			f.BudgetLabor += costInt
+		}
+		management, err := decryptToIntFunc(lc.Management)
+		if err != nil {
+			return f, err
+		}
+		f.BudgetLabor += management
+	}
+	for _, list := range [][]BGShotAsset{typedata.ShotList, typedata.AssetList} {
+		for _, bid := range calTotalBidFunc(list) {
+			f.TotalMandays += bid
+		}
+	}
+
+	for _, v := range vendors {
+		expenses, err := decryptToIntFunc(v.Expenses)
+		if err != nil {
+			return f, err
+		}
+		f.VendorContract += expenses
+	}
+
+	calBGForecastFunc(&f)
+	return f, nil
+}
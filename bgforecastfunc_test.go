@@ -0,0 +1,55 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 최종 비용 예측(EAC) 테스트 스크립트
+
+package main
+
+import "testing"
+
+// 실제 비용 소진율로 최종 비용과 예상 수익을 계산하는지 테스트하기 위한 함수
+func Test_calBGForecast(t *testing.T) {
+	cases := []struct {
+		in         BGForecast
+		eac        int
+		margin     int
+		ratio      string
+		overBudget bool
+	}{{
+		// 절반 완료, 인건비를 예산보다 빠르게 소진
+		in: BGForecast{
+			Decision: 1000, BudgetLabor: 500, BudgetExpense: 100, TotalMandays: 100,
+			WorkingCuts: 10, CompletedCuts: 5, VendorContract: 200,
+			ActualLabor: 400, ActualExpense: 30, VendorPaid: 50,
+		},
+		eac:        400 + 400 + 30 + 70 + 50 + 150,
+		margin:     1000 - 1100,
+		ratio:      "-10.0",
+		overBudget: true,
+	}, {
+		// 완료된 컷이 없으면 예산안 단가 사용, 진행비는 예산을 넘은 만큼 남은 비용이 없다.
+		in: BGForecast{
+			Decision: 1000, BudgetLabor: 500, BudgetExpense: 100, TotalMandays: 100,
+			WorkingCuts: 10, ActualLabor: 50, ActualExpense: 120,
+		},
+		eac:        50 + 500 + 120,
+		margin:     1000 - 670,
+		ratio:      "33.0",
+		overBudget: false,
+	}, {
+		// 계약 결정액과 작업 컷수가 없는 경우
+		in: BGForecast{
+			ActualLabor: 100, VendorContract: 100, VendorPaid: 150,
+		},
+		eac:        100 + 150,
+		margin:     -250,
+		ratio:      "",
+		overBudget: true,
+	}}
+	for _, c := range cases {
+		got := c.in
+		calBGForecastFunc(&got)
+		if got.EAC != c.eac || got.Margin != c.margin || got.MarginRatio != c.ratio || got.OverBudget != c.overBudget {
+			t.Fatalf("Test_calBGForecast(): 입력 값: %v, 원하는 값: %v %v %v %v, 얻은 값: %v %v %v %v\n", c.in, c.eac, c.margin, c.ratio, c.overBudget, got.EAC, got.Margin, got.MarginRatio, got.OverBudget)
+		}
+	}
+}
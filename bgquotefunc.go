@@ -0,0 +1,564 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산안 견적서 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+)
+
+// BGQuoteDefaultTerms 는 견적서에 기본으로 들어가는 견적 조건이다.
+var BGQuoteDefaultTerms = []string{
+	"견적 금액은 부가가치세 별도입니다.",
+	"견적 유효기간이 지나면 금액이 변경될 수 있습니다.",
+	"컷수 및 작업 범위가 변경되면 견적 금액을 다시 협의합니다.",
+	"대금은 계약 시 협의한 일정에 따라 지급합니다.",
+}
+
+// BGQuoteAssetItem 은 견적서에서 어셋 작업을 묶어서 보여주는 항목 이름이다.
+const BGQuoteAssetItem = "어셋"
+
+// getShotSequenceFunc 함수는 샷 이름에서 시퀀스를 반환하는 함수이다. ex) s0010_c0010 -> s0010
+func getShotSequenceFunc(name string) string {
+	return strings.Split(name, "_")[0]
+}
+
+// allocateBGQuoteFunc 함수는 total을 weights 비율로 나누는 함수이다.
+// 반올림으로 생기는 차이는 나머지가 큰 항목부터 더해 합계가 total과 같도록 한다. 비율이 모두 0이면 똑같이 나눈다.
+func allocateBGQuoteFunc(total int, weights []float64) []int {
+	results := make([]int, len(weights))
+	if len(weights) == 0 {
+		return results
+	}
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	shares := make([]float64, len(weights))
+	for i, w := range weights {
+		if sum == 0 {
+			shares[i] = float64(total) / float64(len(weights))
+		} else {
+			shares[i] = float64(total) * w / sum
+		}
+	}
+	remain := total
+	for i, s := range shares {
+		results[i] = int(math.Floor(s))
+		remain -= results[i]
+	}
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return shares[order[a]]-math.Floor(shares[order[a]]) > shares[order[b]]-math.Floor(shares[order[b]])
+	})
+	for i := 0; remain > 0; i = (i + 1) % len(order) {
+		results[order[i]]++
+		remain--
+	}
+	return results
+}
+
+// newBGQuoteItemsFunc 함수는 샷, 어셋 리스트로 에피소드(드라마) 또는 시퀀스(영화)별 견적 항목을 만드는 함수이다.
+// 견적 금액은 샷과 어셋의 bid 비율로 나누고, 드라마는 에피소드별 비용이 있으면 샷 금액을 에피소드별 비용 비율로 나눈다.
+// 내부 인건비는 항상 bid 비율로 나눈다.
+func newBGQuoteItemsFunc(shots []BGShotAsset, assets []BGShotAsset, episodes []Episode, drama bool, episodeCost map[string]int, total int, laborCost int) []BGQuoteItem {
+	sumBid := func(item BGShotAsset) float64 {
+		bid := 0.0
+		for _, b := range item.Manday {
+			bid += b
+		}
+		return bid
+	}
+
+	// 샷을 에피소드 또는 시퀀스로 묶는다.
+	var names []string
+	groups := make(map[string]*BGQuoteItem)
+	if drama {
+		for _, ep := range episodes {
+			names = append(names, ep.Name)
+			groups[ep.Name] = &BGQuoteItem{Name: ep.Name}
+		}
+	}
+	var extra []string
+	for _, shot := range shots {
+		name := getShotSequenceFunc(shot.Name)
+		if drama {
+			name = getShotEpisodeFunc(shot)
+		}
+		if name == "" {
+			name = "미지정"
+		}
+		if _, ok := groups[name]; !ok {
+			extra = append(extra, name)
+			groups[name] = &BGQuoteItem{Name: name}
+		}
+		groups[name].Cuts++
+		groups[name].Mandays += sumBid(shot)
+	}
+	sort.Strings(extra)
+	names = append(names, extra...)
+
+	var items []BGQuoteItem
+	for _, name := range names {
+		items = append(items, *groups[name])
+	}
+	shotNum := len(items)
+	if len(assets) != 0 {
+		asset := BGQuoteItem{Name: BGQuoteAssetItem}
+		for _, a := range assets {
+			asset.Mandays += sumBid(a)
+		}
+		items = append(items, asset)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	// 내부 인건비는 bid 비율로 나눈다.
+	var bids []float64
+	for _, item := range items {
+		bids = append(bids, item.Mandays)
+	}
+	for i, cost := range allocateBGQuoteFunc(laborCost, bids) {
+		items[i].Cost = cost
+	}
+
+	// 견적 금액은 샷과 어셋으로 먼저 나누고, 샷 금액을 에피소드별 비용 또는 bid 비율로 나눈다.
+	shotBid := 0.0
+	for _, bid := range bids[:shotNum] {
+		shotBid += bid
+	}
+	shotTotal := total
+	if shotNum < len(items) {
+		parts := allocateBGQuoteFunc(total, []float64{shotBid, bids[shotNum]})
+		shotTotal = parts[0]
+		items[shotNum].Price = parts[1]
+	}
+	weights := bids[:shotNum]
+	if drama {
+		var costs []float64
+		sum := 0
+		for _, item := range items[:shotNum] {
+			costs = append(costs, float64(episodeCost[item.Name]))
+			sum += episodeCost[item.Name]
+		}
+		if sum != 0 {
+			weights = costs
+		}
+	}
+	if shotBid == 0 && !drama {
+		weights = nil
+		for _, item := range items[:shotNum] {
+			weights = append(weights, float64(item.Cuts))
+		}
+	}
+	for i, price := range allocateBGQuoteFunc(shotTotal, weights) {
+		items[i].Price = price
+	}
+	return items
+}
+
+// calBGQuoteMarginFunc 함수는 견적서의 내부 비용과 수익을 계산하는 함수이다.
+func calBGQuoteMarginFunc(q *BGQuote) {
+	q.InternalCost = q.LaborCost + q.Expense
+	q.Margin = q.Total - q.InternalCost
+	q.MarginRatio = ""
+	if q.Total != 0 {
+		q.MarginRatio = fmt.Sprintf("%.1f", float64(q.Margin)/float64(q.Total)*100)
+	}
+}
+
+// newBGQuoteFunc 함수는 예산 프로젝트의 예산안으로 견적서를 만드는 함수이다.
+func newBGQuoteFunc(bgp BGProject, bgtype string) (BGQuote, error) {
+	typedata, ok := bgp.TypeData[bgtype]
+	if !ok {
+		return BGQuote{}, fmt.Errorf("%s 예산안이 존재하지 않습니다", bgtype)
+	}
+	q := BGQuote{
+		ProjectID:    bgp.ID,
+		ProjectName:  bgp.Name,
+		BGType:       bgtype,
+		Type:         bgp.Type,
+		ProducerName: bgp.ProducerName,
+		DirectorName: bgp.DirectorName,
+		StartDate:    bgp.StartDate,
+		EndDate:      bgp.EndDate,
+		ContractCuts: typedata.ContractCuts,
+		Shots:        len(typedata.ShotList),
+		Assets:       len(typedata.AssetList),
+		Terms:        BGQuoteDefaultTerms,
+		HideMargin:   true,
+	}
+
+	// 계약 결정액이 없으면 제안 견적을 사용한다.
+	var err error
+	q.Total, err = decryptToIntFunc(typedata.Decision)
+	if err != nil {
+		return q, err
+	}
+	if q.Total == 0 {
+		q.Total, err = decryptToIntFunc(typedata.Proposal)
+		if err != nil {
+			return q, err
+		}
+	}
+	q.Expense = int(math.Round(float64(q.Total) * typedata.ProgressRatio / 100))
+	for _, lc := range typedata.LaborCosts {
+		for _, cost := range lc.DepartmentCost {
+			c, err := decryptToIntFunc(cost)
+			if err != nil {
+				return q, err
+			}
+			q.LaborCost += c
+		}
+		c, err := decryptToIntFunc(lc.Management)
+		if err != nil {
+			return q, err
+		}
+		q.LaborCost += c
+	}
+	calBGQuoteMarginFunc(&q)
+
+	episodeCost := make(map[string]int)
+	for ep, cost := range typedata.EpisodeCost {
+		episodeCost[ep], err = decryptToIntFunc(cost)
+		if err != nil {
+			return q, err
+		}
+	}
+	drama := bgp.Type == "drama"
+	q.GroupTitle = "시퀀스"
+	if drama {
+		q.GroupTitle = "에피소드"
+	}
+	q.Items = newBGQuoteItemsFunc(typedata.ShotList, typedata.AssetList, bgp.Episodes, drama, episodeCost, q.Total, q.LaborCost)
+	for _, item := range q.Items {
+		q.Mandays += item.Mandays
+	}
+	return q, nil
+}
+
+// getBGQuoteFileNameFunc 함수는 견적서 파일 이름을 반환하는 함수이다.
+func getBGQuoteFileNameFunc(q BGQuote, ext string) string {
+	return fmt.Sprintf("quote_%s_%s_%s.%s", q.ProjectID, q.BGType, q.QuoteDate, ext)
+}
+
+// getBGQuoteTypeNameFunc 함수는 견적서에 표시할 프로젝트 타입 이름을 반환하는 함수이다.
+func getBGQuoteTypeNameFunc(typ string) string {
+	if typ == "drama" {
+		return "드라마"
+	}
+	return "영화"
+}
+
+// getBGQuoteCoverFunc 함수는 견적서 표지에 들어가는 항목과 값을 순서대로 반환하는 함수이다.
+func getBGQuoteCoverFunc(q BGQuote) [][2]string {
+	return [][2]string{
+		{"프로젝트", fmt.Sprintf("%s (%s)", q.ProjectName, q.ProjectID)},
+		{"구분", getBGQuoteTypeNameFunc(q.Type)},
+		{"제작사", q.ProducerName},
+		{"감독", q.DirectorName},
+		{"작업 기간", fmt.Sprintf("%s ~ %s", stringToDateFunc(q.StartDate), stringToDateFunc(q.EndDate))},
+		{"견적일", stringToDateFunc(q.QuoteDate)},
+		{"유효기간", stringToDateFunc(q.ValidDate)},
+		{"견적 금액", putCommaFunc(q.Total) + " 원"},
+	}
+}
+
+// getBGQuoteScopeFunc 함수는 견적서 작업 범위에 들어가는 항목과 값을 순서대로 반환하는 함수이다.
+func getBGQuoteScopeFunc(q BGQuote) [][2]string {
+	return [][2]string{
+		{"계약 컷수", putCommaFunc(q.ContractCuts)},
+		{"샷", putCommaFunc(q.Shots)},
+		{"어셋", putCommaFunc(q.Assets)},
+	}
+}
+
+// getBGQuoteInternalFunc 함수는 견적서 내부 비용에 들어가는 항목과 값을 순서대로 반환하는 함수이다.
+func getBGQuoteInternalFunc(q BGQuote) [][2]string {
+	ratio := "-"
+	if q.MarginRatio != "" {
+		ratio = q.MarginRatio + " %"
+	}
+	return [][2]string{
+		{"총 bid", fmt.Sprintf("%.1f", q.Mandays)},
+		{"인건비", putCommaFunc(q.LaborCost) + " 원"},
+		{"진행비", putCommaFunc(q.Expense) + " 원"},
+		{"내부 비용", putCommaFunc(q.InternalCost) + " 원"},
+		{"수익", putCommaFunc(q.Margin) + " 원"},
+		{"수익률", ratio},
+	}
+}
+
+// genBGQuoteExcelFunc 함수는 견적서를 엑셀 파일로 만드는 함수이다.
+// 표지, 견적 내역, 견적 조건 시트를 만들고, 내부 비용을 숨기지 않으면 내부 비용 시트를 추가한다.
+func genBGQuoteExcelFunc(q BGQuote, path string) error {
+	f := excelize.NewFile()
+	titleStyle, err := f.NewStyle(`{"font":{"bold":true,"size":20},"alignment":{"horizontal":"center","vertical":"center"}}`)
+	if err != nil {
+		return err
+	}
+	headerStyle, err := f.NewStyle(
+		`
+		{"font":{"bold":true},
+		"alignment":{"horizontal":"center","vertical":"center","wrap_text":true},
+		"fill":{"type":"pattern","color":["#D9D9D9"],"pattern":1},
+		"border":[{"type":"left","color":"000000","style":1},{"type":"top","color":"000000","style":1},{"type":"bottom","color":"000000","style":1},{"type":"right","color":"000000","style":1}]}
+		`)
+	if err != nil {
+		return err
+	}
+	textStyle, err := f.NewStyle(
+		`
+		{"alignment":{"horizontal":"left","vertical":"center","wrap_text":true},
+		"border":[{"type":"left","color":"000000","style":1},{"type":"top","color":"000000","style":1},{"type":"bottom","color":"000000","style":1},{"type":"right","color":"000000","style":1}]}
+		`)
+	if err != nil {
+		return err
+	}
+	numberStyle, err := f.NewStyle(
+		`
+		{"alignment":{"horizontal":"right","vertical":"center"},
+		"border":[{"type":"left","color":"000000","style":1},{"type":"top","color":"000000","style":1},{"type":"bottom","color":"000000","style":1},{"type":"right","color":"000000","style":1}],
+		"number_format": 3}
+		`)
+	if err != nil {
+		return err
+	}
+	totalStyle, err := f.NewStyle(
+		`
+		{"font":{"bold":true},
+		"alignment":{"horizontal":"right","vertical":"center"},
+		"border":[{"type":"left","color":"000000","style":1},{"type":"top","color":"000000","style":2},{"type":"bottom","color":"000000","style":1},{"type":"right","color":"000000","style":1}],
+		"number_format": 3}
+		`)
+	if err != nil {
+		return err
+	}
+
+	// 항목과 값이 두 열로 이어지는 표를 만든다.
+	writePairs := func(sheet string, row int, pairs [][2]string) int {
+		for _, p := range pairs {
+			f.SetCellValue(sheet, fmt.Sprintf("B%d", row), p[0])
+			f.SetCellValue(sheet, fmt.Sprintf("C%d", row), p[1])
+			f.SetCellStyle(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("B%d", row), headerStyle)
+			f.SetCellStyle(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("C%d", row), textStyle)
+			f.SetRowHeight(sheet, row, 22)
+			row++
+		}
+		return row
+	}
+
+	// 표지
+	sheet := "표지"
+	f.SetSheetName("Sheet1", sheet)
+	f.SetColWidth(sheet, "A", "A", 3)
+	f.SetColWidth(sheet, "B", "B", 18)
+	f.SetColWidth(sheet, "C", "C", 50)
+	f.SetCellValue(sheet, "B2", "견 적 서")
+	f.MergeCell(sheet, "B2", "C2")
+	f.SetCellStyle(sheet, "B2", "C2", titleStyle)
+	f.SetRowHeight(sheet, 2, 40)
+	row := writePairs(sheet, 4, getBGQuoteCoverFunc(q))
+	row++
+	f.SetCellValue(sheet, fmt.Sprintf("B%d", row), "작업 범위")
+	row = writePairs(sheet, row+1, getBGQuoteScopeFunc(q))
+
+	// 견적 내역
+	sheet = "견적 내역"
+	f.NewSheet(sheet)
+	f.SetColWidth(sheet, "A", "A", 3)
+	f.SetColWidth(sheet, "B", "B", 18)
+	f.SetColWidth(sheet, "C", "D", 18)
+	f.SetColWidth(sheet, "E", "F", 18)
+	headers := []string{q.GroupTitle, "샷", "견적 금액"}
+	if !q.HideMargin {
+		headers = append(headers, "bid", "인건비")
+	}
+	for i, h := range headers {
+		pos, err := excelize.CoordinatesToCellName(i+2, 2)
+		if err != nil {
+			return err
+		}
+		f.SetCellValue(sheet, pos, h)
+		f.SetCellStyle(sheet, pos, pos, headerStyle)
+	}
+	lastCol, err := excelize.ColumnNumberToName(len(headers) + 1)
+	if err != nil {
+		return err
+	}
+	row = 3
+	for _, item := range q.Items {
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), item.Name)
+		f.SetCellStyle(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("B%d", row), textStyle)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), item.Cuts)
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), item.Price)
+		if !q.HideMargin {
+			f.SetCellValue(sheet, fmt.Sprintf("E%d", row), item.Mandays)
+			f.SetCellValue(sheet, fmt.Sprintf("F%d", row), item.Cost)
+		}
+		f.SetCellStyle(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%s%d", lastCol, row), numberStyle)
+		row++
+	}
+	f.SetCellValue(sheet, fmt.Sprintf("B%d", row), "합계")
+	f.SetCellStyle(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("B%d", row), headerStyle)
+	f.SetCellValue(sheet, fmt.Sprintf("C%d", row), q.Shots)
+	f.SetCellValue(sheet, fmt.Sprintf("D%d", row), q.Total)
+	if !q.HideMargin {
+		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), q.Mandays)
+		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), q.LaborCost)
+	}
+	f.SetCellStyle(sheet, fmt.Sprintf("C%d", row), fmt.Sprintf("%s%d", lastCol, row), totalStyle)
+
+	// 견적 조건
+	sheet = "견적 조건"
+	f.NewSheet(sheet)
+	f.SetColWidth(sheet, "A", "A", 3)
+	f.SetColWidth(sheet, "B", "B", 80)
+	f.SetCellValue(sheet, "B2", "견적 조건")
+	f.SetCellStyle(sheet, "B2", "B2", headerStyle)
+	for i, term := range q.Terms {
+		pos := fmt.Sprintf("B%d", i+3)
+		f.SetCellValue(sheet, pos, fmt.Sprintf("%d. %s", i+1, term))
+		f.SetCellStyle(sheet, pos, pos, textStyle)
+	}
+
+	// 내부 비용
+	if !q.HideMargin {
+		sheet = "내부 비용"
+		f.NewSheet(sheet)
+		f.SetColWidth(sheet, "A", "A", 3)
+		f.SetColWidth(sheet, "B", "B", 18)
+		f.SetColWidth(sheet, "C", "C", 30)
+		f.SetCellValue(sheet, "B2", "내부 검토용 - 클라이언트에게 제출하지 마세요.")
+		writePairs(sheet, 3, getBGQuoteInternalFunc(q))
+	}
+
+	f.SetActiveSheet(0)
+	return f.SaveAs(path)
+}
+
+// genBGQuotePDFFunc 함수는 견적서를 PDF 파일로 만드는 함수이다.
+// 첫 페이지는 표지, 다음 페이지부터 작업 범위, 견적 내역, 견적 조건을 넣고, 내부 비용을 숨기지 않으면 마지막에 내부 비용을 넣는다.
+func genBGQuotePDFFunc(q BGQuote, path string) error {
+	const (
+		left   = 50.0
+		right  = PDFPageWidth - 50.0
+		bottom = PDFPageHeight - 60.0
+		lineH  = 20.0
+	)
+	doc := newPDFDocumentFunc()
+
+	// 항목과 값이 두 열로 이어지는 표를 그린다.
+	drawPairs := func(y float64, pairs [][2]string) float64 {
+		for _, p := range pairs {
+			doc.FillRect(left, y, 120, lineH, 0.9)
+			doc.Text(left+8, y+5, 10, p[0])
+			doc.Text(left+130, y+5, 10, p[1])
+			doc.Line(left, y+lineH, right, y+lineH, 0.5)
+			y += lineH
+		}
+		return y
+	}
+
+	// 표지
+	doc.AddPage()
+	doc.TextCenter(PDFPageWidth/2, 150, 32, "견 적 서")
+	doc.Line(left, 200, right, 200, 1.5)
+	y := drawPairs(220, getBGQuoteCoverFunc(q))
+	doc.Line(left, y+10, right, y+10, 1.5)
+	doc.TextRight(right, bottom, 9, fmt.Sprintf("작성자 : %s", q.Writer))
+
+	// 작업 범위
+	doc.AddPage()
+	doc.Text(left, 60, 14, "1. 작업 범위")
+	y = drawPairs(90, getBGQuoteScopeFunc(q))
+
+	// 견적 내역
+	type column struct {
+		title string
+		x     float64 // 오른쪽 끝 위치, 이름 열은 왼쪽 끝 위치
+	}
+	columns := []column{{q.GroupTitle, left + 8}, {"샷", left + 200}, {"견적 금액", right - 8}}
+	if !q.HideMargin {
+		columns = []column{{q.GroupTitle, left + 8}, {"샷", left + 150}, {"bid", left + 230}, {"인건비", left + 350}, {"견적 금액", right - 8}}
+	}
+	drawHeader := func(y float64) float64 {
+		doc.FillRect(left, y, right-left, lineH, 0.8)
+		for i, c := range columns {
+			if i == 0 {
+				doc.Text(c.x, y+5, 10, c.title)
+			} else {
+				doc.TextRight(c.x, y+5, 10, c.title)
+			}
+		}
+		return y + lineH
+	}
+	drawRow := func(y float64, name string, cuts int, mandays float64, cost int, price int) {
+		values := []string{name, putCommaFunc(cuts), putCommaFunc(price)}
+		if !q.HideMargin {
+			values = []string{name, putCommaFunc(cuts), fmt.Sprintf("%.1f", mandays), putCommaFunc(cost), putCommaFunc(price)}
+		}
+		for i, c := range columns {
+			if i == 0 {
+				doc.Text(c.x, y+5, 10, values[i])
+			} else {
+				doc.TextRight(c.x, y+5, 10, values[i])
+			}
+		}
+		doc.Line(left, y+lineH, right, y+lineH, 0.5)
+	}
+	y += 30
+	doc.Text(left, y, 14, fmt.Sprintf("2. %s별 견적", q.GroupTitle))
+	y = drawHeader(y + 30)
+	for _, item := range q.Items {
+		if y+lineH > bottom {
+			doc.AddPage()
+			y = drawHeader(60)
+		}
+		drawRow(y, item.Name, item.Cuts, item.Mandays, item.Cost, item.Price)
+		y += lineH
+	}
+	if y+lineH > bottom {
+		doc.AddPage()
+		y = drawHeader(60)
+	}
+	doc.FillRect(left, y, right-left, lineH, 0.9)
+	drawRow(y, "합계", q.Shots, q.Mandays, q.LaborCost, q.Total)
+	y += lineH
+
+	// 견적 조건
+	if y+60+float64(len(q.Terms))*lineH > bottom {
+		doc.AddPage()
+		y = 30
+	}
+	y += 30
+	doc.Text(left, y, 14, "3. 견적 조건")
+	y += 30
+	for i, term := range q.Terms {
+		if y+lineH > bottom {
+			doc.AddPage()
+			y = 60
+		}
+		doc.Text(left+8, y, 10, fmt.Sprintf("%d. %s", i+1, term))
+		y += lineH
+	}
+
+	// 내부 비용
+	if !q.HideMargin {
+		doc.AddPage()
+		doc.Text(left, 60, 14, "내부 비용 (내부 검토용 - 클라이언트에게 제출하지 마세요)")
+		drawPairs(90, getBGQuoteInternalFunc(q))
+	}
+
+	return doc.SaveAs(path)
+}
@@ -0,0 +1,99 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산안 견적서 테스트 스크립트
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// 금액을 비율대로 나누고 합계를 맞추는지 테스트하기 위한 함수
+func Test_allocateBGQuote(t *testing.T) {
+	cases := []struct {
+		total   int
+		weights []float64
+		want    []int
+	}{
+		{total: 100, weights: []float64{1, 1, 2}, want: []int{25, 25, 50}},
+		{total: 100, weights: []float64{1, 1, 1}, want: []int{34, 33, 33}},
+		{total: 10, weights: []float64{0, 0}, want: []int{5, 5}},
+		{total: 0, weights: []float64{3, 1}, want: []int{0, 0}},
+		{total: 7, weights: nil, want: []int{}},
+	}
+	for _, c := range cases {
+		got := allocateBGQuoteFunc(c.total, c.weights)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Test_allocateBGQuote(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.total, c.weights, c.want, got)
+		}
+	}
+}
+
+// 샷을 시퀀스, 에피소드로 묶어 견적 금액과 인건비를 나누는지 테스트하기 위한 함수
+func Test_newBGQuoteItems(t *testing.T) {
+	shots := []BGShotAsset{
+		{Name: "s0010_c0010", Episode: "EP02", Manday: map[string]float64{"comp": 2}},
+		{Name: "s0010_c0020", Episode: "EP02", Manday: map[string]float64{"comp": 1, "mm": 1}},
+		{Name: "s0020_c0010", Episode: "EP01", Manday: map[string]float64{"comp": 2}},
+	}
+	assets := []BGShotAsset{{Name: "시뮬레이션", Manday: map[string]float64{"fx": 2}}}
+	episodes := []Episode{{Name: "EP01"}, {Name: "EP02"}}
+
+	// 영화: 시퀀스별, bid 비율
+	want := []BGQuoteItem{
+		{Name: "s0010", Cuts: 2, Mandays: 4, Price: 500, Cost: 200},
+		{Name: "s0020", Cuts: 1, Mandays: 2, Price: 250, Cost: 100},
+		{Name: BGQuoteAssetItem, Mandays: 2, Price: 250, Cost: 100},
+	}
+	got := newBGQuoteItemsFunc(shots, assets, episodes, false, nil, 1000, 400)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Test_newBGQuoteItems(): 입력 값: movie, 원하는 값: %v, 얻은 값: %v\n", want, got)
+	}
+
+	// 드라마: 에피소드 순서, 에피소드별 비용 비율
+	want = []BGQuoteItem{
+		{Name: "EP01", Cuts: 1, Mandays: 2, Price: 600, Cost: 100},
+		{Name: "EP02", Cuts: 2, Mandays: 4, Price: 150, Cost: 200},
+		{Name: BGQuoteAssetItem, Mandays: 2, Price: 250, Cost: 100},
+	}
+	got = newBGQuoteItemsFunc(shots, assets, episodes, true, map[string]int{"EP01": 40, "EP02": 10}, 1000, 400)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Test_newBGQuoteItems(): 입력 값: drama, 원하는 값: %v, 얻은 값: %v\n", want, got)
+	}
+}
+
+// PDF 문서의 글자 인코딩과 xref 위치가 올바른지 테스트하기 위한 함수
+func Test_PDFDocument(t *testing.T) {
+	if got := encodePDFTextFunc("A견"); got != "0041ACAC" {
+		t.Fatalf("Test_PDFDocument(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "A견", "0041ACAC", got)
+	}
+	if got := calPDFTextWidthFunc("AB견적", 10); got != 30 {
+		t.Fatalf("Test_PDFDocument(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "AB견적", 30, got)
+	}
+
+	doc := newPDFDocumentFunc()
+	doc.Text(50, 50, 12, "견적서")
+	doc.AddPage()
+	doc.Line(50, 50, 100, 50, 1)
+	buf := new(bytes.Buffer)
+	_, err := doc.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.Bytes()
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.Contains(out, []byte("/Count 2")) {
+		t.Fatalf("Test_PDFDocument(): PDF 헤더 또는 페이지 수가 잘못되었습니다.\n")
+	}
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("Test_PDFDocument(): startxref가 없습니다.\n")
+	}
+	offset, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[offset:], []byte("xref")) {
+		t.Fatalf("Test_PDFDocument(): 입력 값: startxref, 원하는 값: xref, 얻은 값: %q\n", out[offset:offset+4])
+	}
+}
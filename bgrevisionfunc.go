@@ -0,0 +1,166 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산안 리비전, 비교 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// getBGCompareTargetFunc 함수는 비교 대상 문자열에 해당하는 예산안 데이터와 이름을 가져오는 함수이다.
+// 비교 대상은 현재 예산안(type:<예산안 이름>) 또는 리비전(rev:<리비전 ID>)이다.
+func getBGCompareTargetFunc(client *mongo.Client, bgp BGProject, target string) (BGTypeData, string, error) {
+	switch {
+	case strings.HasPrefix(target, "type:"):
+		bgtype := strings.TrimPrefix(target, "type:")
+		typedata, ok := bgp.TypeData[bgtype]
+		if !ok {
+			return BGTypeData{}, "", fmt.Errorf("%s 예산안이 존재하지 않습니다", bgtype)
+		}
+		return typedata, bgtype + " (현재)", nil
+	case strings.HasPrefix(target, "rev:"):
+		rev, err := getBGRevisionFunc(client, strings.TrimPrefix(target, "rev:"))
+		if err != nil {
+			return BGTypeData{}, "", err
+		}
+		if rev.ProjectID != bgp.ID {
+			return BGTypeData{}, "", fmt.Errorf("%s 예산 프로젝트의 리비전이 아닙니다", bgp.ID)
+		}
+		return rev.TypeData, fmt.Sprintf("%s v%d", rev.BGType, rev.Revision), nil
+	}
+	return BGTypeData{}, "", fmt.Errorf("비교 대상이 올바르지 않습니다: %s", target)
+}
+
+// newBGCompareRowFunc 함수는 두 값으로 예산안 비교 항목을 만드는 함수이다.
+func newBGCompareRowFunc(section string, item string, a float64, b float64, money bool) BGCompareRow {
+	format := func(v float64) string {
+		if money {
+			return humanize.Comma(int64(v))
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	diff := math.Round((b-a)*10000) / 10000 // 비율의 부동소수점 오차 제거
+	delta := format(diff)
+	if diff > 0 {
+		delta = "+" + delta
+	}
+	return BGCompareRow{
+		Section: section,
+		Item:    item,
+		A:       format(a),
+		B:       format(b),
+		Delta:   delta,
+		Diff:    diff,
+	}
+}
+
+// getBGLaborCostMapFunc 함수는 예산안의 인건비를 "본부 부서" 항목별로 정리하는 함수이다.
+func getBGLaborCostMapFunc(typedata BGTypeData) (map[string]int, error) {
+	result := make(map[string]int)
+	for _, ls := range typedata.LaborCosts {
+		for dept, cost := range ls.DepartmentCost {
+			c, err := decryptToIntFunc(cost)
+			if err != nil {
+				return nil, err
+			}
+			result[ls.Headquarter+" "+dept] += c
+		}
+		c, err := decryptToIntFunc(ls.Management)
+		if err != nil {
+			return nil, err
+		}
+		result[ls.Headquarter+" "+BGDeptManagement] += c
+	}
+	return result, nil
+}
+
+// calBGCompareFunc 함수는 두 예산안의 계약 정보, 비율, 부서별 인건비, 태스크별 manday를 비교하는 함수이다.
+func calBGCompareFunc(a BGTypeData, b BGTypeData) ([]BGCompareRow, error) {
+	var rows []BGCompareRow
+
+	// 계약 정보
+	for _, item := range []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{name: "계약 결정액", a: a.Decision, b: b.Decision},
+		{name: "제안 견적", a: a.Proposal, b: b.Proposal},
+	} {
+		av, err := decryptToIntFunc(item.a)
+		if err != nil {
+			return nil, err
+		}
+		bv, err := decryptToIntFunc(item.b)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, newBGCompareRowFunc("계약", item.name, float64(av), float64(bv), true))
+	}
+	rows = append(rows, newBGCompareRowFunc("계약", "계약 컷수", float64(a.ContractCuts), float64(b.ContractCuts), false))
+	rows = append(rows, newBGCompareRowFunc("계약", "작업 컷수", float64(a.WorkingCuts), float64(b.WorkingCuts), false))
+
+	// 비율
+	rows = append(rows, newBGCompareRowFunc("비율", "Retake율", a.RetakeRatio, b.RetakeRatio, false))
+	rows = append(rows, newBGCompareRowFunc("비율", "진행비율", a.ProgressRatio, b.ProgressRatio, false))
+	rows = append(rows, newBGCompareRowFunc("비율", "외주비율", a.VendorRatio, b.VendorRatio, false))
+
+	// 부서별 인건비
+	aCost, err := getBGLaborCostMapFunc(a)
+	if err != nil {
+		return nil, err
+	}
+	bCost, err := getBGLaborCostMapFunc(b)
+	if err != nil {
+		return nil, err
+	}
+	var aTotal, bTotal int
+	for _, item := range unionKeysFunc(aCost, bCost) {
+		rows = append(rows, newBGCompareRowFunc("인건비", item, float64(aCost[item]), float64(bCost[item]), true))
+		aTotal += aCost[item]
+		bTotal += bCost[item]
+	}
+	rows = append(rows, newBGCompareRowFunc("인건비", BGHeadTotal, float64(aTotal), float64(bTotal), true))
+
+	// 태스크별 manday
+	aBid := calTotalBidFunc(append(append([]BGShotAsset{}, a.ShotList...), a.AssetList...))
+	bBid := calTotalBidFunc(append(append([]BGShotAsset{}, b.ShotList...), b.AssetList...))
+	var tasks []string
+	for task := range aBid {
+		tasks = append(tasks, task)
+	}
+	for task := range bBid {
+		if _, ok := aBid[task]; !ok {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Strings(tasks)
+	for _, task := range tasks {
+		rows = append(rows, newBGCompareRowFunc("Manday", task, aBid[task], bBid[task], false))
+	}
+
+	return rows, nil
+}
+
+// unionKeysFunc 함수는 두 맵의 키를 합쳐 정렬된 리스트로 반환하는 함수이다.
+func unionKeysFunc(a map[string]int, b map[string]int) []string {
+	var keys []string
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
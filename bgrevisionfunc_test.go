@@ -0,0 +1,76 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산안 리비전, 비교 테스트 스크립트
+
+package main
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 예산안 비교 항목의 값과 차이를 만드는지 테스트하기 위한 함수
+func Test_newBGCompareRow(t *testing.T) {
+	cases := []struct {
+		a     float64
+		b     float64
+		money bool
+		want  BGCompareRow
+	}{
+		{a: 1000, b: 1500000, money: true, want: BGCompareRow{A: "1,000", B: "1,500,000", Delta: "+1,499,000", Diff: 1499000}},
+		{a: 0.3, b: 0.25, money: false, want: BGCompareRow{A: "0.3", B: "0.25", Delta: "-0.05", Diff: -0.05}},
+		{a: 10, b: 10, money: false, want: BGCompareRow{A: "10", B: "10", Delta: "0", Diff: 0}},
+	}
+	for _, c := range cases {
+		got := newBGCompareRowFunc("", "", c.a, c.b, c.money)
+		if got != c.want {
+			t.Fatalf("Test_newBGCompareRow(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", c.a, c.b, c.want, got)
+		}
+	}
+}
+
+// 두 예산안의 태스크별 manday를 비교하는지 테스트하기 위한 함수
+func Test_calBGCompare(t *testing.T) {
+	a := BGTypeData{
+		ContractCuts: 100,
+		ShotList:     []BGShotAsset{{Name: "s0010", Manday: map[string]float64{"MM": 1}}},
+	}
+	b := BGTypeData{
+		ContractCuts: 120,
+		ShotList:     []BGShotAsset{{Name: "s0010", Manday: map[string]float64{"MM": 2}}},
+		AssetList:    []BGShotAsset{{Name: "tree", Manday: map[string]float64{"model": 3}}},
+	}
+	rows, err := calBGCompareFunc(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{"계약 컷수": 20, "MM": 1, "model": 3, "합계": 0}
+	for _, row := range rows {
+		if diff, ok := want[row.Item]; ok && diff != row.Diff {
+			t.Fatalf("Test_calBGCompare(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", row.Item, diff, row.Diff)
+		}
+	}
+}
+
+// DB에 저장된 예산안과 현재 예산안이 같은지 비교하는지 테스트하기 위한 함수
+func Test_equalBGTypeData(t *testing.T) {
+	typedata := BGTypeData{ID: primitive.NewObjectID(), RetakeRatio: 0.1, EpisodeCost: map[string]string{}}
+	saved := typedata
+	saved.EpisodeCost = map[string]string{}
+	same, err := equalBGTypeDataFunc(saved, typedata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same {
+		t.Fatalf("Test_equalBGTypeData(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", typedata, true, same)
+	}
+	typedata.RetakeRatio = 0.2
+	same, err = equalBGTypeDataFunc(saved, typedata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same {
+		t.Fatalf("Test_equalBGTypeData(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", typedata, false, same)
+	}
+}
@@ -0,0 +1,289 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 팀세팅 버전 비교 및 마이그레이션 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BGTeamSettingDiffSections 는 팀세팅 비교 결과를 보여주는 구분 순서이다.
+var BGTeamSettingDiffSections = []string{"본부", "부서", "태스크", "태스크별 팀", "컨트롤 팀"}
+
+// BGMigrationDelete 는 마이그레이션할 때 옮기지 않고 삭제하는 항목을 나타낸다.
+const BGMigrationDelete = "-"
+
+// flattenBGTeamSettingFunc 함수는 팀세팅 구조를 구분별 항목과 값으로 펼치는 함수이다.
+// 결재자는 예산안 구조와 관계가 없으므로 포함하지 않는다.
+func flattenBGTeamSettingFunc(ts BGTeamSetting) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, section := range BGTeamSettingDiffSections {
+		result[section] = make(map[string]string)
+	}
+	valueOf := func(list []string) string {
+		if len(list) == 0 {
+			return BGMigrationDelete
+		}
+		return listToStringFunc(list, true)
+	}
+	for _, head := range ts.Headquarters {
+		result["본부"][head] = head
+		for _, dept := range ts.Departments[head] {
+			typ := "샷"
+			if dept.Type {
+				typ = "어셋"
+			}
+			result["부서"][head+"/"+dept.Name] = typ
+			for _, part := range dept.Parts {
+				for _, task := range part.Tasks {
+					result["태스크"][task] = fmt.Sprintf("%s/%s/%s", head, dept.Name, part.Name)
+					result["태스크별 팀"][task] = valueOf(ts.Teams[task])
+				}
+			}
+		}
+		for _, control := range ts.Controls[head] {
+			for _, part := range control.Parts {
+				result["컨트롤 팀"][fmt.Sprintf("%s/%s/%s", head, control.Name, part.Name)] = valueOf(part.Teams)
+			}
+		}
+	}
+	return result
+}
+
+// equalBGTeamSettingFunc 함수는 두 팀세팅의 구조(본부, 부서, 태스크, 팀)가 같은지 확인하는 함수이다.
+func equalBGTeamSettingFunc(a BGTeamSetting, b BGTeamSetting) bool {
+	return reflect.DeepEqual(flattenBGTeamSettingFunc(a), flattenBGTeamSettingFunc(b)) &&
+		reflect.DeepEqual(a.Headquarters, b.Headquarters)
+}
+
+// diffBGTeamSettingFunc 함수는 두 팀세팅의 차이를 구분, 항목 순서대로 반환하는 함수이다.
+func diffBGTeamSettingFunc(before BGTeamSetting, after BGTeamSetting) []BGTeamSettingDiff {
+	b := flattenBGTeamSettingFunc(before)
+	a := flattenBGTeamSettingFunc(after)
+	var results []BGTeamSettingDiff
+	for _, section := range BGTeamSettingDiffSections {
+		var items []string
+		for item := range b[section] {
+			items = append(items, item)
+		}
+		for item := range a[section] {
+			if _, ok := b[section][item]; !ok {
+				items = append(items, item)
+			}
+		}
+		sort.Strings(items)
+		for _, item := range items {
+			if b[section][item] == a[section][item] {
+				continue
+			}
+			results = append(results, BGTeamSettingDiff{
+				Section: section,
+				Item:    item,
+				Before:  b[section][item],
+				After:   a[section][item],
+			})
+		}
+	}
+	return results
+}
+
+// getBGMigrationTasksFunc 함수는 예산안들의 샷, 어셋 bid와 팀세팅에서 사용하는 태스크 리스트를 반환하는 함수이다.
+func getBGMigrationTasksFunc(tds []BGTypeData) []string {
+	var tasks []string
+	add := func(task string) {
+		if task != "" && !checkStringInListFunc(task, tasks) {
+			tasks = append(tasks, task)
+		}
+	}
+	for _, td := range tds {
+		for _, list := range [][]BGShotAsset{td.ShotList, td.AssetList} {
+			for _, item := range list {
+				for task := range item.Manday {
+					add(task)
+				}
+			}
+		}
+		for _, depts := range td.TeamSetting.Departments {
+			for _, dept := range depts {
+				for _, part := range dept.Parts {
+					for _, task := range part.Tasks {
+						add(task)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(tasks)
+	return tasks
+}
+
+// getBGMigrationDeptsFunc 함수는 예산안들의 인건비에서 사용하는 본부/부서 리스트를 반환하는 함수이다.
+// 본부별 매니지먼트 비용은 본부/Management 로 나타낸다.
+func getBGMigrationDeptsFunc(tds []BGTypeData) []string {
+	var depts []string
+	add := func(dept string) {
+		if !checkStringInListFunc(dept, depts) {
+			depts = append(depts, dept)
+		}
+	}
+	for _, td := range tds {
+		for _, lc := range td.LaborCosts {
+			for dept := range lc.DepartmentCost {
+				add(lc.Headquarter + "/" + dept)
+			}
+			add(lc.Headquarter + "/" + BGDeptManagement)
+		}
+	}
+	sort.Strings(depts)
+	return depts
+}
+
+// getBGDeptOptionsFunc 함수는 팀세팅의 본부/부서 리스트를 반환하는 함수이다. 본부별 매니지먼트를 마지막에 붙인다.
+func getBGDeptOptionsFunc(ts BGTeamSetting) []string {
+	var results []string
+	for _, head := range ts.Headquarters {
+		for _, dept := range ts.Departments[head] {
+			results = append(results, head+"/"+dept.Name)
+		}
+		results = append(results, head+"/"+BGDeptManagement)
+	}
+	return results
+}
+
+// defaultBGMigrationMapFunc 함수는 이전 항목과 같은 이름이 새 항목에 있으면 그대로 연결하는 기본 매핑을 반환하는 함수이다.
+func defaultBGMigrationMapFunc(olds []string, news []string) map[string]string {
+	result := make(map[string]string)
+	for _, old := range olds {
+		if checkStringInListFunc(old, news) {
+			result[old] = old
+		} else {
+			result[old] = ""
+		}
+	}
+	return result
+}
+
+// remapBGMandayFunc 함수는 샷, 어셋 리스트의 태스크별 bid를 매핑에 따라 새 태스크로 옮기는 함수이다.
+// 여러 태스크가 한 태스크로 합쳐지면 bid를 더하고, 삭제로 매핑된 태스크의 bid는 옮기지 않는다.
+func remapBGMandayFunc(list []BGShotAsset, taskMap map[string]string) ([]BGShotAsset, error) {
+	var results []BGShotAsset
+	for _, item := range list {
+		manday := make(map[string]float64)
+		for task, bid := range item.Manday {
+			target := taskMap[task]
+			if target == "" {
+				return nil, fmt.Errorf("%s 태스크를 옮길 태스크를 선택해주세요", task)
+			}
+			if target == BGMigrationDelete {
+				continue
+			}
+			manday[target] += bid
+		}
+		item.Manday = manday
+		results = append(results, item)
+	}
+	return results, nil
+}
+
+// remapBGDeptCostFunc 함수는 본부/부서별 비용을 매핑에 따라 새 본부/부서로 옮기는 함수이다.
+// 여러 부서가 한 부서로 합쳐지면 비용을 더하고, 비용이 있는 항목은 삭제할 수 없다.
+func remapBGDeptCostFunc(costs map[string]int, deptMap map[string]string) (map[string]int, error) {
+	result := make(map[string]int)
+	for dept, cost := range costs {
+		target := deptMap[dept]
+		if target == "" || target == BGMigrationDelete {
+			if cost == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("%s 부서의 비용을 옮길 부서를 선택해주세요", dept)
+		}
+		if strings.HasSuffix(dept, "/"+BGDeptManagement) != strings.HasSuffix(target, "/"+BGDeptManagement) {
+			return nil, fmt.Errorf("%s 비용은 %s(으)로 옮길 수 없습니다", dept, target)
+		}
+		result[target] += cost
+	}
+	return result, nil
+}
+
+// migrateBGTypeDataFunc 함수는 예산안의 bid와 인건비를 매핑에 따라 새 팀세팅 구조로 옮기는 함수이다.
+func migrateBGTypeDataFunc(td *BGTypeData, ts BGTeamSetting, taskMap map[string]string, deptMap map[string]string) error {
+	shotList, err := remapBGMandayFunc(td.ShotList, taskMap)
+	if err != nil {
+		return err
+	}
+	assetList, err := remapBGMandayFunc(td.AssetList, taskMap)
+	if err != nil {
+		return err
+	}
+
+	costs := make(map[string]int)
+	for _, lc := range td.LaborCosts {
+		for dept, cost := range lc.DepartmentCost {
+			c, err := decryptToIntFunc(cost)
+			if err != nil {
+				return err
+			}
+			costs[lc.Headquarter+"/"+dept] += c
+		}
+		c, err := decryptToIntFunc(lc.Management)
+		if err != nil {
+			return err
+		}
+		costs[lc.Headquarter+"/"+BGDeptManagement] += c
+	}
+	newCosts, err := remapBGDeptCostFunc(costs, deptMap)
+	if err != nil {
+		return err
+	}
+
+	for dept := range newCosts {
+		if !checkStringInListFunc(dept, getBGDeptOptionsFunc(ts)) {
+			return fmt.Errorf("%s 부서가 현재 팀세팅에 없습니다", dept)
+		}
+	}
+
+	// 새 팀세팅의 본부 순서대로 인건비를 정리한다.
+	var laborCosts []BGLaborCost
+	for _, head := range ts.Headquarters {
+		lc := BGLaborCost{
+			Headquarter:    head,
+			DepartmentCost: make(map[string]string),
+		}
+		for _, dept := range ts.Departments[head] {
+			lc.DepartmentCost[dept.Name], err = encryptAES256Func(strconv.Itoa(newCosts[head+"/"+dept.Name]))
+			if err != nil {
+				return err
+			}
+		}
+		lc.Management, err = encryptAES256Func(strconv.Itoa(newCosts[head+"/"+BGDeptManagement]))
+		if err != nil {
+			return err
+		}
+		laborCosts = append(laborCosts, lc)
+	}
+
+	td.ShotList = shotList
+	td.AssetList = assetList
+	td.LaborCosts = laborCosts
+	td.TeamSetting = ts
+	return nil
+}
+
+// getBGTeamSettingOnDateFunc 함수는 최신순으로 정렬된 팀세팅 버전 기록에서 date(2020-03-01) 당시에 사용하던 팀세팅을 찾는 함수이다.
+// date 이전에 저장된 버전이 없으면 false를 반환한다.
+func getBGTeamSettingOnDateFunc(versions []BGTeamSettingVersion, date string) (BGTeamSetting, bool) {
+	for _, v := range versions {
+		if len(v.CreatedTime) < 10 {
+			continue
+		}
+		if v.CreatedTime[:10] <= date {
+			return v.TeamSetting, true
+		}
+	}
+	return BGTeamSetting{}, false
+}
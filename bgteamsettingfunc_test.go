@@ -0,0 +1,132 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 팀세팅 버전 비교 및 마이그레이션 테스트 스크립트
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 두 팀세팅의 추가, 삭제, 변경된 항목을 구분별로 반환하는지 테스트하기 위한 함수
+func Test_diffBGTeamSetting(t *testing.T) {
+	before := BGTeamSetting{
+		Headquarters: []string{"VFX"},
+		Departments: map[string][]BGDept{
+			"VFX": {{Name: "3D", Parts: []BGPart{{Name: "Ani", Tasks: []string{"ani", "mm"}}}}},
+		},
+		Teams: map[string][]string{"ani": {"Animation"}, "mm": {"MatchMove"}},
+	}
+	after := BGTeamSetting{
+		Headquarters: []string{"VFX"},
+		Departments: map[string][]BGDept{
+			"VFX": {{Name: "3D", Parts: []BGPart{{Name: "Ani", Tasks: []string{"ani", "layout"}}}}},
+		},
+		Teams:     map[string][]string{"ani": {"Animation", "Layout"}, "layout": {"Layout"}},
+		Approvers: map[string]BGApprover{"VFX": {}},
+	}
+	want := []BGTeamSettingDiff{
+		{Section: "태스크", Item: "layout", Before: "", After: "VFX/3D/Ani"},
+		{Section: "태스크", Item: "mm", Before: "VFX/3D/Ani", After: ""},
+		{Section: "태스크별 팀", Item: "ani", Before: "Animation", After: "Animation,Layout"},
+		{Section: "태스크별 팀", Item: "layout", Before: "", After: "Layout"},
+		{Section: "태스크별 팀", Item: "mm", Before: "MatchMove", After: ""},
+	}
+	got := diffBGTeamSettingFunc(before, after)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Test_diffBGTeamSetting(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", before, after, want, got)
+	}
+	if !equalBGTeamSettingFunc(after, BGTeamSetting{Headquarters: after.Headquarters, Departments: after.Departments, Teams: after.Teams}) {
+		t.Fatalf("Test_diffBGTeamSetting(): 결재자만 다른 팀세팅을 다른 구조로 판단하였습니다.\n")
+	}
+}
+
+// 태스크 매핑에 따라 bid를 옮기고 합치는지 테스트하기 위한 함수
+func Test_remapBGManday(t *testing.T) {
+	list := []BGShotAsset{{Name: "s0010", Manday: map[string]float64{"mm": 1, "layout": 0.5, "ani": 2}}}
+	cases := []struct {
+		taskMap map[string]string
+		want    map[string]float64
+		err     bool
+	}{{
+		taskMap: map[string]string{"mm": "track", "layout": "ani", "ani": "ani"},
+		want:    map[string]float64{"track": 1, "ani": 2.5},
+	}, {
+		taskMap: map[string]string{"mm": BGMigrationDelete, "layout": "layout", "ani": "ani"},
+		want:    map[string]float64{"layout": 0.5, "ani": 2},
+	}, {
+		// 매핑하지 않은 태스크가 있는 경우
+		taskMap: map[string]string{"mm": "mm", "ani": "ani"},
+		err:     true,
+	}}
+	for _, c := range cases {
+		got, err := remapBGMandayFunc(list, c.taskMap)
+		if c.err {
+			if err == nil {
+				t.Fatalf("Test_remapBGManday(): 입력 값: %v, 원하는 값: 에러, 얻은 값: %v\n", c.taskMap, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got[0].Manday, c.want) {
+			t.Fatalf("Test_remapBGManday(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v (%v)\n", c.taskMap, c.want, got, err)
+		}
+	}
+}
+
+// 부서 매핑에 따라 비용을 옮기고 잘못된 매핑은 에러를 반환하는지 테스트하기 위한 함수
+func Test_remapBGDeptCost(t *testing.T) {
+	costs := map[string]int{"VFX/3D": 100, "VFX/FX": 50, "VFX/Old": 0, "VFX/Management": 30}
+	cases := []struct {
+		deptMap map[string]string
+		want    map[string]int
+		err     bool
+	}{{
+		deptMap: map[string]string{"VFX/3D": "VFX/3D+FX", "VFX/FX": "VFX/3D+FX", "VFX/Management": "VFX/Management"},
+		want:    map[string]int{"VFX/3D+FX": 150, "VFX/Management": 30},
+	}, {
+		// 비용이 있는 부서를 삭제하는 경우
+		deptMap: map[string]string{"VFX/3D": "VFX/3D", "VFX/Management": "VFX/Management"},
+		err:     true,
+	}, {
+		// 매니지먼트 비용을 일반 부서로 옮기는 경우
+		deptMap: map[string]string{"VFX/3D": "VFX/3D", "VFX/FX": "VFX/FX", "VFX/Management": "VFX/3D"},
+		err:     true,
+	}}
+	for _, c := range cases {
+		got, err := remapBGDeptCostFunc(costs, c.deptMap)
+		if c.err {
+			if err == nil {
+				t.Fatalf("Test_remapBGDeptCost(): 입력 값: %v, 원하는 값: 에러, 얻은 값: %v\n", c.deptMap, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Test_remapBGDeptCost(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v (%v)\n", c.deptMap, c.want, got, err)
+		}
+	}
+}
+
+// 날짜에 사용하던 팀세팅 버전을 찾는지 테스트하기 위한 함수
+func Test_getBGTeamSettingOnDate(t *testing.T) {
+	versions := []BGTeamSettingVersion{ // 최신순
+		{Version: 3, TeamSetting: BGTeamSetting{Version: 3}, CreatedTime: "2020-05-20T10:00:00+09:00"},
+		{Version: 2, TeamSetting: BGTeamSetting{Version: 2}, CreatedTime: "2020-03-01T09:00:00+09:00"},
+		{Version: 1, TeamSetting: BGTeamSetting{Version: 1}, CreatedTime: ""},
+	}
+	cases := []struct {
+		date    string
+		version int
+		ok      bool
+	}{
+		{date: "2020-06-01", version: 3, ok: true},
+		{date: "2020-03-01", version: 2, ok: true},
+		{date: "2020-01-01", version: 0, ok: false}, // 저장 시간이 없는 버전은 사용하지 않는다.
+	}
+	for _, c := range cases {
+		got, ok := getBGTeamSettingOnDateFunc(versions, c.date)
+		if ok != c.ok || got.Version != c.version {
+			t.Fatalf("Test_getBGTeamSettingOnDate(): 입력 값: %v, 원하는 값: %v %v, 얻은 값: %v %v\n", c.date, c.version, c.ok, got.Version, ok)
+		}
+	}
+}
@@ -25,4 +25,5 @@ var (
 	regexProject      = regexp.MustCompile(`^[A-Z0-9_]+$`) // BEE, RND2020, CM_ART
 	regexDigit        = regexp.MustCompile(`^[0-9]+$`)     // 숫자
 	regexWebColor     = regexp.MustCompile(`^#([A-Fa-f0-9]{6}|[A-Fa-f0-9]{3})$`)
+	regexEmail        = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`) // aerim.shim@rd101.co.kr
 )
@@ -217,3 +217,37 @@ func Test_checkProject(t *testing.T) {
 		}
 	}
 }
+
+// 이메일 형식을 테스트하기 위한 함수
+func Test_checkEmail(t *testing.T) {
+	cases := []struct {
+		email string
+		want  bool
+	}{{
+		email: "aerim.shim@rd101.co.kr",
+		want:  true,
+	}, {
+		email: "producer+budget@example.com",
+		want:  true,
+	}, {
+		email: "aerim.shim", // @가 없는 경우
+		want:  false,
+	}, {
+		email: "aerim.shim@rd101", // 도메인이 없는 경우
+		want:  false,
+	}, {
+		email: "aerim shim@rd101.co.kr", // 띄어쓰기가 포함된 경우
+		want:  false,
+	}, {
+		email: "", // 빈문자열인 경우
+		want:  false,
+	},
+	}
+
+	for _, c := range cases {
+		b := regexEmail.MatchString(c.email)
+		if c.want != b {
+			t.Fatalf("Test_checkEmail(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.email, c.want, b)
+		}
+	}
+}
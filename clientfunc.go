@@ -0,0 +1,184 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 클라이언트(제작사, 감독) 관련 스크립트
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// setClientOfProjectFunc 함수는 프로젝트의 제작사, 감독 이름으로 클라이언트를 찾아 프로젝트에 연결하는 함수이다.
+// 이름에 해당하는 클라이언트가 없으면 새로 추가한다.
+func setClientOfProjectFunc(client *mongo.Client, project *Project) error {
+	producerID, err := getClientIDByNameFunc(client, ClientTypeProducer, project.ProducerName)
+	if err != nil {
+		return err
+	}
+	project.ProducerID = producerID
+
+	directorID, err := getClientIDByNameFunc(client, ClientTypeDirector, project.DirectorName)
+	if err != nil {
+		return err
+	}
+	project.DirectorID = directorID
+	return nil
+}
+
+// getClientIDByNameFunc 함수는 이름에 해당하는 클라이언트의 ID를 반환하는 함수이다. 클라이언트가 없으면 추가한다.
+func getClientIDByNameFunc(client *mongo.Client, typ string, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	c, err := getClientByNameFunc(client, typ, name)
+	if err == nil {
+		return c.ID.Hex(), nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return "", err
+	}
+
+	// DB에 존재하지 않는 이름이면 클라이언트를 새로 추가한다.
+	newClient := Client{
+		Type: typ,
+		Name: name,
+	}
+	err = newClient.CheckErrorFunc()
+	if err != nil {
+		return "", err
+	}
+	id, err := addClientFunc(client, newClient)
+	if err != nil {
+		return "", err
+	}
+	return id.Hex(), nil
+}
+
+// calClientSummaryFunc 함수는 클라이언트와 연결된 프로젝트들의 계약금액, 입금액, 미수금, 수익을 계산하는 함수이다.
+func calClientSummaryFunc(client *mongo.Client, c Client) (ClientSummary, error) {
+	summary := ClientSummary{
+		Client: c,
+	}
+	projects, err := getProjectsByClientFunc(client, c)
+	if err != nil {
+		return summary, err
+	}
+	summary.Projects = projects
+
+	for _, project := range projects {
+		projectSummary, err := calProjectSummaryForClientFunc(client, project)
+		if err != nil {
+			return summary, err
+		}
+		summary.Contract += projectSummary.Contract
+		summary.Received += projectSummary.Received
+		summary.Outstanding += projectSummary.Outstanding
+		summary.Overdue += projectSummary.Overdue
+		summary.Profit += projectSummary.Profit
+	}
+	return summary, nil
+}
+
+// calProjectSummaryForClientFunc 함수는 프로젝트 하나의 계약금액, 입금액, 미수금, 수익을 계산하는 함수이다.
+func calProjectSummaryForClientFunc(client *mongo.Client, project Project) (ClientSummary, error) {
+	summary := ClientSummary{}
+	today := time.Now().Format("2006-01-02")
+
+	// 총 계약금액
+	for _, payment := range project.Payment {
+		expenses, err := decryptToIntFunc(payment.Expenses)
+		if err != nil {
+			return summary, err
+		}
+		summary.Contract += expenses
+	}
+
+	// 월별로 발행된 매출의 입금 여부를 확인한다.
+	for _, monthlyPayment := range project.SMMonthlyPayment {
+		for _, payment := range monthlyPayment {
+			expenses, err := decryptToIntFunc(payment.Expenses)
+			if err != nil {
+				return summary, err
+			}
+			if payment.Status {
+				summary.Received += expenses
+				continue
+			}
+			summary.Outstanding += expenses
+			if payment.Date != "" && payment.Date < today {
+				summary.Overdue += expenses
+			}
+		}
+	}
+
+	// 수익
+	profit, err := calProfitOfProjectFunc(client, project)
+	if err != nil {
+		return summary, err
+	}
+	summary.Profit = profit
+	return summary, nil
+}
+
+// calProfitOfProjectFunc 함수는 프로젝트의 매출에서 인건비, 진행비, 구매비, 외주비를 뺀 수익을 계산하는 함수이다.
+func calProfitOfProjectFunc(client *mongo.Client, project Project) (int, error) {
+	profit := 0
+	if project.IsFinished {
+		revenue, err := getRevenueOfFPFunc(project)
+		if err != nil {
+			return 0, err
+		}
+		profit = revenue
+	} else {
+		dates, err := getDatesFunc(project.StartDate, project.SMEndDate)
+		if err != nil {
+			return 0, err
+		}
+		for _, date := range dates {
+			revenue, err := getMonthlyRevenueFunc(project, date)
+			if err != nil {
+				return 0, err
+			}
+			profit += revenue
+		}
+	}
+
+	// 외주비
+	vendors, err := searchVendorFunc(client, "project:"+project.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, v := range vendors {
+		vendorCost, err := calVendorCostFunc(v)
+		if err != nil {
+			return 0, err
+		}
+		profit -= vendorCost
+	}
+	return profit, nil
+}
+
+// calVendorCostFunc 함수는 벤더의 계약금, 중도금, 잔금을 합산하여 반환하는 함수이다.
+func calVendorCostFunc(v Vendor) (int, error) {
+	total, err := decryptToIntFunc(v.Downpayment.Expenses)
+	if err != nil {
+		return 0, err
+	}
+	for _, mp := range v.MediumPlating {
+		mediumplating, err := decryptToIntFunc(mp.Expenses)
+		if err != nil {
+			return 0, err
+		}
+		total += mediumplating
+	}
+	balance, err := decryptToIntFunc(v.Balance.Expenses)
+	if err != nil {
+		return 0, err
+	}
+	total += balance
+	return total, nil
+}
@@ -6,6 +6,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strconv"
 	"time"
@@ -57,7 +58,25 @@ func setMonthlyStatusCmdFunc() {
 		}
 	}
 
-	err = setMonthlyStatusFunc(client, ms)
+	before, err := getMonthlyStatusFunc(client, ms.Date)
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Print(err)
+		return
+	}
+	err = setMonthlyStatusAndNotifyFunc(client, ms)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s 결산 상태가 %t로 변경되었습니다.", ms.Date, ms.Status),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityMonthlyStatus,
+		EntityID:   ms.Date,
+		Changes:    diffEntityFunc(before, ms),
+	})
 	if err != nil {
 		log.Print(err)
 	}
@@ -47,6 +47,18 @@ func rmArtistCmdFunc() {
 	}
 
 	err = rmArtistFunc(client, id)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("아티스트 ID %s가 삭제되었습니다.", id),
+		Action:     LogActionDelete,
+		EntityType: LogEntityArtist,
+		EntityID:   id,
+	})
 	if err != nil {
 		log.Print(err)
 	}
@@ -196,6 +208,26 @@ func setResinationCmdFunc() {
 			if err != nil {
 				log.Fatal(err)
 			}
+			after, err := getArtistFunc(client, artist.ID)
+			if err != nil {
+				log.Fatal(err)
+			}
+			changes := diffEntityFunc(artist, after)
+			if len(changes) == 0 {
+				continue
+			}
+			err = addLogsFunc(client, Log{
+				UserID:     cmdActorFunc(),
+				CreatedAt:  time.Now(),
+				Content:    fmt.Sprintf("아티스트 ID %s의 퇴사 여부가 업데이트되었습니다.", artist.ID),
+				Action:     LogActionUpdate,
+				EntityType: LogEntityArtist,
+				EntityID:   artist.ID,
+				Changes:    changes,
+			})
+			if err != nil {
+				log.Fatal(err)
+			}
 		}
 	}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
@@ -70,6 +71,19 @@ func addArtistCMCmdFunc() {
 	}
 
 	err = addArtistFunc(client, a)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("CM 아티스트 ID %s가 추가되었습니다.", a.ID),
+		Action:     LogActionCreate,
+		EntityType: LogEntityArtist,
+		EntityID:   a.ID,
+		Changes:    diffEntityFunc(Artist{}, a),
+	})
 	if err != nil {
 		log.Print(err)
 	}
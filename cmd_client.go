@@ -0,0 +1,73 @@
+// 프로젝트 결산 프로그램
+//
+// Description : cmd 클라이언트(제작사, 감독) 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// updateClientCmdFunc 함수는 기존 프로젝트에 입력된 제작사, 감독 이름으로 클라이언트를 만들어 프로젝트에 연결하는 함수이다.
+func updateClientCmdFunc() {
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	projects, err := getAllProjectsFunc(client)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, project := range projects {
+		before := snapshotEntityFunc(project)
+		err = setClientOfProjectFunc(client, &project)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = setProjectFunc(client, project)
+		if err != nil {
+			log.Fatal(err)
+		}
+		changes := diffEntityFunc(before, project)
+		if len(changes) != 0 {
+			err = addLogsFunc(client, Log{
+				UserID:     cmdActorFunc(),
+				CreatedAt:  time.Now(),
+				Content:    fmt.Sprintf("프로젝트 %s의 클라이언트가 연결되었습니다.", project.ID),
+				Action:     LogActionUpdate,
+				EntityType: LogEntityProject,
+				EntityID:   project.ID,
+				Changes:    changes,
+			})
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		fmt.Printf("%s: 제작사(%s) 감독(%s)\n", project.ID, project.ProducerName, project.DirectorName)
+	}
+}
@@ -0,0 +1,54 @@
+// 프로젝트 결산 프로그램
+//
+// Description : cmd 로그 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// archiveLogsCmdFunc 함수는 cmd에서 보관 기간이 지난 로그를 파일로 저장하고 DB에서 삭제하는 함수이다.
+func archiveLogsCmdFunc() {
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = ensureLogIndexesFunc(client)
+	if err != nil {
+		log.Fatal(err)
+	}
+	path, num, err := archiveOldLogsFunc(client, cmdActorFunc())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if path == "" {
+		fmt.Println("보관 기간이 지난 로그가 없거나 Admin Setting의 로그 보관 기간이 0입니다.")
+		return
+	}
+	fmt.Printf("로그 %d개를 %s 파일에 저장하고 삭제하였습니다.\n", num, path)
+}
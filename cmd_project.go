@@ -117,6 +117,18 @@ func addProjectCmdFunc() {
 		log.Print(err)
 		return
 	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s가 추가되었습니다.", p.ID),
+		Action:     LogActionCreate,
+		EntityType: LogEntityProject,
+		EntityID:   p.ID,
+		Changes:    diffEntityFunc(Project{}, p),
+	})
+	if err != nil {
+		log.Print(err)
+	}
 }
 
 func rmProjectCmdFunc() {
@@ -156,6 +168,18 @@ func rmProjectCmdFunc() {
 	}
 
 	err = rmProjectFunc(client, id)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s가 삭제되었습니다.", id),
+		Action:     LogActionDelete,
+		EntityType: LogEntityProject,
+		EntityID:   id,
+	})
 	if err != nil {
 		log.Print(err)
 	}
@@ -245,6 +269,7 @@ func setProjectCmdFunc() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	before := snapshotEntityFunc(project)
 	if *flagName != "" {
 		project.Name = *flagName
 	}
@@ -264,6 +289,18 @@ func setProjectCmdFunc() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s가 수정되었습니다.", project.ID),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityProject,
+		EntityID:   project.ID,
+		Changes:    diffEntityFunc(before, project),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
 func searchProjectCmdFunc() {
@@ -395,6 +432,17 @@ func updateProjectCmdFunc() {
 		if err != nil {
 			log.Fatal(err)
 		}
+		err = addLogsFunc(client, Log{
+			UserID:     cmdActorFunc(),
+			CreatedAt:  time.Now(),
+			Content:    fmt.Sprintf("프로젝트 %s가 새로운 구조로 업데이트되었습니다.", project.ID),
+			Action:     LogActionUpdate,
+			EntityType: LogEntityProject,
+			EntityID:   project.ID,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
 
 		log.Print(project.ID, "  updated")
 	}
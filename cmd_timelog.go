@@ -57,6 +57,18 @@ func addTimelogCmdFunc() {
 	}
 
 	err = addTimelogFunc(client, t)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s의 %04d년 %02d월 %s 프로젝트 타임로그가 추가되었습니다.", t.UserID, t.Year, t.Month, t.Project),
+		Action:     LogActionCreate,
+		EntityType: LogEntityTimelog,
+		EntityID:   fmt.Sprintf("%04d-%02d", t.Year, t.Month),
+	})
 	if err != nil {
 		log.Print(err)
 	}
@@ -224,7 +236,28 @@ func updateTimelogCmdFunc() {
 		log.Fatal(err)
 	}
 
+	content := "VFX 타임로그를 업데이트하였습니다."
 	if updateErr != "" {
+		content = "VFX 타임로그 업데이트 중 에러가 발생하였습니다.\n" + updateErr
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    content,
+		Action:     LogActionSync,
+		EntityType: LogEntityTimelog,
+		EntityID:   thisdate,
+	})
+	if err != nil {
+		log.Print(err)
+	}
+
+	if updateErr != "" {
+		// 타임로그 업데이트 실패 알림을 구독한 사용자들에게 알린다.
+		err = addTimelogSyncFailedNotificationFunc(client, updateErr)
+		if err != nil {
+			log.Print(err)
+		}
 		log.Fatal(updateErr)
 	}
 }
@@ -271,6 +304,18 @@ func rmTimelogCmdFunc() {
 	}
 
 	err = rmTimelogFunc(client, t)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s의 %04d년 %02d월 %s 프로젝트 타임로그가 삭제되었습니다.", t.UserID, t.Year, t.Month, t.Project),
+		Action:     LogActionDelete,
+		EntityType: LogEntityTimelog,
+		EntityID:   fmt.Sprintf("%04d-%02d", t.Year, t.Month),
+	})
 	if err != nil {
 		log.Print(err)
 	}
@@ -319,6 +364,18 @@ func subTimelogCmdFunc() {
 	}
 
 	err = subTimelogFunc(client, t)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s의 %04d년 %02d월 %s 프로젝트 타임로그에서 %.1f시간이 차감되었습니다.", t.UserID, t.Year, t.Month, t.Project, t.Duration),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityTimelog,
+		EntityID:   fmt.Sprintf("%04d-%02d", t.Year, t.Month),
+	})
 	if err != nil {
 		log.Print(err)
 	}
@@ -529,6 +586,22 @@ func resetAllTimelogCmdFunc() {
 		log.Fatal(err)
 	}
 
+	content := "모든 VFX 타임로그를 리셋하였습니다."
+	if updateErr != "" {
+		content = "모든 VFX 타임로그 리셋 중 에러가 발생하였습니다.\n" + updateErr
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    content,
+		Action:     LogActionSync,
+		EntityType: LogEntityTimelog,
+		EntityID:   "",
+	})
+	if err != nil {
+		log.Print(err)
+	}
+
 	if updateErr != "" {
 		log.Fatal(updateErr)
 	}
@@ -6,7 +6,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,7 +19,7 @@ import (
 func addUserCmdFunc() {
 	u := User{}
 
-	u.ID = *flagID
+	u.ID = strings.ToLower(*flagID) // 로그인할 때 ID를 소문자로 바꿔서 찾는다.
 	u.Password = *flagPW
 	u.Name = *flagName
 	u.Team = *flagTeam
@@ -73,4 +75,17 @@ func addUserCmdFunc() {
 		log.Print(err)
 		return
 	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("유저 %s가 추가되었습니다.", u.ID),
+		Action:     LogActionCreate,
+		EntityType: LogEntityUser,
+		EntityID:   u.ID,
+		Changes:    diffEntityFunc(User{}, u),
+	})
+	if err != nil {
+		log.Print(err)
+		return
+	}
 }
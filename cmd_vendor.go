@@ -109,7 +109,20 @@ func addVendorCmdFunc() {
 	}
 
 	// 벤더 정보를 DB에 추가한다.
-	err = addVendorFunc(client, v)
+	vendorID, err := addVendorFunc(client, v)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s에 벤더 %s가 추가되었습니다.", v.Project, v.Name),
+		Action:     LogActionCreate,
+		EntityType: LogEntityVendor,
+		EntityID:   vendorID.Hex(),
+		Changes:    diffEntityFunc(Vendor{}, v),
+	})
 	if err != nil {
 		log.Print(err)
 		return
@@ -164,6 +177,18 @@ func rmVendorCmdFunc() {
 		log.Print(err)
 		return
 	}
+	err = addLogsFunc(client, Log{
+		UserID:     cmdActorFunc(),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s에 벤더 %s가 삭제되었습니다.", project, name),
+		Action:     LogActionDelete,
+		EntityType: LogEntityVendor,
+		EntityID:   project + "/" + name,
+	})
+	if err != nil {
+		log.Print(err)
+		return
+	}
 }
 
 // searchVendorCmdFunc 함수는 cmd에서 Vendor를 검색하는 함수이다.
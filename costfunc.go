@@ -133,3 +133,15 @@ func calMonthlyCMLaborCostFunc(projectID string, year int, month int) (int, erro
 
 	return int(math.Round(monthlyCMLaborCost)), nil
 }
+
+// decryptToIntFunc 함수는 AES 256으로 암호화된 금액을 복호화하여 정수로 반환하는 함수이다. 빈 문자열은 0으로 처리한다.
+func decryptToIntFunc(cipherText string) (int, error) {
+	decrypted, err := decryptAES256Func(cipherText)
+	if err != nil {
+		return 0, err
+	}
+	if decrypted == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(decrypted)
+}
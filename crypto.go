@@ -8,15 +8,15 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/pem"
 	"io/ioutil"
-	"net/mail"
 	"os"
 	"os/user"
 	"path"
-	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -123,6 +123,18 @@ func readKEYFileFunc() ([]byte, error) {
 	return block.Bytes, nil
 }
 
+// sessionSignKeyFunc 함수는 로그인 세션 토큰(JWT)을 서명할 때 사용하는 서버 키를 반환하는 함수이다.
+// .key 파일의 키에서 만들기 때문에 키가 브라우저로 전달되지 않고, key 파일을 바꾸면 모든 세션이 무효가 된다.
+func sessionSignKeyFunc() ([]byte, error) {
+	key, err := readKEYFileFunc()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("budget session"))
+	return mac.Sum(nil), nil
+}
+
 // encryptAES256Func 함수는 문자열을 입력받아 AES 256 암호화 기법으로 암호화하는 함수이다.
 func encryptAES256Func(s string) (string, error) {
 	key, err := readKEYFileFunc()
@@ -200,9 +212,3 @@ func PKCS5UnPadding(orig []byte) []byte {
 	unpadding := int(orig[length-1])
 	return orig[:(length - unpadding)]
 }
-
-// encodeRFC2047Func 함수는 메일 제목 변경하는 함수이다.
-func encodeRFC2047Func(s string) string {
-	addr := mail.Address{s, ""}
-	return strings.Trim(addr.String(), "<@>")
-}
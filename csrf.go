@@ -0,0 +1,195 @@
+// 프로젝트 결산 프로그램
+//
+// Description : CSRF 방어와 쿠키 속성 관련 스크립트
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	CSRFCookieName = "CSRFToken"    // CSRF 토큰을 담는 쿠키 이름, budget 스크립트가 읽어서 form에 넣어야 하므로 HttpOnly를 쓰지 않는다.
+	CSRFFieldName  = "csrf_token"   // form으로 CSRF 토큰을 보낼 때 사용하는 필드 이름
+	CSRFHeaderName = "X-CSRF-Token" // ajax로 CSRF 토큰을 보낼 때 사용하는 헤더 이름
+)
+
+// csrfPreviewRoutes 는 이름이 -submit으로 끝나지만 업로드한 엑셀 파일을 확인만 하는 URL이다. 링크로 열리므로 GET을 허용한다.
+var csrfPreviewRoutes = map[string]bool{
+	"/timelogvfxexcel-submit": true,
+	"/timelogcmexcel-submit":  true,
+	"/artistsvfxexcel-submit": true,
+	"/artistscmexcel-submit":  true,
+	"/shotexcel-submit":       true,
+	"/assetexcel-submit":      true,
+}
+
+// csrfPostRoutes 는 이름이 -submit으로 끝나지 않지만 서버의 상태를 바꾸는 URL이다. -submit URL처럼 POST로만 요청할 수 있다.
+var csrfPostRoutes = map[string]bool{
+	"/signout":                true,
+	"/addapitoken":            true,
+	"/rmapitoken":             true,
+	"/enabletotp":             true,
+	"/disabletotp":            true,
+	"/episode-timelog-sync":   true,
+	"/upload-timelogvfxexcel": true,
+	"/upload-timelogcmexcel":  true,
+	"/update-users":           true,
+	"/update-roles":           true,
+	"/rmsession":              true,
+	"/rmsessions":             true,
+	"/unlockuser":             true,
+	"/resettotp":              true,
+	"/upload-artistsexcel":    true,
+	"/bgrevision-restore":     true,
+	"/upload-shotexcel":       true,
+	"/upload-assetexcel":      true,
+}
+
+// isStateChangingRouteFunc 함수는 서버의 상태를 바꾸는 URL인지 확인하는 함수이다.
+func isStateChangingRouteFunc(path string) bool {
+	if csrfPreviewRoutes[path] {
+		return false
+	}
+	return strings.HasSuffix(path, "-submit") || csrfPostRoutes[path]
+}
+
+// cookieSameSiteFunc 함수는 -cookiesamesite 값을 http.SameSite로 바꾸는 함수이다.
+func cookieSameSiteFunc(s string) (http.SameSite, error) {
+	switch strings.ToLower(s) {
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+	return http.SameSiteDefaultMode, errors.New("cookiesamesite 값은 lax, strict, none 중 하나여야 합니다")
+}
+
+// checkCookieFlagsFunc 함수는 쿠키 관련 옵션이 올바른지 확인하는 함수이다.
+func checkCookieFlagsFunc() error {
+	sameSite, err := cookieSameSiteFunc(*flagCookieSameSite)
+	if err != nil {
+		return err
+	}
+	if sameSite == http.SameSiteNoneMode && !*flagCookieSecure {
+		return errors.New("cookiesamesite가 none이면 cookiesecure를 켜야 합니다")
+	}
+	return nil
+}
+
+// newCookieFunc 함수는 -cookiesecure, -cookiesamesite 옵션을 적용한 쿠키를 만드는 함수이다.
+// expires가 비어있으면 브라우저를 닫을 때 지워지는 쿠키가 되고, maxAge가 음수이면 쿠키를 삭제한다.
+func newCookieFunc(name, value string, expires time.Time, maxAge int, httpOnly bool) *http.Cookie {
+	sameSite, err := cookieSameSiteFunc(*flagCookieSameSite)
+	if err != nil {
+		sameSite = http.SameSiteLaxMode
+	}
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		MaxAge:   maxAge,
+		HttpOnly: httpOnly,
+		Secure:   *flagCookieSecure,
+		SameSite: sameSite,
+	}
+}
+
+// csrfTokenFunc 함수는 세션 ID를 서버 키로 서명해서 세션별 CSRF 토큰을 만드는 함수이다.
+func csrfTokenFunc(sessionID string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("csrf:" + sessionID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// setCSRFCookieFunc 함수는 CSRF 토큰을 쿠키에 저장하는 함수이다.
+func setCSRFCookieFunc(w http.ResponseWriter, token string) {
+	http.SetCookie(w, newCookieFunc(CSRFCookieName, token, time.Time{}, 0, false))
+}
+
+// expectedCSRFTokenFunc 함수는 요청에 맞는 CSRF 토큰을 반환하는 함수이다.
+// 로그인한 요청은 세션 ID로 만든 토큰이고, 로그인 전 요청(로그인, 회원가입)은 쿠키에 저장된 임의의 토큰이다.
+func expectedCSRFTokenFunc(r *http.Request) (string, bool) {
+	key, err := sessionSignKeyFunc()
+	if err == nil {
+		tk, err := parseSessionTokenFunc(getSessionTokenFromCookieFunc(r), key)
+		if err == nil {
+			return csrfTokenFunc(tk.Id, key), true
+		}
+	}
+	cookie, err := r.Cookie(CSRFCookieName)
+	if err != nil || len(cookie.Value) < 32 {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// checkCSRFTokenFunc 함수는 요청에 들어있는 CSRF 토큰이 맞는지 확인하는 함수이다. 헤더를 먼저 보고 없으면 form 값을 본다.
+func checkCSRFTokenFunc(r *http.Request, expected string) bool {
+	got := r.Header.Get(CSRFHeaderName)
+	if got == "" {
+		got = r.PostFormValue(CSRFFieldName)
+	}
+	if got == "" || expected == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(expected)) == 1
+}
+
+// isSafeMethodFunc 함수는 서버의 상태를 바꾸지 않는 메소드인지 확인하는 함수이다.
+func isSafeMethodFunc(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// csrfMiddlewareFunc 미들웨어는 웹 페이지의 상태를 바꾸는 요청에 CSRF 토큰이 있는지 확인한다.
+// restAPI는 쿠키가 아니라 Authorization 헤더의 토큰으로 인증하므로 확인하지 않는다.
+// 상태를 바꾸는 URL(-submit으로 끝나는 URL과 csrfPostRoutes)은 POST로만 요청할 수 있으므로 항상 토큰을 확인한다.
+func csrfMiddlewareFunc(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/assets/") || strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		expected, ok := expectedCSRFTokenFunc(r)
+		if isSafeMethodFunc(r.Method) {
+			if isStateChangingRouteFunc(r.URL.Path) {
+				http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+				return
+			}
+			// 쿠키에 CSRF 토큰이 없거나 세션이 바뀌었으면 새 토큰을 쿠키에 저장한다.
+			if !ok {
+				id, err := newSessionIDFunc()
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				expected = id
+			}
+			cookie, err := r.Cookie(CSRFCookieName)
+			if err != nil || cookie.Value != expected {
+				setCSRFCookieFunc(w, expected)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !ok || !checkCSRFTokenFunc(r, expected) {
+			http.Error(w, "CSRF 토큰이 없거나 일치하지 않습니다. 페이지를 새로고침한 뒤 다시 시도해주세요", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,74 @@
+// 프로젝트 결산 프로그램
+//
+// Description : CSRF 방어 테스트 스크립트
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// 상태를 바꾸는 요청에 CSRF 토큰이 있어야 하는지 테스트하기 위한 함수
+func Test_csrfMiddleware(t *testing.T) {
+	handler := csrfMiddlewareFunc(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// 로그인 전 페이지를 열면 CSRF 토큰을 쿠키에 저장한다.
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/signin", nil))
+	var csrf *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == CSRFCookieName {
+			csrf = c
+		}
+	}
+	if csrf == nil || csrf.Value == "" || csrf.HttpOnly || csrf.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("Test_csrfMiddleware(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "GET /signin", "CSRFToken 쿠키", csrf)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		field  string
+		header string
+		cookie bool
+		want   int
+	}{
+		{name: "form token", method: http.MethodPost, path: "/signin-submit", field: csrf.Value, cookie: true, want: http.StatusOK},
+		{name: "header token", method: http.MethodPost, path: "/upload-shotexcel", header: csrf.Value, cookie: true, want: http.StatusOK},
+		{name: "no token", method: http.MethodPost, path: "/signin-submit", cookie: true, want: http.StatusForbidden},
+		{name: "wrong token", method: http.MethodPost, path: "/signin-submit", field: "x" + csrf.Value[1:], cookie: true, want: http.StatusForbidden},
+		{name: "no cookie", method: http.MethodPost, path: "/signin-submit", field: csrf.Value, want: http.StatusForbidden},
+		{name: "get submit", method: http.MethodGet, path: "/editprofile-submit", cookie: true, want: http.StatusMethodNotAllowed},
+		{name: "get update-users", method: http.MethodGet, path: "/update-users", cookie: true, want: http.StatusMethodNotAllowed},
+		{name: "post update-users without token", method: http.MethodPost, path: "/update-users", cookie: true, want: http.StatusForbidden},
+		{name: "get signout", method: http.MethodGet, path: "/signout", cookie: true, want: http.StatusMethodNotAllowed},
+		{name: "get preview", method: http.MethodGet, path: "/shotexcel-submit", cookie: true, want: http.StatusOK},
+		{name: "restapi", method: http.MethodPost, path: "/api/rmuser", want: http.StatusOK},
+	}
+	for _, c := range cases {
+		form := url.Values{}
+		if c.field != "" {
+			form.Set(CSRFFieldName, c.field)
+		}
+		r := httptest.NewRequest(c.method, c.path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if c.header != "" {
+			r.Header.Set(CSRFHeaderName, c.header)
+		}
+		if c.cookie {
+			r.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: csrf.Value})
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != c.want {
+			t.Fatalf("Test_csrfMiddleware(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.name, c.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB API Token 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addAPITokenFunc 함수는 DB에 API 토큰을 추가하는 함수이다.
+func addAPITokenFunc(client *mongo.Client, t APIToken) error {
+	collection := client.Database(*flagDBName).Collection("apitokens")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.InsertOne(ctx, t)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getAPITokenByHashFunc 함수는 DB에서 토큰 원문의 해시값으로 API 토큰을 가져오는 함수이다.
+func getAPITokenByHashFunc(client *mongo.Client, hash string) (APIToken, error) {
+	collection := client.Database(*flagDBName).Collection("apitokens")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var result APIToken
+	err := collection.FindOne(ctx, bson.M{"hash": hash}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getAPITokensFunc 함수는 DB에서 사용자의 API 토큰을 최근 순으로 가져오는 함수이다.
+func getAPITokensFunc(client *mongo.Client, userID string) ([]APIToken, error) {
+	collection := client.Database(*flagDBName).Collection("apitokens")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var results []APIToken
+	opts := options.Find().SetSort(bson.M{"createdat": -1})
+	cursor, err := collection.Find(ctx, bson.M{"userid": userID}, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// setAPITokenLastUsedFunc 함수는 DB에 API 토큰을 마지막으로 사용한 시간을 저장하는 함수이다.
+func setAPITokenLastUsedFunc(client *mongo.Client, id string, t time.Time) error {
+	collection := client.Database(*flagDBName).Collection("apitokens")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.UpdateOne(ctx, bson.M{"id": id}, bson.M{"$set": bson.M{"lastusedat": t}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// rmAPITokenFunc 함수는 DB에서 사용자의 API 토큰을 삭제하는 함수이다.
+func rmAPITokenFunc(client *mongo.Client, userID string, id string) error {
+	collection := client.Database(*flagDBName).Collection("apitokens")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.DeleteOne(ctx, bson.M{"userid": userID, "id": id})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// rmAPITokensFunc 함수는 DB에서 사용자의 모든 API 토큰을 삭제하는 함수이다.
+func rmAPITokensFunc(client *mongo.Client, userID string) error {
+	collection := client.Database(*flagDBName).Collection("apitokens")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.DeleteMany(ctx, bson.M{"userid": userID})
+	if err != nil {
+		return err
+	}
+	return nil
+}
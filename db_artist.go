@@ -211,7 +211,15 @@ func setArtistFunc(client *mongo.Client, artist Artist) error {
 		artist.Resination = false
 	}
 
-	_, err := collection.UpdateOne(
+	// 팀이 바뀌었으면 이전 팀을 팀 변경 기록에 남긴다.
+	var before Artist
+	err := collection.FindOne(ctx, bson.M{"id": artist.ID}).Decode(&before)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	addArtistTeamHistoryFunc(before, &artist, time.Now().Format("2006-01-02"))
+
+	_, err = collection.UpdateOne(
 		ctx,
 		bson.M{"id": artist.ID},
 		bson.D{{Key: "$set", Value: artist}},
@@ -253,7 +261,8 @@ func updateArtistFunc(client *mongo.Client, artist Artist) error {
 		return err
 	}
 
-	// 아티스트가 존재하면 Update한다.
+	// 아티스트가 존재하면 팀 변경 기록을 남기고 Update한다.
+	addArtistTeamHistoryFunc(a, &artist, time.Now().Format("2006-01-02"))
 	_, err = collection.UpdateOne(
 		ctx,
 		bson.M{"id": artist.ID},
@@ -0,0 +1,152 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB 예산안 리비전 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addBGRevisionsFunc 함수는 예산 프로젝트의 예산안별로 리비전을 추가하는 함수이다.
+// 마지막 리비전과 예산안 데이터가 같으면 리비전을 추가하지 않는다.
+func addBGRevisionsFunc(client *mongo.Client, bgp BGProject, userID string, action string) error {
+	collection := client.Database(*flagDBName).Collection("bgrevisions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now().Format(time.RFC3339)
+	for _, bgtype := range bgp.TypeList {
+		typedata, ok := bgp.TypeData[bgtype]
+		if !ok {
+			continue
+		}
+		typeID := getBGTypeIDFunc(typedata, bgtype)
+
+		var last BGRevision
+		opts := options.FindOne().SetSort(bson.M{"revision": -1})
+		err := collection.FindOne(ctx, bson.M{"projectid": bgp.ID, "typeid": typeID}, opts).Decode(&last)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+		if err == nil {
+			same, err := equalBGTypeDataFunc(last.TypeData, typedata)
+			if err != nil {
+				return err
+			}
+			if same && last.BGType == bgtype {
+				continue
+			}
+		}
+
+		rev := BGRevision{
+			ProjectID:   bgp.ID,
+			BGType:      bgtype,
+			TypeID:      typeID,
+			Revision:    last.Revision + 1,
+			TypeData:    typedata,
+			Action:      action,
+			UserID:      userID,
+			CreatedTime: now,
+		}
+		_, err = collection.InsertOne(ctx, rev)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getBGTypeIDFunc 함수는 리비전에서 예산안을 구분하기 위한 ID를 반환하는 함수이다. 고유 ID가 없는 예산안은 이름을 사용한다.
+func getBGTypeIDFunc(typedata BGTypeData, bgtype string) string {
+	if typedata.ID.IsZero() {
+		return bgtype
+	}
+	return typedata.ID.Hex()
+}
+
+// equalBGTypeDataFunc 함수는 DB에 저장된 예산안 데이터와 현재 예산안 데이터가 같은지 확인하는 함수이다.
+// 빈 리스트와 nil을 같게 비교하기 위해 현재 데이터를 DB에 저장되는 형태로 변환한 후 비교한다.
+func equalBGTypeDataFunc(saved BGTypeData, typedata BGTypeData) (bool, error) {
+	data, err := bson.Marshal(typedata)
+	if err != nil {
+		return false, err
+	}
+	var converted BGTypeData
+	err = bson.Unmarshal(data, &converted)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(saved, converted), nil
+}
+
+// getBGRevisionFunc 함수는 DB에서 id가 일치하는 리비전을 가져오는 함수이다.
+func getBGRevisionFunc(client *mongo.Client, id string) (BGRevision, error) {
+	collection := client.Database(*flagDBName).Collection("bgrevisions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result BGRevision
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return result, err
+	}
+	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getBGRevisionsFunc 함수는 DB에서 예산 프로젝트의 리비전을 최신순으로 가져오는 함수이다.
+func getBGRevisionsFunc(client *mongo.Client, projectID string) ([]BGRevision, error) {
+	collection := client.Database(*flagDBName).Collection("bgrevisions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var results []BGRevision
+	opts := options.Find()
+	opts.SetSort(bson.D{{Key: "createdtime", Value: -1}, {Key: "revision", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"projectid": projectID}, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// renameBGRevisionsFunc 함수는 예산 프로젝트 ID가 바뀌었을 때 리비전의 프로젝트 ID를 함께 바꾸는 함수이다.
+func renameBGRevisionsFunc(client *mongo.Client, oldID string, newID string) error {
+	collection := client.Database(*flagDBName).Collection("bgrevisions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateMany(ctx, bson.M{"projectid": oldID}, bson.M{"$set": bson.M{"projectid": newID}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// rmBGRevisionsFunc 함수는 DB에서 예산 프로젝트의 리비전을 모두 삭제하는 함수이다.
+func rmBGRevisionsFunc(client *mongo.Client, projectID string) error {
+	collection := client.Database(*flagDBName).Collection("bgrevisions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx, bson.M{"projectid": projectID})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // getBGTeamSettingFunc 함수는 DB에서 예산 Team Setting 정보를 가져오는 함수이다.
@@ -55,3 +56,94 @@ func setBGTeamSettingFunc(client *mongo.Client, ts BGTeamSetting) error {
 	}
 	return nil
 }
+
+// addBGTeamSettingVersionFunc 함수는 팀세팅을 새 버전으로 저장하고 버전 기록을 남기는 함수이다.
+// 현재 팀세팅과 구조가 같으면 버전을 올리지 않고 결재자 등 나머지 정보만 업데이트한다.
+// 버전 기록이 없을 때 기존 팀세팅이 있으면 기존 팀세팅을 먼저 1 버전으로 기록한다.
+func addBGTeamSettingVersionFunc(client *mongo.Client, ts BGTeamSetting, userID string, note string) (BGTeamSetting, error) {
+	collection := client.Database(*flagDBName).Collection("bgteamsettings")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		return ts, err
+	}
+	now := time.Now().Format(time.RFC3339)
+	if len(current.Headquarters) != 0 && equalBGTeamSettingFunc(current, ts) {
+		ts.Version = current.Version
+		ts.UpdatedTime = now
+		return ts, setBGTeamSettingFunc(client, ts)
+	}
+
+	var last BGTeamSettingVersion
+	opts := options.FindOne().SetSort(bson.M{"version": -1})
+	err = collection.FindOne(ctx, bson.M{}, opts).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return ts, err
+	}
+	if err == mongo.ErrNoDocuments && len(current.Headquarters) != 0 {
+		current.Version = 1
+		last = BGTeamSettingVersion{
+			Version:     1,
+			TeamSetting: current,
+			Note:        "버전 관리 전 팀세팅",
+			CreatedTime: current.UpdatedTime,
+		}
+		_, err = collection.InsertOne(ctx, last)
+		if err != nil {
+			return ts, err
+		}
+	}
+
+	ts.Version = last.Version + 1
+	ts.UpdatedTime = now
+	_, err = collection.InsertOne(ctx, BGTeamSettingVersion{
+		Version:     ts.Version,
+		TeamSetting: ts,
+		Note:        note,
+		UserID:      userID,
+		CreatedTime: now,
+	})
+	if err != nil {
+		return ts, err
+	}
+	err = setBGTeamSettingFunc(client, ts)
+	if err != nil {
+		return ts, err
+	}
+	return ts, nil
+}
+
+// getBGTeamSettingVersionFunc 함수는 DB에서 버전이 일치하는 팀세팅 버전을 가져오는 함수이다.
+func getBGTeamSettingVersionFunc(client *mongo.Client, version int) (BGTeamSettingVersion, error) {
+	collection := client.Database(*flagDBName).Collection("bgteamsettings")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result BGTeamSettingVersion
+	err := collection.FindOne(ctx, bson.M{"version": version}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getBGTeamSettingVersionsFunc 함수는 DB에서 팀세팅 버전 기록을 최신순으로 가져오는 함수이다.
+func getBGTeamSettingVersionsFunc(client *mongo.Client) ([]BGTeamSettingVersion, error) {
+	collection := client.Database(*flagDBName).Collection("bgteamsettings")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var results []BGTeamSettingVersion
+	opts := options.Find().SetSort(bson.M{"version": -1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
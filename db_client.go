@@ -0,0 +1,203 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB 클라이언트(제작사, 감독) 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addClientFunc 함수는 DB에 클라이언트를 추가하고 추가된 클라이언트의 ID를 반환하는 함수이다.
+func addClientFunc(client *mongo.Client, c Client) (primitive.ObjectID, error) {
+	collection := client.Database(*flagDBName).Collection("clients")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 같은 타입에 같은 이름의 클라이언트가 존재하는지 확인한다.
+	n, err := collection.CountDocuments(ctx, bson.M{"type": c.Type, "name": c.Name})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if n != 0 {
+		return primitive.NilObjectID, fmt.Errorf("%s 이름을 가진 클라이언트가 이미 존재합니다", c.Name)
+	}
+
+	c.UpdatedTime = time.Now().Format(time.RFC3339)
+	result, err := collection.InsertOne(ctx, c)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// getClientFunc 함수는 DB에서 id가 일치하는 클라이언트를 가져오는 함수이다.
+func getClientFunc(client *mongo.Client, id string) (Client, error) {
+	collection := client.Database(*flagDBName).Collection("clients")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result Client
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return result, err
+	}
+	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getClientByNameFunc 함수는 DB에서 타입과 이름이 일치하는 클라이언트를 가져오는 함수이다.
+func getClientByNameFunc(client *mongo.Client, typ string, name string) (Client, error) {
+	collection := client.Database(*flagDBName).Collection("clients")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result Client
+	err := collection.FindOne(ctx, bson.M{"type": typ, "name": name}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getClientsFunc 함수는 DB에서 해당 타입의 모든 클라이언트를 가져오는 함수이다.
+func getClientsFunc(client *mongo.Client, typ string) ([]Client, error) {
+	collection := client.Database(*flagDBName).Collection("clients")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var results []Client
+	opts := options.Find()
+	opts.SetSort(bson.M{"name": 1}) // 이름을 기준으로 오름차순 정렬
+	cursor, err := collection.Find(ctx, bson.M{"type": typ}, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// searchClientFunc 함수는 DB에서 해당 타입의 클라이언트를 검색어로 검색하는 함수이다.
+func searchClientFunc(client *mongo.Client, typ string, searchWord string) ([]Client, error) {
+	if searchWord == "" {
+		return getClientsFunc(client, typ)
+	}
+	collection := client.Database(*flagDBName).Collection("clients")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wordQueries := []bson.M{bson.M{"type": typ}}
+	for _, word := range strings.Split(searchWord, " ") {
+		if word == "" {
+			continue
+		}
+		querys := []bson.M{}
+		querys = append(querys, bson.M{"name": primitive.Regex{Pattern: word, Options: "i"}})
+		querys = append(querys, bson.M{"contacts.name": primitive.Regex{Pattern: word, Options: "i"}})
+		querys = append(querys, bson.M{"note": primitive.Regex{Pattern: word, Options: "i"}})
+		wordQueries = append(wordQueries, bson.M{"$or": querys})
+	}
+
+	var results []Client
+	opts := options.Find()
+	opts.SetSort(bson.M{"name": 1})
+	cursor, err := collection.Find(ctx, bson.M{"$and": wordQueries}, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// setClientFunc 함수는 DB에서 클라이언트 정보를 업데이트하는 함수이다.
+func setClientFunc(client *mongo.Client, c Client) error {
+	collection := client.Database(*flagDBName).Collection("clients")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c.UpdatedTime = time.Now().Format(time.RFC3339)
+	_, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": c.ID},
+		bson.D{{Key: "$set", Value: c}},
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// rmClientFunc 함수는 DB에서 클라이언트를 삭제하는 함수이다. 연결된 프로젝트가 있으면 삭제하지 않는다.
+func rmClientFunc(client *mongo.Client, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 클라이언트와 연결된 프로젝트가 있는지 확인한다.
+	n, err := client.Database(*flagDBName).Collection("projects").CountDocuments(ctx, bson.M{"$or": []bson.M{
+		bson.M{"producerid": id},
+		bson.M{"directorid": id},
+	}})
+	if err != nil {
+		return err
+	}
+	if n != 0 {
+		return errors.New("클라이언트와 연결된 프로젝트가 존재하여 삭제할 수 없습니다")
+	}
+
+	collection := client.Database(*flagDBName).Collection("clients")
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("삭제할 클라이언트가 없습니다")
+	}
+	return nil
+}
+
+// getProjectsByClientFunc 함수는 DB에서 클라이언트와 연결된 결산 프로젝트를 가져오는 함수이다.
+func getProjectsByClientFunc(client *mongo.Client, c Client) ([]Project, error) {
+	collection := client.Database(*flagDBName).Collection("projects")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := "producerid"
+	if c.Type == ClientTypeDirector {
+		key = "directorid"
+	}
+
+	var results []Project
+	opts := options.Find()
+	opts.SetSort(bson.M{"startdate": -1}) // 최근 프로젝트부터 정렬
+	cursor, err := collection.Find(ctx, bson.M{key: c.ID.Hex()}, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
@@ -0,0 +1,58 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB 에피소드별 타임로그 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// setEpisodeTimelogsFunc 함수는 프로젝트의 에피소드별 타임로그를 모두 지우고 새로 저장하는 함수이다.
+func setEpisodeTimelogsFunc(client *mongo.Client, project string, timelogs []EpisodeTimelog) error {
+	collection := client.Database(*flagDBName).Collection("episodetimelogs")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	project = strings.ToUpper(project)
+	_, err := collection.DeleteMany(ctx, bson.M{"project": project})
+	if err != nil {
+		return err
+	}
+	if len(timelogs) == 0 {
+		return nil
+	}
+	var docs []interface{}
+	for _, t := range timelogs {
+		t.Project = project
+		docs = append(docs, t)
+	}
+	_, err = collection.InsertMany(ctx, docs)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getEpisodeTimelogsFunc 함수는 DB에서 프로젝트의 에피소드별 타임로그를 가져오는 함수이다.
+func getEpisodeTimelogsFunc(client *mongo.Client, project string) ([]EpisodeTimelog, error) {
+	collection := client.Database(*flagDBName).Collection("episodetimelogs")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var results []EpisodeTimelog
+	cursor, err := collection.Find(ctx, bson.M{"project": strings.ToUpper(project)})
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
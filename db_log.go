@@ -6,11 +6,14 @@ package main
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -27,15 +30,57 @@ func addLogsFunc(client *mongo.Client, log Log) error {
 	return nil
 }
 
-// SearchLogsFunc 함수는 로그 데이터를 반환한다.
-func SearchLogsFunc(client *mongo.Client, page int64, limitnum int64) (int64, int64, []Log, error) {
+// logFilterQueryFunc 함수는 로그 검색 조건을 DB 쿼리로 바꾸는 함수이다.
+func logFilterQueryFunc(f LogFilter) bson.M {
+	q := bson.M{}
+	if f.UserID != "" {
+		q["userid"] = f.UserID
+	}
+	if f.EntityType != "" {
+		q["entitytype"] = f.EntityType
+	}
+	if f.EntityID != "" {
+		q["entityid"] = f.EntityID
+	}
+	if f.Action != "" {
+		q["action"] = f.Action
+	}
+	date := bson.M{}
+	if !f.Start.IsZero() {
+		date["$gte"] = f.Start
+	}
+	if !f.End.IsZero() {
+		date["$lt"] = f.End
+	}
+	if len(date) != 0 {
+		q["created_at"] = date
+	}
+	// 검색어는 띄어쓰기로 구분하고, 모든 단어가 로그 내용, 유저 ID, 대상 ID, 바뀐 항목 중 한 곳에 들어간 로그를 찾는다.
+	wordQueries := []bson.M{}
+	for _, word := range strings.Fields(f.Keyword) {
+		pattern := regexp.QuoteMeta(word)
+		wordQueries = append(wordQueries, bson.M{"$or": []bson.M{
+			{"content": primitive.Regex{Pattern: pattern, Options: "i"}},
+			{"userid": primitive.Regex{Pattern: pattern, Options: "i"}},
+			{"entityid": primitive.Regex{Pattern: pattern, Options: "i"}},
+			{"changes.field": primitive.Regex{Pattern: pattern, Options: "i"}},
+		}})
+	}
+	if len(wordQueries) != 0 {
+		q["$and"] = wordQueries
+	}
+	return q
+}
+
+// SearchLogsFunc 함수는 검색 조건에 맞는 로그 데이터를 반환한다.
+func SearchLogsFunc(client *mongo.Client, filter LogFilter, page int64, limitnum int64) (int64, int64, []Log, error) {
 	var results []Log
 
 	collection := client.Database(*flagDBName).Collection("logs")
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	q := bson.D{}
+	q := logFilterQueryFunc(filter)
 	opts := options.Find()
 	opts.SetSort(bson.M{"created_at": -1})
 	opts.SetSkip(int64((page - 1) * limitnum))
@@ -54,3 +99,83 @@ func SearchLogsFunc(client *mongo.Client, page int64, limitnum int64) (int64, in
 	}
 	return TotalPageFunc(totalNum, limitnum), totalNum, results, nil
 }
+
+// getLogsFunc 함수는 검색 조건에 맞는 로그를 모두 반환하는 함수이다. CSV로 내보낼 때 사용한다.
+func getLogsFunc(client *mongo.Client, filter LogFilter) ([]Log, error) {
+	var results []Log
+
+	collection := client.Database(*flagDBName).Collection("logs")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	opts := options.Find()
+	opts.SetSort(bson.M{"created_at": -1})
+	cursor, err := collection.Find(ctx, logFilterQueryFunc(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ensureLogIndexesFunc 함수는 로그 페이지의 정렬, 검색과 오래된 로그 삭제에 사용하는 인덱스를 만드는 함수이다.
+// 이미 같은 인덱스가 있으면 아무것도 하지 않는다.
+func ensureLogIndexesFunc(client *mongo.Client) error {
+	collection := client.Database(*flagDBName).Collection("logs")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "entitytype", Value: 1}, {Key: "entityid", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	return err
+}
+
+// forEachLogBeforeFunc 함수는 before 이전의 로그를 오래된 순으로 하나씩 fn에 넘겨주는 함수이다. 넘겨준 로그 개수를 반환한다.
+// 로그가 많을 수 있으므로 한번에 가져오지 않고 cursor로 읽는다.
+func forEachLogBeforeFunc(client *mongo.Client, before time.Time, fn func(Log) error) (int64, error) {
+	collection := client.Database(*flagDBName).Collection("logs")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	opts := options.Find()
+	opts.SetSort(bson.M{"created_at": 1})
+	cursor, err := collection.Find(ctx, bson.M{"created_at": bson.M{"$lt": before}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var num int64
+	for cursor.Next(ctx) {
+		var l Log
+		err = cursor.Decode(&l)
+		if err != nil {
+			return num, err
+		}
+		err = fn(l)
+		if err != nil {
+			return num, err
+		}
+		num++
+	}
+	return num, cursor.Err()
+}
+
+// rmLogsBeforeFunc 함수는 DB에서 before 이전의 로그를 삭제하는 함수이다. 삭제한 로그 개수를 반환한다.
+func rmLogsBeforeFunc(client *mongo.Client, before time.Time) (int64, error) {
+	collection := client.Database(*flagDBName).Collection("logs")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	result, err := collection.DeleteMany(ctx, bson.M{"created_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,72 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB 로그인 기록 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addLoginHistoryFunc 함수는 DB에 로그인 시도 기록을 추가하는 함수이다.
+func addLoginHistoryFunc(client *mongo.Client, h LoginHistory) error {
+	collection := client.Database(*flagDBName).Collection("loginhistory")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.InsertOne(ctx, h)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getLoginHistoryFunc 함수는 DB에서 로그인 시도 기록을 최근 순으로 limit개 가져오는 함수이다. userID가 빈 문자열이면 모든 사용자의 기록을 가져온다.
+func getLoginHistoryFunc(client *mongo.Client, userID string, limit int64) ([]LoginHistory, error) {
+	collection := client.Database(*flagDBName).Collection("loginhistory")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var results []LoginHistory
+	filter := bson.M{}
+	if userID != "" {
+		filter["userid"] = userID
+	}
+	opts := options.Find().SetSort(bson.M{"createdat": -1}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// countFailedLoginsByIPFunc 함수는 DB에서 since 이후에 IP에서 로그인에 실패한 횟수를 가져오는 함수이다.
+func countFailedLoginsByIPFunc(client *mongo.Client, ip string, since time.Time) (int64, error) {
+	collection := client.Database(*flagDBName).Collection("loginhistory")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return collection.CountDocuments(ctx, bson.M{
+		"ip":        ip,
+		"success":   false,
+		"createdat": bson.M{"$gt": since},
+	})
+}
+
+// rmLoginHistoryFunc 함수는 DB에서 before 이전의 로그인 시도 기록을 삭제하는 함수이다.
+func rmLoginHistoryFunc(client *mongo.Client, before time.Time) error {
+	collection := client.Database(*flagDBName).Collection("loginhistory")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.DeleteMany(ctx, bson.M{"createdat": bson.M{"$lt": before}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB 알림 outbox 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addNotificationFunc 함수는 DB의 outbox에 알림을 추가하는 함수이다.
+func addNotificationFunc(client *mongo.Client, n Notification) (primitive.ObjectID, error) {
+	collection := client.Database(*flagDBName).Collection("notifications")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := collection.InsertOne(ctx, n)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// claimNotificationFunc 함수는 DB의 outbox에서 지금 전송해야 하는 알림 하나를 전송중 상태로 바꾸면서 가져오는 함수이다.
+// 여러 곳에서 동시에 전송해도 같은 알림을 두 번 보내지 않도록 findOneAndUpdate로 한 번에 가져간다.
+// 전송중 상태의 알림도 leasetime이 지났으면 전송이 중단된 것으로 보고 다시 가져간다. 전송할 알림이 없으면 mongo.ErrNoDocuments를 반환한다.
+func claimNotificationFunc(client *mongo.Client) (Notification, error) {
+	collection := client.Database(*flagDBName).Collection("notifications")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": NotificationStatusPending, "nexttrytime": bson.M{"$lte": now.Format(time.RFC3339)}},
+			bson.M{"status": NotificationStatusSending, "leasetime": bson.M{"$lte": now.Format(time.RFC3339)}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":    NotificationStatusSending,
+			"leasetime": now.Add(NotificationLeaseDuration).Format(time.RFC3339),
+		},
+	}
+	opts := options.FindOneAndUpdate()
+	opts.SetSort(bson.M{"createdtime": 1}) // 먼저 생성된 알림부터 전송
+	opts.SetReturnDocument(options.After)
+
+	var result Notification
+	err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// setNotificationFunc 함수는 DB의 outbox에서 알림의 전송 상태를 업데이트하는 함수이다.
+func setNotificationFunc(client *mongo.Client, n Notification) error {
+	collection := client.Database(*flagDBName).Collection("notifications")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": n.ID},
+		bson.D{{Key: "$set", Value: n}},
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
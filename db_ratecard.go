@@ -0,0 +1,142 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB 단가표 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addRateCardFunc 함수는 DB에 단가표를 추가하는 함수이다. 버전은 같은 본부, 연도의 마지막 버전 다음 번호로 정해진다.
+func addRateCardFunc(client *mongo.Client, rc RateCard) (primitive.ObjectID, error) {
+	collection := client.Database(*flagDBName).Collection("ratecards")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var last RateCard
+	opts := options.FindOne().SetSort(bson.M{"version": -1})
+	err := collection.FindOne(ctx, bson.M{"headquarter": rc.Headquarter, "year": rc.Year}, opts).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return primitive.NilObjectID, err
+	}
+	rc.Version = last.Version + 1
+	rc.CreatedTime = time.Now().Format(time.RFC3339)
+
+	result, err := collection.InsertOne(ctx, rc)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// getRateCardFunc 함수는 DB에서 id가 일치하는 단가표를 가져오는 함수이다.
+func getRateCardFunc(client *mongo.Client, id string) (RateCard, error) {
+	collection := client.Database(*flagDBName).Collection("ratecards")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result RateCard
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return result, err
+	}
+	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getRateCardsFunc 함수는 DB에서 본부의 단가표를 가져오는 함수이다. 본부가 빈 문자열이면 모든 단가표를 가져온다.
+func getRateCardsFunc(client *mongo.Client, head string) ([]RateCard, error) {
+	collection := client.Database(*flagDBName).Collection("ratecards")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := bson.M{}
+	if head != "" {
+		q["headquarter"] = head
+	}
+	var results []RateCard
+	opts := options.Find()
+	opts.SetSort(bson.D{{Key: "headquarter", Value: 1}, {Key: "effectivedate", Value: -1}, {Key: "version", Value: -1}}) // 본부별 최신 단가표 순서로 정렬
+	cursor, err := collection.Find(ctx, q, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// getEffectiveRateCardFunc 함수는 DB에서 날짜에 적용되는 본부의 단가표를 가져오는 함수이다.
+// 적용 시작일이 날짜 이전인 단가표 중 가장 최근 단가표의 마지막 버전을 가져온다.
+func getEffectiveRateCardFunc(client *mongo.Client, head string, date string) (RateCard, error) {
+	collection := client.Database(*flagDBName).Collection("ratecards")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result RateCard
+	opts := options.FindOne().SetSort(bson.D{{Key: "effectivedate", Value: -1}, {Key: "version", Value: -1}})
+	err := collection.FindOne(ctx, bson.M{"headquarter": head, "effectivedate": bson.M{"$lte": date}}, opts).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// rmRateCardFunc 함수는 DB에서 단가표를 삭제하는 함수이다. 예산안에 고정된 단가표는 삭제할 수 없다.
+func rmRateCardFunc(client *mongo.Client, id string) error {
+	collection := client.Database(*flagDBName).Collection("ratecards")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	used, err := getBGProjectsByRateCardFunc(client, id)
+	if err != nil {
+		return err
+	}
+	if len(used) != 0 {
+		return fmt.Errorf("예산 프로젝트 %s의 예산안에서 사용 중인 단가표는 삭제할 수 없습니다", used[0].ID)
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%s 단가표가 존재하지 않습니다", id)
+	}
+	return nil
+}
+
+// getBGProjectsByRateCardFunc 함수는 예산안에 단가표가 고정된 예산 프로젝트를 가져오는 함수이다.
+func getBGProjectsByRateCardFunc(client *mongo.Client, id string) ([]BGProject, error) {
+	bgprojects, err := searchBGProjectFunc(client, "id:", "id")
+	if err != nil {
+		return nil, err
+	}
+	var results []BGProject
+	for _, bgp := range bgprojects {
+		for _, typedata := range bgp.TypeData {
+			if checkRateCardPinnedFunc(typedata, id) {
+				results = append(results, bgp)
+				break
+			}
+		}
+	}
+	return results, nil
+}
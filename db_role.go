@@ -0,0 +1,74 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB Role 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getRoleFunc 함수는 DB에서 역할 정보를 가져오는 함수이다.
+func getRoleFunc(client *mongo.Client, name string) (Role, error) {
+	collection := client.Database(*flagDBName).Collection("roles")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var result Role
+	err := collection.FindOne(ctx, bson.M{"name": name}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getAllRolesFunc 함수는 DB에서 모든 역할 정보를 이름 순으로 가져오는 함수이다.
+func getAllRolesFunc(client *mongo.Client) ([]Role, error) {
+	collection := client.Database(*flagDBName).Collection("roles")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var results []Role
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// setRoleFunc 함수는 DB에 역할 정보를 저장하는 함수이다. 같은 이름의 역할이 없으면 추가한다.
+func setRoleFunc(client *mongo.Client, role Role) error {
+	collection := client.Database(*flagDBName).Collection("roles")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.UpdateOne(
+		ctx,
+		bson.M{"name": role.Name},
+		bson.D{{Key: "$set", Value: role}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// rmRoleFunc 함수는 DB에서 역할 정보를 삭제하는 함수이다.
+func rmRoleFunc(client *mongo.Client, name string) error {
+	collection := client.Database(*flagDBName).Collection("roles")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// 프로젝트 결산 프로그램
+//
+// Description : DB Session 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addSessionFunc 함수는 DB에 로그인 세션을 추가하는 함수이다.
+func addSessionFunc(client *mongo.Client, s Session) error {
+	collection := client.Database(*flagDBName).Collection("sessions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.InsertOne(ctx, s)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getSessionFunc 함수는 DB에서 만료되지 않은 로그인 세션을 가져오는 함수이다.
+func getSessionFunc(client *mongo.Client, id string) (Session, error) {
+	collection := client.Database(*flagDBName).Collection("sessions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var result Session
+	err := collection.FindOne(ctx, bson.M{"id": id, "expiresat": bson.M{"$gt": time.Now()}}).Decode(&result)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// getSessionsFunc 함수는 DB에서 만료되지 않은 로그인 세션을 최근 순으로 가져오는 함수이다. userID가 빈 문자열이면 모든 사용자의 세션을 가져온다.
+func getSessionsFunc(client *mongo.Client, userID string) ([]Session, error) {
+	collection := client.Database(*flagDBName).Collection("sessions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var results []Session
+	filter := bson.M{"expiresat": bson.M{"$gt": time.Now()}}
+	if userID != "" {
+		filter["userid"] = userID
+	}
+	opts := options.Find().SetSort(bson.M{"createdat": -1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// rmSessionFunc 함수는 DB에서 로그인 세션을 삭제하는 함수이다.
+func rmSessionFunc(client *mongo.Client, id string) error {
+	collection := client.Database(*flagDBName).Collection("sessions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// rmSessionsFunc 함수는 DB에서 사용자의 모든 로그인 세션을 삭제하는 함수이다.
+func rmSessionsFunc(client *mongo.Client, userID string) error {
+	collection := client.Database(*flagDBName).Collection("sessions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.DeleteMany(ctx, bson.M{"userid": userID})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// rmExpiredSessionsFunc 함수는 DB에서 만료된 로그인 세션을 삭제하는 함수이다.
+func rmExpiredSessionsFunc(client *mongo.Client) error {
+	collection := client.Database(*flagDBName).Collection("sessions")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.DeleteMany(ctx, bson.M{"expiresat": bson.M{"$lte": time.Now()}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -70,6 +70,23 @@ func getAllUsersFunc(client *mongo.Client) ([]User, error) {
 	return results, nil
 }
 
+// getUsersBySubscriptionFunc 함수는 DB에서 알림 이벤트를 구독한 사용자들의 정보를 가져오는 함수이다.
+func getUsersBySubscriptionFunc(client *mongo.Client, event string) ([]User, error) {
+	collection := client.Database(*flagDBName).Collection("users")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var results []User
+	cursor, err := collection.Find(ctx, bson.M{"subscriptions.event": event})
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
 // setUserFunc 함수는 유저 정보를 업데이트하는 함수이다.
 func setUserFunc(client *mongo.Client, u User) error {
 	collection := client.Database(*flagDBName).Collection("users")
@@ -16,16 +16,16 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// addVendorFunc 함수는 DB에 Vendor를 추가하는 함수이다.
-func addVendorFunc(client *mongo.Client, v Vendor) error {
+// addVendorFunc 함수는 DB에 Vendor를 추가하고 추가된 Vendor의 ID를 반환하는 함수이다.
+func addVendorFunc(client *mongo.Client, v Vendor) (primitive.ObjectID, error) {
 	collection := client.Database(*flagDBName).Collection("vendors")
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err := collection.InsertOne(ctx, v)
+	result, err := collection.InsertOne(ctx, v)
 	if err != nil {
-		return err
+		return primitive.NilObjectID, err
 	}
-	return nil
+	return result.InsertedID.(primitive.ObjectID), nil
 }
 
 // searchVendorFunc 함수는 DB에서 해당하는 Vendor를 검색하는 함수이다.
@@ -0,0 +1,172 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 드라마 에피소드 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// getShotEpisodeFunc 함수는 샷이 속한 에피소드를 반환하는 함수이다.
+// 에피소드가 연결되지 않은 이전 샷은 노트에 입력된 에피소드를 사용한다.
+func getShotEpisodeFunc(shot BGShotAsset) string {
+	if shot.Episode != "" {
+		return shot.Episode
+	}
+	return shot.Note
+}
+
+// countShotsByEpisodeFunc 함수는 에피소드별 샷 개수를 반환하는 함수이다.
+func countShotsByEpisodeFunc(shots []BGShotAsset) map[string]int {
+	result := make(map[string]int)
+	for _, shot := range shots {
+		result[getShotEpisodeFunc(shot)]++
+	}
+	return result
+}
+
+// getShotEpisodeMapFunc 함수는 샷 이름별 에피소드를 반환하는 함수이다. 에피소드가 없는 샷은 제외한다.
+func getShotEpisodeMapFunc(shots []BGShotAsset) map[string]string {
+	result := make(map[string]string)
+	for _, shot := range shots {
+		if ep := getShotEpisodeFunc(shot); ep != "" {
+			result[shot.Name] = ep
+		}
+	}
+	return result
+}
+
+// checkEpisodesFunc 함수는 에피소드 리스트에 빈 이름이나 중복된 이름이 있는지 확인하는 함수이다.
+func checkEpisodesFunc(episodes []Episode) error {
+	var names []string
+	for _, ep := range episodes {
+		if ep.Name == "" {
+			return fmt.Errorf("에피소드 이름을 입력해주세요")
+		}
+		if checkStringInListFunc(ep.Name, names) {
+			return fmt.Errorf("%s 에피소드가 중복되었습니다", ep.Name)
+		}
+		if ep.Cuts < 0 {
+			return fmt.Errorf("%s 에피소드의 컷수가 0보다 작습니다", ep.Name)
+		}
+		names = append(names, ep.Name)
+	}
+	return nil
+}
+
+// calEpisodeLaborCostFunc 함수는 에피소드별 타임로그와 아티스트의 시급으로 에피소드별 실제 인건비를 계산하는 함수이다.
+func calEpisodeLaborCostFunc(client *mongo.Client, timelogs []EpisodeTimelog) (map[string]float64, error) {
+	result := make(map[string]float64)
+	artists := make(map[string]Artist)
+	for _, t := range timelogs {
+		artist, ok := artists[t.UserID]
+		if !ok {
+			var err error
+			artist, err = getArtistFunc(client, t.UserID)
+			if err != nil {
+				if err == mongo.ErrNoDocuments {
+					continue
+				}
+				return nil, err
+			}
+			artists[t.UserID] = artist
+		}
+		hourlyWage := 0.0
+		if artist.Salary[fmt.Sprintf("%d", t.Year)] != "" {
+			var err error
+			hourlyWage, err = hourlyWageFunc(artist, t.Year, t.Month) // 시급 계산
+			if err != nil {
+				return nil, err
+			}
+		}
+		duration := math.Round(t.Duration/60*10) / 10
+		result[t.Episode] += duration * hourlyWage
+	}
+	return result, nil
+}
+
+// newBGEpisodeActualsFunc 함수는 에피소드별 예산, 실제 인건비, 샷 개수를 에피소드 순서대로 정리하는 함수이다.
+// 에피소드 리스트에 없는 에피소드는 이름순으로 뒤에 붙이고, 에피소드가 연결되지 않은 항목은 마지막에 붙인다.
+func newBGEpisodeActualsFunc(episodes []Episode, budget map[string]int, actual map[string]int, shots map[string]int) []BGEpisodeActual {
+	var names []string
+	for _, ep := range episodes {
+		names = append(names, ep.Name)
+	}
+	var extra []string
+	for _, m := range []map[string]int{budget, actual, shots} {
+		for name := range m {
+			if name != "" && !checkStringInListFunc(name, names) && !checkStringInListFunc(name, extra) {
+				extra = append(extra, name)
+			}
+		}
+	}
+	sort.Strings(extra)
+
+	var results []BGEpisodeActual
+	for i, name := range append(names, extra...) {
+		ep := Episode{Name: name}
+		if i < len(episodes) {
+			ep = episodes[i]
+		}
+		results = append(results, BGEpisodeActual{
+			Episode: ep,
+			Shots:   shots[name],
+			Cost:    newBGActualFunc("", name, budget[name], actual[name]),
+		})
+	}
+	if budget[""] != 0 || actual[""] != 0 || shots[""] != 0 {
+		results = append(results, BGEpisodeActual{
+			Shots: shots[""],
+			Cost:  newBGActualFunc("", "", budget[""], actual[""]),
+		})
+	}
+	return results
+}
+
+// calBGEpisodeActualFunc 함수는 드라마 프로젝트의 에피소드별 예산과 실제 인건비를 계산하는 함수이다.
+// 에피소드 예산이 입력되지 않았으면 메인 예산안의 샷 bid로 계산한 에피소드 비용을 예산으로 사용한다.
+func calBGEpisodeActualFunc(client *mongo.Client, project Project, bgp BGProject) ([]BGEpisodeActual, error) {
+	typedata := bgp.TypeData[bgp.MainType]
+	episodes := project.Episodes
+	if len(episodes) == 0 {
+		episodes = bgp.Episodes
+	}
+
+	budget := make(map[string]int)
+	for ep, cost := range typedata.EpisodeCost {
+		c, err := decryptToIntFunc(cost)
+		if err != nil {
+			return nil, err
+		}
+		budget[ep] = c
+	}
+	for _, ep := range episodes {
+		c, err := decryptToIntFunc(ep.Budget)
+		if err != nil {
+			return nil, err
+		}
+		if c != 0 {
+			budget[ep.Name] = c
+		}
+	}
+
+	timelogs, err := getEpisodeTimelogsFunc(client, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	laborCost, err := calEpisodeLaborCostFunc(client, timelogs)
+	if err != nil {
+		return nil, err
+	}
+	actual := make(map[string]int)
+	for ep, cost := range laborCost {
+		actual[ep] = int(math.Round(cost))
+	}
+
+	return newBGEpisodeActualsFunc(episodes, budget, actual, countShotsByEpisodeFunc(typedata.ShotList)), nil
+}
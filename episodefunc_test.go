@@ -0,0 +1,74 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 드라마 에피소드 테스트 스크립트
+
+package main
+
+import "testing"
+
+// 에피소드별 예산 대비 실제 비용을 에피소드 순서대로 정리하는지 테스트하기 위한 함수
+func Test_newBGEpisodeActuals(t *testing.T) {
+	episodes := []Episode{{Name: "EP02", Cuts: 30}, {Name: "EP01", Cuts: 20}}
+	budget := map[string]int{"EP01": 1000, "EP02": 2000, "EP03": 500}
+	actual := map[string]int{"EP01": 1500, "": 300}
+	shots := map[string]int{"EP01": 10, "EP02": 12}
+
+	got := newBGEpisodeActualsFunc(episodes, budget, actual, shots)
+	want := []struct {
+		name     string
+		cuts     int
+		shots    int
+		variance int
+	}{
+		{name: "EP02", cuts: 30, shots: 12, variance: -2000},
+		{name: "EP01", cuts: 20, shots: 10, variance: 500},
+		{name: "EP03", cuts: 0, shots: 0, variance: -500},
+		{name: "", cuts: 0, shots: 0, variance: 300},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Test_newBGEpisodeActuals(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", episodes, want, got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Episode.Name != w.name || g.Episode.Cuts != w.cuts || g.Shots != w.shots || g.Cost.Variance != w.variance {
+			t.Fatalf("Test_newBGEpisodeActuals(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", episodes, w, g)
+		}
+	}
+}
+
+// 이전 샷은 노트의 에피소드를 사용하는지 테스트하기 위한 함수
+func Test_getShotEpisode(t *testing.T) {
+	cases := []struct {
+		shot BGShotAsset
+		want string
+	}{
+		{shot: BGShotAsset{Name: "s0010", Note: "EP01"}, want: "EP01"},
+		{shot: BGShotAsset{Name: "s0020", Note: "EP01", Episode: "EP02"}, want: "EP02"},
+		{shot: BGShotAsset{Name: "s0030"}, want: ""},
+	}
+	for _, c := range cases {
+		got := getShotEpisodeFunc(c.shot)
+		if got != c.want {
+			t.Fatalf("Test_getShotEpisode(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.shot, c.want, got)
+		}
+	}
+}
+
+// 에피소드 이름이 비어있거나 중복되면 에러가 나는지 테스트하기 위한 함수
+func Test_checkEpisodes(t *testing.T) {
+	cases := []struct {
+		episodes []Episode
+		wantErr  bool
+	}{
+		{episodes: []Episode{{Name: "EP01"}, {Name: "EP02"}}, wantErr: false},
+		{episodes: []Episode{{Name: "EP01"}, {Name: "EP01"}}, wantErr: true},
+		{episodes: []Episode{{Name: ""}}, wantErr: true},
+		{episodes: []Episode{{Name: "EP01", Cuts: -1}}, wantErr: true},
+	}
+	for _, c := range cases {
+		err := checkEpisodesFunc(c.episodes)
+		if (err != nil) != c.wantErr {
+			t.Fatalf("Test_checkEpisodes(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.episodes, c.wantErr, err)
+		}
+	}
+}
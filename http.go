@@ -5,6 +5,7 @@
 package main
 
 import (
+	"crypto/tls"
 	"html/template"
 	"log"
 	"net/http"
@@ -56,8 +57,9 @@ var funcMap = template.FuncMap{
 	"checkMediumPlatingStatusFunc": checkMediumPlatingStatusFunc,
 
 	// templatefunc_bg.go
-	"calNegoRatioFunc":     calNegoRatioFunc,
-	"getBGPartInfoMapFunc": getBGPartInfoMapFunc,
+	"calNegoRatioFunc":            calNegoRatioFunc,
+	"getBGPartInfoMapFunc":        getBGPartInfoMapFunc,
+	"getBGApprovalStatusNameFunc": getBGApprovalStatusNameFunc,
 
 	// etc
 	"listToStringFunc":                listToStringFunc,
@@ -68,250 +70,343 @@ var funcMap = template.FuncMap{
 	"PreviousPageFunc":                PreviousPageFunc,
 	"NextPageFunc":                    NextPageFunc,
 	"SplitPageFunc":                   SplitPageFunc,
+	"logOptionNameFunc":               logOptionNameFunc,
 }
 
 func webServerFunc() {
-	vfsTemplate, err := loadTemplatesFunc()
+	// 템플릿은 main에서 로딩한 TEMPLATES를 사용한다.
+	registerRoutesFunc(http.DefaultServeMux)
+
+	// 웹서버 실행
+	handler := csrfMiddlewareFunc(permissionMiddlewareFunc(http.DefaultServeMux))
+	if *flagHTTPSPort == "" {
+		err := http.ListenAndServe(*flagHTTPPort, handler)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// HTTPS로 서비스하면 HTTP 포트는 HTTPS 주소로 리다이렉트만 한다.
+	if *flagHTTPPort != "" {
+		go func() {
+			err := http.ListenAndServe(*flagHTTPPort, httpsRedirectHandlerFunc(*flagHTTPSPort))
+			if err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+	certPath, keyPath, err := tlsFilePathFunc()
 	if err != nil {
 		log.Fatal(err)
 	}
-	TEMPLATES = vfsTemplate
+	server := &http.Server{
+		Addr:      *flagHTTPSPort,
+		Handler:   hstsMiddlewareFunc(handler),
+		TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	err = server.ListenAndServeTLS(certPath, keyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
 
+// routeMux 인터페이스는 URL에 핸들러를 등록하는 http.ServeMux의 메소드이다.
+type routeMux interface {
+	Handle(pattern string, handler http.Handler)
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// registerRoutesFunc 함수는 웹서버의 모든 URL과 핸들러를 mux에 등록하는 함수이다.
+func registerRoutesFunc(mux routeMux) {
 	// 리소스 로딩
-	http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(assets)))
+	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(assets)))
 
 	// 웹주소 설정
 	// 오류 제거를 위한 무시
-	http.HandleFunc("/favicon.ico", handleIconFunc)
+	mux.HandleFunc("/favicon.ico", handleIconFunc)
 
 	// 로그인
-	http.HandleFunc("/signup", handleSignupFunc)
-	http.HandleFunc("/signup-submit", handleSignupSubmitFunc)
-	http.HandleFunc("/signup-success", handleSignupSuccessFunc)
-	http.HandleFunc("/signin", handleSigninFunc)
-	http.HandleFunc("/signin-submit", handleSigninSubmitFunc)
-	http.HandleFunc("/signout", handleSignOutFunc)
-	http.HandleFunc("/invalidaccess", handleInvalidAccessFunc)
+	mux.HandleFunc("/signup", handleSignupFunc)
+	mux.HandleFunc("/signup-submit", handleSignupSubmitFunc)
+	mux.HandleFunc("/signup-success", handleSignupSuccessFunc)
+	mux.HandleFunc("/signin", handleSigninFunc)
+	mux.HandleFunc("/signin-submit", handleSigninSubmitFunc)
+	mux.HandleFunc("/signout", handleSignOutFunc)
+	mux.HandleFunc("/invalidaccess", handleInvalidAccessFunc)
 
 	// profile
-	http.HandleFunc("/editprofile", handleEditProfileFunc)
-	http.HandleFunc("/editprofile-submit", handleEditProfileSubmitFunc)
-	http.HandleFunc("/editprofile-success", handleEditProfileSuccessFunc)
-	http.HandleFunc("/updatepassword", handleUpdatePasswordFunc)
-	http.HandleFunc("/updatepassword-submit", handleUpdatePasswordSubmitFunc)
-	http.HandleFunc("/updatepassword-success", handleUpdatePasswordSuccessFunc)
+	mux.HandleFunc("/editprofile", handleEditProfileFunc)
+	mux.HandleFunc("/editprofile-submit", handleEditProfileSubmitFunc)
+	mux.HandleFunc("/editprofile-success", handleEditProfileSuccessFunc)
+	mux.HandleFunc("/updatepassword", handleUpdatePasswordFunc)
+	mux.HandleFunc("/updatepassword-submit", handleUpdatePasswordSubmitFunc)
+	mux.HandleFunc("/updatepassword-success", handleUpdatePasswordSuccessFunc)
+	mux.HandleFunc("/addapitoken", handleAddAPITokenFunc)
+	mux.HandleFunc("/rmapitoken", handleRmAPITokenFunc)
+	mux.HandleFunc("/enabletotp", handleEnableTOTPFunc)
+	mux.HandleFunc("/disabletotp", handleDisableTOTPFunc)
 
 	// 메인 페이지
-	http.HandleFunc("/", handleInitFunc)
-	http.HandleFunc("/search", handleSearchFunc)
-	http.HandleFunc("/exportinit", handleExportInitFunc)
+	mux.HandleFunc("/", handleInitFunc)
+	mux.HandleFunc("/search", handleSearchFunc)
+	mux.HandleFunc("/exportinit", handleExportInitFunc)
 
 	// 디테일 페이지
-	http.HandleFunc("/detail-sm", handleDetailSMFunc)
-	http.HandleFunc("/exportdetailsm", handleExportDetailSMFunc)
+	mux.HandleFunc("/detail-sm", handleDetailSMFunc)
+	mux.HandleFunc("/exportdetailsm", handleExportDetailSMFunc)
+	mux.HandleFunc("/bgactual", handleBGActualFunc)
+	mux.HandleFunc("/exportbgactual", handleExportBGActualFunc)
+	mux.HandleFunc("/episode-actual", handleEpisodeActualFunc)
+	mux.HandleFunc("/episode-timelog-sync", handleEpisodeTimelogSyncFunc)
+	mux.HandleFunc("/retake", handleRetakeFunc)
 
 	// VFX 타임로그
-	http.HandleFunc("/timelog-vfx", handleTimelogVFXFunc)
-	http.HandleFunc("/searchtimelog-vfx", handleSearchTimelogVFXFunc)
-	http.HandleFunc("/updatetimelog-vfx", handleUpdateTimelogVFXFunc)
-	http.HandleFunc("/timelogvfxexcel-download", handleTimelogVFXExcelDownloadFunc)
-	http.HandleFunc("/upload-timelogvfxexcel", handleUploadTimelogVFXExcelFunc)
-	http.HandleFunc("/timelogvfxexcel-submit", handleTimelogVFXExcelSubmitFunc)
-	http.HandleFunc("/updatetimelogvfx-submit", handleUpdateTimelogVFXSubmitFunc)
-	http.HandleFunc("/updatetimelogvfx-success", handleUpdateTimelogVFXSuccessFunc)
-	http.HandleFunc("/exporttimelog-vfx", handleExportTimelogVFXFunc)
+	mux.HandleFunc("/timelog-vfx", handleTimelogVFXFunc)
+	mux.HandleFunc("/searchtimelog-vfx", handleSearchTimelogVFXFunc)
+	mux.HandleFunc("/updatetimelog-vfx", handleUpdateTimelogVFXFunc)
+	mux.HandleFunc("/timelogvfxexcel-download", handleTimelogVFXExcelDownloadFunc)
+	mux.HandleFunc("/upload-timelogvfxexcel", handleUploadTimelogVFXExcelFunc)
+	mux.HandleFunc("/timelogvfxexcel-submit", handleTimelogVFXExcelSubmitFunc)
+	mux.HandleFunc("/updatetimelogvfx-submit", handleUpdateTimelogVFXSubmitFunc)
+	mux.HandleFunc("/updatetimelogvfx-success", handleUpdateTimelogVFXSuccessFunc)
+	mux.HandleFunc("/exporttimelog-vfx", handleExportTimelogVFXFunc)
 
 	// CM 타임로그
-	http.HandleFunc("/timelog-cm", handleTimelogCMFunc)
-	http.HandleFunc("/searchtimelog-cm", handleSearchTimelogCMFunc)
-	http.HandleFunc("/updatetimelog-cm", handleUpdateTimelogCMFunc)
-	http.HandleFunc("/timelogcmexcel-download", handleTimelogCMExcelDownloadFunc)
-	http.HandleFunc("/upload-timelogcmexcel", handleUploadTimelogCMExcelFunc)
-	http.HandleFunc("/timelogcmexcel-submit", handleTimelogCMExcelSubmitFunc)
-	http.HandleFunc("/updatetimelogcm-submit", handleUpdateTimelogCMSubmitFunc)
-	http.HandleFunc("/updatetimelogcm-success", handleUpdateTimelogCMSuccessFunc)
-	http.HandleFunc("/exporttimelog-cm", handleExportTimelogCMFunc)
+	mux.HandleFunc("/timelog-cm", handleTimelogCMFunc)
+	mux.HandleFunc("/searchtimelog-cm", handleSearchTimelogCMFunc)
+	mux.HandleFunc("/updatetimelog-cm", handleUpdateTimelogCMFunc)
+	mux.HandleFunc("/timelogcmexcel-download", handleTimelogCMExcelDownloadFunc)
+	mux.HandleFunc("/upload-timelogcmexcel", handleUploadTimelogCMExcelFunc)
+	mux.HandleFunc("/timelogcmexcel-submit", handleTimelogCMExcelSubmitFunc)
+	mux.HandleFunc("/updatetimelogcm-submit", handleUpdateTimelogCMSubmitFunc)
+	mux.HandleFunc("/updatetimelogcm-success", handleUpdateTimelogCMSuccessFunc)
+	mux.HandleFunc("/exporttimelog-cm", handleExportTimelogCMFunc)
 
 	// 누계 타임로그
-	http.HandleFunc("/timelog-total", handleTimelogTotalFunc)
-	http.HandleFunc("/searchtimelog-total", handleSearchTimelogTotalFunc)
-	http.HandleFunc("/exporttimelog-total", handleExportTimelogTotalFunc)
+	mux.HandleFunc("/timelog-total", handleTimelogTotalFunc)
+	mux.HandleFunc("/searchtimelog-total", handleSearchTimelogTotalFunc)
+	mux.HandleFunc("/exporttimelog-total", handleExportTimelogTotalFunc)
 
 	// 타임로그
-	http.HandleFunc("/finishedtimelog", handleFinishedTimelogFunc)
-	http.HandleFunc("/finishedtimelog-submit", handleFinishedTimelogSubmitFunc)
+	mux.HandleFunc("/finishedtimelog", handleFinishedTimelogFunc)
+	mux.HandleFunc("/finishedtimelog-submit", handleFinishedTimelogSubmitFunc)
 
 	// 결산 현황
-	http.HandleFunc("/smpayment-status", handleSMPaymentStatusFunc)
-	http.HandleFunc("/export-smpaymentstatus", handleExportSMPaymentStatusFunc)
-	http.HandleFunc("/smvendor-status", handleSMVendorStatusFunc)
-	http.HandleFunc("/export-smvendorstatus", handleExportSMVendorStatusFunc)
-	http.HandleFunc("/smtotal-status", handleSMTotalStatusFunc)
-	http.HandleFunc("/export-smtotalstatus", handleExportSMTotalStatusFunc)
+	mux.HandleFunc("/smpayment-status", handleSMPaymentStatusFunc)
+	mux.HandleFunc("/export-smpaymentstatus", handleExportSMPaymentStatusFunc)
+	mux.HandleFunc("/smvendor-status", handleSMVendorStatusFunc)
+	mux.HandleFunc("/export-smvendorstatus", handleExportSMVendorStatusFunc)
+	mux.HandleFunc("/smtotal-status", handleSMTotalStatusFunc)
+	mux.HandleFunc("/export-smtotalstatus", handleExportSMTotalStatusFunc)
 
 	// 결산 인건비
-	http.HandleFunc("/smdetail-laborcost", handleSMDetailLaborCostFunc)
-	http.HandleFunc("/export-smdetaillaborcost", handleExportSMDetailLaborCostFunc)
-	http.HandleFunc("/smtotal-laborcost", handleSMTotalLaborCostFunc)
-	http.HandleFunc("/export-smtotallaborcost", handleExportSMTotalLaborCostFunc)
+	mux.HandleFunc("/smdetail-laborcost", handleSMDetailLaborCostFunc)
+	mux.HandleFunc("/export-smdetaillaborcost", handleExportSMDetailLaborCostFunc)
+	mux.HandleFunc("/smtotal-laborcost", handleSMTotalLaborCostFunc)
+	mux.HandleFunc("/export-smtotallaborcost", handleExportSMTotalLaborCostFunc)
 
 	// 예산 디테일
-	http.HandleFunc("/bg/detail", handleBGDetailFunc)
+	mux.HandleFunc("/bg/detail", handleBGDetailFunc)
+
+	// 단가표
+	mux.HandleFunc("/ratecards", handleRateCardsFunc)
+	mux.HandleFunc("/addratecard", handleAddRateCardFunc)
+	mux.HandleFunc("/addratecard-submit", handleAddRateCardSubmitFunc)
+	mux.HandleFunc("/addratecard-success", handleAddRateCardSuccessFunc)
+	mux.HandleFunc("/ratecard-compare", handleRateCardCompareFunc)
 
 	// 유저
-	http.HandleFunc("/users", handleUsersFunc)
-	http.HandleFunc("/update-users", handleUpdateUsersFunc)
-	http.HandleFunc("/updateusers-success", handleUpdateUsersSuccess)
-	http.HandleFunc("/changepassword", handleChangePasswordFunc)
-	http.HandleFunc("/changepassword-submit", handleChangePasswordSubmitFunc)
-	http.HandleFunc("/changepassword-success", handleChangePasswordSuccessFunc)
+	mux.HandleFunc("/users", handleUsersFunc)
+	mux.HandleFunc("/update-users", handleUpdateUsersFunc)
+	mux.HandleFunc("/updateusers-success", handleUpdateUsersSuccess)
+	mux.HandleFunc("/update-roles", handleUpdateRolesFunc)
+	mux.HandleFunc("/rmsession", handleRmSessionFunc)
+	mux.HandleFunc("/rmsessions", handleRmSessionsFunc)
+	mux.HandleFunc("/unlockuser", handleUnlockUserFunc)
+	mux.HandleFunc("/resettotp", handleResetTOTPFunc)
+	mux.HandleFunc("/changepassword", handleChangePasswordFunc)
+	mux.HandleFunc("/changepassword-submit", handleChangePasswordSubmitFunc)
+	mux.HandleFunc("/changepassword-success", handleChangePasswordSuccessFunc)
 
 	// 아티스트
-	http.HandleFunc("/upload-artistsexcel", handleUploadArtistsExcelFunc)
+	mux.HandleFunc("/upload-artistsexcel", handleUploadArtistsExcelFunc)
 
 	// VFX 아티스트
-	http.HandleFunc("/artists-vfx", handleArtistsVFXFunc)
-	http.HandleFunc("/edit-artistvfx", handleEditArtistVFXFunc)
-	http.HandleFunc("/editartistvfx-submit", handleEditArtistVFXSubmitFunc)
-	http.HandleFunc("/editartistvfx-success", handleEditArtistVFXSuccessFunc)
-	http.HandleFunc("/updateartists-vfx", handleUpdateArtistsVFXFunc)
-	http.HandleFunc("/artistsvfxexcel-download", handleArtistsVFXExcelDownloadFunc)
-	http.HandleFunc("/artistsvfxexcel-submit", handleArtistsVFXExcelSubmitFunc)
-	http.HandleFunc("/updateartistsvfx-submit", handleUpdateArtistsVFXSubmitFunc)
-	http.HandleFunc("/updateartistsvfx-success", handleUpdateArtistsVFXSuccessFunc)
-	http.HandleFunc("/exportartists-vfx", handleExportArtistsVFXFunc)
+	mux.HandleFunc("/artists-vfx", handleArtistsVFXFunc)
+	mux.HandleFunc("/edit-artistvfx", handleEditArtistVFXFunc)
+	mux.HandleFunc("/editartistvfx-submit", handleEditArtistVFXSubmitFunc)
+	mux.HandleFunc("/editartistvfx-success", handleEditArtistVFXSuccessFunc)
+	mux.HandleFunc("/updateartists-vfx", handleUpdateArtistsVFXFunc)
+	mux.HandleFunc("/artistsvfxexcel-download", handleArtistsVFXExcelDownloadFunc)
+	mux.HandleFunc("/artistsvfxexcel-submit", handleArtistsVFXExcelSubmitFunc)
+	mux.HandleFunc("/updateartistsvfx-submit", handleUpdateArtistsVFXSubmitFunc)
+	mux.HandleFunc("/updateartistsvfx-success", handleUpdateArtistsVFXSuccessFunc)
+	mux.HandleFunc("/exportartists-vfx", handleExportArtistsVFXFunc)
 
 	// CM 아티스트
-	http.HandleFunc("/artists-cm", handleArtistsCMFunc)
-	http.HandleFunc("/edit-artistcm", handleEditArtistCMFunc)
-	http.HandleFunc("/editartistcm-submit", handleEditArtistCMSubmitFunc)
-	http.HandleFunc("/editartistcm-success", handleEditArtistCMSuccessFunc)
-	http.HandleFunc("/updateartists-cm", handleUpdateArtistsCMFunc)
-	http.HandleFunc("/artistscmexcel-download", handleArtistsCMExcelDownloadFunc)
-	http.HandleFunc("/artistscmexcel-submit", handleArtistsCMExcelSubmitFunc)
-	http.HandleFunc("/updateartistscm-submit", handleUpdateArtistsCMSubmitFunc)
-	http.HandleFunc("/updateartistscm-success", handleUpdateArtistsCMSuccessFunc)
-	http.HandleFunc("/exportartists-cm", handleExportArtistsCMFunc)
+	mux.HandleFunc("/artists-cm", handleArtistsCMFunc)
+	mux.HandleFunc("/edit-artistcm", handleEditArtistCMFunc)
+	mux.HandleFunc("/editartistcm-submit", handleEditArtistCMSubmitFunc)
+	mux.HandleFunc("/editartistcm-success", handleEditArtistCMSuccessFunc)
+	mux.HandleFunc("/updateartists-cm", handleUpdateArtistsCMFunc)
+	mux.HandleFunc("/artistscmexcel-download", handleArtistsCMExcelDownloadFunc)
+	mux.HandleFunc("/artistscmexcel-submit", handleArtistsCMExcelSubmitFunc)
+	mux.HandleFunc("/updateartistscm-submit", handleUpdateArtistsCMSubmitFunc)
+	mux.HandleFunc("/updateartistscm-success", handleUpdateArtistsCMSuccessFunc)
+	mux.HandleFunc("/exportartists-cm", handleExportArtistsCMFunc)
 
 	// SUP 타임로그
-	http.HandleFunc("/timelogs-sup", handleTimelogSUPFunc)
-	http.HandleFunc("/editsuptimelogs-submit", handleEditSUPTimelogFunc)
-	http.HandleFunc("/editsuptimelogs-success", handleEditSUPTimelogSuccessFunc)
+	mux.HandleFunc("/timelogs-sup", handleTimelogSUPFunc)
+	mux.HandleFunc("/editsuptimelogs-submit", handleEditSUPTimelogFunc)
+	mux.HandleFunc("/editsuptimelogs-success", handleEditSUPTimelogSuccessFunc)
 
 	// 프로젝트 - 결산
-	http.HandleFunc("/projects", handleProjectsFunc)
-	http.HandleFunc("/searchprojects", handleSearchProjectsFunc)
-	http.HandleFunc("/addproject", handleAddProjectFunc)
-	http.HandleFunc("/addproject-submit", handleAddProjectSubmitFunc)
-	http.HandleFunc("/addproject-success", handleAddProjectSuccessFunc)
-	http.HandleFunc("/edit-projectsm", handleEditProjectSMFunc)
-	http.HandleFunc("/editprojectsm-submit", handleEditProjectSMSubmitFunc)
-	http.HandleFunc("/editprojectsm-success", handleEditProjectSMSuccessFunc)
-	http.HandleFunc("/exportprojects", handleExportProjectsFunc)
+	mux.HandleFunc("/projects", handleProjectsFunc)
+	mux.HandleFunc("/searchprojects", handleSearchProjectsFunc)
+	mux.HandleFunc("/addproject", handleAddProjectFunc)
+	mux.HandleFunc("/addproject-submit", handleAddProjectSubmitFunc)
+	mux.HandleFunc("/addproject-success", handleAddProjectSuccessFunc)
+	mux.HandleFunc("/edit-projectsm", handleEditProjectSMFunc)
+	mux.HandleFunc("/editprojectsm-submit", handleEditProjectSMSubmitFunc)
+	mux.HandleFunc("/editprojectsm-success", handleEditProjectSMSuccessFunc)
+	mux.HandleFunc("/episodes-sm", handleEpisodesSMFunc)
+	mux.HandleFunc("/episodes-sm-submit", handleEpisodesSMSubmitFunc)
+	mux.HandleFunc("/exportprojects", handleExportProjectsFunc)
 
 	// 프로젝트 - 예산
-	http.HandleFunc("/bgprojects", handleBGProjectsFunc)
-	http.HandleFunc("/searchbgprojects", handleSearchBGProjectsFunc)
-	http.HandleFunc("/addbgproject", handleAddBGProjectFunc)
-	http.HandleFunc("/addbgproject-submit", handleAddBGProjectSubmitFunc)
-	http.HandleFunc("/addbgproject-success", handleAddBGProjectSuccessFunc)
-	http.HandleFunc("/edit-bgproject", handleEditBGProjectFunc)
-	http.HandleFunc("/editbgproject-submit", handleEditBGProjectSubmitFunc)
-	http.HandleFunc("/editbgproject-success", handleEditBGProjectSuccessFunc)
-	http.HandleFunc("/exportbgprojects", handleExportBGProjectsFunc)
-	http.HandleFunc("/bgproject-teamsetting", handleBGProjectTSFunc)
-	http.HandleFunc("/bgproject-teamsetting-submit", handleBGProjectTSSubmitFunc)
-	http.HandleFunc("/bgproject-teamsetting-success", handleBGProjectTSSuccessFunc)
+	mux.HandleFunc("/bgprojects", handleBGProjectsFunc)
+	mux.HandleFunc("/searchbgprojects", handleSearchBGProjectsFunc)
+	mux.HandleFunc("/addbgproject", handleAddBGProjectFunc)
+	mux.HandleFunc("/addbgproject-submit", handleAddBGProjectSubmitFunc)
+	mux.HandleFunc("/addbgproject-success", handleAddBGProjectSuccessFunc)
+	mux.HandleFunc("/edit-bgproject", handleEditBGProjectFunc)
+	mux.HandleFunc("/editbgproject-submit", handleEditBGProjectSubmitFunc)
+	mux.HandleFunc("/editbgproject-success", handleEditBGProjectSuccessFunc)
+	mux.HandleFunc("/exportbgprojects", handleExportBGProjectsFunc)
+	mux.HandleFunc("/bgproject-teamsetting", handleBGProjectTSFunc)
+	mux.HandleFunc("/bgproject-teamsetting-submit", handleBGProjectTSSubmitFunc)
+	mux.HandleFunc("/bgproject-teamsetting-success", handleBGProjectTSSuccessFunc)
+	mux.HandleFunc("/bgrevisions", handleBGRevisionsFunc)
+	mux.HandleFunc("/bgrevision-restore", handleBGRevisionRestoreFunc)
+	mux.HandleFunc("/bgcompare", handleBGCompareFunc)
+	mux.HandleFunc("/bgapproval", handleBGApprovalFunc)
+	mux.HandleFunc("/bgapproval-submit", handleBGApprovalSubmitFunc)
+	mux.HandleFunc("/bgepisodes", handleBGEpisodesFunc)
+	mux.HandleFunc("/bgepisodes-submit", handleBGEpisodesSubmitFunc)
+	mux.HandleFunc("/bgquote", handleBGQuoteFunc)
+	mux.HandleFunc("/bgquote-submit", handleBGQuoteSubmitFunc)
+	mux.HandleFunc("/bgcapacity", handleBGCapacityFunc)
+	mux.HandleFunc("/exportbgcapacity", handleExportBGCapacityFunc)
 
 	// 샷, 어셋 - 예산
-	http.HandleFunc("/shotasset", handelShotAssetFunc)
-	http.HandleFunc("/searchshotasset", handleSearchShotAssetFunc)
-	http.HandleFunc("/uploadshot", handleUploadShotFunc)
-	http.HandleFunc("/shotexcel-download", handleShotExcelDownloadFunc)
-	http.HandleFunc("/upload-shotexcel", handleUploadShotExcelFunc)
-	http.HandleFunc("/shotexcel-submit", handleShotExcelSubmitFunc)
-	http.HandleFunc("/uploadshot-submit", handleUploadShotSubmitFunc)
-	http.HandleFunc("/uploadshot-success", handleUploadShotSuccessFunc)
-	http.HandleFunc("/detail-shot", handleDetailShotFunc)
-	http.HandleFunc("/exportdetailshot", handleExportDetailShotFunc)
-	http.HandleFunc("/uploadasset", handleUploadAssetFunc)
-	http.HandleFunc("/assetexcel-download", handleAssetExcelDownloadFunc)
-	http.HandleFunc("/upload-assetexcel", handleUploadAssetExcelFunc)
-	http.HandleFunc("/assetexcel-submit", handleAssetExcelSubmitFunc)
-	http.HandleFunc("/uploadasset-submit", handleUploadAssetSubmitFunc)
-	http.HandleFunc("/uploadasset-success", handleUploadAssetSuccessFunc)
-	http.HandleFunc("/detail-asset", handleDetailAssetFunc)
-	http.HandleFunc("/exportdetailasset", handleExportDetailAssetFunc)
+	mux.HandleFunc("/shotasset", handelShotAssetFunc)
+	mux.HandleFunc("/searchshotasset", handleSearchShotAssetFunc)
+	mux.HandleFunc("/uploadshot", handleUploadShotFunc)
+	mux.HandleFunc("/shotexcel-download", handleShotExcelDownloadFunc)
+	mux.HandleFunc("/upload-shotexcel", handleUploadShotExcelFunc)
+	mux.HandleFunc("/shotexcel-submit", handleShotExcelSubmitFunc)
+	mux.HandleFunc("/uploadshot-submit", handleUploadShotSubmitFunc)
+	mux.HandleFunc("/uploadshot-success", handleUploadShotSuccessFunc)
+	mux.HandleFunc("/detail-shot", handleDetailShotFunc)
+	mux.HandleFunc("/exportdetailshot", handleExportDetailShotFunc)
+	mux.HandleFunc("/uploadasset", handleUploadAssetFunc)
+	mux.HandleFunc("/assetexcel-download", handleAssetExcelDownloadFunc)
+	mux.HandleFunc("/upload-assetexcel", handleUploadAssetExcelFunc)
+	mux.HandleFunc("/assetexcel-submit", handleAssetExcelSubmitFunc)
+	mux.HandleFunc("/uploadasset-submit", handleUploadAssetSubmitFunc)
+	mux.HandleFunc("/uploadasset-success", handleUploadAssetSuccessFunc)
+	mux.HandleFunc("/detail-asset", handleDetailAssetFunc)
+	mux.HandleFunc("/exportdetailasset", handleExportDetailAssetFunc)
+	mux.HandleFunc("/importsgbid", handleImportSGBidFunc)
+	mux.HandleFunc("/importsgbid-submit", handleImportSGBidSubmitFunc)
+	mux.HandleFunc("/importsgbid-success", handleImportSGBidSuccessFunc)
 
 	// 벤더 관리
-	http.HandleFunc("/vendors", handleVendorsFunc)
-	http.HandleFunc("/searchvendors", handleSearchVendorsFunc)
-	http.HandleFunc("/addvendor-page", handleAddVendorPageFunc)
-	http.HandleFunc("/addvendor", handleAddVendorFunc)
-	http.HandleFunc("/addvendor-submit", handleAddVendorSubmitFunc)
-	http.HandleFunc("/addvendor-success", handleAddVendorSuccessFunc)
-	http.HandleFunc("/edit-vendor", handleEditVendorFunc)
-	http.HandleFunc("/editvendor-submit", handleEditVendorSubmitFunc)
-	http.HandleFunc("/editvendor-success", handleEditVendorSuccessFunc)
-	http.HandleFunc("/exportvendors", handleExportVendorsFunc)
+	mux.HandleFunc("/vendors", handleVendorsFunc)
+	mux.HandleFunc("/searchvendors", handleSearchVendorsFunc)
+	mux.HandleFunc("/addvendor-page", handleAddVendorPageFunc)
+	mux.HandleFunc("/addvendor", handleAddVendorFunc)
+	mux.HandleFunc("/addvendor-submit", handleAddVendorSubmitFunc)
+	mux.HandleFunc("/addvendor-success", handleAddVendorSuccessFunc)
+	mux.HandleFunc("/edit-vendor", handleEditVendorFunc)
+	mux.HandleFunc("/editvendor-submit", handleEditVendorSubmitFunc)
+	mux.HandleFunc("/editvendor-success", handleEditVendorSuccessFunc)
+	mux.HandleFunc("/exportvendors", handleExportVendorsFunc)
+
+	// 클라이언트(제작사, 감독) 관리
+	mux.HandleFunc("/clients", handleClientsFunc)
+	mux.HandleFunc("/searchclients", handleSearchClientsFunc)
+	mux.HandleFunc("/client", handleClientFunc)
+	mux.HandleFunc("/addclient", handleAddClientFunc)
+	mux.HandleFunc("/addclient-submit", handleAddClientSubmitFunc)
+	mux.HandleFunc("/addclient-success", handleAddClientSuccessFunc)
+	mux.HandleFunc("/edit-client", handleEditClientFunc)
+	mux.HandleFunc("/editclient-submit", handleEditClientSubmitFunc)
+	mux.HandleFunc("/editclient-success", handleEditClientSuccessFunc)
 
 	// Team Setting
-	http.HandleFunc("/bgteamsetting", handleBGTeamSettingFunc)
-	http.HandleFunc("/bgteamsetting-submit", handleBGTeamSettingSubmitFunc)
-	http.HandleFunc("/bgteamsetting-success", handleBGTeamSEttingSuccessFunc)
+	mux.HandleFunc("/bgteamsetting", handleBGTeamSettingFunc)
+	mux.HandleFunc("/bgteamsetting-submit", handleBGTeamSettingSubmitFunc)
+	mux.HandleFunc("/bgteamsetting-success", handleBGTeamSEttingSuccessFunc)
+	mux.HandleFunc("/bgteamsetting-history", handleBGTeamSettingHistoryFunc)
+	mux.HandleFunc("/bgteamsetting-diff", handleBGTeamSettingDiffFunc)
+	mux.HandleFunc("/bgteamsetting-migrate", handleBGTeamSettingMigrateFunc)
+	mux.HandleFunc("/bgteamsetting-migrate-submit", handleBGTeamSettingMigrateSubmitFunc)
 
 	// admin setting
-	http.HandleFunc("/adminsetting", handleAdminSettingFunc)
-	http.HandleFunc("/adminsetting-submit", handleAdminSettingSubmitFunc)
-	http.HandleFunc("/adminsetting-success", handleAdminSettingSuccessFunc)
+	mux.HandleFunc("/adminsetting", handleAdminSettingFunc)
+	mux.HandleFunc("/adminsetting-submit", handleAdminSettingSubmitFunc)
+	mux.HandleFunc("/adminsetting-success", handleAdminSettingSuccessFunc)
 
 	// Help
-	http.HandleFunc("/help", handleHelpFunc)
+	mux.HandleFunc("/help", handleHelpFunc)
 
 	// 로그 페이지
-	http.HandleFunc("/log", handleLogFunc)
+	mux.HandleFunc("/log", handleLogFunc)
+	mux.HandleFunc("/exportlog", handleExportLogFunc)
 
 	// 유저 restAPI
-	http.HandleFunc("/api/rmuser", handleAPIRmUserFunc)
+	mux.HandleFunc("/api/rmuser", handleAPIRmUserFunc)
 
 	// 아티스트 restAPI
-	http.HandleFunc("/api/addartistvfx", handleAPIAddArtistVFXFunc)
-	http.HandleFunc("/api/addartistcm", handleAPIAddArtistCMFunc)
-	http.HandleFunc("/api/rmartist", handleAPIRmArtistFunc)
-	http.HandleFunc("/api/shotgunevent/humanuser/new", handleEventSGAPIAddArtistVFXFunc)
+	mux.HandleFunc("/api/addartistvfx", handleAPIAddArtistVFXFunc)
+	mux.HandleFunc("/api/addartistcm", handleAPIAddArtistCMFunc)
+	mux.HandleFunc("/api/rmartist", handleAPIRmArtistFunc)
+	mux.HandleFunc("/api/shotgunevent/humanuser/new", handleEventSGAPIAddArtistVFXFunc)
 
 	// 타임로그 restAPI
-	http.HandleFunc("/api/checkmonthlystatus", handleAPICheckMonthlyStatusFunc)
-	http.HandleFunc("/api/updatetimelog", handleAPIUpdateTimelogFunc)
-	http.HandleFunc("/api/rmtimelogbyid", handleAPIRmTimelogByIDFunc)
-	http.HandleFunc("/api/rmtimelogbyproject", handleAPIRmTimelogByProjectFunc)
-	http.HandleFunc("/api/resettimelog", handleAPIResetTimelogFunc)
+	mux.HandleFunc("/api/checkmonthlystatus", handleAPICheckMonthlyStatusFunc)
+	mux.HandleFunc("/api/updatetimelog", handleAPIUpdateTimelogFunc)
+	mux.HandleFunc("/api/rmtimelogbyid", handleAPIRmTimelogByIDFunc)
+	mux.HandleFunc("/api/rmtimelogbyproject", handleAPIRmTimelogByProjectFunc)
+	mux.HandleFunc("/api/resettimelog", handleAPIResetTimelogFunc)
 
 	// 프로젝트 restAPI
-	http.HandleFunc("/api/rmproject", handleAPIRmProjectFunc)
-	http.HandleFunc("/api/monthlyPurchaseCost", handleMonthlyPurchaseCostFunc)
-	http.HandleFunc("/api/setMonthlyPurchaseCost", handleAPISetMonthlyPurchaseCostFunc)
-	http.HandleFunc("/api/monthlyPayment", handleAPIMonthlyPaymentFunc)
-	http.HandleFunc("/api/setMonthlyPayment", handleAPISetMonthlyPaymentFunc)
-	http.HandleFunc("/api/updateprojects", handleAPIUpdateProjectsFunc)
-	http.HandleFunc("/api/shotgunevent/project/new", handleEventSGAPIAddProjectFunc)
+	mux.HandleFunc("/api/rmproject", handleAPIRmProjectFunc)
+	mux.HandleFunc("/api/monthlyPurchaseCost", handleMonthlyPurchaseCostFunc)
+	mux.HandleFunc("/api/setMonthlyPurchaseCost", handleAPISetMonthlyPurchaseCostFunc)
+	mux.HandleFunc("/api/monthlyPayment", handleAPIMonthlyPaymentFunc)
+	mux.HandleFunc("/api/setMonthlyPayment", handleAPISetMonthlyPaymentFunc)
+	mux.HandleFunc("/api/updateprojects", handleAPIUpdateProjectsFunc)
+	mux.HandleFunc("/api/shotgunevent/project/new", handleEventSGAPIAddProjectFunc)
 
 	// 예산 프로젝트 restAPI
-	http.HandleFunc("/api/rmbgproject", handleAPIRmBGProjectFunc)
+	mux.HandleFunc("/api/rmbgproject", handleAPIRmBGProjectFunc)
 
 	// Vendor restAPI
-	http.HandleFunc("/api/rmvendor", handleAPIRmVendorFunc)
+	mux.HandleFunc("/api/rmvendor", handleAPIRmVendorFunc)
+
+	// 클라이언트 restAPI
+	mux.HandleFunc("/api/rmclient", handleAPIRmClientFunc)
+
+	// restAPI 단가표
+	mux.HandleFunc("/api/rmratecard", handleAPIRmRateCardFunc)
 
 	// Shotgun restAPI
-	http.HandleFunc("/api/sgartist", handleAPISGArtistFunc)
+	mux.HandleFunc("/api/sgartist", handleAPISGArtistFunc)
 
 	// Admin setting restAPI
-	http.HandleFunc("/api/vfxteams", handleAPIVFXTeamsFunc)
-	http.HandleFunc("/api/totalteams", handleAPITotalTeamsFunc)
-
-	// 웹서버 실행
-	err = http.ListenAndServe(*flagHTTPPort, nil)
-	if err != nil {
-		log.Fatal(err)
-	}
+	mux.HandleFunc("/api/vfxteams", handleAPIVFXTeamsFunc)
+	mux.HandleFunc("/api/totalteams", handleAPITotalTeamsFunc)
 }
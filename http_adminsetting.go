@@ -26,11 +26,7 @@ func handleAdminSettingFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -65,9 +61,11 @@ func handleAdminSettingFunc(w http.ResponseWriter, r *http.Request) {
 		CurMonthlyStatus        MonthlyStatus           // 이번달의 결산 상태
 		LastFTStatus            []FinishedTimelogStatus // 전달의 끝난 프로젝트의 타임로그 처리 상태
 		CurFTStatus             []FinishedTimelogStatus // 이번달의 끝난 프로젝트의 타임로그 처리 상태
+		NotificationEvents      []NotificationEvent     // 알림 이벤트 리스트
 	}
 	rcp := Recipe{}
 	rcp.Token = token
+	rcp.NotificationEvents = NotificationEvents
 	rcp.User, err = getUserFunc(client, token.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -156,11 +154,7 @@ func handleAdminSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -190,6 +184,7 @@ func handleAdminSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
+	before := a
 
 	a.VFXDepts = stringToListFunc(r.FormValue("vfxdepts"), " ")
 	vfxTeams, err := sgGetTeamMapFunc(a.VFXDepts) // 팀 태그 리스트를 통해서 map 형식의 팀리스트를 얻는다.
@@ -200,6 +195,8 @@ func handleAdminSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	a.CMTeams = stringToListFunc(r.FormValue("cmteams"), " ")
 	a.SGExcludeID = stringToListFunc(r.FormValue("sgexcludeid"), " ")
 	a.SGExcludeProjects = stringToListFunc(r.FormValue("sgexcludeprojects"), " ")
+	a.SGRetakeKeywords = stringToListFunc(r.FormValue("sgretakekeywords"), " ")
+	a.SGRetakeStatuses = stringToListFunc(r.FormValue("sgretakestatuses"), " ")
 	projectStatusNum, err := strconv.Atoi(r.FormValue("projectStatusNum"))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -229,6 +226,78 @@ func handleAdminSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	a.GWIDsForProject = stringToListFunc(r.FormValue("gwidsforproject"), " ")
 	a.GWIDs = stringToListFunc(r.FormValue("gwids"), " ")
 
+	// 알림 설정
+	a.NotifierType = r.FormValue("notifiertype")
+	a.NotifierFilePath = strings.TrimSpace(r.FormValue("notifierfilepath"))
+	a.SMTPHost = strings.TrimSpace(r.FormValue("smtphost"))
+	a.SMTPPort = strings.TrimSpace(r.FormValue("smtpport"))
+	a.SMTPTLS = r.FormValue("smtptls")
+	a.SMTPUsername = strings.TrimSpace(r.FormValue("smtpusername"))
+	a.SMTPFrom = strings.TrimSpace(r.FormValue("smtpfrom"))
+	if r.FormValue("smtppassword") != "" { // 비밀번호는 입력한 경우에만 변경한다.
+		a.SMTPPassword, err = encryptAES256Func(r.FormValue("smtppassword"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Webhook 설정
+	a.WebhookURLs = make(map[string]string)
+	for _, e := range NotificationEvents {
+		url := strings.TrimSpace(r.FormValue("webhook-" + e.ID))
+		if url != "" {
+			a.WebhookURLs[e.ID] = url
+		}
+	}
+	a.WebhookPayload = strings.TrimSpace(r.FormValue("webhookpayload"))
+
+	// LDAP 설정
+	a.LDAPURL = strings.TrimSpace(r.FormValue("ldapurl"))
+	a.LDAPBaseDN = strings.TrimSpace(r.FormValue("ldapbasedn"))
+	a.LDAPUserAttr = strings.TrimSpace(r.FormValue("ldapuserattr"))
+	a.LDAPBindDN = strings.TrimSpace(r.FormValue("ldapbinddn"))
+	if r.FormValue("ldapbindpassword") != "" { // 비밀번호는 입력한 경우에만 변경한다.
+		a.LDAPBindPassword, err = encryptAES256Func(r.FormValue("ldapbindpassword"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	ldapGroupNum, err := strconv.Atoi(r.FormValue("ldapGroupNum"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	a.LDAPGroupRoles = nil
+	for i := 0; i < ldapGroupNum; i++ {
+		group := strings.TrimSpace(r.FormValue(fmt.Sprintf("ldapgroup%d", i)))
+		if group == "" {
+			continue
+		}
+		level, err := strconv.Atoi(r.FormValue(fmt.Sprintf("ldaplevel%d", i)))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.LDAPGroupRoles = append(a.LDAPGroupRoles, LDAPGroupRole{
+			Group:       group,
+			Role:        strings.TrimSpace(r.FormValue(fmt.Sprintf("ldaprole%d", i))),
+			AccessLevel: AccessLevel(level),
+		})
+	}
+
+	// 로그 보관 설정
+	a.LogRetentionDays = 0
+	if r.FormValue("logretentiondays") != "" {
+		a.LogRetentionDays, err = strconv.Atoi(r.FormValue("logretentiondays"))
+		if err != nil {
+			http.Error(w, "로그 보관 기간은 숫자만 가능합니다", http.StatusBadRequest)
+			return
+		}
+	}
+	a.LogArchivePath = strings.TrimSpace(r.FormValue("logarchivepath"))
+
 	// 예산 관련 수퍼바이저 / 프로덕션 / 매니지먼트 팀 설정
 	a.BGSupervisorTeams = r.Form["bgsupervisorteams"] // 예산 관련 슈퍼바이저 팀
 	a.BGProductionTeams = r.Form["bgproductionteams"] // 예산 관련 프로덕션 팀
@@ -262,7 +331,7 @@ func handleAdminSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	err = setMonthlyStatusFunc(client, beforeLastMonthlyStatus)
+	err = setMonthlyStatusAndNotifyFunc(client, beforeLastMonthlyStatus)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -284,7 +353,7 @@ func handleAdminSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	err = setMonthlyStatusFunc(client, lastMonthlyStatus)
+	err = setMonthlyStatusAndNotifyFunc(client, lastMonthlyStatus)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -306,16 +375,20 @@ func handleAdminSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	err = setMonthlyStatusFunc(client, curMonthlyStatus)
+	err = setMonthlyStatusAndNotifyFunc(client, curMonthlyStatus)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   "AdminSetting이 수정되었습니다.",
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "AdminSetting이 수정되었습니다.",
+		Action:     LogActionUpdate,
+		EntityType: LogEntityAdminSetting,
+		Changes:    diffEntityFunc(before, a),
+		IP:         clientIPFunc(r),
 	}
 
 	// 끝난 프로젝트의 결산 처리 상태 확인 - 이번달
@@ -733,11 +806,7 @@ func handleAdminSettingSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -0,0 +1,221 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 개인 API 토큰 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleAddAPITokenFunc 함수는 사용자의 개인 API 토큰을 만들고 토큰 원문을 한 번만 보여주는 함수이다.
+func handleAddAPITokenFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		http.Error(w, "토큰 이름을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+	r.ParseForm()
+	var scopes []string
+	for _, s := range APIScopes {
+		if checkStringInListFunc(s.ID, r.Form["scopes"]) {
+			scopes = append(scopes, s.ID)
+		}
+	}
+	if len(scopes) == 0 {
+		http.Error(w, "API 범위를 하나 이상 선택해주세요", http.StatusBadRequest)
+		return
+	}
+	expiresAt := time.Time{} // 만료일을 입력하지 않으면 만료되지 않는다.
+	if date := r.FormValue("expiresat"); date != "" {
+		if !regexDate2.MatchString(date) {
+			http.Error(w, "만료일이 2020-09-01 형식이 아닙니다", http.StatusBadRequest)
+			return
+		}
+		expiresAt, err = time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		expiresAt = expiresAt.AddDate(0, 0, 1) // 만료일 하루가 끝날 때까지 사용할 수 있다.
+		if !expiresAt.After(time.Now()) {
+			http.Error(w, "만료일은 오늘 이후여야 합니다", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, secret, err := newAPITokenFunc(token.ID, name, scopes, expiresAt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	err = addAPITokenFunc(client, t)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("API 토큰 %s(%s)를 만들었습니다.", t.Name, t.Prefix),
+		Action:     LogActionCreate,
+		EntityType: LogEntityAPIToken,
+		EntityID:   t.ID,
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token    Token
+		APIToken APIToken
+		Secret   string // 토큰 원문, 이 페이지에서만 보여준다.
+	}
+	rcp := Recipe{
+		Token:    token,
+		APIToken: t,
+		Secret:   secret,
+	}
+
+	// 토큰 원문이 브라우저에 캐시되지 않게 한다.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "addapitoken-success", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleRmAPITokenFunc 함수는 사용자의 개인 API 토큰을 삭제해서 더 이상 사용할 수 없게 하는 함수이다.
+func handleRmAPITokenFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 본인의 토큰만 삭제할 수 있다.
+	tokens, err := getAPITokensFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var t APIToken
+	for _, v := range tokens {
+		if v.ID == id {
+			t = v
+			break
+		}
+	}
+	if t.ID == "" {
+		http.Error(w, "API 토큰이 존재하지 않습니다", http.StatusBadRequest)
+		return
+	}
+	err = rmAPITokenFunc(client, token.ID, t.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("API 토큰 %s(%s)를 삭제했습니다.", t.Name, t.Prefix),
+		Action:     LogActionDelete,
+		EntityType: LogEntityAPIToken,
+		EntityID:   t.ID,
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/editprofile", http.StatusSeeOther)
+}
@@ -19,11 +19,7 @@ func handleUploadArtistsExcelFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	err = r.ParseMultipartForm(200000)
 	if err != nil {
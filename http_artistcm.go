@@ -30,11 +30,7 @@ func handleArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -66,6 +62,7 @@ func handleArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		Year       string
 		Resination bool // 퇴사자 토글 옵션값
 		Artists    []Artist
+		ViewSalary bool // 시급을 볼 수 있는지 여부
 	}
 	rcp := Recipe{}
 	rcp.Token = token
@@ -74,6 +71,11 @@ func handleArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	rcp.ViewSalary, err = checkPermissionFunc(client, rcp.User, "viewsalary")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	year := r.FormValue("year")
 	if year == "" { // year 값이 없으면 올해로 검색
@@ -104,7 +106,25 @@ func handleArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = genArtistsCMExcelFunc(rcp.Artists, token.ID) // 엑셀 파일 미리 생성
+	// 급여 보기 권한이 없으면 연봉 정보를 지우고, 있으면 급여 정보 조회 로그를 남긴다.
+	if !rcp.ViewSalary {
+		rcp.Artists = maskArtistsSalaryFunc(rcp.Artists)
+	} else {
+		err = addLogsFunc(client, Log{
+			UserID:     token.ID,
+			CreatedAt:  time.Now(),
+			Content:    fmt.Sprintf("CM 아티스트 페이지에서 %s년 아티스트 시급을 조회하였습니다.", rcp.Year),
+			Action:     LogActionView,
+			EntityType: LogEntityArtist,
+			IP:         clientIPFunc(r),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err = genArtistsCMExcelFunc(rcp.Artists, rcp.ViewSalary, token.ID) // 엑셀 파일 미리 생성
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -128,11 +148,7 @@ func handleEditArtistCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -192,6 +208,20 @@ func handleEditArtistCMFunc(w http.ResponseWriter, r *http.Request) {
 		rcp.Artist.Salary[key] = result
 	}
 
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("CM 아티스트 %s의 연봉 정보를 조회하였습니다.", rcp.Artist.ID),
+		Action:     LogActionView,
+		EntityType: LogEntityArtist,
+		EntityID:   rcp.Artist.ID,
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// Edit 페이지를 띄운다
 	w.Header().Set("Content-Type", "text/html")
 	err = TEMPLATES.ExecuteTemplate(w, "edit-artistcm", rcp)
@@ -210,11 +240,7 @@ func handleEditArtistCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 	id := r.FormValue("id")
 
 	// mongoDB client 연결
@@ -246,6 +272,7 @@ func handleEditArtistCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	before := artist
 	artist.Team = r.FormValue("team")
 	artist.Name = r.FormValue("name")
 	artist.StartDay = r.FormValue("startday")
@@ -320,11 +347,22 @@ func handleEditArtistCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// 퇴사 여부는 DB에 저장할 때 정해지므로 저장된 값과 비교한다.
+	after, err := getArtistFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	log := Log{}
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("CM 아티스트 ID %s의 정보가 수정되었습니다.", id)
+	log.Action = LogActionUpdate
+	log.EntityType = LogEntityArtist
+	log.EntityID = id
+	log.Changes = diffEntityFunc(before, after)
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -344,11 +382,7 @@ func handleEditArtistCMSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -383,11 +417,7 @@ func handleUpdateArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -406,17 +436,13 @@ func handleUpdateArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 // handleArtistsCMExcelDownloadFunc 함수는 CM 아티스트 정보를 입력할 엑셀 파일의 템플릿을 생성하여 다운로드하는 함수이다.
 func handleArtistsCMExcelDownloadFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Only", http.StatusMethodNotAllowed)
@@ -477,11 +503,7 @@ func handleArtistsCMExcelSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Method Only", http.StatusMethodNotAllowed)
@@ -642,11 +664,7 @@ func handleUpdateArtistsCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -781,11 +799,47 @@ func handleUpdateArtistsCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
+		before, err := getArtistFunc(client, a.ID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		err = updateArtistFunc(client, a) // DB에 아티스트 추가
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		after, err := getArtistFunc(client, a.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// 바뀐 아티스트만 로그를 남긴다.
+		changes := diffEntityFunc(before, after)
+		if len(changes) == 0 {
+			continue
+		}
+		action := LogActionUpdate
+		content := fmt.Sprintf("CM 아티스트 ID %s의 정보가 수정되었습니다.(엑셀)", a.ID)
+		if before.ID == "" {
+			action = LogActionCreate
+			content = fmt.Sprintf("CM 아티스트 ID %s가 추가되었습니다.(엑셀)", a.ID)
+		}
+		err = addLogsFunc(client, Log{
+			UserID:     token.ID,
+			CreatedAt:  time.Now(),
+			Content:    content,
+			Action:     action,
+			EntityType: LogEntityArtist,
+			EntityID:   a.ID,
+			Changes:    changes,
+			IP:         clientIPFunc(r),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
 	log := Log{}
@@ -804,6 +858,9 @@ func handleUpdateArtistsCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		}
 
 		log.Content = "CM 아티스트 업데이트에 실패했습니다."
+		log.Action = LogActionImport
+		log.EntityType = LogEntityArtist
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -819,6 +876,9 @@ func handleUpdateArtistsCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		log.Content = "CM 아티스트 업데이트를 완료했습니다."
+		log.Action = LogActionImport
+		log.EntityType = LogEntityArtist
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -839,11 +899,7 @@ func handleUpdateArtistsCMSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -860,9 +916,9 @@ func handleUpdateArtistsCMSuccessFunc(w http.ResponseWriter, r *http.Request) {
 }
 
 // genArtistsCMExcelFunc 함수는 CM팀의 아티스트 데이터를 엑셀 파일로 생성하는 함수이다.
-func genArtistsCMExcelFunc(artists []Artist, userID string) error {
+func genArtistsCMExcelFunc(artists []Artist, withSalary bool, userID string) error {
 	path := os.TempDir() + "/budget/" + userID + "/artistscm"
-	fileName := "cm_artists.xlsx"
+	fileName := artistsExcelFileNameFunc("cm", withSalary)
 
 	err := createFolderFunc(path)
 	if err != nil {
@@ -989,11 +1045,7 @@ func handleExportArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1003,20 +1055,6 @@ func handleExportArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 
 	path := os.TempDir() + "/budget/" + token.ID + "/artistscm"
 
-	// path에 있는 파일들을 가져온다.
-	fileInfo, err := ioutil.ReadDir(path)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	// 파일의 확장자가 xlsx가 아니면 엑셀 파일이 다시 생성되도록 리다이렉트
-	ext := filepath.Ext(fileInfo[0].Name())
-	if ext != ".xlsx" {
-		http.Redirect(w, r, "artists-cm", http.StatusSeeOther)
-		return
-	}
-
 	// mongoDB client 연결
 	credential := options.Credential{
 		Username: *flagDBID,
@@ -1041,10 +1079,34 @@ func handleExportArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 급여 보기 권한에 맞는 엑셀 파일만 다운로드한다. 파일이 없으면 엑셀 파일이 다시 생성되도록 리다이렉트
+	user, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	withSalary, err := checkPermissionFunc(client, user, "viewsalary")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fileName := artistsExcelFileNameFunc("cm", withSalary)
+	_, err = os.Stat(path + "/" + fileName)
+	if err != nil {
+		http.Redirect(w, r, "/artists-cm", http.StatusSeeOther)
+		return
+	}
+
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   "CM 아티스트 페이지에서 아티스트 데이터를 다운로드하였습니다.",
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "CM 아티스트 페이지에서 아티스트 데이터를 다운로드하였습니다.",
+		Action:     LogActionExport,
+		EntityType: LogEntityArtist,
+		IP:         clientIPFunc(r),
+	}
+	if withSalary {
+		log.Content = "CM 아티스트 페이지에서 연봉 정보가 포함된 아티스트 데이터를 다운로드하였습니다."
 	}
 
 	err = addLogsFunc(client, log)
@@ -1053,6 +1115,6 @@ func handleExportArtistsCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=%s", fileInfo[0].Name()))
-	http.ServeFile(w, r, path+"/"+fileInfo[0].Name())
+	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=%s", fileName))
+	http.ServeFile(w, r, path+"/"+fileName)
 }
@@ -29,11 +29,7 @@ func handleArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -65,6 +61,7 @@ func handleArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		Year       string
 		Resination bool // 퇴사자 토글 옵션값
 		Artists    []Artist
+		ViewSalary bool // 시급을 볼 수 있는지 여부
 	}
 	rcp := Recipe{}
 	rcp.Token = token
@@ -73,6 +70,11 @@ func handleArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	rcp.ViewSalary, err = checkPermissionFunc(client, rcp.User, "viewsalary")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	year := r.FormValue("year")
 	if year == "" { // year 값이 없으면 올해로 검색
@@ -102,7 +104,25 @@ func handleArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = genArtistsVFXExcelFunc(rcp.Artists, token.ID) // 엑셀 파일 미리 생성
+	// 급여 보기 권한이 없으면 연봉 정보를 지우고, 있으면 급여 정보 조회 로그를 남긴다.
+	if !rcp.ViewSalary {
+		rcp.Artists = maskArtistsSalaryFunc(rcp.Artists)
+	} else {
+		err = addLogsFunc(client, Log{
+			UserID:     token.ID,
+			CreatedAt:  time.Now(),
+			Content:    fmt.Sprintf("VFX 아티스트 페이지에서 %s년 아티스트 시급을 조회하였습니다.", rcp.Year),
+			Action:     LogActionView,
+			EntityType: LogEntityArtist,
+			IP:         clientIPFunc(r),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err = genArtistsVFXExcelFunc(rcp.Artists, rcp.ViewSalary, token.ID) // 엑셀 파일 미리 생성
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -125,11 +145,7 @@ func handleEditArtistVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -190,6 +206,20 @@ func handleEditArtistVFXFunc(w http.ResponseWriter, r *http.Request) {
 		rcp.Artist.Salary[key] = result
 	}
 
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("VFX 아티스트 %s의 연봉 정보를 조회하였습니다.", rcp.Artist.ID),
+		Action:     LogActionView,
+		EntityType: LogEntityArtist,
+		EntityID:   rcp.Artist.ID,
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/html")
 	err = TEMPLATES.ExecuteTemplate(w, "edit-artistvfx", rcp)
 	if err != nil {
@@ -207,11 +237,7 @@ func handleEditArtistVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -243,6 +269,7 @@ func handleEditArtistVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	before := artist
 	artist.Dept = r.FormValue("dept")
 	artist.Team = r.FormValue("team")
 	artist.Name = r.FormValue("name")
@@ -318,11 +345,22 @@ func handleEditArtistVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// 퇴사 여부는 DB에 저장할 때 정해지므로 저장된 값과 비교한다.
+	after, err := getArtistFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	log := Log{}
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("VFX 아티스트 ID %s의 정보가 수정되었습니다.", id)
+	log.Action = LogActionUpdate
+	log.EntityType = LogEntityArtist
+	log.EntityID = id
+	log.Changes = diffEntityFunc(before, after)
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -342,11 +380,7 @@ func handleEditArtistVFXSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -381,11 +415,7 @@ func handleUpdateArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -404,17 +434,13 @@ func handleUpdateArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 // handleArtistsVFXExcelDownloadFunc 함수는 VFX 아티스트 정보를 입력할 엑셀 파일의 템플릿을 생성하여 다운로드하는 함수이다.
 func handleArtistsVFXExcelDownloadFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Only", http.StatusMethodNotAllowed)
@@ -473,11 +499,7 @@ func handleArtistsVFXExcelSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Method Only", http.StatusMethodNotAllowed)
@@ -628,11 +650,7 @@ func handleUpdateArtistsVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -761,11 +779,47 @@ func handleUpdateArtistsVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		before, err := getArtistFunc(client, a.ID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		err = updateArtistFunc(client, a) // DB에 아티스트 추가
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		after, err := getArtistFunc(client, a.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// 바뀐 아티스트만 로그를 남긴다.
+		changes := diffEntityFunc(before, after)
+		if len(changes) == 0 {
+			continue
+		}
+		action := LogActionUpdate
+		content := fmt.Sprintf("VFX 아티스트 ID %s의 정보가 수정되었습니다.(엑셀)", a.ID)
+		if before.ID == "" {
+			action = LogActionCreate
+			content = fmt.Sprintf("VFX 아티스트 ID %s가 추가되었습니다.(엑셀)", a.ID)
+		}
+		err = addLogsFunc(client, Log{
+			UserID:     token.ID,
+			CreatedAt:  time.Now(),
+			Content:    content,
+			Action:     action,
+			EntityType: LogEntityArtist,
+			EntityID:   a.ID,
+			Changes:    changes,
+			IP:         clientIPFunc(r),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
 	log := Log{}
@@ -784,6 +838,9 @@ func handleUpdateArtistsVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		}
 
 		log.Content = "VFX 아티스트 업데이트에 실패했습니다."
+		log.Action = LogActionImport
+		log.EntityType = LogEntityArtist
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -799,6 +856,9 @@ func handleUpdateArtistsVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		log.Content = "VFX 아티스트 업데이트를 완료했습니다."
+		log.Action = LogActionImport
+		log.EntityType = LogEntityArtist
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -818,11 +878,7 @@ func handleUpdateArtistsVFXSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -839,9 +895,9 @@ func handleUpdateArtistsVFXSuccessFunc(w http.ResponseWriter, r *http.Request) {
 }
 
 // genArtistsVFXExcelFunc 함수는 VFX팀의 아티스트 데이터를 엑셀 파일로 생성하는 함수이다.
-func genArtistsVFXExcelFunc(artists []Artist, userID string) error {
+func genArtistsVFXExcelFunc(artists []Artist, withSalary bool, userID string) error {
 	path := os.TempDir() + "/budget/" + userID + "/artistsvfx"
-	fileName := "vfx_artists.xlsx"
+	fileName := artistsExcelFileNameFunc("vfx", withSalary)
 
 	err := createFolderFunc(path)
 	if err != nil {
@@ -976,11 +1032,7 @@ func handleExportArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -990,26 +1042,6 @@ func handleExportArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 
 	path := os.TempDir() + "/budget/" + token.ID + "/artistsvfx"
 
-	// path에 있는 파일들을 가져온다.
-	fileInfo, err := ioutil.ReadDir(path)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	// path에 파일의 개수가 하나가 아니면 엑셀 파일이 다시 생성되도록 리다이렉트
-	if len(fileInfo) != 1 {
-		http.Redirect(w, r, "/artists-vfx", http.StatusSeeOther)
-		return
-	}
-
-	// 파일의 확장자가 xlsx가 아니면 엑셀 파일이 다시 생성되도록 리다이렉트
-	ext := filepath.Ext(fileInfo[0].Name())
-	if ext != ".xlsx" {
-		http.Redirect(w, r, "/artists-vfx", http.StatusSeeOther)
-		return
-	}
-
 	// mongoDB client 연결
 	credential := options.Credential{
 		Username: *flagDBID,
@@ -1034,10 +1066,34 @@ func handleExportArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 급여 보기 권한에 맞는 엑셀 파일만 다운로드한다. 파일이 없으면 엑셀 파일이 다시 생성되도록 리다이렉트
+	user, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	withSalary, err := checkPermissionFunc(client, user, "viewsalary")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fileName := artistsExcelFileNameFunc("vfx", withSalary)
+	_, err = os.Stat(path + "/" + fileName)
+	if err != nil {
+		http.Redirect(w, r, "/artists-vfx", http.StatusSeeOther)
+		return
+	}
+
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   "VFX 아티스트 페이지에서 아티스트 데이터를 다운로드하였습니다.",
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "VFX 아티스트 페이지에서 아티스트 데이터를 다운로드하였습니다.",
+		Action:     LogActionExport,
+		EntityType: LogEntityArtist,
+		IP:         clientIPFunc(r),
+	}
+	if withSalary {
+		log.Content = "VFX 아티스트 페이지에서 연봉 정보가 포함된 아티스트 데이터를 다운로드하였습니다."
 	}
 
 	err = addLogsFunc(client, log)
@@ -1046,6 +1102,6 @@ func handleExportArtistsVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=%s", fileInfo[0].Name()))
-	http.ServeFile(w, r, path+"/"+fileInfo[0].Name())
+	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=%s", fileName))
+	http.ServeFile(w, r, path+"/"+fileName)
 }
@@ -0,0 +1,294 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 예산 대비 실제 비용 페이지 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleBGActualFunc 함수는 결산 프로젝트의 예산 대비 실제 비용 페이지를 여는 함수이다.
+func handleBGActualFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token     Token
+		Project   Project    // 결산 프로젝트
+		BGProject BGProject  // 연결된 예산 프로젝트
+		Items     []BGActual // 본부, 부서별 예산 대비 실제 비용
+		Total     BGActual   // 합계
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Project, err = getProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 연결된 예산 프로젝트가 있을 때만 비교한다.
+	if rcp.Project.BGProjectID != "" {
+		rcp.BGProject, err = getBGProjectFunc(client, rcp.Project.BGProjectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.Items, err = calBGActualFunc(client, rcp.Project, rcp.BGProject)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.Total = calBGActualTotalFunc(rcp.Items)
+
+		err = genBGActualExcelFunc(rcp.Project, rcp.BGProject, rcp.Items, rcp.Total, token.ID) // 엑셀 파일 미리 생성
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgactual", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// genBGActualExcelFunc 함수는 예산 대비 실제 비용 엑셀 파일을 생성하는 함수이다.
+func genBGActualExcelFunc(project Project, bgp BGProject, items []BGActual, total BGActual, userID string) error {
+	path := os.TempDir() + "/budget/" + userID + "/bgactual/"
+	excelFileName := fmt.Sprintf("bgactual_%s.xlsx", project.ID)
+
+	err := createFolderFunc(path)
+	if err != nil {
+		return err
+	}
+	err = delAllFilesFunc(path)
+	if err != nil {
+		return err
+	}
+
+	// 엑셀 파일 생성
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+	index := f.NewSheet(sheet)
+	f.SetActiveSheet(index)
+
+	// 스타일
+	style, err := f.NewStyle(`{"alignment":{"horizontal":"center","vertical":"center","wrap_text":true}}`)
+	if err != nil {
+		return err
+	}
+	numberStyle, err := f.NewStyle(`{"alignment":{"horizontal":"right","vertical":"center","wrap_text":true}, "number_format": 3}`)
+	if err != nil {
+		return err
+	}
+	totalStyle, err := f.NewStyle(
+		`
+		{"alignment":{"horizontal":"center","vertical":"center","wrap_text":true},
+		"font":{"bold":true},
+		"fill":{"type":"pattern","color":["#FFC000"],"pattern":1}}
+		`)
+	if err != nil {
+		return err
+	}
+	totalNumStyle, err := f.NewStyle(
+		`
+		{"alignment":{"horizontal":"right","vertical":"center","wrap_text":true},
+		"font":{"bold":true},
+		"fill":{"type":"pattern","color":["#FFC000"],"pattern":1},
+		"number_format": 3}
+		`)
+	if err != nil {
+		return err
+	}
+
+	// 제목 입력
+	f.SetCellValue(sheet, "A1", fmt.Sprintf("%s (%s) - 예산안: %s %s", project.Name, project.ID, bgp.Name, bgp.MainType))
+	f.MergeCell(sheet, "A1", "F1")
+	titles := []string{"본부", "부서", "예산", "실제 비용", "차이", "차이 비율(%)"}
+	for i, title := range titles {
+		pos, err := excelize.CoordinatesToCellName(i+1, 2)
+		if err != nil {
+			return err
+		}
+		f.SetCellValue(sheet, pos, title)
+	}
+	f.SetColWidth(sheet, "A", "B", 15)
+	f.SetColWidth(sheet, "C", "E", 18)
+	f.SetColWidth(sheet, "F", "F", 12)
+
+	// 데이터 입력
+	row := 3
+	for _, item := range append(items, total) {
+		values := []interface{}{item.Headquarter, item.Department, item.Budget, item.Actual, item.Variance, item.VarianceRatio}
+		if item.VarianceRatio != "" {
+			ratio, err := strconv.ParseFloat(item.VarianceRatio, 64)
+			if err != nil {
+				return err
+			}
+			values[5] = ratio
+		}
+		for i, value := range values {
+			pos, err := excelize.CoordinatesToCellName(i+1, row)
+			if err != nil {
+				return err
+			}
+			f.SetCellValue(sheet, pos, value)
+		}
+		f.SetRowHeight(sheet, row, 20)
+		row++
+	}
+
+	last := fmt.Sprintf("F%d", row-1)
+	f.SetCellStyle(sheet, "A1", last, style)
+	f.SetCellStyle(sheet, "C3", fmt.Sprintf("E%d", row-1), numberStyle)
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row-1), fmt.Sprintf("B%d", row-1), totalStyle)
+	f.SetCellStyle(sheet, fmt.Sprintf("C%d", row-1), last, totalNumStyle)
+
+	// 엑셀 파일 저장
+	err = f.SaveAs(path + excelFileName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// handleExportBGActualFunc 함수는 임시 폴더에 저장된 예산 대비 실제 비용 엑셀 파일을 다운로드하는 함수이다.
+func handleExportBGActualFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := os.TempDir() + "/budget/" + token.ID + "/bgactual"
+
+	// path에 있는 파일들을 가져온다.
+	fileInfo, err := ioutil.ReadDir(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(fileInfo) == 0 || filepath.Ext(fileInfo[0].Name()) != ".xlsx" {
+		http.Error(w, "다운로드할 엑셀 파일이 없습니다. 페이지를 새로고침 해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 엑셀 파일의 프로젝트가 사용자에게 허용된 프로젝트인지 확인한다.
+	project := strings.TrimSuffix(strings.TrimPrefix(fileInfo[0].Name(), "bgactual_"), ".xlsx")
+	granted, err := checkProjectGrantFunc(client, token.ID, project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !granted {
+		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
+		return
+	}
+
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s 예산 대비 실제 비용 페이지에서 데이터를 다운로드하였습니다.", project),
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		EntityID:   project,
+		IP:         clientIPFunc(r),
+	}
+
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=%s", fileInfo[0].Name()))
+	http.ServeFile(w, r, path+"/"+fileInfo[0].Name())
+}
@@ -0,0 +1,263 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 예산안 결재 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleBGApprovalFunc 함수는 예산안의 결재 상태와 결재 기록을 보여주는 페이지를 여는 함수이다.
+func handleBGApprovalFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	q := r.URL.Query()
+	id := q.Get("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+	bgtype := q.Get("bgtype")
+	if bgtype == "" {
+		http.Error(w, "URL에 bgtype을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token        Token
+		BGProject    BGProject
+		BGType       string        // 예산안 이름
+		TypeData     BGTypeData    // 예산안 데이터
+		Status       string        // 결재 상태 ex) draft, submitted
+		TeamSetting  BGTeamSetting // 본부별 결재자가 설정된 예산 팀세팅
+		PendingHeads []string      // 현재 결재 상태에서 아직 승인하지 않은 본부 리스트
+		Submitter    string        // 결재 요청자 ID
+		CanApprove   bool          // 예산안 승인 권한이 있는지 여부
+		CanManage    bool          // 예산안 결재 관리 권한이 있는지 여부
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	user, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.CanApprove, err = checkPermissionFunc(client, user, "approvebudget")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.CanManage, err = checkPermissionFunc(client, user, "manageapproval")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.BGProject, err = getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	typedata, ok := rcp.BGProject.TypeData[bgtype]
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s 예산안이 존재하지 않습니다", bgtype), http.StatusBadRequest)
+		return
+	}
+	rcp.BGType = bgtype
+	rcp.TypeData = typedata
+	rcp.Status = getBGApprovalStatusFunc(typedata)
+	rcp.TeamSetting, err = getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.PendingHeads = getBGPendingHeadsFunc(rcp.TeamSetting, typedata)
+	rcp.Submitter = getBGSubmitterFunc(typedata)
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgapproval", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleBGApprovalSubmitFunc 함수는 예산안 결재 페이지에서 결재 요청, 승인, 반려 등의 버튼을 클릭했을 때 실행되는 함수이다.
+func handleBGApprovalSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	bgtype := r.FormValue("bgtype")
+	action := r.FormValue("action")
+	comment := r.FormValue("comment")
+	if id == "" || bgtype == "" || action == "" {
+		http.Error(w, "id, bgtype, action을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bgp, err := getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	snapshot := snapshotEntityFunc(bgp)
+	typedata, ok := bgp.TypeData[bgtype]
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s 예산안이 존재하지 않습니다", bgtype), http.StatusBadRequest)
+		return
+	}
+	ts, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 페이지는 예산 프로젝트 수정 권한으로 열 수 있고, 승인과 반려는 예산안 승인 권한이 있어야 한다.
+	user, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if action == BGApprovalActionApprove || action == BGApprovalActionReject {
+		canApprove, err := checkPermissionFunc(client, user, "approvebudget")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !canApprove {
+			http.Error(w, "예산안 승인 권한이 없습니다", http.StatusForbidden)
+			return
+		}
+	}
+	manage, err := checkPermissionFunc(client, user, "manageapproval")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	before := getBGApprovalStatusNameFunc(typedata)
+	err = applyBGApprovalFunc(ts, &typedata, action, token.ID, manage, comment)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bgp.TypeData[bgtype] = typedata
+	bgp.UpdatedTime = time.Now().Format(time.RFC3339)
+
+	err = setBGProjectFunc(client, bgp, bgp.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 결재 상태와 기록도 예산안 데이터이므로 리비전을 추가한다.
+	err = addBGRevisionsFunc(client, bgp, token.ID, fmt.Sprintf("예산안 결재 %s (%s -> %s)", action, before, getBGApprovalStatusNameFunc(typedata)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	content := fmt.Sprintf("예산 프로젝트 %s의 %s 예산안에 %s 결재 작업을 했습니다(%s -> %s).", bgp.ID, bgtype, action, before, getBGApprovalStatusNameFunc(typedata))
+	if comment != "" {
+		content += " 코멘트: " + comment
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    content,
+		Action:     LogActionUpdate,
+		EntityType: LogEntityBGProject,
+		EntityID:   bgp.ID,
+		Changes:    diffEntityFunc(snapshot, bgp),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 다음 결재자, 결재 요청자, 예산안 결재 알림을 구독한 사용자들에게 알린다.
+	err = addBGApprovalNotificationFunc(client, bgp, bgtype, ts, token.ID, content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/bgapproval?id=%s&bgtype=%s", url.QueryEscape(bgp.ID), url.QueryEscape(bgtype)), http.StatusSeeOther)
+}
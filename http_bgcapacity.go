@@ -0,0 +1,356 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 예산 프로젝트 인력 수급 계획 페이지 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/360EntSecGroup-Skylar/excelize/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleBGCapacityFunc 함수는 예산 프로젝트의 bid로 팀별 월별 필요 인력과 가용 인력을 비교하는 페이지를 여는 함수이다.
+func handleBGCapacityFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	q := r.URL.Query()
+	startDate := q.Get("start")
+	endDate := q.Get("end")
+	if startDate == "" { // 시작월이 없으면 이번 달부터 검색
+		y, m, _ := time.Now().Date()
+		startDate = fmt.Sprintf("%04d-%02d", y, m)
+	}
+	if endDate == "" { // 마감월이 없으면 시작월부터 12개월 검색
+		s, err := time.Parse("2006-01", startDate)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		endDate = s.AddDate(0, 11, 0).Format("2006-01")
+	}
+	if startDate > endDate {
+		http.Error(w, "시작월이 마감월보다 늦습니다", http.StatusBadRequest)
+		return
+	}
+	dates, err := getDatesFunc(startDate, endDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(dates) > 24 {
+		http.Error(w, "최대 24개월까지 검색할 수 있습니다", http.StatusBadRequest)
+		return
+	}
+	status := q.Get("status") // all: 계약 완료 + 사전 검토, true: 계약 완료
+	if status != "true" {
+		status = "all"
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token      Token
+		StartDate  string       // 시작월 yyyy-MM
+		EndDate    string       // 마감월 yyyy-MM
+		Status     string       // all, true
+		Dates      []string     // 검색 기간의 월 리스트
+		BGProjects []BGProject  // 검색 기간에 작업하는 예산 프로젝트 리스트
+		Capacities []BGCapacity // 팀별 월별 인력 수급
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.StartDate = startDate
+	rcp.EndDate = endDate
+	rcp.Status = status
+	rcp.Dates = dates
+
+	rcp.BGProjects, rcp.Capacities, err = calBGCapacitiesFunc(client, dates, status == "true")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = genBGCapacityExcelFunc(dates, rcp.Capacities, token.ID) // 엑셀 파일 미리 생성
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgcapacity", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// calBGCapacitiesFunc 함수는 검색 기간에 작업하는 예산 프로젝트들의 팀별 월별 인력 수급을 계산하는 함수이다.
+// contracted가 true이면 계약 완료된 프로젝트만 계산하고, false이면 사전 검토 프로젝트도 포함한다.
+func calBGCapacitiesFunc(client *mongo.Client, dates []string, contracted bool) ([]BGProject, []BGCapacity, error) {
+	searchword := "id:"
+	if contracted {
+		searchword += " status:true"
+	}
+	bgprojects, err := searchBGProjectFunc(client, searchword, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+	ts, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		return nil, nil, err
+	}
+	artists, err := getAllArtistFunc(client)
+	if err != nil {
+		return nil, nil, err
+	}
+	userTeams := make(map[string]string)
+	for _, artist := range artists {
+		userTeams[artist.ID] = getCapacityTeamFunc(artist)
+	}
+
+	startDate := dates[0]
+	endDate := dates[len(dates)-1]
+	var results []BGProject
+	demand := make(map[string]map[string]float64)
+	projects := make(map[string][]string)
+	for _, bgp := range bgprojects {
+		// 검색 기간에 작업하지 않는 프로젝트는 제외한다.
+		if bgp.StartDate == "" || bgp.EndDate == "" || bgp.StartDate > endDate || bgp.EndDate < startDate {
+			continue
+		}
+		bgts := bgp.TypeData[bgp.MainType].TeamSetting
+		if len(bgts.Headquarters) == 0 { // 예산안에 팀세팅이 저장되어 있지 않으면 현재 팀세팅을 사용한다.
+			bgts = ts
+		}
+		err = addBGCapacityDemandFunc(demand, projects, bgp, bgts, userTeams)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, bgp)
+	}
+
+	capacities, err := newBGCapacitiesFunc(dates, getBGTeamsFunc(ts), demand, projects, artists)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results, capacities, nil
+}
+
+// genBGCapacityExcelFunc 함수는 팀별 월별 인력 수급 엑셀 파일을 생성하는 함수이다.
+func genBGCapacityExcelFunc(dates []string, capacities []BGCapacity, userID string) error {
+	path := os.TempDir() + "/budget/" + userID + "/bgcapacity/"
+	excelFileName := fmt.Sprintf("bgcapacity_%s_%s.xlsx", dates[0], dates[len(dates)-1])
+
+	err := createFolderFunc(path)
+	if err != nil {
+		return err
+	}
+	err = delAllFilesFunc(path)
+	if err != nil {
+		return err
+	}
+
+	// 엑셀 파일 생성
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+	index := f.NewSheet(sheet)
+	f.SetActiveSheet(index)
+
+	// 스타일
+	style, err := f.NewStyle(`{"alignment":{"horizontal":"center","vertical":"center","wrap_text":true}}`)
+	if err != nil {
+		return err
+	}
+	numberStyle, err := f.NewStyle(`{"alignment":{"horizontal":"right","vertical":"center","wrap_text":true}, "number_format": 4}`)
+	if err != nil {
+		return err
+	}
+	overStyle, err := f.NewStyle(
+		`
+		{"alignment":{"horizontal":"right","vertical":"center","wrap_text":true},
+		"font":{"bold":true,"color":"#FF0000"},
+		"number_format": 4}
+		`)
+	if err != nil {
+		return err
+	}
+
+	// 제목 입력
+	f.SetCellValue(sheet, "A1", "팀")
+	f.SetCellValue(sheet, "B1", "구분")
+	for i, date := range dates {
+		pos, err := excelize.CoordinatesToCellName(i+3, 1)
+		if err != nil {
+			return err
+		}
+		f.SetCellValue(sheet, pos, date)
+	}
+	lastCol, err := excelize.ColumnNumberToName(len(dates) + 2)
+	if err != nil {
+		return err
+	}
+	f.SetColWidth(sheet, "A", "A", 20)
+	f.SetColWidth(sheet, "B", "B", 15)
+	f.SetColWidth(sheet, "C", lastCol, 12)
+
+	// 데이터 입력
+	row := 2
+	for _, c := range capacities {
+		team := c.Team
+		if team == "" {
+			team = "미지정"
+		}
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), team)
+		f.MergeCell(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row+4))
+		items := []struct {
+			title  string
+			values map[string]float64
+		}{
+			{"인원", c.Headcount},
+			{"가용 manday", c.Capacity},
+			{"필요 manday", c.Demand},
+			{"여유 manday", c.Balance},
+			{"배정률(%)", c.Ratio},
+		}
+		for _, item := range items {
+			f.SetCellValue(sheet, fmt.Sprintf("B%d", row), item.title)
+			for i, date := range dates {
+				pos, err := excelize.CoordinatesToCellName(i+3, row)
+				if err != nil {
+					return err
+				}
+				f.SetCellValue(sheet, pos, item.values[date])
+				f.SetCellStyle(sheet, pos, pos, numberStyle)
+				if c.Balance[date] < 0 && (item.title == "여유 manday" || item.title == "배정률(%)") {
+					f.SetCellStyle(sheet, pos, pos, overStyle)
+				}
+			}
+			f.SetRowHeight(sheet, row, 20)
+			row++
+		}
+	}
+	f.SetCellStyle(sheet, "A1", fmt.Sprintf("B%d", row-1), style)
+	f.SetCellStyle(sheet, "C1", fmt.Sprintf("%s1", lastCol), style)
+
+	// 엑셀 파일 저장
+	err = f.SaveAs(path + excelFileName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// handleExportBGCapacityFunc 함수는 임시 폴더에 저장된 인력 수급 엑셀 파일을 다운로드하는 함수이다.
+func handleExportBGCapacityFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := os.TempDir() + "/budget/" + token.ID + "/bgcapacity"
+
+	// path에 있는 파일들을 가져온다.
+	fileInfo, err := ioutil.ReadDir(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(fileInfo) == 0 || filepath.Ext(fileInfo[0].Name()) != ".xlsx" {
+		http.Error(w, "다운로드할 엑셀 파일이 없습니다. 페이지를 새로고침 해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	period := strings.Split(strings.TrimSuffix(strings.TrimPrefix(fileInfo[0].Name(), "bgcapacity_"), ".xlsx"), "_")
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("인력 수급 페이지에서 %s 데이터를 다운로드하였습니다.", strings.Join(period, " ~ ")),
+		Action:     LogActionExport,
+		EntityType: LogEntityBGProject,
+		IP:         clientIPFunc(r),
+	}
+
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=%s", fileInfo[0].Name()))
+	http.ServeFile(w, r, path+"/"+fileInfo[0].Name())
+}
@@ -23,11 +23,7 @@ func handleBGDetailFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -33,11 +33,7 @@ func handleBGProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -96,6 +92,7 @@ func handleBGProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	rcp.BGProjects = filterBGProjectsByGrantFunc(rcp.User, rcp.BGProjects) // 사용자에게 허용된 프로젝트만 보여준다.
 
 	err = genBGProjectsExcelFunc(date, rcp.BGProjects, token.ID)
 	if err != nil {
@@ -114,17 +111,13 @@ func handleBGProjectsFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchBGProjectsFunc 함수는 예산 프로젝트 페이지에서 Search를 눌렀을 때 실행되는 함수이다.
 func handleSearchBGProjectsFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	date := r.FormValue("date")
 	searchword := r.FormValue("searchword")
@@ -141,11 +134,7 @@ func handleAddBGProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -219,11 +208,7 @@ func handleAddBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -276,10 +261,18 @@ func handleAddBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	bgp.TypeList = append(bgp.TypeList, bgtype)        // 예산안 타입 리스트
 	bgp.TypeData = make(map[string]BGTypeData)         // 예산안 데이터
 
+	// 오늘 적용되는 본부별 단가표
+	rateCards, err := getEffectiveRateCardIDsFunc(client, bgts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// 예산안 정보
 	bgtd := BGTypeData{}                              // 예산안 타입 데이터
 	bgtd.ID = primitive.NewObjectID()                 // 예산안 Object ID 생성 후 저장
 	bgtd.TeamSetting = bgts                           // 예산안 팀세팅 저장
+	bgtd.RateCards = rateCards                        // 예산안 생성 당시 적용되는 단가표 고정
 	bgtd.ContractDate = r.FormValue("bgcontractdate") // 예산안 계약일
 	if r.FormValue("bgmaintypestatus") != "" {
 		status, err := strconv.ParseBool(r.FormValue("bgmaintypestatus"))
@@ -653,10 +646,21 @@ func handleAddBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 저장된 예산안의 리비전을 추가한다.
+	err = addBGRevisionsFunc(client, bgp, token.ID, "예산 프로젝트 추가")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("예산 프로젝트 %s %s가 추가되었습니다.", bgp.ID, bgp.Name),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("예산 프로젝트 %s %s가 추가되었습니다.", bgp.ID, bgp.Name),
+		Action:     LogActionCreate,
+		EntityType: LogEntityBGProject,
+		EntityID:   bgp.ID,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -677,11 +681,7 @@ func handleAddBGProjectSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -706,11 +706,7 @@ func handleEditBGProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -805,11 +801,7 @@ func handleEditBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 	originalID := r.FormValue("originalid")
 	searchedDate := r.FormValue("searcheddate")
 
@@ -850,6 +842,7 @@ func handleEditBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	snapshot := snapshotEntityFunc(bgp)
 	bgp.ID = strings.TrimSpace(strings.ToUpper(r.FormValue("id"))) // 프로젝트 ID
 	bgp.Name = strings.TrimSpace(r.FormValue("name"))              // 프로젝트 한글명
 	bgp.StartDate = r.FormValue("startdate")                       // 프로젝트 예상 시작일
@@ -865,6 +858,7 @@ func handleEditBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 
 	origTypeData := make(map[string]BGTypeData) // 기존의 예산안 데이터
 	origTypeData = bgp.TypeData
+	origMainType := bgp.MainType               // 기존의 메인 예산안
 	bgp.TypeList = nil                         // 예산안 리스트 초기화
 	bgp.TypeData = make(map[string]BGTypeData) // 예산안 데이터 초기화
 
@@ -886,18 +880,36 @@ func handleEditBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 
 		// 예산안이 기존의 예산안인지 고유 ID로 판단하기
 		bgtd := BGTypeData{}                                      // 예산안 타입 데이터
+		origName := ""                                            // 기존 예산안의 이름
 		if r.FormValue(fmt.Sprintf("type%d-bgtypeid", i)) == "" { // 예산안 ID가 존재하지 않는다면 -> 새로 생긴 탭
 			bgtd.ID = primitive.NewObjectID()
 			bgtd.TeamSetting = bgts
+			bgtd.RateCards, err = getEffectiveRateCardIDsFunc(client, bgts) // 예산안 생성 당시 적용되는 단가표 고정
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
 		} else { // 예산안 ID 정보가 있다면 기존의 예산안 정보를 가져오기
-			for _, value := range origTypeData {
+			for name, value := range origTypeData {
 				if r.FormValue(fmt.Sprintf("type%d-bgtypeid", i)) == value.ID.Hex() {
 					bgtd = value
+					origName = name
 					break
 				}
 			}
 		}
 
+		// 결재가 진행중이거나 승인된 예산안은 이름을 포함해 어떤 값도 바꿀 수 없으므로 바꾸려고 하면 에러를 반환한다.
+		err = checkBGTypeEditableFunc(origName, bgtd)
+		if err != nil {
+			if bgtype != origName || checkBGTypeFormEditedFunc(r.PostForm, i) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			bgp.TypeData[bgtype] = bgtd
+			continue
+		}
+
 		// 예산안 정보 입력
 		bgtd.ContractDate = r.FormValue(fmt.Sprintf("type%d-bgcontractdate", i)) // 예산안 계약일
 		if r.FormValue(fmt.Sprintf("type%d-bgmaintypestatus", i)) != "" {
@@ -1281,6 +1293,39 @@ func handleEditBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		bgp.TypeData[bgtype] = bgtd
 	}
 
+	// 결재가 진행중이거나 승인된 예산안은 메인 예산안으로 정하거나 메인 예산안에서 뺄 수 없다.
+	if bgp.MainType != origMainType {
+		for _, name := range []string{origMainType, bgp.MainType} {
+			orig, ok := origTypeData[name]
+			if !ok {
+				continue
+			}
+			err = checkBGTypeEditableFunc(name, orig)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	// 결재가 진행중이거나 승인된 예산안은 삭제할 수 없다.
+	for name, orig := range origTypeData {
+		if getBGApprovalStatusFunc(orig) == BGApprovalDraft {
+			continue
+		}
+		exist := false
+		for _, value := range bgp.TypeData {
+			if value.ID == orig.ID {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			http.Error(w, fmt.Sprintf("%s 예산안은 결재가 진행중이거나 승인되어 삭제할 수 없습니다", name), http.StatusBadRequest)
+			return
+		}
+	}
+
 	bgp.UpdatedTime = time.Now().Format(time.RFC3339) // 프로젝트의 마지막 업데이트된 시간을 현재 시간으로 설정
 
 	err = setBGProjectFunc(client, bgp, originalID)
@@ -1289,10 +1334,29 @@ func handleEditBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 예산 프로젝트 ID가 바뀌었으면 리비전의 프로젝트 ID도 바꾼 후 저장된 예산안의 리비전을 추가한다.
+	if originalID != bgp.ID {
+		err = renameBGRevisionsFunc(client, originalID, bgp.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	err = addBGRevisionsFunc(client, bgp, token.ID, "예산 프로젝트 수정")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("예산 프로젝트 %s의 정보가 수정되었습니다.", originalID),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("예산 프로젝트 %s의 정보가 수정되었습니다.", originalID),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityBGProject,
+		EntityID:   bgp.ID,
+		Changes:    diffEntityFunc(snapshot, bgp),
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -1301,6 +1365,13 @@ func handleEditBGProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 예산 변경 알림을 구독한 사용자들에게 알린다.
+	err = addBudgetChangedNotificationFunc(client, bgp, token.ID, log.Content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	http.Redirect(w, r, fmt.Sprintf("/editbgproject-success?id=%s&date=%s", bgp.ID, searchedDate), http.StatusSeeOther)
 }
 
@@ -1313,11 +1384,7 @@ func handleEditBGProjectSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -1581,11 +1648,7 @@ func handleExportBGProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1642,9 +1705,12 @@ func handleExportBGProjectsFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("예산 프로젝트 관리 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[1], filename[2]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("예산 프로젝트 관리 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[1], filename[2]),
+		Action:     LogActionExport,
+		EntityType: LogEntityBGProject,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -1666,11 +1732,7 @@ func handleBGProjectTSFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -1779,11 +1841,7 @@ func handleBGProjectTSSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -1819,7 +1877,13 @@ func handleBGProjectTSSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	snapshot := snapshotEntityFunc(bgp)
 	bgTypeData := bgp.TypeData[bgtype]
+	err = checkBGTypeEditableFunc(bgtype, bgTypeData)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	ts := BGTeamSetting{}
 	ts.Departments = make(map[string][]BGDept)
@@ -1945,6 +2009,7 @@ func handleBGProjectTSSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		ts.Teams[taskName] = teamList
 	}
 
+	ts.Version = bgTypeData.TeamSetting.Version // 예산안 팀세팅을 수정해도 기반이 된 팀세팅 버전은 유지한다.
 	bgTypeData.TeamSetting = ts
 	bgTypeData.TeamSetting.UpdatedTime = time.Now().Format(time.RFC3339) // 팀세팅의 마지막 업데이트된 시간을 현재 시간으로 설정
 	bgp.TypeData[bgtype] = bgTypeData
@@ -1955,6 +2020,28 @@ func handleBGProjectTSSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 저장된 예산안의 리비전을 추가한다.
+	err = addBGRevisionsFunc(client, bgp, token.ID, "예산안 팀세팅 수정")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("예산 프로젝트 %s의 %s 예산안 팀세팅이 수정되었습니다.", id, bgtype),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityBGProject,
+		EntityID:   id,
+		Changes:    diffEntityFunc(snapshot, bgp),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	http.Redirect(w, r, fmt.Sprintf("/bgproject-teamsetting-success?id=%s&bgtype=%s&date=%s", id, bgtype, date), http.StatusSeeOther)
 }
 
@@ -1967,11 +2054,7 @@ func handleBGProjectTSSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token  Token
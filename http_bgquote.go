@@ -0,0 +1,229 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 예산안 견적서 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleBGQuoteFunc 함수는 예산안으로 만든 견적서를 미리 보고 다운로드 옵션을 정하는 페이지를 여는 함수이다.
+func handleBGQuoteFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	q := r.URL.Query()
+	id := q.Get("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+	bgtype := q.Get("bgtype")
+	if bgtype == "" {
+		http.Error(w, "URL에 bgtype을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bgp, err := getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token Token
+		Quote BGQuote // 미리 보기 견적서
+		Terms string  // 견적 조건, 한 줄에 하나씩
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Quote, err = newBGQuoteFunc(bgp, bgtype)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	rcp.Quote.QuoteDate = now.Format("2006-01-02")
+	rcp.Quote.ValidDate = now.AddDate(0, 0, 30).Format("2006-01-02") // 기본 유효기간은 30일
+	rcp.Terms = strings.Join(rcp.Quote.Terms, "\n")
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgquote", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleBGQuoteSubmitFunc 함수는 입력한 옵션으로 견적서를 엑셀 또는 PDF 파일로 만들어 다운로드하는 함수이다.
+func handleBGQuoteSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	bgtype := r.FormValue("bgtype")
+	if id == "" || bgtype == "" {
+		http.Error(w, "id, bgtype을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+	format := r.FormValue("format")
+	if format != "xlsx" && format != "pdf" {
+		http.Error(w, "format은 xlsx, pdf 중 하나를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+	quoteDate := r.FormValue("quotedate")
+	validDate := r.FormValue("validdate")
+	for _, date := range []string{quoteDate, validDate} {
+		_, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			http.Error(w, "견적일과 유효기간을 yyyy-MM-dd 형식으로 입력해주세요", http.StatusBadRequest)
+			return
+		}
+	}
+	if quoteDate > validDate {
+		http.Error(w, "유효기간이 견적일보다 빠릅니다", http.StatusBadRequest)
+		return
+	}
+	var terms []string
+	for _, line := range strings.Split(r.FormValue("terms"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			terms = append(terms, line)
+		}
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bgp, err := getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	quote, err := newBGQuoteFunc(bgp, bgtype)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	quote.QuoteDate = quoteDate
+	quote.ValidDate = validDate
+	quote.Terms = terms
+	quote.HideMargin = r.FormValue("hidemargin") == "on"
+	quote.Writer = token.ID
+
+	path := os.TempDir() + "/budget/" + token.ID + "/bgquote/"
+	err = createFolderFunc(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	err = delAllFilesFunc(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fileName := getBGQuoteFileNameFunc(quote, format)
+	if format == "pdf" {
+		err = genBGQuotePDFFunc(quote, path+fileName)
+	} else {
+		err = genBGQuoteExcelFunc(quote, path+fileName)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	content := fmt.Sprintf("%s 예산 프로젝트의 %s 예산안으로 견적서(%s)를 다운로드하였습니다.", id, bgtype, format)
+	if !quote.HideMargin {
+		content = fmt.Sprintf("%s 예산 프로젝트의 %s 예산안으로 내부 비용이 포함된 견적서(%s)를 다운로드하였습니다.", id, bgtype, format)
+	}
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    content,
+		Action:     LogActionExport,
+		EntityType: LogEntityBGProject,
+		EntityID:   id,
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=%s", fileName))
+	http.ServeFile(w, r, path+fileName)
+}
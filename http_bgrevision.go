@@ -0,0 +1,325 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 예산안 리비전, 비교 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleBGRevisionsFunc 함수는 예산 프로젝트의 예산안 리비전 히스토리 페이지를 여는 함수이다.
+func handleBGRevisionsFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token     Token
+		BGProject BGProject
+		Revisions []BGRevision      // 예산안 리비전 리스트
+		TypeNames map[string]string // 예산안 고유 ID별 현재 예산안 이름
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.BGProject, err = getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Revisions, err = getBGRevisionsFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.TypeNames = make(map[string]string)
+	for bgtype, typedata := range rcp.BGProject.TypeData {
+		rcp.TypeNames[getBGTypeIDFunc(typedata, bgtype)] = bgtype
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgrevisions", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleBGRevisionRestoreFunc 함수는 예산안을 선택한 리비전의 데이터로 되돌리는 함수이다.
+// 되돌린 결과도 새로운 리비전으로 저장되므로 이전 리비전은 그대로 유지된다.
+func handleBGRevisionRestoreFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	revID := r.FormValue("revision")
+	if revID == "" {
+		http.Error(w, "복원할 리비전을 선택해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rev, err := getBGRevisionFunc(client, revID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// 리비전의 프로젝트가 사용자에게 허용된 프로젝트인지 확인한다.
+	granted, err := checkProjectGrantFunc(client, token.ID, rev.ProjectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !granted {
+		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
+		return
+	}
+	bgp, err := getBGProjectFunc(client, rev.ProjectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	snapshot := snapshotEntityFunc(bgp)
+
+	// 리비전의 예산안이 현재 남아있으면 그 예산안을 되돌리고, 삭제되었으면 저장 당시 이름으로 다시 추가한다.
+	bgtype := ""
+	for name, typedata := range bgp.TypeData {
+		if getBGTypeIDFunc(typedata, name) == rev.TypeID {
+			bgtype = name
+			break
+		}
+	}
+	if bgtype == "" {
+		if _, ok := bgp.TypeData[rev.BGType]; ok {
+			http.Error(w, fmt.Sprintf("%s 이름의 다른 예산안이 있어 복원할 수 없습니다. 예산안 이름을 바꾼 후 다시 시도해주세요", rev.BGType), http.StatusBadRequest)
+			return
+		}
+		bgtype = rev.BGType
+		bgp.TypeList = append(bgp.TypeList, bgtype)
+	} else {
+		err = checkBGTypeEditableFunc(bgtype, bgp.TypeData[bgtype])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// 복원한 예산안은 작성중 상태가 되고, 결재 기록은 현재 예산안의 기록을 유지한다.
+	restored := rev.TypeData
+	restored.ApprovalStatus = BGApprovalDraft
+	restored.Approvals = bgp.TypeData[bgtype].Approvals
+	bgp.TypeData[bgtype] = restored
+	bgp.UpdatedTime = time.Now().Format(time.RFC3339)
+
+	err = setBGProjectFunc(client, bgp, bgp.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 저장된 예산안의 리비전을 추가한다.
+	err = addBGRevisionsFunc(client, bgp, token.ID, fmt.Sprintf("v%d 복원", rev.Revision))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("예산 프로젝트 %s의 %s 예산안이 v%d 리비전으로 복원되었습니다.", bgp.ID, bgtype, rev.Revision),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityBGProject,
+		EntityID:   bgp.ID,
+		Changes:    diffEntityFunc(snapshot, bgp),
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 예산 변경 알림을 구독한 사용자들에게 알린다.
+	err = addBudgetChangedNotificationFunc(client, bgp, token.ID, log.Content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/bgrevisions?id=%s", bgp.ID), http.StatusSeeOther)
+}
+
+// handleBGCompareFunc 함수는 두 예산안 또는 리비전을 나란히 비교하는 페이지를 여는 함수이다.
+func handleBGCompareFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	q := r.URL.Query()
+	id := q.Get("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token     Token
+		BGProject BGProject
+		Revisions []BGRevision   // 선택할 수 있는 리비전 리스트
+		A         string         // 기준 비교 대상 ex) type:A안, rev:60a1...
+		B         string         // 비교 대상
+		ALabel    string         // 기준 비교 대상 이름
+		BLabel    string         // 비교 대상 이름
+		Rows      []BGCompareRow // 비교 항목
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.BGProject, err = getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Revisions, err = getBGRevisionsFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 비교 대상을 선택하지 않았으면 메인 예산안을 기준으로 한다.
+	rcp.A = q.Get("a")
+	if rcp.A == "" {
+		rcp.A = "type:" + rcp.BGProject.MainType
+	}
+	rcp.B = q.Get("b")
+	if rcp.B != "" {
+		a, aLabel, err := getBGCompareTargetFunc(client, rcp.BGProject, rcp.A)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b, bLabel, err := getBGCompareTargetFunc(client, rcp.BGProject, rcp.B)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rcp.ALabel = aLabel
+		rcp.BLabel = bLabel
+		rcp.Rows, err = calBGCompareFunc(a, b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgcompare", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -25,11 +25,7 @@ func handleBGTeamSettingFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -122,11 +118,7 @@ func handleBGTeamSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -156,6 +148,7 @@ func handleBGTeamSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	ts.Departments = make(map[string][]BGDept)
 	ts.Controls = make(map[string][]BGControl)
 	ts.Teams = make(map[string][]string)
+	ts.Approvers = make(map[string]BGApprover)
 
 	headNum, err := strconv.Atoi(r.FormValue("headnum"))
 	if err != nil {
@@ -172,6 +165,12 @@ func handleBGTeamSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 
 		ts.Headquarters = append(ts.Headquarters, headName)
 
+		// 본부별 예산안 결재자
+		ts.Approvers[headName] = BGApprover{
+			Production: stringToListFunc(r.FormValue(fmt.Sprintf("head%d-approver-production", hIndex)), " "),
+			Management: stringToListFunc(r.FormValue(fmt.Sprintf("head%d-approver-management", hIndex)), " "),
+		}
+
 		// 본부별 부서
 		deptNum, err := strconv.Atoi(r.FormValue(fmt.Sprintf("head%d-deptnum", hIndex)))
 		if err != nil {
@@ -277,7 +276,27 @@ func handleBGTeamSettingSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		ts.Teams[taskName] = teamList
 	}
 
-	err = setBGTeamSettingFunc(client, ts)
+	before, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ts, err = addBGTeamSettingVersionFunc(client, ts, token.ID, r.FormValue("note"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("예산 팀세팅을 수정하였습니다. (버전 %d)", ts.Version),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityTeamSetting,
+		Changes:    diffEntityFunc(before, ts),
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -295,11 +314,7 @@ func handleBGTeamSEttingSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -0,0 +1,485 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 예산 팀세팅 버전 기록 및 마이그레이션 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// getBGTypesByTeamSettingVersionFunc 함수는 팀세팅 버전별 예산안 리스트를 반환하는 함수이다.
+func getBGTypesByTeamSettingVersionFunc(client *mongo.Client) (map[int][]BGMigrationTarget, error) {
+	bgprojects, err := searchBGProjectFunc(client, "id:", "id")
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int][]BGMigrationTarget)
+	for _, bgp := range bgprojects {
+		for _, bgtype := range bgp.TypeList {
+			typedata, ok := bgp.TypeData[bgtype]
+			if !ok {
+				continue
+			}
+			version := typedata.TeamSetting.Version
+			result[version] = append(result[version], BGMigrationTarget{
+				ProjectID:   bgp.ID,
+				ProjectName: bgp.Name,
+				BGType:      bgtype,
+				Status:      getBGApprovalStatusFunc(typedata),
+				Editable:    checkBGTypeEditableFunc(bgtype, typedata) == nil,
+			})
+		}
+	}
+	return result, nil
+}
+
+// handleBGTeamSettingHistoryFunc 함수는 예산 팀세팅 버전 기록 페이지를 여는 함수이다.
+func handleBGTeamSettingHistoryFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token    Token
+		Current  BGTeamSetting          // 현재 팀세팅
+		Versions []BGTeamSettingVersion // 팀세팅 버전 기록
+		Usage    map[int]int            // 버전별 예산안 개수
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Current, err = getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Versions, err = getBGTeamSettingVersionsFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	types, err := getBGTypesByTeamSettingVersionFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Usage = make(map[int]int)
+	for version, targets := range types {
+		rcp.Usage[version] = len(targets)
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgteamsetting-history", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleBGTeamSettingDiffFunc 함수는 두 팀세팅 버전의 차이를 보여주는 페이지를 여는 함수이다.
+// to가 없으면 현재 팀세팅, from이 없으면 to의 이전 버전과 비교한다.
+func handleBGTeamSettingDiffFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	current, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	q := r.URL.Query()
+	to := current.Version
+	if q.Get("to") != "" {
+		to, err = strconv.Atoi(q.Get("to"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	from := to - 1
+	if q.Get("from") != "" {
+		from, err = strconv.Atoi(q.Get("from"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if from < 1 || to < 1 {
+		http.Error(w, "비교할 팀세팅 버전이 없습니다", http.StatusBadRequest)
+		return
+	}
+
+	type Recipe struct {
+		Token  Token
+		From   BGTeamSettingVersion // 이전 버전
+		To     BGTeamSettingVersion // 이후 버전
+		Diffs  []BGTeamSettingDiff  // 차이 항목
+		Latest int                  // 현재 팀세팅 버전
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Latest = current.Version
+	rcp.From, err = getBGTeamSettingVersionFunc(client, from)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, fmt.Sprintf("%d 버전의 팀세팅이 없습니다", from), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.To, err = getBGTeamSettingVersionFunc(client, to)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, fmt.Sprintf("%d 버전의 팀세팅이 없습니다", to), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Diffs = diffBGTeamSettingFunc(rcp.From.TeamSetting, rcp.To.TeamSetting)
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgteamsetting-diff", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleBGTeamSettingMigrateFunc 함수는 이전 버전의 팀세팅으로 만든 예산안을 현재 팀세팅으로 옮기는 페이지를 여는 함수이다.
+func handleBGTeamSettingMigrateFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	from, err := strconv.Atoi(r.FormValue("from"))
+	if err != nil {
+		http.Error(w, "URL에 from 버전을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token       Token
+		From        int                 // 이전 팀세팅 버전
+		Current     BGTeamSetting       // 현재 팀세팅
+		Diffs       []BGTeamSettingDiff // 이전 버전과 현재 팀세팅의 차이
+		Targets     []BGMigrationTarget // 이전 버전의 팀세팅을 사용하는 예산안
+		Tasks       []string            // 예산안에서 사용하는 태스크
+		TaskOptions []string            // 현재 팀세팅의 태스크
+		TaskMap     map[string]string   // 태스크 기본 매핑
+		Depts       []string            // 예산안 인건비의 본부/부서
+		DeptOptions []string            // 현재 팀세팅의 본부/부서
+		DeptMap     map[string]string   // 부서 기본 매핑
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.From = from
+	rcp.Current, err = getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if from == rcp.Current.Version {
+		http.Error(w, "현재 버전의 팀세팅입니다", http.StatusBadRequest)
+		return
+	}
+	if from > 0 {
+		v, err := getBGTeamSettingVersionFunc(client, from)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err == nil {
+			rcp.Diffs = diffBGTeamSettingFunc(v.TeamSetting, rcp.Current)
+		}
+	}
+
+	types, err := getBGTypesByTeamSettingVersionFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Targets = types[from]
+
+	var tds []BGTypeData
+	for _, t := range rcp.Targets {
+		bgp, err := getBGProjectFunc(client, t.ProjectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		tds = append(tds, bgp.TypeData[t.BGType])
+	}
+	rcp.Tasks = getBGMigrationTasksFunc(tds)
+	for _, head := range rcp.Current.Headquarters {
+		rcp.TaskOptions = append(rcp.TaskOptions, getTasksOfHeadFunc(rcp.Current, head)...)
+	}
+	rcp.TaskMap = defaultBGMigrationMapFunc(rcp.Tasks, rcp.TaskOptions)
+	rcp.Depts = getBGMigrationDeptsFunc(tds)
+	rcp.DeptOptions = getBGDeptOptionsFunc(rcp.Current)
+	rcp.DeptMap = defaultBGMigrationMapFunc(rcp.Depts, rcp.DeptOptions)
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "bgteamsetting-migrate", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleBGTeamSettingMigrateSubmitFunc 함수는 선택한 예산안의 bid와 인건비를 매핑에 따라 현재 팀세팅으로 옮기는 함수이다.
+// 모든 예산안을 먼저 변환해보고 에러가 없을 때만 저장한다.
+func handleBGTeamSettingMigrateSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, err := strconv.Atoi(r.FormValue("from"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	taskNum, err := strconv.Atoi(r.FormValue("tasknum"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	taskMap := make(map[string]string)
+	for i := 0; i < taskNum; i++ {
+		taskMap[r.FormValue(fmt.Sprintf("task%d", i))] = r.FormValue(fmt.Sprintf("task%d-target", i))
+	}
+	deptNum, err := strconv.Atoi(r.FormValue("deptnum"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	deptMap := make(map[string]string)
+	for i := 0; i < deptNum; i++ {
+		deptMap[r.FormValue(fmt.Sprintf("dept%d", i))] = r.FormValue(fmt.Sprintf("dept%d-target", i))
+	}
+	typeNum, err := strconv.Atoi(r.FormValue("typenum"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ts, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if from == ts.Version {
+		http.Error(w, "현재 버전의 팀세팅입니다", http.StatusBadRequest)
+		return
+	}
+
+	bgprojects := make(map[string]BGProject)
+	snapshots := make(map[string]entitySnapshot) // 로그에 남길 수정 전 예산 프로젝트
+	var projectIDs []string                      // 예산 프로젝트 저장 순서
+	migrated := make(map[string][]string)        // 로그에 남길 예산 프로젝트별 예산안 리스트
+	for i := 0; i < typeNum; i++ {
+		if r.FormValue(fmt.Sprintf("type%d-check", i)) != "on" {
+			continue
+		}
+		id := r.FormValue(fmt.Sprintf("type%d-id", i))
+		bgtype := r.FormValue(fmt.Sprintf("type%d-bgtype", i))
+		bgp, ok := bgprojects[id]
+		if !ok {
+			bgp, err = getBGProjectFunc(client, id)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			projectIDs = append(projectIDs, id)
+			snapshots[id] = snapshotEntityFunc(bgp)
+		}
+		typedata, ok := bgp.TypeData[bgtype]
+		if !ok {
+			http.Error(w, fmt.Sprintf("%s 예산 프로젝트에 %s 예산안이 없습니다", id, bgtype), http.StatusBadRequest)
+			return
+		}
+		if typedata.TeamSetting.Version != from {
+			http.Error(w, fmt.Sprintf("%s %s 예산안은 %d 버전의 팀세팅을 사용하지 않습니다", id, bgtype, from), http.StatusBadRequest)
+			return
+		}
+		err = checkBGTypeEditableFunc(bgtype, typedata)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = migrateBGTypeDataFunc(&typedata, ts, taskMap, deptMap)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s %s: %s", id, bgtype, err.Error()), http.StatusBadRequest)
+			return
+		}
+		bgp.TypeData[bgtype] = typedata
+		bgprojects[id] = bgp
+		migrated[id] = append(migrated[id], bgtype)
+	}
+	if len(migrated) == 0 {
+		http.Error(w, "마이그레이션할 예산안을 선택해주세요", http.StatusBadRequest)
+		return
+	}
+
+	for _, id := range projectIDs {
+		bgp := bgprojects[id]
+		err = setBGProjectFunc(client, bgp, id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = addBGRevisionsFunc(client, bgp, token.ID, fmt.Sprintf("팀세팅 마이그레이션 (버전 %d → %d)", from, ts.Version))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = addLogsFunc(client, Log{
+			UserID:     token.ID,
+			CreatedAt:  time.Now(),
+			Content:    fmt.Sprintf("예산 프로젝트 %s의 %s 예산안의 팀세팅을 %d 버전에서 %d 버전으로 마이그레이션하였습니다.", id, listToStringFunc(migrated[id], true), from, ts.Version),
+			Action:     LogActionUpdate,
+			EntityType: LogEntityBGProject,
+			EntityID:   id,
+			Changes:    diffEntityFunc(snapshots[id], bgp),
+			IP:         clientIPFunc(r),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/bgteamsetting-history", http.StatusSeeOther)
+}
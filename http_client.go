@@ -0,0 +1,571 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 클라이언트(제작사, 감독) 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleClientsFunc 함수는 클라이언트(제작사, 감독) 관리 페이지를 띄우는 함수이다.
+func handleClientsFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token      Token
+		User       User
+		Type       string          // producer, director
+		SearchWord string          // 검색어
+		Summaries  []ClientSummary // 클라이언트별 합계 정보
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.User, err = getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	rcp.Type = q.Get("type")
+	if rcp.Type != ClientTypeDirector { // type 값이 없으면 제작사로 설정
+		rcp.Type = ClientTypeProducer
+	}
+	rcp.SearchWord = q.Get("searchword")
+
+	clients, err := searchClientFunc(client, rcp.Type, rcp.SearchWord)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, c := range clients {
+		summary, err := calClientSummaryFunc(client, c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.Summaries = append(rcp.Summaries, summary)
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "clients", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleSearchClientsFunc 함수는 클라이언트 관리 페이지에서 Search 버튼을 눌렀을 때 실행되는 함수이다.
+func handleSearchClientsFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	_, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	typ := r.FormValue("type")
+	searchword := r.FormValue("searchword")
+
+	http.Redirect(w, r, fmt.Sprintf("/clients?type=%s&searchword=%s", typ, searchword), http.StatusSeeOther)
+}
+
+// handleClientFunc 함수는 클라이언트의 연락처와 계약 이력을 보여주는 페이지를 띄우는 함수이다.
+func handleClientFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	q := r.URL.Query()
+	id := q.Get("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 프로젝트별 계약 이력
+	type History struct {
+		Project     Project
+		Contract    int // 계약금액
+		Received    int // 입금액
+		Outstanding int // 미수금
+		Overdue     int // 연체된 미수금
+		Profit      int // 수익
+	}
+
+	type Recipe struct {
+		Token     Token
+		User      User
+		Summary   ClientSummary // 클라이언트 합계 정보
+		Histories []History     // 프로젝트별 계약 이력
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.User, err = getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	c, err := getClientFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Summary, err = calClientSummaryFunc(client, c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 프로젝트 하나씩 합계를 계산하여 계약 이력을 만든다.
+	for _, project := range rcp.Summary.Projects {
+		projectSummary, err := calProjectSummaryForClientFunc(client, project)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.Histories = append(rcp.Histories, History{
+			Project:     project,
+			Contract:    projectSummary.Contract,
+			Received:    projectSummary.Received,
+			Outstanding: projectSummary.Outstanding,
+			Overdue:     projectSummary.Overdue,
+			Profit:      projectSummary.Profit,
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "client", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleAddClientFunc 함수는 클라이언트 관리 페이지에서 +를 눌렀을 때 실행되는 함수이다.
+func handleAddClientFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	type Recipe struct {
+		Token Token
+		Type  string // producer, director
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Type = r.URL.Query().Get("type")
+	if rcp.Type != ClientTypeDirector {
+		rcp.Type = ClientTypeProducer
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "add-client", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleAddClientSubmitFunc 함수는 add-client 페이지에서 ADD 버튼을 눌렀을 때 실행되는 함수이다.
+func handleAddClientSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	c, err := getClientFromFormFunc(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	id, err := addClientFunc(client, c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("클라이언트 %s(%s)가 추가되었습니다.", c.Name, c.Type),
+		Action:     LogActionCreate,
+		EntityType: LogEntityClient,
+		EntityID:   id.Hex(),
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/addclient-success?id=%s", id.Hex()), http.StatusSeeOther)
+}
+
+// handleAddClientSuccessFunc 함수는 클라이언트 추가를 성공했다는 페이지를 연다.
+func handleAddClientSuccessFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	type Recipe struct {
+		Token
+		ID string // 클라이언트 ID
+	}
+	rcp := Recipe{
+		Token: token,
+		ID:    r.URL.Query().Get("id"),
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "addclient-success", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleEditClientFunc 함수는 클라이언트 정보를 수정하는 페이지를 띄우는 함수이다.
+func handleEditClientFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token  Token
+		Client Client
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Client, err = getClientFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "edit-client", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleEditClientSubmitFunc 함수는 클라이언트 Edit 페이지에서 Update 버튼을 눌렀을 때 실행되는 함수이다.
+func handleEditClientSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.FormValue("id")
+	formClient, err := getClientFromFormFunc(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	c, err := getClientFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 이름이 바뀐 경우 같은 이름의 클라이언트가 있는지 확인한다.
+	if c.Name != formClient.Name {
+		_, err := getClientByNameFunc(client, c.Type, formClient.Name)
+		if err == nil {
+			http.Error(w, fmt.Sprintf("%s 이름을 가진 클라이언트가 이미 존재합니다", formClient.Name), http.StatusBadRequest)
+			return
+		}
+		if err != mongo.ErrNoDocuments {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	before := c
+	originalName := c.Name
+	c.Name = formClient.Name
+	c.Contacts = formClient.Contacts
+	c.Note = formClient.Note
+
+	err = setClientFunc(client, c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 클라이언트의 이름이 바뀐 경우 연결된 프로젝트의 제작사, 감독 이름도 변경해준다.
+	if originalName != c.Name {
+		projects, err := getProjectsByClientFunc(client, c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, project := range projects {
+			if c.Type == ClientTypeDirector {
+				project.DirectorName = c.Name
+			} else {
+				project.ProducerName = c.Name
+			}
+			err = setProjectFunc(client, project)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("클라이언트 %s(%s)의 정보가 수정되었습니다.", c.Name, c.Type),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityClient,
+		EntityID:   id,
+		Changes:    diffEntityFunc(before, c),
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/editclient-success?id=%s", id), http.StatusSeeOther)
+}
+
+// handleEditClientSuccessFunc 함수는 클라이언트 수정을 성공했다는 페이지를 연다.
+func handleEditClientSuccessFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	type Recipe struct {
+		Token
+		ID string // 클라이언트 ID
+	}
+	rcp := Recipe{
+		Token: token,
+		ID:    r.URL.Query().Get("id"),
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "editclient-success", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getClientFromFormFunc 함수는 add-client, edit-client 페이지의 폼 값으로 Client 자료구조를 만드는 함수이다.
+func getClientFromFormFunc(r *http.Request) (Client, error) {
+	c := Client{}
+	c.Type = r.FormValue("type")
+	c.Name = strings.TrimSpace(r.FormValue("name"))
+	c.Note = r.FormValue("note")
+
+	contactNum, err := strconv.Atoi(r.FormValue("contactNum"))
+	if err != nil {
+		return c, err
+	}
+	for i := 0; i < contactNum; i++ {
+		contact := ClientContact{
+			Name:     strings.TrimSpace(r.FormValue(fmt.Sprintf("contactname%d", i))),
+			Position: strings.TrimSpace(r.FormValue(fmt.Sprintf("contactposition%d", i))),
+			Phone:    strings.TrimSpace(r.FormValue(fmt.Sprintf("contactphone%d", i))),
+			Email:    strings.TrimSpace(r.FormValue(fmt.Sprintf("contactemail%d", i))),
+		}
+		if contact == (ClientContact{}) { // 빈 연락처는 저장하지 않는다.
+			continue
+		}
+		c.Contacts = append(c.Contacts, contact)
+	}
+
+	err = c.CheckErrorFunc()
+	if err != nil {
+		return c, err
+	}
+	return c, nil
+}
@@ -32,11 +32,7 @@ func handleDetailSMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -87,6 +83,9 @@ func handleDetailSMFunc(w http.ResponseWriter, r *http.Request) {
 		Vendors     map[string]string // 프로젝트에 해당하는 벤더 업체별 금액 정보
 		MonthlyInfo []Info            // 프로젝트의 월별 데이터
 		CostSum     map[string]string // 프로젝트 월별 각 지출의 합
+
+		HasForecast bool       // 최종 비용 예측 여부
+		Forecast    BGForecast // 예산 프로젝트 기반 최종 비용 예측
 	}
 	rcp := Recipe{}
 	rcp.Token = token
@@ -388,6 +387,26 @@ func handleDetailSMFunc(w http.ResponseWriter, r *http.Request) {
 	}
 	rcp.CostSum = costSum
 
+	// 예산 프로젝트가 연결된 진행중인 프로젝트는 최종 비용을 예측한다. 예산 대비 실제 비용이 나오므로 실제 비용 보기 권한이 있어야 한다.
+	user, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	viewForecast, err := checkPermissionFunc(client, user, "viewactual")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if viewForecast && rcp.Project.BGProjectID != "" && !rcp.Project.IsFinished {
+		rcp.Forecast, err = getBGForecastFunc(client, rcp.Project, vendors, vfxLaborCostSum+cmLaborCostSum, progressCostSum+purchaseCostSum, vendorSum)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.HasForecast = err == nil
+	}
+
 	// 프로젝트에 해당하는 외주비를 업체별로 보여주기 위해 정리한다.
 	vendorsMap := make(map[string]int)
 	for _, v := range vendors {
@@ -939,11 +958,7 @@ func handleExportDetailSMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -991,11 +1006,26 @@ func handleExportDetailSMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 엑셀 파일의 프로젝트가 사용자에게 허용된 프로젝트인지 확인한다.
 	project := strings.Split(strings.Split(fileInfo[0].Name(), "_")[1], ".")[0]
+	granted, err := checkProjectGrantFunc(client, token.ID, project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !granted {
+		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
+		return
+	}
+
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("%s 디테일 페이지에서 프로젝트 데이터를 다운로드하였습니다.", project),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s 디테일 페이지에서 프로젝트 데이터를 다운로드하였습니다.", project),
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		EntityID:   project,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
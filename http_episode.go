@@ -0,0 +1,586 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 드라마 에피소드 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// parseEpisodesFormFunc 함수는 에피소드 페이지의 form 값으로 에피소드 리스트를 만드는 함수이다. 이름이 없는 줄은 무시한다.
+func parseEpisodesFormFunc(r *http.Request) ([]Episode, error) {
+	num, err := strconv.Atoi(r.FormValue("episodenum"))
+	if err != nil {
+		return nil, err
+	}
+	var episodes []Episode
+	for i := 0; i < num; i++ {
+		name := strings.TrimSpace(r.FormValue(fmt.Sprintf("episode%d-name", i)))
+		if name == "" {
+			continue
+		}
+		ep := Episode{
+			Name:         name,
+			AirDate:      r.FormValue(fmt.Sprintf("episode%d-airdate", i)),
+			DeliveryDate: r.FormValue(fmt.Sprintf("episode%d-deliverydate", i)),
+		}
+		cuts := strings.ReplaceAll(r.FormValue(fmt.Sprintf("episode%d-cuts", i)), ",", "")
+		if cuts != "" {
+			ep.Cuts, err = strconv.Atoi(cuts)
+			if err != nil {
+				return nil, err
+			}
+		}
+		budget := strings.ReplaceAll(r.FormValue(fmt.Sprintf("episode%d-budget", i)), ",", "")
+		if budget != "" {
+			_, err = strconv.Atoi(budget)
+			if err != nil {
+				return nil, err
+			}
+			ep.Budget, err = encryptAES256Func(budget)
+			if err != nil {
+				return nil, err
+			}
+		}
+		episodes = append(episodes, ep)
+	}
+	err = checkEpisodesFunc(episodes)
+	if err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+// handleBGEpisodesFunc 함수는 예산 프로젝트의 에피소드 페이지를 여는 함수이다.
+func handleBGEpisodesFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bgp, err := getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token    Token
+		Title    string         // 페이지 제목
+		ID       string         // 프로젝트 ID
+		Action   string         // 저장할 주소
+		Episodes []Episode      // 에피소드 리스트
+		Shots    map[string]int // 메인 예산안의 에피소드별 샷 개수
+		BGLinked bool           // 예산 프로젝트의 에피소드를 가져올 수 있는지 여부
+	}
+	rcp := Recipe{
+		Token:    token,
+		Title:    fmt.Sprintf("%s (%s) 예산 프로젝트", bgp.Name, bgp.ID),
+		ID:       bgp.ID,
+		Action:   "/bgepisodes-submit",
+		Episodes: bgp.Episodes,
+		Shots:    countShotsByEpisodeFunc(bgp.TypeData[bgp.MainType].ShotList),
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "episodes", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleBGEpisodesSubmitFunc 함수는 예산 프로젝트의 에피소드 페이지에서 Update 버튼을 클릭했을 때 실행되는 함수이다.
+func handleBGEpisodesSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	episodes, err := parseEpisodesFormFunc(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bgp, err := getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	before := bgp
+	bgp.Episodes = episodes
+	bgp.UpdatedTime = time.Now().Format(time.RFC3339)
+	err = setBGProjectFunc(client, bgp, bgp.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("예산 프로젝트 %s의 에피소드 정보가 수정되었습니다.", bgp.ID),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityBGProject,
+		EntityID:   bgp.ID,
+		Changes:    diffEntityFunc(before, bgp),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/bgepisodes?id=%s", bgp.ID), http.StatusSeeOther)
+}
+
+// handleEpisodesSMFunc 함수는 결산 프로젝트의 에피소드 페이지를 여는 함수이다.
+func handleEpisodesSMFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	project, err := getProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token    Token
+		Title    string         // 페이지 제목
+		ID       string         // 프로젝트 ID
+		Action   string         // 저장할 주소
+		Episodes []Episode      // 에피소드 리스트
+		Shots    map[string]int // 연결된 예산 프로젝트 메인 예산안의 에피소드별 샷 개수
+		BGLinked bool           // 예산 프로젝트의 에피소드를 가져올 수 있는지 여부
+	}
+	rcp := Recipe{
+		Token:    token,
+		Title:    fmt.Sprintf("%s (%s) 결산 프로젝트", project.Name, project.ID),
+		ID:       project.ID,
+		Action:   "/episodes-sm-submit",
+		Episodes: project.Episodes,
+		BGLinked: project.BGProjectID != "",
+	}
+	if project.BGProjectID != "" {
+		bgp, err := getBGProjectFunc(client, project.BGProjectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.Shots = countShotsByEpisodeFunc(bgp.TypeData[bgp.MainType].ShotList)
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "episodes", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleEpisodesSMSubmitFunc 함수는 결산 프로젝트의 에피소드 페이지에서 Update 버튼을 클릭했을 때 실행되는 함수이다.
+// 가져오기 버튼을 클릭했으면 연결된 예산 프로젝트의 에피소드 정보를 그대로 가져온다.
+func handleEpisodesSMSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	project, err := getProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	before := project
+	if r.FormValue("action") == "import" {
+		if project.BGProjectID == "" {
+			http.Error(w, "연결된 예산 프로젝트가 없습니다", http.StatusBadRequest)
+			return
+		}
+		bgp, err := getBGProjectFunc(client, project.BGProjectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		project.Episodes = bgp.Episodes
+	} else {
+		project.Episodes, err = parseEpisodesFormFunc(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	err = setProjectFunc(client, project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s의 에피소드 정보가 수정되었습니다.", project.ID),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityProject,
+		EntityID:   project.ID,
+		Changes:    diffEntityFunc(before, project),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/episodes-sm?id=%s", project.ID), http.StatusSeeOther)
+}
+
+// handleEpisodeActualFunc 함수는 드라마 프로젝트의 에피소드별 예산 대비 실제 비용 페이지를 여는 함수이다.
+func handleEpisodeActualFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token     Token
+		Project   Project           // 결산 프로젝트
+		BGProject BGProject         // 연결된 예산 프로젝트
+		Items     []BGEpisodeActual // 에피소드별 예산 대비 실제 비용
+		Total     BGActual          // 합계
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Project, err = getProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 연결된 예산 프로젝트가 있을 때만 비교한다.
+	if rcp.Project.BGProjectID != "" {
+		rcp.BGProject, err = getBGProjectFunc(client, rcp.Project.BGProjectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.Items, err = calBGEpisodeActualFunc(client, rcp.Project, rcp.BGProject)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var costs []BGActual
+		for _, item := range rcp.Items {
+			costs = append(costs, item.Cost)
+		}
+		rcp.Total = calBGActualTotalFunc(costs)
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "episode-actual", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleEpisodeTimelogSyncFunc 함수는 Shotgun에서 프로젝트의 타임로그를 가져와 에피소드, 태스크, 태스크 상태별로 저장하는 함수이다.
+// 샷의 에피소드는 연결된 예산 프로젝트의 메인 예산안 샷 리스트를 먼저 사용하고, 없으면 Shotgun 샷의 에피소드를 사용한다.
+// 리테이크 인건비 페이지도 같은 타임로그를 사용하므로 page가 retake이면 리테이크 인건비 페이지로 돌아간다.
+func handleEpisodeTimelogSyncFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	project, err := getProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	shotEpisodes := make(map[string]string)
+	bids, err := sgGetTaskBidsFunc(project.ID, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, bid := range bids {
+		if bid.Entity != "" && bid.Episode != "" {
+			shotEpisodes[bid.Entity] = bid.Episode
+		}
+	}
+	if project.BGProjectID != "" {
+		bgp, err := getBGProjectFunc(client, project.BGProjectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for shot, ep := range getShotEpisodeMapFunc(bgp.TypeData[bgp.MainType].ShotList) {
+			shotEpisodes[shot] = ep
+		}
+	}
+
+	// 리테이크 인건비는 타임로그를 작성한 날의 태스크 상태로 구분하므로 태스크 상태 변경 기록을 같이 가져온다.
+	statusHistory, err := sgGetTaskStatusHistoryFunc(project.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	timelogs, err := sgGetEpisodeTimelogsFunc(project.ID, shotEpisodes, statusHistory)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	err = setEpisodeTimelogsFunc(client, project.ID, timelogs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s의 에피소드, 태스크별 타임로그를 Shotgun에서 가져왔습니다.", project.ID),
+		Action:     LogActionSync,
+		EntityType: LogEntityProject,
+		EntityID:   project.ID,
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.FormValue("page") == "retake" {
+		http.Redirect(w, r, fmt.Sprintf("/retake?id=%s", project.ID), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/episode-actual?id=%s", project.ID), http.StatusSeeOther)
+}
@@ -16,10 +16,7 @@ func handleHelpFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token Token
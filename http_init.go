@@ -34,11 +34,7 @@ func handleInitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -109,6 +105,7 @@ func handleInitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	rcp.AllProject = filterProjectsByGrantFunc(rcp.User, rcp.AllProject) // 사용자에게 허용된 프로젝트만 보여준다.
 	rcp.SelectedProjectID = q.Get("project")
 	rcp.Status = adminSetting.ProjectStatus
 	rcp.SelectedStatus = q.Get("status")
@@ -131,6 +128,11 @@ func handleInitFunc(w http.ResponseWriter, r *http.Request) {
 		searchword = searchword + " " + rcp.SearchWord
 	}
 	searchedProjects, err := searchProjectFunc(client, searchword, "") // DB에서 searchword로 프로젝트 검색
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	searchedProjects = filterProjectsByGrantFunc(rcp.User, searchedProjects) // 사용자에게 허용된 프로젝트만 보여준다.
 	var projects []Project
 	if rcp.SelectedStatus != "" { // 선택한 status가 있다면 DB에서 검색한 프로젝트들의 status 확인하여 Projects에 추가
 		statusList := stringToListFunc(rcp.SelectedStatus, ",")
@@ -337,8 +339,13 @@ func handleInitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Admin 권한일 경우 세금 계산서 발행일이 오늘 날짜인 프로젝트와 벤더가 있는지 확인한다.
-	if token.AccessLevel == AdminLevel {
+	// 세금 계산서 발행 알림 보기 권한이 있으면 세금 계산서 발행일이 오늘 날짜인 프로젝트와 벤더가 있는지 확인한다.
+	viewInvoice, err := checkPermissionFunc(client, rcp.User, "viewinvoice")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if viewInvoice {
 		// 프로젝트의 월별 매출 발행일이 오늘인 프로젝트가 있는지 확인한다.
 		projects, err := getProjectsByTodayFunc(client)
 		if err != nil {
@@ -377,17 +384,13 @@ func handleInitFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchFunc 함수는 메인 페이지에서 Search를 눌렀을 때 실행되는 함수이다.
 func handleSearchFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	project := r.FormValue("project")
 	status := r.FormValue("status")
@@ -712,11 +715,7 @@ func handleExportInitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -765,9 +764,12 @@ func handleExportInitFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   "메인 페이지에서 프로젝트 데이터를 다운로드하였습니다.",
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "메인 페이지에서 프로젝트 데이터를 다운로드하였습니다.",
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
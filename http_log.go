@@ -6,7 +6,10 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"html/template"
 	"net/http"
+	"net/url"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -23,11 +26,7 @@ func handleLogFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -58,6 +57,11 @@ func handleLogFunc(w http.ResponseWriter, r *http.Request) {
 	if page == "" || page == "0" {
 		page = "1"
 	}
+	filter, err := logFilterFromQueryFunc(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	type Recipe struct {
 		Token       Token
@@ -67,12 +71,38 @@ func handleLogFunc(w http.ResponseWriter, r *http.Request) {
 		Pages       []int64
 		CurrentPage int64
 		User        User
+		UserID      string       // 검색한 유저 ID
+		EntityType  string       // 검색한 대상 종류
+		EntityID    string       // 검색한 대상 ID
+		Action      string       // 검색한 동작
+		Keyword     string       // 검색어
+		Start       string       // 검색한 시작 날짜
+		End         string       // 검색한 끝 날짜
+		Query       template.URL // 페이지 이동, CSV 다운로드에 사용하는 검색 쿼리
+		Actions     []LogOption  // 동작 리스트
+		EntityTypes []LogOption  // 대상 종류 리스트
 	}
 
 	rcp := Recipe{}
 	rcp.Token = token
 	rcp.CurrentPage = PageToIntFunc(page)
-	totalPage, totalNum, logs, err := SearchLogsFunc(client, rcp.CurrentPage, *flagPagenum)
+	rcp.UserID = filter.UserID
+	rcp.EntityType = filter.EntityType
+	rcp.EntityID = filter.EntityID
+	rcp.Action = filter.Action
+	rcp.Keyword = filter.Keyword
+	rcp.Start = q.Get("start")
+	rcp.End = q.Get("end")
+	rcp.Actions = LogActions
+	rcp.EntityTypes = LogEntityTypes
+	query := url.Values{}
+	for _, key := range []string{"userid", "entitytype", "entityid", "action", "keyword", "start", "end"} {
+		if q.Get(key) != "" {
+			query.Set(key, q.Get(key))
+		}
+	}
+	rcp.Query = template.URL(query.Encode())
+	totalPage, totalNum, logs, err := SearchLogsFunc(client, filter, rcp.CurrentPage, *flagPagenum)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -104,3 +134,76 @@ func handleLogFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// handleExportLogFunc 함수는 검색 조건에 맞는 로그를 CSV 파일로 다운로드하는 함수이다.
+func handleExportLogFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	filter, err := logFilterFromQueryFunc(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	logs, err := getLogsFunc(client, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// UTC Time을 한국 시간에 맞춘다.
+	for i := range logs {
+		logs[i].CreatedAt = logs[i].CreatedAt.Add(time.Hour * 9)
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("로그 페이지에서 로그 %d개를 다운로드하였습니다.", len(logs)),
+		Action:     LogActionExport,
+		EntityType: LogEntityLog,
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("Attachment; filename=budget_log_%s.csv", time.Now().Format("20060102150405")))
+	err = writeLogsCSVFunc(w, logs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
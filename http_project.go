@@ -30,11 +30,7 @@ func handleProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -110,6 +106,7 @@ func handleProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	searchedProjects = filterProjectsByGrantFunc(rcp.User, searchedProjects) // 사용자에게 허용된 프로젝트만 보여준다.
 
 	var projects []Project
 	if rcp.SelectedStatus != "" { // 선택한 status가 있다면 DB에서 검색한 프로젝트들의 status 확인하여 Projects에 추가
@@ -153,17 +150,13 @@ func handleProjectsFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchProjectsFunc 함수는 프로젝트 페이지에서 Search를 눌렀을 때 실행되는 함수이다.
 func handleSearchProjectsFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	date := r.FormValue("date")
 	status := r.FormValue("status")
@@ -185,11 +178,7 @@ func handleAddProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token Token
@@ -216,11 +205,7 @@ func handleAddProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -355,6 +340,13 @@ func handleAddProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// 제작사, 감독 이름으로 클라이언트를 연결한다.
+	err = setClientOfProjectFunc(client, &p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	err = addProjectFunc(client, p)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -362,9 +354,13 @@ func handleAddProjectSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("프로젝트 %s %s가 추가되었습니다.", p.ID, p.Name),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s %s가 추가되었습니다.", p.ID, p.Name),
+		Action:     LogActionCreate,
+		EntityType: LogEntityProject,
+		EntityID:   p.ID,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -385,11 +381,7 @@ func handleAddProjectSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -414,11 +406,7 @@ func handleEditProjectSMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -470,6 +458,8 @@ func handleEditProjectSMFunc(w http.ResponseWriter, r *http.Request) {
 		Status       []Status // 프로젝트(결산) 상태 리스트
 		FinishedType bool     // 정산 타입(true: 월별 합산 값으로 저장, false: 최종 입력 값으로 저장)
 
+		BGProjects []BGProject // 연결할 수 있는 예산 프로젝트 리스트
+
 		SearchedDate string // 이전에 검색된 날짜
 	}
 
@@ -501,6 +491,13 @@ func handleEditProjectSMFunc(w http.ResponseWriter, r *http.Request) {
 
 	rcp.Status = adminSetting.ProjectStatus // 테이블에 들어갈 Status 설정
 
+	rcp.BGProjects, err = searchBGProjectFunc(client, "id:", "id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.BGProjects = filterBGProjectsByGrantFunc(rcp.User, rcp.BGProjects) // 사용자에게 허용된 예산 프로젝트만 연결할 수 있다.
+
 	w.Header().Set("Content-Type", "text/html")
 	err = TEMPLATES.ExecuteTemplate(w, "edit-projectsm", rcp)
 	if err != nil {
@@ -518,11 +515,7 @@ func handleEditProjectSMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 	id := r.FormValue("id")
 	searchedDate := r.FormValue("searcheddate")
 
@@ -561,6 +554,7 @@ func handleEditProjectSMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	snapshot := snapshotEntityFunc(project)
 
 	dateList, err := getDatesFunc(project.StartDate, project.SMEndDate) // 기존의 작업시작과 작업마감 사이의 Date를 가져온다.
 	if err != nil {
@@ -590,6 +584,20 @@ func handleEditProjectSMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	project.SMEndDate = r.FormValue("enddate")
 	project.DirectorName = r.FormValue("directorname")
 	project.ProducerName = r.FormValue("producername")
+	bgprojectID := r.FormValue("bgprojectid")
+	if bgprojectID != "" && bgprojectID != project.BGProjectID {
+		// 사용자에게 허용된 예산 프로젝트만 연결할 수 있다.
+		granted, err := checkProjectGrantFunc(client, token.ID, bgprojectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !granted {
+			http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
+			return
+		}
+	}
+	project.BGProjectID = bgprojectID
 
 	// 프로젝트 부가정보 컷수
 	if r.FormValue("contractcuts") != "" {
@@ -620,6 +628,20 @@ func handleEditProjectSMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		project.WorkingCuts = 0
 	}
 
+	if r.FormValue("completedcuts") != "" {
+		completedCuts := r.FormValue("completedcuts")
+		if strings.Contains(completedCuts, ",") {
+			completedCuts = strings.ReplaceAll(completedCuts, ",", "")
+		}
+		project.CompletedCuts, err = strconv.Atoi(completedCuts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		project.CompletedCuts = 0
+	}
+
 	// 총 매출
 	paymentNum, err := strconv.Atoi(r.FormValue("paymentNum"))
 	if err != nil {
@@ -795,6 +817,13 @@ func handleEditProjectSMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// 제작사, 감독 이름으로 클라이언트를 연결한다.
+	err = setClientOfProjectFunc(client, &project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	err = setProjectFunc(client, project)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -811,9 +840,14 @@ func handleEditProjectSMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("프로젝트 %s의 정보가 수정되었습니다.", id),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s의 정보가 수정되었습니다.", id),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityProject,
+		EntityID:   id,
+		Changes:    diffEntityFunc(snapshot, project),
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -834,11 +868,7 @@ func handleEditProjectSMSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -1056,11 +1086,7 @@ func handleExportProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1117,9 +1143,12 @@ func handleExportProjectsFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("프로젝트 관리 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[1], filename[2]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 관리 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[1], filename[2]),
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -0,0 +1,406 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 단가표 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleRateCardsFunc 함수는 단가표 리스트 페이지를 여는 함수이다.
+func handleRateCardsFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token        Token
+		User         User
+		Headquarters []string          // 본부 리스트
+		Headquarter  string            // 선택한 본부
+		RateCards    []RateCard        // 단가표 리스트
+		Effective    map[string]string // 본부별 오늘 적용되는 단가표 ID
+		Pinned       map[string]int    // 단가표별 고정된 예산안 개수
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.User, err = getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ts, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Headquarters = ts.Headquarters
+	rcp.Headquarter = r.FormValue("headquarter")
+	rcp.RateCards, err = getRateCardsFunc(client, rcp.Headquarter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Effective, err = getEffectiveRateCardIDsFunc(client, ts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 단가표별로 고정된 예산안 개수를 센다.
+	rcp.Pinned = make(map[string]int)
+	bgprojects, err := searchBGProjectFunc(client, "id:", "id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, bgp := range bgprojects {
+		for _, typedata := range bgp.TypeData {
+			for _, id := range typedata.RateCards {
+				rcp.Pinned[id]++
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "ratecards", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleAddRateCardFunc 함수는 단가표 추가 페이지를 여는 함수이다. from 값이 있으면 해당 단가표의 단가로 새 버전을 만든다.
+func handleAddRateCardFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token         Token
+		Headquarters  []string       // 본부 리스트
+		Headquarter   string         // 선택한 본부
+		Tasks         []string       // 본부에 해당하는 태스크 리스트
+		Rates         map[string]int // 태스크별 단가
+		Year          int            // 적용 연도
+		EffectiveDate string         // 적용 시작일
+		Note          string         // 메모
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Rates = make(map[string]int)
+	rcp.Year = time.Now().Year()
+	rcp.EffectiveDate = time.Now().Format("2006-01-02")
+
+	q := r.URL.Query()
+	if q.Get("from") != "" { // 기존 단가표의 단가를 가져온다.
+		from, err := getRateCardFunc(client, q.Get("from"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.Headquarter = from.Headquarter
+		rcp.Year = from.Year
+		rcp.Note = from.Note
+		for task, rate := range from.Rates {
+			rcp.Rates[task], err = decryptToIntFunc(rate)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+	if q.Get("headquarter") != "" {
+		rcp.Headquarter = q.Get("headquarter")
+	}
+
+	ts, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Headquarters = ts.Headquarters
+	if rcp.Headquarter == "" && len(ts.Headquarters) != 0 {
+		rcp.Headquarter = ts.Headquarters[0]
+	}
+	rcp.Tasks = getTasksOfHeadFunc(ts, rcp.Headquarter)
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "add-ratecard", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleAddRateCardSubmitFunc 함수는 add-ratecard 페이지에서 ADD 버튼을 눌렀을 때 실행되는 함수이다.
+func handleAddRateCardSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ts, err := getBGTeamSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rc := RateCard{}
+	rc.Headquarter = r.FormValue("headquarter")
+	rc.EffectiveDate = r.FormValue("effectivedate")
+	rc.Note = strings.TrimSpace(r.FormValue("note"))
+	rc.UserID = token.ID
+	rc.Year, err = strconv.Atoi(r.FormValue("year"))
+	if err != nil {
+		http.Error(w, "적용 연도는 숫자로 입력해주세요", http.StatusBadRequest)
+		return
+	}
+	rc.Rates = make(map[string]string)
+	for _, task := range getTasksOfHeadFunc(ts, rc.Headquarter) {
+		rate := strings.ReplaceAll(strings.TrimSpace(r.FormValue("rate-"+task)), ",", "")
+		if rate == "" { // 단가를 입력하지 않은 태스크는 제외한다.
+			continue
+		}
+		rateInt, err := strconv.Atoi(rate)
+		if err != nil || rateInt < 0 {
+			http.Error(w, fmt.Sprintf("태스크 %s의 단가가 올바르지 않습니다", task), http.StatusBadRequest)
+			return
+		}
+		rc.Rates[task], err = encryptAES256Func(strconv.Itoa(rateInt))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	err = rc.CheckErrorFunc()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := addRateCardFunc(client, rc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s 본부의 %d년 단가표(적용 시작일 %s)가 추가되었습니다.", rc.Headquarter, rc.Year, rc.EffectiveDate),
+		Action:     LogActionCreate,
+		EntityType: LogEntityRateCard,
+		EntityID:   id.Hex(),
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/addratecard-success?id=%s", id.Hex()), http.StatusSeeOther)
+}
+
+// handleAddRateCardSuccessFunc 함수는 단가표 추가를 성공했다는 페이지를 연다.
+func handleAddRateCardSuccessFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	type Recipe struct {
+		Token
+		ID string // 단가표 ID
+	}
+	rcp := Recipe{
+		Token: token,
+		ID:    r.URL.Query().Get("id"),
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "addratecard-success", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleRateCardCompareFunc 함수는 단가표의 단가와 현재 팀 평균 인건비를 비교하는 페이지를 여는 함수이다.
+func handleRateCardCompareFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token      Token
+		RateCard   RateCard      // 단가표
+		Compares   []RateCompare // 태스크별 단가와 현재 팀 평균 인건비 비교
+		BGProjects []BGProject   // 단가표가 고정된 예산 프로젝트
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.RateCard, err = getRateCardFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ts, err := getBGTeamSettingFunc(client) // 현재 팀세팅 기준으로 팀 평균 인건비를 계산한다.
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Compares, err = calRateCompareFunc(rcp.RateCard, ts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.BGProjects, err = getBGProjectsByRateCardFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "ratecard-compare", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
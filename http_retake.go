@@ -0,0 +1,108 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 리테이크 인건비 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleRetakeFunc 함수는 프로젝트의 월별 리테이크 인건비와 예산안 Retake율을 비교하는 페이지를 여는 함수이다.
+func handleRetakeFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "URL에 id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token        Token
+		Project      Project      // 결산 프로젝트
+		BGProject    BGProject    // 연결된 예산 프로젝트
+		AdminSetting AdminSetting // 리테이크 태스크 이름, 상태 설정
+		RetakeRatio  float64      // 메인 예산안의 Retake율(%)
+		BudgetShare  string       // 메인 예산안 Retake율을 전체 인건비 중 비율로 바꾼 값(%)
+		Items        []BGRetake   // 월별 리테이크 인건비
+		Total        BGRetake     // 합계
+		OverBudget   bool         // 실제 Retake율이 예산안 Retake율보다 높은지 여부
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.Project, err = getProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.AdminSetting, err = getAdminSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 연결된 예산 프로젝트가 있으면 메인 예산안의 Retake율과 비교한다.
+	if rcp.Project.BGProjectID != "" {
+		rcp.BGProject, err = getBGProjectFunc(client, rcp.Project.BGProjectID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.RetakeRatio = rcp.BGProject.TypeData[rcp.BGProject.MainType].RetakeRatio
+		rcp.BudgetShare = calBGRetakeBudgetShareFunc(rcp.RetakeRatio)
+	}
+
+	rcp.Items, rcp.Total, err = calBGRetakesFunc(client, rcp.Project.ID, rcp.AdminSetting)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rcp.Total.LaborCost != 0 && rcp.Project.BGProjectID != "" {
+		rcp.OverBudget = float64(rcp.Total.RetakeCost) > float64(rcp.Total.LaborCost-rcp.Total.RetakeCost)*rcp.RetakeRatio/100
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "retake", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,336 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 예산 샷, 어셋 Shotgun bid 가져오기 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleImportSGBidFunc 함수는 Shotgun 프로젝트의 샷, 어셋 bid를 가져와 현재 예산안과 비교하는 페이지를 여는 함수이다.
+func handleImportSGBidFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	q := r.URL.Query()
+	id := q.Get("id")
+	bgtype := q.Get("bgtype")
+	kind := q.Get("kind")
+	if id == "" || bgtype == "" || (kind != "shot" && kind != "asset") {
+		http.Redirect(w, r, "/shotasset", http.StatusSeeOther)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type Recipe struct {
+		Token      Token
+		BGProject  BGProject
+		BGType     string
+		Kind       string             // shot, asset
+		SGProjects []string           // Shotgun 프로젝트 리스트
+		SGProject  string             // 선택한 Shotgun 프로젝트
+		NewType    string             // 새로 추가할 예산안 이름
+		Tasks      []string           // 예산 팀세팅의 태스크 리스트
+		Count      int                // 가져온 샷, 어셋 개수
+		Diffs      []BGBidDiff        // 현재 예산안과 바뀐 샷, 어셋
+		Before     map[string]float64 // 현재 예산안의 태스크별 bid 합계
+		After      map[string]float64 // 가져온 태스크별 bid 합계
+		Unmapped   []string           // 예산 팀세팅의 태스크로 변환하지 못한 Shotgun 태스크
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	rcp.BGType = bgtype
+	rcp.Kind = kind
+	rcp.BGProject, err = getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	typedata, ok := rcp.BGProject.TypeData[bgtype]
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s 예산안이 존재하지 않습니다", bgtype), http.StatusBadRequest)
+		return
+	}
+
+	adminSetting, err := getAdminSettingFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.SGProjects, err = sgGetProjectsFunc(adminSetting.SGExcludeProjects)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Shotgun 프로젝트를 선택하지 않았으면 예산 프로젝트 ID와 같은 이름의 프로젝트를 선택한다.
+	rcp.SGProject = q.Get("sgproject")
+	if rcp.SGProject == "" {
+		for _, p := range rcp.SGProjects {
+			if strings.EqualFold(p, id) {
+				rcp.SGProject = p
+				break
+			}
+		}
+	}
+	rcp.NewType = fmt.Sprintf("%s_SG%s", bgtype, time.Now().Format("0102"))
+
+	if rcp.SGProject != "" {
+		bids, err := sgGetTaskBidsFunc(rcp.SGProject, kind == "asset")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		list, unmapped := sgBidsToShotAssetFunc(bids, typedata.TeamSetting, kind == "asset")
+		current := typedata.ShotList
+		if kind == "asset" {
+			current = typedata.AssetList
+		}
+		rcp.Tasks = getBGTasksByTypeFunc(typedata.TeamSetting, kind == "asset")
+		rcp.Count = len(list)
+		rcp.Diffs = calBidDiffFunc(current, list)
+		rcp.Before = calTotalBidFunc(current)
+		rcp.After = calTotalBidFunc(list)
+		rcp.Unmapped = unmapped
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "importsgbid", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleImportSGBidSubmitFunc 함수는 Shotgun에서 가져온 샷, 어셋 bid를 새로운 예산안으로 저장하는 함수이다.
+// 기존 예산안의 정보를 복사하고 샷 또는 어셋 리스트와 비용만 새로 계산하므로 기존 예산안은 그대로 유지된다.
+func handleImportSGBidSubmitFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	bgtype := r.FormValue("bgtype")
+	kind := r.FormValue("kind")
+	sgproject := r.FormValue("sgproject")
+	newtype := strings.TrimSpace(r.FormValue("newtype"))
+	if id == "" || bgtype == "" || sgproject == "" || (kind != "shot" && kind != "asset") {
+		http.Error(w, "예산 프로젝트, 예산안, Shotgun 프로젝트 정보가 필요합니다", http.StatusBadRequest)
+		return
+	}
+	if newtype == "" {
+		http.Error(w, "새로운 예산안 이름을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bgp, err := getBGProjectFunc(client, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	base, ok := bgp.TypeData[bgtype]
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s 예산안이 존재하지 않습니다", bgtype), http.StatusBadRequest)
+		return
+	}
+	if checkStringInListFunc(newtype, bgp.TypeList) {
+		http.Error(w, fmt.Sprintf("%s 예산안이 이미 존재합니다. 다른 이름을 입력해주세요", newtype), http.StatusBadRequest)
+		return
+	}
+
+	bids, err := sgGetTaskBidsFunc(sgproject, kind == "asset")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	list, _ := sgBidsToShotAssetFunc(bids, base.TeamSetting, kind == "asset")
+
+	// 기존 예산안을 복사하여 새로운 예산안을 만든다. 부서별 비용은 다시 계산되므로 따로 복사한다.
+	bgtd := base
+	bgtd.ID = primitive.NewObjectID()
+	bgtd.ApprovalStatus = BGApprovalDraft // 새로운 예산안은 결재를 다시 받아야 한다.
+	bgtd.Approvals = nil
+	bgtd.LaborCosts = nil
+	for _, ls := range base.LaborCosts {
+		deptCost := make(map[string]string)
+		for dept, cost := range ls.DepartmentCost {
+			deptCost[dept] = cost
+		}
+		ls.DepartmentCost = deptCost
+		bgtd.LaborCosts = append(bgtd.LaborCosts, ls)
+	}
+
+	if kind == "shot" {
+		bgtd.ShotList = list
+		err = setLaborCostByBidFunc(client, &bgtd, calTotalBidFunc(list), false)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		bgtd.EpisodeCost = make(map[string]string)
+		if bgp.Type == "drama" {
+			err = setEpisodeCostFunc(client, &bgtd)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	} else {
+		// Shotgun에서 가져오지 않는 어셋 설명과 샷 개수는 기존 예산안에서 가져온다.
+		for i, asset := range list {
+			for _, orig := range base.AssetList {
+				if orig.Name == asset.Name {
+					list[i].Note = orig.Note
+					list[i].Shot = orig.Shot
+					if list[i].Class == "" {
+						list[i].Class = orig.Class
+					}
+					break
+				}
+			}
+		}
+		bgtd.AssetList = list
+		err = setLaborCostByBidFunc(client, &bgtd, calTotalBidFunc(list), true)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	bgp.TypeList = append(bgp.TypeList, newtype)
+	bgp.TypeData[newtype] = bgtd
+	bgp.UpdatedTime = time.Now().Format(time.RFC3339)
+	err = setBGProjectFunc(client, bgp, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 저장된 예산안의 리비전을 추가한다.
+	err = addBGRevisionsFunc(client, bgp, token.ID, "Shotgun bid 가져오기")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log := Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s의 %s 예산안을 기반으로 Shotgun %s 프로젝트의 %s bid를 가져와 %s 예산안을 추가하였습니다.", id, bgtype, sgproject, kind, newtype),
+		Action:     LogActionImport,
+		EntityType: LogEntityBGProject,
+		EntityID:   id,
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/importsgbid-success?id=%s&bgtype=%s", id, newtype), http.StatusSeeOther)
+}
+
+// handleImportSGBidSuccessFunc 함수는 Shotgun bid로 새로운 예산안을 추가했다는 페이지를 여는 함수이다.
+func handleImportSGBidSuccessFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	type Recipe struct {
+		Token  Token
+		ID     string
+		BGType string
+	}
+	rcp := Recipe{}
+	rcp.Token = token
+	q := r.URL.Query()
+	rcp.ID = q.Get("id")
+	rcp.BGType = q.Get("bgtype")
+
+	w.Header().Set("Content-Type", "text/html")
+	err = TEMPLATES.ExecuteTemplate(w, "importsgbid-success", rcp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"math"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -33,11 +32,7 @@ func handelShotAssetFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -65,6 +60,7 @@ func handelShotAssetFunc(w http.ResponseWriter, r *http.Request) {
 
 	type Recipe struct {
 		Token             Token
+		User              User
 		Year              string      // 연도
 		AllBGProjects     []BGProject // 해당 연도의 모든 예산 프로젝트
 		SearchWord        string      // 검색어
@@ -74,6 +70,11 @@ func handelShotAssetFunc(w http.ResponseWriter, r *http.Request) {
 	}
 	rcp := Recipe{}
 	rcp.Token = token
+	rcp.User, err = getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	q := r.URL.Query()
 	year := q.Get("year")
 	if year == "" { // year 값이 없으면 올해로 검색
@@ -96,6 +97,7 @@ func handelShotAssetFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	rcp.AllBGProjects = filterBGProjectsByGrantFunc(rcp.User, rcp.AllBGProjects) // 사용자에게 허용된 프로젝트만 보여준다.
 
 	// Search에 따른 프로젝트
 	if rcp.SelectedProjectID != "" {
@@ -112,6 +114,7 @@ func handelShotAssetFunc(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		rcp.BGProjects = filterBGProjectsByGrantFunc(rcp.User, rcp.BGProjects)
 	}
 
 	w.Header().Set("Content-Type", "text/html")
@@ -125,17 +128,13 @@ func handelShotAssetFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchShotAssetFunc 함수는 Shot, Asset 관리 페이지에서 Search 버튼을 눌렀을 때 검색을 실행하는 함수이다,
 func handleSearchShotAssetFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	status := ""
 	if r.FormValue("truestatus") == "on" && r.FormValue("falsestatus") == "on" {
@@ -161,11 +160,7 @@ func handleUploadShotFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -200,17 +195,13 @@ func handleUploadShotFunc(w http.ResponseWriter, r *http.Request) {
 // handleShotExcelDownloadFunc 함수는 Shot 업로드를 위한 엑셀 파일의 템플릿을 생성하여 다운로드하는 함수이다.
 func handleShotExcelDownloadFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Only", http.StatusMethodNotAllowed)
@@ -335,11 +326,7 @@ func handleUploadShotExcelFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	err = r.ParseMultipartForm(200000)
 	if err != nil {
@@ -401,11 +388,7 @@ func handleShotExcelSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
@@ -524,6 +507,7 @@ func handleShotExcelSubmitFunc(w http.ResponseWriter, r *http.Request) {
 			if typ == "drama" {
 				if num == 1 {
 					shotInfo.Note = episode
+					shotInfo.Episode = episode
 					continue
 				}
 			}
@@ -567,11 +551,7 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -608,6 +588,11 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	bgTypeData := bgp.TypeData[bgtype]
+	err = checkBGTypeEditableFunc(bgtype, bgTypeData)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	var taskList []string // 예산 팀세팅의 샷 관련 태스크 리스트
 	bgTeamSetting := bgTypeData.TeamSetting
@@ -640,7 +625,6 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 
 	bgTypeData.ShotList = []BGShotAsset{}
 	totalBid := make(map[string]float64)
-	totalBidByEpisode := make(map[string]map[string]float64) // 드라마 프로젝트의 경우 에피소드별 TotalBid
 
 	var shotList []string              // 샷 코드 리스트
 	errShot := make(map[string]string) // 에러가 있는 샷 정보
@@ -655,9 +639,7 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		shotInfo.Name = shot.Name
 		if typ == "drama" {
 			shotInfo.Note = shot.Note
-			if totalBidByEpisode[shot.Note] == nil {
-				totalBidByEpisode[shot.Note] = make(map[string]float64)
-			}
+			shotInfo.Episode = getShotEpisodeFunc(shot)
 		}
 		shotInfo.Manday = make(map[string]float64)
 		for task, bid := range shot.Manday {
@@ -671,9 +653,6 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 			}
 			shotInfo.Manday[task] = bid
 			totalBid[task] += bid
-			if typ == "drama" {
-				totalBidByEpisode[shot.Note][task] += bid
-			}
 		}
 
 		if shotInfo.Manday != nil {
@@ -696,6 +675,10 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("프로젝트 %s의 %s 샷 정보를 업로드하지 못했습니다.", id, bgtype)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityBGProject
+		log.EntityID = id
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -713,108 +696,20 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 샷 정보를 기반으로 인건비를 계산한 후에 태스크별 비용을 정리한다.
-	taskCost := make(map[string]float64)
-	for task, bid := range totalBid {
-		wage, err := averageWageByTeamsFunc(task, bgTeamSetting.Teams[task])
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		taskCost[task] = math.Round(wage * bid)
-	}
-
-	// 현재 예산안의 팀세팅 정보를 기반으로 본부별 샷 관련 태스크를 정리한다.
-	taskByHead := make(map[string]map[string][]string)
-	for head, deptList := range bgTypeData.TeamSetting.Departments {
-		taskByHead[head] = make(map[string][]string)
-		for _, dept := range deptList {
-			if !dept.Type {
-				for _, parts := range dept.Parts {
-					taskByHead[head][dept.Name] = append(taskByHead[head][dept.Name], parts.Tasks...)
-				}
-			}
-		}
-	}
-
-	// 본부별로 정리된 태스크에 따라서 비용을 정리한다.
-	costByHead := make(map[string]map[string]float64)
-	for head, tasksByDept := range taskByHead {
-		costByHead[head] = make(map[string]float64)
-		for dept, tasks := range tasksByDept {
-			for task, cost := range taskCost {
-				if checkStringInListFunc(task, tasks) {
-					costByHead[head][dept] += cost
-				}
-			}
-		}
-	}
-
-	// 기존 예산안이 있는지 확인후 비교하여 예산안 비용을 암호화하여 업데이트한다.
-	bglsList := []BGLaborCost{}
-	for head, costByDept := range costByHead {
-		bgls := BGLaborCost{}
-		bgls.Headquarter = head
-		if bgTypeData.LaborCosts != nil { // 기존 예산안이 있는 경우
-			for _, ls := range bgTypeData.LaborCosts {
-				if ls.Headquarter == head { // 기존에 저장된 본부의 비용의 경우
-					bgls = ls
-					break
-				}
-			}
-		}
-
-		// 이미 부서별 비용이 있는 경우 -> 샷 관련 부서 비용만 초기화
-		if bgls.DepartmentCost == nil {
-			bgls.DepartmentCost = make(map[string]string)
-		} else {
-			var shotDept []string
-			for _, parts := range bgTypeData.TeamSetting.Departments { // 현재 팀세팅의 Shot 관련 부서
-				for _, part := range parts {
-					if !part.Type {
-						shotDept = append(shotDept, part.Name)
-					}
-				}
-			}
-			for dept := range bgls.DepartmentCost {
-				if checkStringInListFunc(dept, shotDept) {
-					delete(bgls.DepartmentCost, dept)
-				}
-			}
-		}
-
-		// 계산된 부서별 비용 암호화하여 저장
-		for dept, cost := range costByDept {
-			encryptedCost, err := encryptAES256Func(strconv.Itoa(int(cost)))
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			bgls.DepartmentCost[dept] = encryptedCost
-		}
-		bglsList = append(bglsList, bgls)
+	// 샷 정보를 기반으로 예산안에 고정된 단가표의 단가로 샷 관련 부서 비용을 계산한다.
+	err = setLaborCostByBidFunc(client, &bgTypeData, totalBid, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	bgTypeData.LaborCosts = bglsList
 
 	// 드라마인 경우 에피소드별 비용을 계산한다.
 	bgTypeData.EpisodeCost = make(map[string]string)
 	if typ == "drama" {
-		for ep, tBid := range totalBidByEpisode {
-			costByEpisode := 0.0
-			for task, bid := range tBid {
-				wage, err := averageWageByTeamsFunc(task, bgTeamSetting.Teams[task])
-				if err != nil {
-					http.Error(w, err.Error(), http.StatusInternalServerError)
-					return
-				}
-				costByEpisode += math.Round(wage * bid)
-			}
-			encryptedCost, err := encryptAES256Func(strconv.Itoa(int(costByEpisode)))
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			bgTypeData.EpisodeCost[ep] = encryptedCost
+		err = setEpisodeCostFunc(client, &bgTypeData)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 	}
 
@@ -826,11 +721,22 @@ func handleUploadShotSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 저장된 예산안의 리비전을 추가한다.
+	err = addBGRevisionsFunc(client, bgp, token.ID, "샷 업로드")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// 프로젝트 예산안 샷 업로드 성공 페이지로 리다이렉트
 	log := Log{}
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("프로젝트 %s의 %s 샷 정보를 업로드하였습니다.", id, bgtype)
+	log.Action = LogActionImport
+	log.EntityType = LogEntityBGProject
+	log.EntityID = id
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -849,11 +755,7 @@ func handleUploadShotSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token  Token
@@ -883,11 +785,7 @@ func handleDetailShotFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -1121,11 +1019,7 @@ func handleExportDetailShotFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1187,9 +1081,13 @@ func handleExportDetailShotFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("프로젝트 %s %s의 세부 샷 정보를 다운로드하였습니다..", filename[1], filename[2]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s %s의 세부 샷 정보를 다운로드하였습니다..", filename[1], filename[2]),
+		Action:     LogActionExport,
+		EntityType: LogEntityBGProject,
+		EntityID:   filename[1],
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -1211,11 +1109,7 @@ func handleUploadAssetFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -1247,17 +1141,13 @@ func handleUploadAssetFunc(w http.ResponseWriter, r *http.Request) {
 // handleAssetExcelDownloadFunc 함수는 Asset 업로드를 위한 엑셀 파일의 템플릿을 생성하여 다운로드하는 함수이다.
 func handleAssetExcelDownloadFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Only", http.StatusMethodNotAllowed)
@@ -1373,11 +1263,7 @@ func handleUploadAssetExcelFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	err = r.ParseMultipartForm(200000)
 	if err != nil {
@@ -1439,11 +1325,7 @@ func handleAssetExcelSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
@@ -1595,11 +1477,7 @@ func handleUploadAssetSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -1635,6 +1513,11 @@ func handleUploadAssetSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	bgTypeData := bgp.TypeData[bgtype]
+	err = checkBGTypeEditableFunc(bgtype, bgTypeData)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	var taskList []string
 	bgTeamSetting := bgTypeData.TeamSetting
@@ -1730,6 +1613,10 @@ func handleUploadAssetSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("프로젝트 %s의 %s 어셋 정보를 업로드하지 못했습니다.", id, bgtype)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityBGProject
+		log.EntityID = id
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -1747,88 +1634,12 @@ func handleUploadAssetSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 어셋 정보를 기반으로 인건비를 계산한 후에 태스크별 비용을 정리한다.
-	taskCost := make(map[string]float64)
-	for task, bid := range totalBid {
-		wage, err := averageWageByTeamsFunc(task, bgTeamSetting.Teams[task])
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		taskCost[task] = math.Round(wage * bid)
-	}
-
-	// 현재 예산안의 팀세팅 정보를 기반으로 본부별 샷 관련 태스크를 정리한다.
-	taskByHead := make(map[string]map[string][]string)
-	for head, deptList := range bgTypeData.TeamSetting.Departments {
-		taskByHead[head] = make(map[string][]string)
-		for _, dept := range deptList {
-			if dept.Type {
-				for _, parts := range dept.Parts {
-					taskByHead[head][dept.Name] = append(taskByHead[head][dept.Name], parts.Tasks...)
-				}
-			}
-		}
-	}
-
-	// 본부별로 정리된 태스크에 따라서 비용을 정리한다.
-	costByHead := make(map[string]map[string]float64)
-	for head, tasksByDept := range taskByHead {
-		costByHead[head] = make(map[string]float64)
-		for dept, tasks := range tasksByDept {
-			for task, cost := range taskCost {
-				if checkStringInListFunc(task, tasks) {
-					costByHead[head][dept] += cost
-				}
-			}
-		}
-	}
-
-	// 기존 예산안이 있는지 확인후 비교하여 예산안 비용을 암호화하여 업데이트한다.
-	bglsList := []BGLaborCost{}
-	for head, costByDept := range costByHead {
-		bgls := BGLaborCost{}
-		bgls.Headquarter = head
-		if bgTypeData.LaborCosts != nil { // 기존 예산안이 있는 경우
-			for _, ls := range bgTypeData.LaborCosts {
-				if ls.Headquarter == head { // 기존에 저장된 본부의 비용의 경우
-					bgls = ls
-					break
-				}
-			}
-		}
-
-		// 이미 부서별 비용이 있는 경우 -> 샷 관련 부서 비용만 초기화
-		if bgls.DepartmentCost == nil {
-			bgls.DepartmentCost = make(map[string]string)
-		} else {
-			var assetDept []string
-			for _, parts := range bgTypeData.TeamSetting.Departments { // 현재 팀세팅의 Asset 관련 부서
-				for _, part := range parts {
-					if part.Type {
-						assetDept = append(assetDept, part.Name)
-					}
-				}
-			}
-			for dept := range bgls.DepartmentCost {
-				if checkStringInListFunc(dept, assetDept) {
-					delete(bgls.DepartmentCost, dept)
-				}
-			}
-		}
-
-		// 계산된 부서별 비용 암호화하여 저장
-		for dept, cost := range costByDept {
-			encryptedCost, err := encryptAES256Func(strconv.Itoa(int(cost)))
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			bgls.DepartmentCost[dept] = encryptedCost
-		}
-		bglsList = append(bglsList, bgls)
+	// 어셋 정보를 기반으로 예산안에 고정된 단가표의 단가로 어셋 관련 부서 비용을 계산한다.
+	err = setLaborCostByBidFunc(client, &bgTypeData, totalBid, true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	bgTypeData.LaborCosts = bglsList
 
 	// 어셋 정보를 저장한 후 프로젝트를 업데이트한다.
 	bgp.TypeData[bgtype] = bgTypeData
@@ -1838,11 +1649,22 @@ func handleUploadAssetSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 저장된 예산안의 리비전을 추가한다.
+	err = addBGRevisionsFunc(client, bgp, token.ID, "어셋 업로드")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// 프로젝트 예산안 샷 업로드 성공 페이지로 리다이렉트
 	log := Log{}
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("프로젝트 %s의 %s 어셋 정보를 업로드하였습니다.", id, bgtype)
+	log.Action = LogActionImport
+	log.EntityType = LogEntityBGProject
+	log.EntityID = id
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -1861,11 +1683,7 @@ func handleUploadAssetSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token  Token
@@ -1895,11 +1713,7 @@ func handleDetailAssetFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -2122,11 +1936,7 @@ func handleExportDetailAssetFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러ㄴ
 	if r.Method != http.MethodPost {
@@ -2187,9 +1997,13 @@ func handleExportDetailAssetFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("프로젝트 %s %s의 세부 어셋 정보를 다운로드하였습니다..", filename[1], filename[2]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s %s의 세부 어셋 정보를 다운로드하였습니다..", filename[1], filename[2]),
+		Action:     LogActionExport,
+		EntityType: LogEntityBGProject,
+		EntityID:   filename[1],
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -33,11 +33,7 @@ func handleSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -75,6 +71,7 @@ func handleSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		TotalArtistLaborCost  map[string]string            // 아티스트별 총 인건비
 		TotalProjectLaborCost map[string]string            // 프로젝트별 총 인건비
 		TotalLaborCost        string                       // 총 인건비
+		ViewSalary            bool                         // 아티스트별 인건비를 볼 수 있는지 여부, 없으면 팀별 인건비만 보여준다.
 	}
 
 	rcp := Recipe{}
@@ -84,6 +81,11 @@ func handleSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	rcp.ViewSalary, err = checkPermissionFunc(client, rcp.User, "viewsalary")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	date := r.FormValue("date")
 	if date == "" { // year 값이 없으면 올해로 검색
 		y, m, _ := time.Now().Date()
@@ -207,6 +209,25 @@ func handleSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		return rcp.Artist[i].Name < rcp.Artist[j].Name
 	})
 
+	// 급여 보기 권한이 없으면 아티스트별 인건비를 팀별로 합쳐서 보여준다.
+	if !rcp.ViewSalary {
+		var teamLaborCost map[string]map[string]int
+		rcp.Artist, teamLaborCost = sumLaborCostByTeamFunc(rcp.Artist, detailLaborCost)
+		rcp.DetailLaborCost = make(map[string]map[string]string)
+		totalArtistLaborCost = make(map[string]int)
+		for team, costs := range teamLaborCost {
+			rcp.DetailLaborCost[team] = make(map[string]string)
+			for project, cost := range costs {
+				rcp.DetailLaborCost[team][project], err = encryptAES256Func(strconv.Itoa(cost))
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				totalArtistLaborCost[team] += cost
+			}
+		}
+	}
+
 	// 합계 인건비 암호화
 	rcp.TotalArtistLaborCost = make(map[string]string)
 	rcp.TotalProjectLaborCost = make(map[string]string)
@@ -230,13 +251,33 @@ func handleSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 타입에 맞게 엑셀 파일 생성
-	err = genSMDetailLaborCostExcelFunc(strings.ToUpper(rcp.Type)+"_"+rcp.Date, rcp.Artist, rcp.Projects, rcp.DetailLaborCost, rcp.TotalArtistLaborCost, rcp.TotalProjectLaborCost, rcp.TotalLaborCost, token.ID)
+	// 타입에 맞게 엑셀 파일 생성, 팀별 인건비 파일은 이름 끝에 _team을 붙인다.
+	excelFileName := strings.ToUpper(rcp.Type) + "_" + rcp.Date
+	if !rcp.ViewSalary {
+		excelFileName += "_team"
+	}
+	err = genSMDetailLaborCostExcelFunc(excelFileName, rcp.Artist, rcp.Projects, rcp.DetailLaborCost, rcp.TotalArtistLaborCost, rcp.TotalProjectLaborCost, rcp.TotalLaborCost, !rcp.ViewSalary, token.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// 아티스트별 인건비를 보여주면 급여 정보 조회 로그를 남긴다.
+	if rcp.ViewSalary && len(rcp.Artist) != 0 {
+		err = addLogsFunc(client, Log{
+			UserID:     token.ID,
+			CreatedAt:  time.Now(),
+			Content:    fmt.Sprintf("세부 인건비 페이지에서 %s %s 아티스트별 인건비를 조회하였습니다.", strings.ToUpper(rcp.Type), rcp.Date),
+			Action:     LogActionView,
+			EntityType: LogEntityArtist,
+			IP:         clientIPFunc(r),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "text/html")
 	err = TEMPLATES.ExecuteTemplate(w, "smdetail-laborcost", rcp)
 	if err != nil {
@@ -246,7 +287,8 @@ func handleSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 }
 
 // genSMDetailLaborCostExcelFunc 함수는 세부 인건비 엑셀 파일을 만드는 함수이다.
-func genSMDetailLaborCostExcelFunc(fileName string, artists []Artist, projects []string, detail map[string]map[string]string, totalArtist map[string]string, totalProject map[string]string, total string, userID string) error {
+// byTeam이 true이면 artists는 sumLaborCostByTeamFunc 함수로 만든 팀 리스트이고 부서, 팀 열을 쓴다.
+func genSMDetailLaborCostExcelFunc(fileName string, artists []Artist, projects []string, detail map[string]map[string]string, totalArtist map[string]string, totalProject map[string]string, total string, byTeam bool, userID string) error {
 	path := os.TempDir() + "/budget/" + userID + "/smdetaillaborcost/"
 	excelFileName := fmt.Sprintf("smdetaillaborcost_%s.xlsx", fileName)
 
@@ -316,9 +358,17 @@ func genSMDetailLaborCostExcelFunc(fileName string, artists []Artist, projects [
 	}
 
 	// 제목 입력
-	f.SetCellValue(sheet, "A1", "ID")
+	if byTeam {
+		f.SetCellValue(sheet, "A1", "부서")
+	} else {
+		f.SetCellValue(sheet, "A1", "ID")
+	}
 	f.MergeCell(sheet, "A1", "A2")
-	f.SetCellValue(sheet, "B1", "이름")
+	if byTeam {
+		f.SetCellValue(sheet, "B1", "팀")
+	} else {
+		f.SetCellValue(sheet, "B1", "이름")
+	}
 	f.MergeCell(sheet, "B1", "B2")
 	f.SetCellValue(sheet, "C1", "프로젝트")
 	pos, err := excelize.CoordinatesToCellName(len(projects)+2, 1)
@@ -358,12 +408,16 @@ func genSMDetailLaborCostExcelFunc(fileName string, artists []Artist, projects [
 
 	// 데이터 입력
 	for i, artist := range artists {
-		// 아티스트 ID
+		// 아티스트 ID, 팀별이면 부서
 		pos, err = excelize.CoordinatesToCellName(1, i+3)
 		if err != nil {
 			return err
 		}
-		f.SetCellValue(sheet, pos, artist.ID)
+		if byTeam {
+			f.SetCellValue(sheet, pos, artist.Dept)
+		} else {
+			f.SetCellValue(sheet, pos, artist.ID)
+		}
 
 		// 아티스트 이름
 		pos, err = excelize.CoordinatesToCellName(2, i+3)
@@ -496,11 +550,7 @@ func handleExportSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -548,12 +598,40 @@ func handleExportSMDetailLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 급여 보기 권한이 있으면 아티스트별, 없으면 팀별 인건비 파일을 내려받는다.
+	user, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	withSalary, err := checkPermissionFunc(client, user, "viewsalary")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 권한에 맞지 않는 파일이면 엑셀 파일이 다시 생성되도록 리다이렉트
+	if strings.HasSuffix(fileInfo[0].Name(), "_team.xlsx") == withSalary {
+		http.Redirect(w, r, "/smdetail-laborcost", http.StatusSeeOther)
+		return
+	}
+
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")
 
+	laborCostType := "팀별"
+	entityType := LogEntityProject
+	if withSalary {
+		laborCostType = "아티스트별"
+		entityType = LogEntityArtist
+	}
+
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("%s 세부 인건비 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[1], strings.Split(filename[2], "-")[0], strings.Split(filename[2], "-")[1]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%s 세부 인건비 페이지에서 %s년 %s월의 %s 인건비 데이터를 다운로드하였습니다.", filename[1], strings.Split(filename[2], "-")[0], strings.Split(filename[2], "-")[1], laborCostType),
+		Action:     LogActionExport,
+		EntityType: entityType,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -575,11 +653,7 @@ func handleSMTotalLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -931,11 +1005,7 @@ func handleExportSMTotalLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -986,9 +1056,12 @@ func handleExportSMTotalLaborCostFunc(w http.ResponseWriter, r *http.Request) {
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("전체 인건비 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename[1]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("전체 인건비 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename[1]),
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
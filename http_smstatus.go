@@ -32,11 +32,7 @@ func handleSMPaymentStatusFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -513,11 +509,7 @@ func handleExportSMPaymentStatusFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -568,9 +560,12 @@ func handleExportSMPaymentStatusFunc(w http.ResponseWriter, r *http.Request) {
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")[1]
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("매출 현황 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("매출 현황 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename),
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -592,11 +587,7 @@ func handleSMVendorStatusFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -1090,11 +1081,7 @@ func handleExportSMVendorStatusFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1145,9 +1132,12 @@ func handleExportSMVendorStatusFunc(w http.ResponseWriter, r *http.Request) {
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")[1]
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("외주 현황 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("외주 현황 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename),
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -1169,11 +1159,7 @@ func handleSMTotalStatusFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -1553,11 +1539,7 @@ func handleExportSMTotalStatusFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1608,9 +1590,12 @@ func handleExportSMTotalStatusFunc(w http.ResponseWriter, r *http.Request) {
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")[1]
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("전체 현황 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("전체 현황 페이지에서 %s년의 데이터를 다운로드하였습니다.", filename),
+		Action:     LogActionExport,
+		EntityType: LogEntityProject,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
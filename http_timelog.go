@@ -21,11 +21,7 @@ func handleFinishedTimelogFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	status := q.Get("status")
@@ -63,11 +59,7 @@ func handleFinishedTimelogSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	status := q.Get("status")
@@ -423,6 +415,9 @@ func handleFinishedTimelogSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = "정산완료된 프로젝트의 타임로그 처리가 완료되었습니다."
+	log.Action = LogActionUpdate
+	log.EntityType = LogEntityTimelog
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
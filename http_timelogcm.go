@@ -34,11 +34,7 @@ func handleTimelogCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// dafult 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -269,17 +265,13 @@ func handleTimelogCMFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchTimelogCMFunc 함수는 CM 타임로그 페이지에서 Search를 눌렀을 때 실행되는 함수이다.
 func handleSearchTimelogCMFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	date := r.FormValue("date")
 	team := url.QueryEscape(r.FormValue("team"))
@@ -297,11 +289,7 @@ func handleUpdateTimelogCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	date := q.Get("date")
@@ -330,17 +318,13 @@ func handleUpdateTimelogCMFunc(w http.ResponseWriter, r *http.Request) {
 // handleTimelogCMExcelDownloadFunc 함수는 CM팀의 타임로그 정보를 입력할 엑셀 파일의 템플릿을 생성하여 다운로드하는 함수이다.
 func handleTimelogCMExcelDownloadFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Only", http.StatusMethodNotAllowed)
@@ -449,11 +433,7 @@ func handleUploadTimelogCMExcelFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	err = r.ParseMultipartForm(200000)
 	if err != nil {
@@ -515,11 +495,7 @@ func handleTimelogCMExcelSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Method Only", http.StatusMethodNotAllowed)
@@ -684,11 +660,7 @@ func handleUpdateTimelogCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -818,6 +790,10 @@ func handleUpdateTimelogCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("%d년 %d월의 CM 타임로그 임포트 중 타임로그를 추가하지 못했습니다.", year, month)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityTimelog
+		log.EntityID = fmt.Sprintf("%04d-%02d", year, month)
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -901,6 +877,10 @@ func handleUpdateTimelogCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("%d년 %d월의 CM 타임로그 임포트 중 존재하지않는 프로젝트로 인해 인건비 계산을 못했습니다.", year, month)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityTimelog
+		log.EntityID = fmt.Sprintf("%04d-%02d", year, month)
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -919,6 +899,10 @@ func handleUpdateTimelogCMSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("%d년 %d월의 CM 타임로그를 임포트 완료했습니다.", year, month)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityTimelog
+		log.EntityID = fmt.Sprintf("%04d-%02d", year, month)
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -938,11 +922,7 @@ func handleUpdateTimelogCMSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -1208,11 +1188,7 @@ func handleExportTimelogCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1263,9 +1239,12 @@ func handleExportTimelogCMFunc(w http.ResponseWriter, r *http.Request) {
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("CM 타임로그 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[2], filename[3]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("CM 타임로그 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[2], filename[3]),
+		Action:     LogActionExport,
+		EntityType: LogEntityTimelog,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -27,11 +27,7 @@ func handleTimelogSUPFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -198,11 +194,7 @@ func handleEditSUPTimelogFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -397,9 +389,13 @@ func handleEditSUPTimelogFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("%d년 %d월의 슈퍼바이저 타임로그 정보가 수정되었습니다.", year, month),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%d년 %d월의 슈퍼바이저 타임로그 정보가 수정되었습니다.", year, month),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityTimelog,
+		EntityID:   fmt.Sprintf("%04d-%02d", year, month),
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -420,11 +416,7 @@ func handleEditSUPTimelogSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 
@@ -35,11 +35,7 @@ func handleTimelogTotalFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -342,17 +338,13 @@ func handleTimelogTotalFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchTimelogTotalFunc 함수는 누계 타임로그 페이지에서 Search를 눌렀을 때 실행되는 함수이다.
 func handleSearchTimelogTotalFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	date := r.FormValue("date")
 	dept := r.FormValue("dept")
@@ -687,11 +679,7 @@ func handleExportTimelogTotalFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -742,9 +730,12 @@ func handleExportTimelogTotalFunc(w http.ResponseWriter, r *http.Request) {
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("Total 타임로그 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[2], filename[3]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("Total 타임로그 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[2], filename[3]),
+		Action:     LogActionExport,
+		EntityType: LogEntityTimelog,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
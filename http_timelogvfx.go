@@ -35,11 +35,7 @@ func handleTimelogVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -299,17 +295,13 @@ func handleTimelogVFXFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchTimelogVFXSubmitFunc 함수는 VFX 타임로그 페이지에서 Search를 눌렀을 때 실행되는 함수이다.
 func handleSearchTimelogVFXFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// default 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < DefaultLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	date := r.FormValue("date")
 	dept := r.FormValue("dept")
@@ -328,11 +320,7 @@ func handleUpdateTimelogVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	date := q.Get("date")
@@ -361,17 +349,13 @@ func handleUpdateTimelogVFXFunc(w http.ResponseWriter, r *http.Request) {
 // handleTimelogVFXExcelDownloadFunc 함수는 VFX팀의 타임로그 정보를 입력할 엑셀 파일의 템플릿을 생성하여 다운로드하는 함수이다.
 func handleTimelogVFXExcelDownloadFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, "Get Only", http.StatusMethodNotAllowed)
@@ -480,11 +464,7 @@ func handleUploadTimelogVFXExcelFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	err = r.ParseMultipartForm(200000)
 	if err != nil {
@@ -546,11 +526,7 @@ func handleTimelogVFXExcelSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	if r.Method != http.MethodGet {
 		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
@@ -714,11 +690,7 @@ func handleUpdateTimelogVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -883,6 +855,10 @@ func handleUpdateTimelogVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("%d년 %d월의 VFX 타임로그 임포트 중 타임로그를 추가하지 못했습니다.", year, month)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityTimelog
+		log.EntityID = fmt.Sprintf("%04d-%02d", year, month)
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -963,6 +939,10 @@ func handleUpdateTimelogVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("%d년 %d월의 VFX 타임로그 임포트 중 존재하지않는 프로젝트로 인해 인건비 계산을 못했습니다.", year, month)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityTimelog
+		log.EntityID = fmt.Sprintf("%04d-%02d", year, month)
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -981,7 +961,7 @@ func handleUpdateTimelogVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		ms := MonthlyStatus{}
 		ms.Date = date
 		ms.Status = true
-		err = setMonthlyStatusFunc(client, ms)
+		err = setMonthlyStatusAndNotifyFunc(client, ms)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -991,6 +971,10 @@ func handleUpdateTimelogVFXSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		log.UserID = token.ID
 		log.CreatedAt = time.Now()
 		log.Content = fmt.Sprintf("%d년 %d월의 VFX 타임로그를 임포트 완료했습니다.", year, month)
+		log.Action = LogActionImport
+		log.EntityType = LogEntityTimelog
+		log.EntityID = fmt.Sprintf("%04d-%02d", year, month)
+		log.IP = clientIPFunc(r)
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -1010,11 +994,7 @@ func handleUpdateTimelogVFXSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -1280,11 +1260,7 @@ func handleExportTimelogVFXFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -1335,9 +1311,12 @@ func handleExportTimelogVFXFunc(w http.ResponseWriter, r *http.Request) {
 	filename := strings.Split(strings.Split(fileInfo[0].Name(), ".")[0], "_")
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("VFX 타임로그 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[2], filename[3]),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("VFX 타임로그 페이지에서 %s년 %s월의 데이터를 다운로드하였습니다.", filename[2], filename[3]),
+		Action:     LogActionExport,
+		EntityType: LogEntityTimelog,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -0,0 +1,184 @@
+// 프로젝트 결산 프로그램
+//
+// Description : http 2단계 인증(TOTP) 관련 스크립트
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// handleEnableTOTPFunc 함수는 로그인한 계정에 2단계 인증(TOTP)을 켜는 함수이다. OTP 앱에 키를 등록하고 만든 코드가 맞아야 켜진다.
+func handleEnableTOTPFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	secret := r.FormValue("secret")
+	if secret == "" {
+		http.Error(w, "secret을 입력해주세요", http.StatusBadRequest)
+		return
+	}
+	if !checkTOTPFunc(secret, r.FormValue("code"), time.Now()) {
+		http.Error(w, "OTP 코드가 일치하지 않습니다. OTP 앱에 키를 등록했는지, 휴대폰 시간이 맞는지 확인해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	u, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	before := u
+	u.TOTPSecret, err = encryptAES256Func(secret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	u.TOTPEnabled = true
+	err = setUserFunc(client, u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "2단계 인증(OTP)을 켰습니다.",
+		Action:     LogActionUpdate,
+		EntityType: LogEntityUser,
+		EntityID:   u.ID,
+		Changes:    diffEntityFunc(before, u),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/editprofile", http.StatusSeeOther)
+}
+
+// handleDisableTOTPFunc 함수는 사용자의 2단계 인증(TOTP)을 끄는 함수이다. 현재 OTP 코드가 맞아야 끌 수 있다.
+func handleDisableTOTPFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	u, err := getUserFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !u.TOTPEnabled {
+		http.Redirect(w, r, "/editprofile", http.StatusSeeOther)
+		return
+	}
+	secret, err := decryptAES256Func(u.TOTPSecret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !checkTOTPFunc(secret, r.FormValue("code"), time.Now()) {
+		http.Error(w, "OTP 코드가 일치하지 않습니다", http.StatusBadRequest)
+		return
+	}
+	before := u
+	u.TOTPSecret = ""
+	u.TOTPEnabled = false
+	err = setUserFunc(client, u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "2단계 인증(OTP)을 껐습니다.",
+		Action:     LogActionUpdate,
+		EntityType: LogEntityUser,
+		EntityID:   u.ID,
+		Changes:    diffEntityFunc(before, u),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/editprofile", http.StatusSeeOther)
+}
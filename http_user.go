@@ -7,8 +7,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -29,7 +31,8 @@ func handleSignupFunc(w http.ResponseWriter, r *http.Request) {
 
 // handleSignupSubmitFunc 함수는 유저를 추가하고 회원가입 완료 페이지로 리다이렉트하는 함수이다.
 func handleSignupSubmitFunc(w http.ResponseWriter, r *http.Request) {
-	id := r.FormValue("ID")
+	// 로그인할 때 ID를 소문자로 바꿔서 찾으므로 계정도 소문자 ID로 만든다.
+	id := strings.ToLower(r.FormValue("ID"))
 	if id == "" {
 		http.Error(w, "ID 값이 빈 문자열입니다", http.StatusBadRequest)
 		return
@@ -105,6 +108,10 @@ func handleSignupSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	log.UserID = u.ID
 	log.CreatedAt = time.Now()
 	log.Content = "회원가입하였습니다."
+	log.Action = LogActionCreate
+	log.EntityType = LogEntityUser
+	log.EntityID = u.ID
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -137,7 +144,8 @@ func handleSigninFunc(w http.ResponseWriter, r *http.Request) {
 
 // handleSigninSubmitFunc 함수는 로그인 정보가 DB와 일치하는지 확인하고 쿠키에 토큰을 저장하는 함수이다.
 func handleSigninSubmitFunc(w http.ResponseWriter, r *http.Request) {
-	id := r.FormValue("ID")
+	// ID는 소문자로 바꿔서 잠금 확인, 실패 기록, 사용자 검색에 모두 같은 값을 사용한다.
+	id := strings.ToLower(r.FormValue("ID"))
 	if id == "" {
 		http.Error(w, "ID 값이 빈 문자열 입니다", http.StatusBadRequest)
 		return
@@ -172,63 +180,321 @@ func handleSigninSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 오래된 로그인 기록을 정리하고, 같은 IP에서 로그인에 너무 많이 실패했으면 잠시 로그인을 막는다.
+	now := time.Now()
+	history := LoginHistory{
+		UserID:    id,
+		IP:        clientIPFunc(r),
+		UserAgent: r.UserAgent(),
+		CreatedAt: now,
+	}
+	err = rmLoginHistoryFunc(client, now.Add(-LoginHistoryRetention))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ipFails, err := countFailedLoginsByIPFunc(client, history.IP, now.Add(-LoginIPFailWindow))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if ipFails >= LoginIPFailLimit {
+		history.Reason = "IP 로그인 시도 제한"
+		err = addLoginHistoryFunc(client, history)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusTooManyRequests)
+		err = TEMPLATES.ExecuteTemplate(w, "signin-locked", now.Add(LoginIPFailWindow))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
 	u, err := getUserFunc(client, id) // DB에서 유저 정보를 가져온다.
+	exist := true
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		exist = false
+	}
+
+	// 잠긴 계정은 비밀번호를 확인하지 않는다.
+	if exist && isLoginLockedFunc(u, now) {
+		history.Reason = "계정 잠김"
+		err = addLoginHistoryFunc(client, history)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = TEMPLATES.ExecuteTemplate(w, "signin-locked", u.LockedUntil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
+	// LDAP 서버가 설정되어 있으면 LDAP으로 먼저 확인하고, 실패하면 DB에 저장된 비밀번호로 확인한다.
+	adminSetting, err := getAdminSettingFunc(client)
 	if err != nil {
-		if err == mongo.ErrNoDocuments { // DB에 저장된 유저가 없을 때 로그인 실패 페이지를 띄운다.
-			err := TEMPLATES.ExecuteTemplate(w, "signin-fail", nil)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entry, ldapErr := ldapAuthenticateFunc(adminSetting, id, pw)
+	if ldapErr == nil && exist && u.Source != "ldap" {
+		// 같은 ID의 로컬 계정은 LDAP 로그인으로 가져갈 수 없다. 관리자가 계정을 정리하기 전까지는 DB에 저장된 비밀번호로만 로그인할 수 있다.
+		log.Printf("%s: %v", id, errLDAPLocalAccount)
+		ldapErr = errLDAPLocalAccount
+	}
+	if ldapErr != nil && ldapErr != errLDAPDisabled && ldapErr != errLDAPInvalidCredentials && ldapErr != errLDAPLocalAccount {
+		log.Println(ldapErr) // LDAP 서버에 연결할 수 없어도 로컬 계정으로 로그인할 수 있게 한다.
+	}
+	if ldapErr == nil {
+		u, err = signinLDAPUserFunc(client, u, exist, id, entry, adminSetting.LDAPGroupRoles, clientIPFunc(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		exist = true
+	} else {
+		// LDAP 로그인으로 만든 계정은 DB에 저장된 비밀번호가 없으므로 로그인할 수 없다.
+		if !exist || u.Source == "ldap" {
+			history.Reason = "아이디 또는 비밀번호 불일치"
+			err = signinFailFunc(w, client, u, exist, history)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// 입력한 비밀번호와 DB에 저장된 비밀번호가 일치하는지 확인
+		err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(pw))
+		if err != nil {
+			history.Reason = "아이디 또는 비밀번호 불일치"
+			err = signinFailFunc(w, client, u, exist, history)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
 			}
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
 	}
 
-	// 입력한 비밀번호와 DB에 저장된 비밀번호가 일치하는지 확인
-	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(pw))
-	if err != nil {
-		err := TEMPLATES.ExecuteTemplate(w, "signin-fail", nil)
+	// 2단계 인증을 켠 사용자는 OTP 코드를 확인한다.
+	if u.TOTPEnabled {
+		secret, err := decryptAES256Func(u.TOTPSecret)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !checkTOTPFunc(secret, r.FormValue("OTP"), now) {
+			history.Reason = "OTP 코드 불일치"
+			err = signinFailFunc(w, client, u, exist, history)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+
+	// 로그인에 성공하면 실패 횟수를 초기화한다.
+	if u.FailedLogins != 0 || !u.LockedUntil.IsZero() {
+		u.FailedLogins = 0
+		u.LockedUntil = time.Time{}
+		err = setUserFunc(client, u)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+	}
+	history.UserID = u.ID
+	history.Success = true
+	err = addLoginHistoryFunc(client, history)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Token을 쿠키에 저장한다.
-	c := http.Cookie{
-		Name:    "SessionToken",
-		Value:   u.Token,
-		Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
+	// 만료된 세션을 정리하고 새 세션을 만들어 세션 토큰을 쿠키에 저장한다.
+	err = rmExpiredSessionsFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	http.SetCookie(w, &c)
-	signKey := http.Cookie{
-		Name:    "SessionSignKey",
-		Value:   u.SignKey,
-		Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
+	err = startSessionFunc(client, w, r, u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	http.SetCookie(w, &signKey)
 
 	// / 로 리다이렉션 한다.
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
-// handleSignOutFunc 함수는 쿠키에 저장된 토큰을 삭제하는 함수이다.
+// signinFailFunc 함수는 로그인 실패를 기록하고 로그인 실패 페이지를 띄우는 함수이다.
+// 있는 계정이면 실패 횟수를 늘리고, LoginFailLimit번 연속으로 실패하면 계정을 잠근다.
+func signinFailFunc(w http.ResponseWriter, client *mongo.Client, u User, exist bool, h LoginHistory) error {
+	err := addLoginHistoryFunc(client, h)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return TEMPLATES.ExecuteTemplate(w, "signin-fail", nil)
+	}
+	u, locked := recordLoginFailureFunc(u, h.CreatedAt)
+	err = setUserFunc(client, u)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return TEMPLATES.ExecuteTemplate(w, "signin-fail", nil)
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     u.ID,
+		CreatedAt:  h.CreatedAt,
+		Content:    fmt.Sprintf("로그인에 %d번 연속으로 실패해서 계정이 %s까지 잠겼습니다. (%s)", LoginFailLimit, u.LockedUntil.Format("2006-01-02 15:04"), h.IP),
+		Action:     LogActionLogin,
+		EntityType: LogEntityUser,
+		EntityID:   u.ID,
+		IP:         h.IP,
+	})
+	if err != nil {
+		return err
+	}
+	return TEMPLATES.ExecuteTemplate(w, "signin-locked", u.LockedUntil)
+}
+
+// signinLDAPUserFunc 함수는 LDAP 로그인에 성공한 사용자를 DB에 저장하는 함수이다.
+// 처음 로그인한 사용자는 계정을 만들고, LDAP 로그인으로 만든 사용자는 LDAP 그룹에 따라 역할과 액세스 레벨을 바꾼다. ip는 로그에 남길 요청 IP이다.
+// 같은 ID의 로컬 계정이 있으면 그 계정을 바꾸지 않고 errLDAPLocalAccount를 반환한다.
+func signinLDAPUserFunc(client *mongo.Client, u User, exist bool, id string, entry LDAPEntry, mappings []LDAPGroupRole, ip string) (User, error) {
+	if exist && u.Source != "ldap" {
+		return u, errLDAPLocalAccount
+	}
+	if !exist {
+		u = User{ID: id, AccessLevel: GuestLevel, Source: "ldap"}
+		u = applyLDAPEntryFunc(u, entry, mappings)
+
+		// DB에 저장된 비밀번호로는 로그인할 수 없도록 임의의 비밀번호를 넣는다.
+		randomPW, err := newSessionIDFunc()
+		if err != nil {
+			return u, err
+		}
+		u.Password, err = encryptFunc(randomPW)
+		if err != nil {
+			return u, err
+		}
+		err = u.CreateToken()
+		if err != nil {
+			return u, err
+		}
+		err = addUserFunc(client, u)
+		if err != nil {
+			return u, err
+		}
+		err = addLogsFunc(client, Log{
+			UserID:     u.ID,
+			CreatedAt:  time.Now(),
+			Content:    fmt.Sprintf("LDAP 로그인으로 계정이 만들어졌습니다. (%s, %s)", u.AccessLevel, entry.DN),
+			Action:     LogActionCreate,
+			EntityType: LogEntityUser,
+			EntityID:   u.ID,
+			IP:         ip,
+		})
+		if err != nil {
+			return u, err
+		}
+		return u, nil
+	}
+
+	updated := applyLDAPEntryFunc(u, entry, mappings)
+	if updated.Role == u.Role && updated.AccessLevel == u.AccessLevel && updated.Name == u.Name && updated.Team == u.Team {
+		return u, nil
+	}
+	if updated.AccessLevel != u.AccessLevel {
+		err := updated.CreateToken()
+		if err != nil {
+			return u, err
+		}
+	}
+	err := setUserFunc(client, updated)
+	if err != nil {
+		return u, err
+	}
+	if updated.Role != u.Role || updated.AccessLevel != u.AccessLevel {
+		err = addLogsFunc(client, Log{
+			UserID:     u.ID,
+			CreatedAt:  time.Now(),
+			Content:    fmt.Sprintf("LDAP 그룹에 따라 역할이 %q, 액세스 레벨이 %s(으)로 바뀌었습니다.", updated.Role, updated.AccessLevel),
+			Action:     LogActionUpdate,
+			EntityType: LogEntityUser,
+			EntityID:   u.ID,
+			Changes:    diffEntityFunc(u, updated),
+			IP:         ip,
+		})
+		if err != nil {
+			return u, err
+		}
+	}
+	return updated, nil
+}
+
+// handleSignOutFunc 함수는 DB에서 로그인 세션을 삭제하고 쿠키에 저장된 토큰을 삭제하는 함수이다.
 func handleSignOutFunc(w http.ResponseWriter, r *http.Request) {
-	tokenKey := http.Cookie{
-		Name:   "SessionToken",
-		Value:  "",
-		MaxAge: -1,
-	}
-	http.SetCookie(w, &tokenKey)
-	signKey := http.Cookie{
-		Name:   "SessionSignKey",
-		Value:  "",
-		MaxAge: -1,
-	}
-	http.SetCookie(w, &signKey)
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+	clearSessionCookieFunc(w)
+
+	// 서버 키로 열리는 토큰이면 DB에서 세션을 삭제한다.
+	key, err := sessionSignKeyFunc()
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+	tk, err := parseSessionTokenFunc(getSessionTokenFromCookieFunc(r), key)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = rmSessionFunc(client, tk.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	http.Redirect(w, r, "/signin", http.StatusSeeOther)
 }
 
@@ -265,18 +531,78 @@ func handleEditProfileFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 이벤트별 알림 구독 정보
+	type SubscriptionInfo struct {
+		Event      NotificationEvent
+		Subscribed bool     // 구독 여부
+		Projects   []string // 알림을 받을 프로젝트 ID 리스트
+	}
+
 	type Recipe struct {
-		User  User
-		Token Token
+		User          User
+		Token         Token
+		Subscriptions []SubscriptionInfo // 이벤트별 알림 구독 정보
+		Projects      []Project          // 알림을 구독할 수 있는 프로젝트 리스트
+		APITokens     []APIToken         // 개인 API 토큰 리스트
+		APIScopes     []APIScope         // API 토큰에 줄 수 있는 API 범위 리스트
+		Now           time.Time          // 토큰 만료 여부를 표시하기 위한 현재 시간
+		History       []LoginHistory     // 최근 로그인 기록
+		TOTPSecret    string             // 2단계 인증을 켤 때 OTP 앱에 등록할 새 키
+		TOTPURI       string             // 새 키의 otpauth URI
 	}
 	rcp := Recipe{}
 	rcp.Token = token
+	rcp.APIScopes = APIScopes
+	rcp.Now = time.Now()
 
 	rcp.User, err = getUserFunc(client, token.ID) // DB에서 유저 정보를 가져온다.
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	rcp.APITokens, err = getAPITokensFunc(client, token.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.History, err = getLoginHistoryFunc(client, token.ID, 10)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 2단계 인증을 켤 수 있도록 새 키를 만들어 보여준다.
+	if !rcp.User.TOTPEnabled {
+		rcp.TOTPSecret, err = newTOTPSecretFunc()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rcp.TOTPURI = totpURIFunc(rcp.User.ID, rcp.TOTPSecret)
+	}
+
+	// 결산 현황을 볼 수 있는 사용자만 알림을 구독할 수 있다.
+	viewStatus, err := checkPermissionFunc(client, rcp.User, "viewstatus")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if viewStatus {
+		for _, event := range NotificationEvents {
+			info := SubscriptionInfo{Event: event}
+			s, ok := getSubscriptionFunc(rcp.User, event.ID)
+			if ok {
+				info.Subscribed = true
+				info.Projects = s.Projects
+			}
+			rcp.Subscriptions = append(rcp.Subscriptions, info)
+		}
+		rcp.Projects, err = getAllProjectsFunc(client)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 
 	// Profile 페이지를 띄운다.
 	w.Header().Set("Content-Type", "text/html")
@@ -327,8 +653,31 @@ func handleEditProfileSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before := u
 	u.Team = r.FormValue("team")
 	u.Name = r.FormValue("name")
+
+	// 알림 구독 정보
+	viewStatus, err := checkPermissionFunc(client, u, "viewstatus")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if viewStatus {
+		u.Subscriptions = nil
+		for _, event := range NotificationEvents {
+			if r.FormValue("subscribe-"+event.ID) != "on" {
+				continue
+			}
+			s := Subscription{
+				Event: event.ID,
+			}
+			if event.ByProject {
+				s.Projects = r.Form["projects-"+event.ID] // 선택하지 않으면 모든 프로젝트의 알림을 받는다.
+			}
+			u.Subscriptions = append(u.Subscriptions, s)
+		}
+	}
 	err = u.CheckError()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -345,6 +694,11 @@ func handleEditProfileSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = "프로필이 수정되었습니다."
+	log.Action = LogActionUpdate
+	log.EntityType = LogEntityUser
+	log.EntityID = u.ID
+	log.Changes = diffEntityFunc(before, u)
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -466,6 +820,7 @@ func handleUpdatePasswordSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	before := u
 	u.Password = encryptedPW
 
 	// token 재생성
@@ -481,24 +836,27 @@ func handleUpdatePasswordSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token을 쿠키에 저장한다.
-	c := http.Cookie{
-		Name:    "SessionToken",
-		Value:   u.Token,
-		Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
+	// 다른 곳에 로그인된 세션을 모두 삭제하고 새 세션을 만든다.
+	err = rmSessionsFunc(client, u.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	http.SetCookie(w, &c)
-	signKey := http.Cookie{
-		Name:    "SessionSignKey",
-		Value:   u.SignKey,
-		Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
+	err = startSessionFunc(client, w, r, u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	http.SetCookie(w, &signKey)
 
 	log := Log{}
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = "비밀번호가 변경되었습니다."
+	log.Action = LogActionUpdate
+	log.EntityType = LogEntityUser
+	log.EntityID = u.ID
+	log.Changes = diffEntityFunc(before, u)
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -563,11 +921,7 @@ func handleUsersFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -594,12 +948,19 @@ func handleUsersFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type Recipe struct {
-		Token Token
-		User  User   // 현재 로그인된 유저
-		Users []User // 유저 리스트
+		Token       Token
+		User        User           // 현재 로그인된 유저
+		Users       []User         // 유저 리스트
+		Sessions    []Session      // 로그인된 세션 리스트
+		SessionNums map[string]int // 유저별 로그인된 세션 수
+		Roles       []Role         // 역할 리스트
+		Permissions []Permission   // 권한 리스트
+		History     []LoginHistory // 최근 로그인 기록
+		Now         time.Time      // 계정 잠금 여부를 표시하기 위한 현재 시간
 	}
 	rcp := Recipe{}
 	rcp.Token = token
+	rcp.Now = time.Now()
 	rcp.User, err = getUserFunc(client, token.ID) // DB에서 현재 로그인된 유저의 정보를 가져온다.
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -612,6 +973,28 @@ func handleUsersFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rcp.Sessions, err = getSessionsFunc(client, "") // DB에서 만료되지 않은 모든 세션을 가져온다.
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.SessionNums = make(map[string]int)
+	for _, s := range rcp.Sessions {
+		rcp.SessionNums[s.UserID]++
+	}
+
+	rcp.Roles, err = getAllRolesFunc(client) // DB에 저장된 모든 역할 정보를 가져온다.
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rcp.Permissions = Permissions
+	rcp.History, err = getLoginHistoryFunc(client, "", 50) // 모든 유저의 최근 로그인 기록을 가져온다.
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/html")
 	err = TEMPLATES.ExecuteTemplate(w, "users", rcp)
 	if err != nil {
@@ -629,9 +1012,11 @@ func handleUpdateUsersFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -679,8 +1064,15 @@ func handleUpdateUsersFunc(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		before := user
+		changed := user.AccessLevel != AccessLevel(accessLevel)
 		user.AccessLevel = AccessLevel(accessLevel)
 
+		// 역할과 허용된 프로젝트 변경
+		user.Role = r.FormValue(fmt.Sprintf("role%d", i))
+		user.Projects = stringToListFunc(strings.ReplaceAll(r.FormValue(fmt.Sprintf("projects%d", i)), " ", ""), ",")
+		changes := diffEntityFunc(before, user) // 토큰을 다시 만들기 전에 비교한다.
+
 		// token 재생성
 		err = user.CreateToken()
 		if err != nil {
@@ -694,32 +1086,41 @@ func handleUpdateUsersFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// 현재 로그인되어 있는 계정인 경우 새로 재생성한 Token을 쿠키에 저장한다.
-		if id == token.ID {
-			SessionToken := http.Cookie{
-				Name:    "SessionToken",
-				Value:   user.Token,
-				Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
-			}
-			http.SetCookie(w, &SessionToken)
-			SessionSignKey := http.Cookie{
-				Name:    "SessionSignKey",
-				Value:   user.SignKey,
-				Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
+		// 바뀐 유저만 로그를 남긴다.
+		if len(changes) != 0 {
+			err = addLogsFunc(client, Log{
+				UserID:     token.ID,
+				CreatedAt:  time.Now(),
+				Content:    fmt.Sprintf("유저 %s의 권한이 수정되었습니다.", user.ID),
+				Action:     LogActionUpdate,
+				EntityType: LogEntityUser,
+				EntityID:   user.ID,
+				Changes:    changes,
+				IP:         clientIPFunc(r),
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
 			}
-			http.SetCookie(w, &SessionSignKey)
 		}
-	}
-
-	log := Log{}
-	log.UserID = token.ID
-	log.CreatedAt = time.Now()
-	log.Content = "유저 권한이 수정되었습니다."
 
-	err = addLogsFunc(client, log)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		// 레벨이 바뀌면 로그인된 세션을 모두 삭제해서 다시 로그인하게 한다.
+		if !changed {
+			continue
+		}
+		err = rmSessionsFunc(client, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// 현재 로그인되어 있는 계정인 경우 바뀐 레벨로 새 세션을 만든다.
+		if id == token.ID {
+			err = startSessionFunc(client, w, r, user)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
 	}
 
 	http.Redirect(w, r, "/updateusers-success", http.StatusSeeOther)
@@ -734,11 +1135,7 @@ func handleUpdateUsersSuccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token
@@ -754,25 +1151,450 @@ func handleUpdateUsersSuccess(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleChangePasswordFunc 함수는 관리자가 User 관리 페이지에서 비밀번호를 변경하는 함수이다.
-func handleChangePasswordFunc(w http.ResponseWriter, r *http.Request) {
+// handleUpdateRolesFunc 함수는 역할별 권한을 업데이트하는 함수이다.
+func handleUpdateRolesFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
 	token, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
 		return
 	}
-	type Recipe struct {
-		ID    string
-		Token Token
+
+	roleNum, err := strconv.Atoi(r.FormValue("roleNum"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	rcp := Recipe{}
-	rcp.Token = token
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	users, err := getAllUsersFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	before, err := getAllRolesFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 마지막 행은 새로 추가하는 역할이다.
+	for i := 0; i <= roleNum; i++ {
+		name := strings.TrimSpace(r.FormValue(fmt.Sprintf("rolename%d", i)))
+		if name == "" {
+			continue
+		}
+		if r.FormValue(fmt.Sprintf("rmrole%d", i)) == "on" {
+			for _, u := range users {
+				if u.Role == name {
+					http.Error(w, fmt.Sprintf("%s 역할을 사용하는 유저(%s)가 있어서 삭제할 수 없습니다", name, u.ID), http.StatusBadRequest)
+					return
+				}
+			}
+			err = rmRoleFunc(client, name)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			continue
+		}
+		role := Role{Name: name, Permissions: []string{}}
+		for _, p := range Permissions {
+			if r.FormValue(fmt.Sprintf("permission%d-%s", i, p.ID)) == "on" {
+				role.Permissions = append(role.Permissions, p.ID)
+			}
+		}
+		err = setRoleFunc(client, role)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	after, err := getAllRolesFunc(client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// 역할 이름별 권한 리스트로 비교한다.
+	beforeMap := make(map[string][]string)
+	for _, role := range before {
+		beforeMap[role.Name] = role.Permissions
+	}
+	afterMap := make(map[string][]string)
+	for _, role := range after {
+		afterMap[role.Name] = role.Permissions
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "역할별 권한이 수정되었습니다.",
+		Action:     LogActionUpdate,
+		EntityType: LogEntityRole,
+		Changes:    diffEntityFunc(beforeMap, afterMap),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// handleRmSessionFunc 함수는 관리자가 로그인 세션 하나를 삭제해서 강제로 로그아웃시키는 함수이다.
+func handleRmSessionFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	if id == "" {
+		http.Error(w, "id를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	session, err := getSessionFunc(client, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments { // 이미 만료되었거나 삭제된 세션이다.
+			http.Redirect(w, r, "/users", http.StatusSeeOther)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	err = rmSessionFunc(client, session.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("유저 %s의 로그인 세션(%s, %s)을 삭제했습니다.", session.UserID, session.IP, session.CreatedAt.Format("2006-01-02 15:04")),
+		Action:     LogActionDelete,
+		EntityType: LogEntitySession,
+		EntityID:   session.UserID, // 세션 ID는 로그에 남기지 않는다.
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// handleRmSessionsFunc 함수는 관리자가 유저의 로그인 세션을 모두 삭제해서 강제로 로그아웃시키는 함수이다.
+func handleRmSessionsFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.FormValue("userid")
+	if userID == "" {
+		http.Error(w, "userid를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = rmSessionsFunc(client, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("유저 %s의 로그인 세션을 모두 삭제했습니다.", userID),
+		Action:     LogActionDelete,
+		EntityType: LogEntitySession,
+		EntityID:   userID,
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// handleUnlockUserFunc 함수는 관리자가 로그인 실패로 잠긴 유저의 계정 잠금을 해제하는 함수이다.
+func handleUnlockUserFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.FormValue("userid")
+	if userID == "" {
+		http.Error(w, "userid를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	u, err := getUserFunc(client, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	before := u
+	u.FailedLogins = 0
+	u.LockedUntil = time.Time{}
+	err = setUserFunc(client, u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("유저 %s의 계정 잠금을 해제했습니다.", u.ID),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityUser,
+		EntityID:   u.ID,
+		Changes:    diffEntityFunc(before, u),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// handleResetTOTPFunc 함수는 관리자가 OTP 앱을 잃어버린 유저의 2단계 인증(TOTP)을 끄는 함수이다.
+func handleResetTOTPFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	// Post 메소드가 아니면 에러
+	if r.Method != http.MethodPost {
+		http.Error(w, "Post Method Only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.FormValue("userid")
+	if userID == "" {
+		http.Error(w, "userid를 입력해주세요", http.StatusBadRequest)
+		return
+	}
+
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	u, err := getUserFunc(client, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	before := u
+	u.TOTPSecret = ""
+	u.TOTPEnabled = false
+	err = setUserFunc(client, u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = addLogsFunc(client, Log{
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("유저 %s의 2단계 인증(OTP)을 초기화했습니다.", u.ID),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityUser,
+		EntityID:   u.ID,
+		Changes:    diffEntityFunc(before, u),
+		IP:         clientIPFunc(r),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// handleChangePasswordFunc 함수는 관리자가 User 관리 페이지에서 비밀번호를 변경하는 함수이다.
+func handleChangePasswordFunc(w http.ResponseWriter, r *http.Request) {
+	// 로그인 상태인지 확인
+	token, err := getTokenFromHeaderFunc(w, r)
+	if err != nil {
+		http.Redirect(w, r, "/signin", http.StatusSeeOther)
+		return
+	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+	type Recipe struct {
+		ID    string
+		Token Token
+	}
+	rcp := Recipe{}
+	rcp.Token = token
 
 	q := r.URL.Query()
 	rcp.ID = q.Get("id")
@@ -797,11 +1619,7 @@ func handleChangePasswordSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// 비밀번호를 변경할 User의 ID를 가져온다.
 	q := r.URL.Query()
@@ -811,9 +1629,9 @@ func handleChangePasswordSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 현재 관리자 계정이고 비밀번호를 변경하려는 ID와 자신의 ID가 같은 경우 현재 비밀번호를 가져온다.
+	// 비밀번호를 변경하려는 ID와 자신의 ID가 같은 경우 현재 비밀번호를 가져온다.
 	nowPW := ""
-	if token.AccessLevel == AdminLevel && id == token.ID {
+	if id == token.ID {
 		nowPW = r.FormValue("nowPassword")
 		if nowPW == "" {
 			http.Error(w, "현재 사용중인 패스워드 값이 빈 문자열입니다", http.StatusBadRequest)
@@ -862,8 +1680,8 @@ func handleChangePasswordSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 관리자가 본인의 비밀번호를 변경하는 경우 입력한 비밀번호와 DB에 저장된 비밀번호가 일치하는지 확인
-	if token.AccessLevel == AdminLevel && id == token.ID {
+	// 본인의 비밀번호를 변경하는 경우 입력한 비밀번호와 DB에 저장된 비밀번호가 일치하는지 확인
+	if id == token.ID {
 		err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(nowPW))
 		if err != nil {
 			if err == bcrypt.ErrMismatchedHashAndPassword {
@@ -881,6 +1699,7 @@ func handleChangePasswordSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	before := u
 	u.Password = encryptedPW
 
 	// 새로 토큰을 생성하고 User를 Set한다.
@@ -896,26 +1715,31 @@ func handleChangePasswordSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 현재 로그인되어있는 관리자의 비밀번호를 변경하는 경우 Token을 쿠키에 저장한다.
-	if token.AccessLevel == AdminLevel && id == token.ID {
-		SessionToken := http.Cookie{
-			Name:    "SessionToken",
-			Value:   u.Token,
-			Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
-		}
-		http.SetCookie(w, &SessionToken)
-		SessionSignKey := http.Cookie{
-			Name:    "SessionSignKey",
-			Value:   u.SignKey,
-			Expires: time.Now().Add(time.Duration(*flagCookieAge) * time.Hour),
+	// 비밀번호가 바뀐 사용자의 세션을 모두 삭제한다.
+	err = rmSessionsFunc(client, u.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 현재 로그인되어있는 사용자의 비밀번호를 변경하는 경우 새 세션을 만든다.
+	if id == token.ID {
+		err = startSessionFunc(client, w, r, u)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-		http.SetCookie(w, &SessionSignKey)
 	}
 
 	log := Log{}
 	log.UserID = token.ID
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("유저 %s의 비밀번호가 수정되었습니다.", id)
+	log.Action = LogActionUpdate
+	log.EntityType = LogEntityUser
+	log.EntityID = id
+	log.Changes = diffEntityFunc(before, u)
+	log.IP = clientIPFunc(r)
 
 	err = addLogsFunc(client, log)
 	if err != nil {
@@ -934,11 +1758,7 @@ func handleChangePasswordSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
-	// admin 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 	type Recipe struct {
 		Token
 	}
@@ -34,11 +34,7 @@ func handleVendorsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -175,17 +171,13 @@ func handleVendorsFunc(w http.ResponseWriter, r *http.Request) {
 // handleSearchVendorsFunc 함수는 벤더관리 페이지에서 Search 버튼을 눌렀을 때 실행하는 함수이다.
 func handleSearchVendorsFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// member 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < MemberLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	isfinished := r.FormValue("isfinished")
 	if isfinished == "on" {
@@ -698,11 +690,7 @@ func handleExportVendorsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// admin 레벨 미만이면 invalideaccess 페이지로 리다이렉트
-	if token.AccessLevel < AdminLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// Post 메소드가 아니면 에러
 	if r.Method != http.MethodPost {
@@ -757,9 +745,12 @@ func handleExportVendorsFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   "벤더 관리 페이지에서 벤더 데이터를 다운로드하였습니다.",
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    "벤더 관리 페이지에서 벤더 데이터를 다운로드하였습니다.",
+		Action:     LogActionExport,
+		EntityType: LogEntityVendor,
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -775,17 +766,13 @@ func handleExportVendorsFunc(w http.ResponseWriter, r *http.Request) {
 // handleAddVendorPageFunc 함수는 URL에 objectID를 붙여서 /add-vendor 페이지로 redirect한다.
 func handleAddVendorPageFunc(w http.ResponseWriter, r *http.Request) {
 	// 로그인 상태인지 확인
-	token, err := getTokenFromHeaderFunc(w, r)
+	_, err := getTokenFromHeaderFunc(w, r)
 	if err != nil {
 		http.Redirect(w, r, "/signin", http.StatusSeeOther)
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 	objectID := primitive.NewObjectID().Hex()
 
 	q := r.URL.Query()
@@ -806,11 +793,7 @@ func handleAddVendorFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -872,11 +855,7 @@ func handleAddVendorSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -1028,16 +1007,20 @@ func handleAddVendorSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = addVendorFunc(client, v)
+	vendorID, err := addVendorFunc(client, v)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("프로젝트 %s에 벤더 %s가 추가되었습니다.", v.Project, v.Name),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s에 벤더 %s가 추가되었습니다.", v.Project, v.Name),
+		Action:     LogActionCreate,
+		EntityType: LogEntityVendor,
+		EntityID:   vendorID.Hex(),
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -1058,11 +1041,7 @@ func handleAddVendorSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	type Recipe struct {
 		Token   Token
@@ -1091,11 +1070,7 @@ func handleEditVendorFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -1172,11 +1147,7 @@ func handleEditVendorSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	id := r.FormValue("id")
 	isfinished := r.FormValue("isfinished")
@@ -1210,6 +1181,7 @@ func handleEditVendorSubmitFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	before := vendor
 
 	vendor.Project = r.FormValue("project")
 	project, err := getProjectFunc(client, vendor.Project)
@@ -1339,9 +1311,14 @@ func handleEditVendorSubmitFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := Log{
-		UserID:    token.ID,
-		CreatedAt: time.Now(),
-		Content:   fmt.Sprintf("프로젝트 %s에 벤더 %s가 수정되었습니다.", vendor.Project, vendor.Name),
+		UserID:     token.ID,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s에 벤더 %s가 수정되었습니다.", vendor.Project, vendor.Name),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityVendor,
+		EntityID:   id,
+		Changes:    diffEntityFunc(before, vendor),
+		IP:         clientIPFunc(r),
 	}
 
 	err = addLogsFunc(client, log)
@@ -1362,11 +1339,7 @@ func handleEditVendorSuccessFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// manager 레벨 미만이면 invalidaccess 페이지로 리다이렉트
-	if token.AccessLevel < ManagerLevel {
-		http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	q := r.URL.Query()
 	id := q.Get("id")
@@ -0,0 +1,415 @@
+// 프로젝트 결산 프로그램
+//
+// Description : LDAP 로그인 관련 스크립트
+// 그룹웨어 디렉토리에 로그인하기 위해 LDAPv3의 Simple Bind, Search만 구현한다.
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LDAP 메시지 태그
+const (
+	ldapTagBindRequest      = 0x60
+	ldapTagBindResponse     = 0x61
+	ldapTagUnbindRequest    = 0x42
+	ldapTagSearchRequest    = 0x63
+	ldapTagSearchResultItem = 0x64
+	ldapTagSearchResultDone = 0x65
+	ldapTagSearchReference  = 0x73
+)
+
+var (
+	// errLDAPInvalidCredentials 는 LDAP에 사용자가 없거나 비밀번호가 틀렸을 때 반환하는 에러이다.
+	errLDAPInvalidCredentials = errors.New("LDAP 계정 정보가 일치하지 않습니다")
+	// errLDAPDisabled 는 LDAP 서버가 설정되지 않았을 때 반환하는 에러이다.
+	errLDAPDisabled = errors.New("LDAP 서버가 설정되지 않았습니다")
+	// errLDAPLocalAccount 는 LDAP 계정과 같은 ID의 로컬 계정(회원가입한 계정)이 있을 때 반환하는 에러이다.
+	errLDAPLocalAccount = errors.New("같은 ID의 로컬 계정이 있어 LDAP으로 로그인할 수 없습니다")
+)
+
+// ldapMaxMessageSize 는 LDAP 응답 값 하나의 최대 크기이다. 길이 필드가 잘못된 응답으로 큰 메모리를 잡지 않도록 제한한다.
+const ldapMaxMessageSize = 1 << 20
+
+// LDAPEntry 자료구조는 LDAP에서 검색한 항목 정보를 담는 자료구조이다.
+type LDAPEntry struct {
+	DN         string              // 항목의 DN
+	Attributes map[string][]string // 속성 이름(소문자)별 값 리스트
+}
+
+// Get 메소드는 속성의 첫번째 값을 반환한다.
+func (e LDAPEntry) Get(attr string) string {
+	values := e.Attributes[strings.ToLower(attr)]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// berElement 자료구조는 BER로 인코딩된 값 하나를 담는 자료구조이다.
+type berElement struct {
+	Tag      byte
+	Data     []byte
+	Children []berElement
+}
+
+// berEncodeFunc 함수는 태그와 내용을 BER로 인코딩하는 함수이다.
+func berEncodeFunc(tag byte, contents ...[]byte) []byte {
+	var body []byte
+	for _, c := range contents {
+		body = append(body, c...)
+	}
+	out := []byte{tag}
+	n := len(body)
+	switch {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n < 0x100:
+		out = append(out, 0x81, byte(n))
+	case n < 0x10000:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	default:
+		out = append(out, 0x83, byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, body...)
+}
+
+// berIntFunc 함수는 정수를 BER로 인코딩하는 함수이다. tag로 INTEGER(0x02), ENUMERATED(0x0a)를 받는다.
+func berIntFunc(tag byte, v int) []byte {
+	var b []byte
+	for {
+		b = append([]byte{byte(v)}, b...)
+		v >>= 8
+		if v == 0 && b[0] < 0x80 {
+			break
+		}
+	}
+	return berEncodeFunc(tag, b)
+}
+
+// berStringFunc 함수는 문자열을 BER OCTET STRING으로 인코딩하는 함수이다.
+func berStringFunc(tag byte, s string) []byte {
+	return berEncodeFunc(tag, []byte(s))
+}
+
+// berReadFunc 함수는 reader에서 BER 값 하나를 읽는 함수이다.
+func berReadFunc(r io.Reader) (berElement, error) {
+	head := make([]byte, 2)
+	_, err := io.ReadFull(r, head)
+	if err != nil {
+		return berElement{}, err
+	}
+	n := int(head[1])
+	if n >= 0x80 {
+		size := n & 0x7f
+		if size == 0 || size > 3 {
+			return berElement{}, errors.New("지원하지 않는 BER 길이입니다")
+		}
+		lb := make([]byte, size)
+		_, err = io.ReadFull(r, lb)
+		if err != nil {
+			return berElement{}, err
+		}
+		n = 0
+		for _, b := range lb {
+			n = n<<8 | int(b)
+		}
+		if n > ldapMaxMessageSize {
+			return berElement{}, errors.New("BER 길이가 너무 깁니다")
+		}
+	}
+	data := make([]byte, n)
+	_, err = io.ReadFull(r, data)
+	if err != nil {
+		return berElement{}, err
+	}
+	return berParseFunc(head[0], data)
+}
+
+// berParseFunc 함수는 태그와 내용으로 berElement를 만드는 함수이다. constructed 태그이면 내용을 하위 값들로 나눈다.
+func berParseFunc(tag byte, data []byte) (berElement, error) {
+	e := berElement{Tag: tag, Data: data}
+	if tag&0x20 == 0 {
+		return e, nil
+	}
+	r := bytes.NewReader(data)
+	for {
+		child, err := berReadFunc(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return e, err
+		}
+		e.Children = append(e.Children, child)
+	}
+	return e, nil
+}
+
+// berIntValueFunc 함수는 BER INTEGER, ENUMERATED 값을 정수로 반환하는 함수이다.
+func berIntValueFunc(e berElement) int {
+	v := 0
+	for i, b := range e.Data {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int(b)
+	}
+	return v
+}
+
+// ldapConn 자료구조는 LDAP 서버와의 연결을 담는 자료구조이다.
+type ldapConn struct {
+	conn  net.Conn
+	msgID int
+}
+
+// dialLDAPFunc 함수는 ldap:// 또는 ldaps:// 주소의 LDAP 서버에 연결하는 함수이다.
+func dialLDAPFunc(rawurl string) (*ldapConn, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		conn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, fmt.Errorf("LDAP 주소는 ldap:// 또는 ldaps://로 시작해야 합니다: %s", rawurl)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	return &ldapConn{conn: conn}, nil
+}
+
+// send 메소드는 LDAP 요청 메시지를 보내고 메시지 ID를 반환한다.
+func (c *ldapConn) send(op []byte) (int, error) {
+	c.msgID++
+	_, err := c.conn.Write(berEncodeFunc(0x30, berIntFunc(0x02, c.msgID), op))
+	return c.msgID, err
+}
+
+// receive 메소드는 LDAP 응답 메시지를 하나 읽어 protocolOp를 반환한다.
+func (c *ldapConn) receive(msgID int) (berElement, error) {
+	msg, err := berReadFunc(c.conn)
+	if err != nil {
+		return berElement{}, err
+	}
+	if len(msg.Children) < 2 || berIntValueFunc(msg.Children[0]) != msgID {
+		return berElement{}, errors.New("LDAP 응답 형식이 올바르지 않습니다")
+	}
+	return msg.Children[1], nil
+}
+
+// ldapResultFunc 함수는 LDAPResult의 결과 코드와 메시지를 반환하는 함수이다.
+func ldapResultFunc(op berElement) (int, string) {
+	if len(op.Children) < 3 {
+		return -1, "LDAP 응답 형식이 올바르지 않습니다"
+	}
+	return berIntValueFunc(op.Children[0]), string(op.Children[2].Data)
+}
+
+// Bind 메소드는 DN과 비밀번호로 Simple Bind를 하는 함수이다. 비밀번호가 틀리면 errLDAPInvalidCredentials를 반환한다.
+func (c *ldapConn) Bind(dn, password string) error {
+	id, err := c.send(berEncodeFunc(ldapTagBindRequest,
+		berIntFunc(0x02, 3),
+		berStringFunc(0x04, dn),
+		berStringFunc(0x80, password),
+	))
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+	if op.Tag != ldapTagBindResponse {
+		return errors.New("LDAP Bind 응답이 아닙니다")
+	}
+	code, message := ldapResultFunc(op)
+	switch code {
+	case 0:
+		return nil
+	case 49: // invalidCredentials
+		return errLDAPInvalidCredentials
+	}
+	return fmt.Errorf("LDAP Bind 실패(%d): %s", code, message)
+}
+
+// Search 메소드는 baseDN 아래에서 속성 값이 일치하는 항목을 검색하는 함수이다.
+func (c *ldapConn) Search(baseDN, attr, value string, attrs []string) ([]LDAPEntry, error) {
+	var attrList [][]byte
+	for _, a := range attrs {
+		attrList = append(attrList, berStringFunc(0x04, a))
+	}
+	id, err := c.send(berEncodeFunc(ldapTagSearchRequest,
+		berStringFunc(0x04, baseDN),
+		berIntFunc(0x0a, 2), // wholeSubtree
+		berIntFunc(0x0a, 0), // neverDerefAliases
+		berIntFunc(0x02, 2), // sizeLimit
+		berIntFunc(0x02, 10),
+		berEncodeFunc(0x01, []byte{0x00}),
+		berEncodeFunc(0xa3, berStringFunc(0x04, attr), berStringFunc(0x04, value)), // equalityMatch
+		berEncodeFunc(0x30, attrList...),
+	))
+	if err != nil {
+		return nil, err
+	}
+	var entries []LDAPEntry
+	for {
+		op, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Tag {
+		case ldapTagSearchResultItem:
+			if len(op.Children) < 2 {
+				return nil, errors.New("LDAP 검색 결과 형식이 올바르지 않습니다")
+			}
+			entry := LDAPEntry{DN: string(op.Children[0].Data), Attributes: make(map[string][]string)}
+			for _, a := range op.Children[1].Children {
+				if len(a.Children) < 2 {
+					continue
+				}
+				name := strings.ToLower(string(a.Children[0].Data))
+				for _, v := range a.Children[1].Children {
+					entry.Attributes[name] = append(entry.Attributes[name], string(v.Data))
+				}
+			}
+			entries = append(entries, entry)
+		case ldapTagSearchReference:
+			continue
+		case ldapTagSearchResultDone:
+			code, message := ldapResultFunc(op)
+			if code == 32 { // noSuchObject
+				return nil, nil
+			}
+			if code != 0 && code != 4 { // 4: sizeLimitExceeded
+				return nil, fmt.Errorf("LDAP 검색 실패(%d): %s", code, message)
+			}
+			return entries, nil
+		default:
+			return nil, errors.New("LDAP 검색 응답이 아닙니다")
+		}
+	}
+}
+
+// Close 메소드는 Unbind 요청을 보내고 연결을 닫는다.
+func (c *ldapConn) Close() error {
+	c.send(berEncodeFunc(ldapTagUnbindRequest))
+	return c.conn.Close()
+}
+
+// ldapAuthenticateFunc 함수는 Admin Setting의 LDAP 서버에서 사용자를 검색하고 비밀번호로 Bind해서 확인하는 함수이다.
+// 사용자가 없거나 비밀번호가 틀리면 errLDAPInvalidCredentials를 반환한다.
+func ldapAuthenticateFunc(a AdminSetting, id, password string) (LDAPEntry, error) {
+	if a.LDAPURL == "" {
+		return LDAPEntry{}, errLDAPDisabled
+	}
+	if id == "" || password == "" { // 비밀번호가 없으면 익명 Bind가 되므로 막는다.
+		return LDAPEntry{}, errLDAPInvalidCredentials
+	}
+	c, err := dialLDAPFunc(a.LDAPURL)
+	if err != nil {
+		return LDAPEntry{}, err
+	}
+	defer c.Close()
+
+	// 사용자 검색
+	if a.LDAPBindDN != "" {
+		bindPassword, err := decryptAES256Func(a.LDAPBindPassword)
+		if err != nil {
+			return LDAPEntry{}, err
+		}
+		err = c.Bind(a.LDAPBindDN, bindPassword)
+		if err != nil {
+			return LDAPEntry{}, err
+		}
+	}
+	attr := a.LDAPUserAttr
+	if attr == "" {
+		attr = "uid"
+	}
+	entries, err := c.Search(a.LDAPBaseDN, attr, id, []string{"cn", "displayName", "department", "memberOf"})
+	if err != nil {
+		return LDAPEntry{}, err
+	}
+	if len(entries) != 1 {
+		return LDAPEntry{}, errLDAPInvalidCredentials
+	}
+
+	// 사용자 비밀번호 확인
+	err = c.Bind(entries[0].DN, password)
+	if err != nil {
+		return LDAPEntry{}, err
+	}
+	return entries[0], nil
+}
+
+// ldapGroupRoleFunc 함수는 사용자가 속한 LDAP 그룹 중 액세스 레벨이 가장 높은 그룹의 역할을 반환하는 함수이다.
+// 그룹은 DN 전체 또는 CN으로 비교하고, 일치하는 그룹이 없으면 false를 반환한다.
+func ldapGroupRoleFunc(entry LDAPEntry, mappings []LDAPGroupRole) (LDAPGroupRole, bool) {
+	result := LDAPGroupRole{}
+	found := false
+	for _, group := range entry.Attributes["memberof"] {
+		cn := strings.SplitN(group, ",", 2)[0]
+		if strings.HasPrefix(strings.ToLower(cn), "cn=") {
+			cn = cn[3:]
+		}
+		for _, m := range mappings {
+			if !strings.EqualFold(m.Group, group) && !strings.EqualFold(m.Group, cn) {
+				continue
+			}
+			if !found || m.AccessLevel > result.AccessLevel {
+				result = m
+				found = true
+			}
+		}
+	}
+	return result, found
+}
+
+// applyLDAPEntryFunc 함수는 LDAP 항목의 이름, 부서와 그룹 역할을 사용자 정보에 반영하는 함수이다.
+func applyLDAPEntryFunc(u User, entry LDAPEntry, mappings []LDAPGroupRole) User {
+	if name := entry.Get("displayName"); name != "" {
+		u.Name = name
+	} else if name := entry.Get("cn"); name != "" {
+		u.Name = name
+	}
+	if u.Name == "" {
+		u.Name = u.ID
+	}
+	if team := entry.Get("department"); team != "" {
+		u.Team = team
+	}
+	if u.Team == "" {
+		u.Team = "LDAP"
+	}
+	// 일치하는 그룹이 없으면 관리자가 정한 역할과 액세스 레벨을 유지한다.
+	if m, ok := ldapGroupRoleFunc(entry, mappings); ok {
+		u.Role = m.Role
+		u.AccessLevel = m.AccessLevel
+	}
+	return u
+}
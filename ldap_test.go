@@ -0,0 +1,285 @@
+// 프로젝트 결산 프로그램
+//
+// Description : LDAP 로그인 테스트 스크립트
+
+package main
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+// ldapStubUser 자료구조는 테스트용 LDAP 서버의 사용자 정보이다.
+type ldapStubUser struct {
+	DN       string
+	Password string
+	Attrs    map[string][]string
+}
+
+// startLDAPStubFunc 함수는 Bind, Search, Unbind에 응답하는 테스트용 LDAP 서버를 띄우고 주소와 Listener를 반환하는 함수이다.
+func startLDAPStubFunc(t *testing.T, users map[string]ldapStubUser) (string, net.Listener) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveLDAPStubFunc(conn, users)
+		}
+	}()
+	return "ldap://" + ln.Addr().String(), ln
+}
+
+func serveLDAPStubFunc(conn net.Conn, users map[string]ldapStubUser) {
+	defer conn.Close()
+	result := func(tag byte, code int) []byte {
+		return berEncodeFunc(tag, berIntFunc(0x0a, code), berStringFunc(0x04, ""), berStringFunc(0x04, ""))
+	}
+	for {
+		msg, err := berReadFunc(conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id := berIntFunc(0x02, berIntValueFunc(msg.Children[0]))
+		op := msg.Children[1]
+		switch op.Tag {
+		case ldapTagBindRequest:
+			dn, pw := string(op.Children[1].Data), string(op.Children[2].Data)
+			code := 49
+			if dn == "" && pw == "" {
+				code = 0
+			}
+			for _, u := range users {
+				if u.DN == dn && u.Password == pw {
+					code = 0
+				}
+			}
+			conn.Write(berEncodeFunc(0x30, id, result(ldapTagBindResponse, code)))
+		case ldapTagSearchRequest:
+			value := string(op.Children[6].Children[1].Data)
+			if u, ok := users[value]; ok {
+				var attrs [][]byte
+				for name, values := range u.Attrs {
+					var vals [][]byte
+					for _, v := range values {
+						vals = append(vals, berStringFunc(0x04, v))
+					}
+					attrs = append(attrs, berEncodeFunc(0x30, berStringFunc(0x04, name), berEncodeFunc(0x31, vals...)))
+				}
+				entry := berEncodeFunc(ldapTagSearchResultItem, berStringFunc(0x04, u.DN), berEncodeFunc(0x30, attrs...))
+				conn.Write(berEncodeFunc(0x30, id, entry))
+			}
+			conn.Write(berEncodeFunc(0x30, id, result(ldapTagSearchResultDone, 0)))
+		default:
+			return
+		}
+	}
+}
+
+// startLDAPReplyStubFunc 함수는 요청을 하나 읽을 때마다 replies의 바이트를 그대로 보내는 테스트용 LDAP 서버를 띄우는 함수이다.
+// 응답을 모두 보내면 연결을 끊으므로 잘린 응답도 흉내낼 수 있다.
+func startLDAPReplyStubFunc(t *testing.T, replies [][]byte) (string, net.Listener) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, reply := range replies {
+			_, err := berReadFunc(conn)
+			if err != nil {
+				return
+			}
+			conn.Write(reply)
+		}
+	}()
+	return "ldap://" + ln.Addr().String(), ln
+}
+
+// ldapStubEntryFunc 함수는 메시지 ID가 id인 SearchResultEntry 메시지를 만드는 함수이다.
+func ldapStubEntryFunc(id int, dn string) []byte {
+	attr := berEncodeFunc(0x30, berStringFunc(0x04, "cn"), berEncodeFunc(0x31, berStringFunc(0x04, dn)))
+	return berEncodeFunc(0x30, berIntFunc(0x02, id), berEncodeFunc(ldapTagSearchResultItem, berStringFunc(0x04, dn), berEncodeFunc(0x30, attr)))
+}
+
+// ldapStubDoneFunc 함수는 메시지 ID가 id이고 결과 코드가 code인 SearchResultDone 메시지를 만드는 함수이다.
+func ldapStubDoneFunc(id int, code int) []byte {
+	return berEncodeFunc(0x30, berIntFunc(0x02, id), berEncodeFunc(ldapTagSearchResultDone, berIntFunc(0x0a, code), berStringFunc(0x04, ""), berStringFunc(0x04, "")))
+}
+
+// 잘리거나 길이 필드가 잘못된 BER 값을 에러로 처리하는지 테스트하기 위한 함수
+func Test_berRead(t *testing.T) {
+	oversized := append([]byte{0x04, 0x83, 0x10, 0x00, 0x01}, make([]byte, ldapMaxMessageSize+1)...)
+	cases := []struct {
+		in   []byte
+		want string
+		err  bool
+	}{
+		{in: []byte{0x04, 0x03, 'a', 'b', 'c'}, want: "abc"},
+		{in: []byte{0x04, 0x81, 0x03, 'a', 'b', 'c'}, want: "abc"},
+		{in: []byte{0x04, 0x05, 'a', 'b'}, err: true},                    // 내용이 잘린 값
+		{in: []byte{0x04, 0x82, 0x01}, err: true},                        // 길이 필드가 잘린 값
+		{in: []byte{0x04}, err: true},                                    // 태그만 있는 값
+		{in: []byte{0x04, 0x80, 'a', 0x00, 0x00}, err: true},             // 길이를 정하지 않은 값
+		{in: []byte{0x04, 0x84, 0x00, 0x00, 0x00, 0x01, 'a'}, err: true}, // 4바이트 길이 필드
+		{in: oversized, err: true},                                       // 최대 크기를 넘는 길이
+		{in: []byte{0x30, 0x03, 0x04, 0x05, 'a'}, err: true},             // 하위 값이 상위 값보다 긴 경우
+		{in: []byte{0x30, 0x01, 0x04}, err: true},                        // 하위 값의 길이 필드가 없는 경우
+	}
+	for _, c := range cases {
+		got, err := berReadFunc(bytes.NewReader(c.in))
+		if c.err {
+			if err == nil {
+				t.Fatalf("Test_berRead(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.in, "에러", got)
+			}
+			continue
+		}
+		if err != nil || string(got.Data) != c.want {
+			t.Fatalf("Test_berRead(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v, %v\n", c.in, c.want, got, err)
+		}
+	}
+}
+
+// 검색 응답의 참조, 여러 항목, 잘못된 응답을 처리하는지 테스트하기 위한 함수
+func Test_ldapSearchResponse(t *testing.T) {
+	reference := berEncodeFunc(0x30, berIntFunc(0x02, 1), berEncodeFunc(ldapTagSearchReference, berStringFunc(0x04, "ldap://other.rd101.co.kr/ou=people,dc=rd101,dc=co,dc=kr")))
+	kim := ldapStubEntryFunc(1, "uid=kim,ou=people,dc=rd101,dc=co,dc=kr")
+	lee := ldapStubEntryFunc(1, "uid=lee,ou=people,dc=rd101,dc=co,dc=kr")
+	join := func(msgs ...[]byte) []byte {
+		return bytes.Join(msgs, nil)
+	}
+	cases := []struct {
+		reply []byte
+		want  int
+		err   bool
+	}{
+		{reply: join(reference, kim, ldapStubDoneFunc(1, 0)), want: 1},                    // 참조는 건너뛴다.
+		{reply: join(kim, lee, ldapStubDoneFunc(1, 0)), want: 2},                          // 여러 항목
+		{reply: join(kim, lee, ldapStubDoneFunc(1, 4)), want: 2},                          // sizeLimitExceeded
+		{reply: ldapStubDoneFunc(1, 32), want: 0},                                         // noSuchObject
+		{reply: join(reference, ldapStubDoneFunc(1, 10)), err: true},                      // referral 결과
+		{reply: join(ldapStubEntryFunc(2, "uid=kim"), ldapStubDoneFunc(1, 0)), err: true}, // 다른 메시지 ID
+		{reply: kim[:len(kim)-3], err: true},                                              // 잘린 응답
+		{reply: join(kim), err: true},                                                     // SearchResultDone 없이 끊긴 연결
+		// 검색 응답이 아닌 메시지
+		{reply: berEncodeFunc(0x30, berIntFunc(0x02, 1), berEncodeFunc(ldapTagBindResponse, berIntFunc(0x0a, 0), berStringFunc(0x04, ""), berStringFunc(0x04, ""))), err: true},
+	}
+	for _, c := range cases {
+		addr, ln := startLDAPReplyStubFunc(t, [][]byte{c.reply})
+		conn, err := dialLDAPFunc(addr)
+		if err != nil {
+			ln.Close()
+			t.Fatal(err)
+		}
+		entries, err := conn.Search("ou=people,dc=rd101,dc=co,dc=kr", "uid", "kim", []string{"cn"})
+		conn.Close()
+		ln.Close()
+		if c.err {
+			if err == nil {
+				t.Fatalf("Test_ldapSearchResponse(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.reply, "에러", entries)
+			}
+			continue
+		}
+		if err != nil || len(entries) != c.want {
+			t.Fatalf("Test_ldapSearchResponse(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v, %v\n", c.reply, c.want, entries, err)
+		}
+	}
+
+	// 같은 ID로 여러 항목이 검색되면 로그인할 수 없다.
+	addr, ln := startLDAPReplyStubFunc(t, [][]byte{join(kim, lee, ldapStubDoneFunc(1, 0))})
+	defer ln.Close()
+	_, err := ldapAuthenticateFunc(AdminSetting{LDAPURL: addr, LDAPBaseDN: "ou=people,dc=rd101,dc=co,dc=kr"}, "kim", "secret")
+	if err != errLDAPInvalidCredentials {
+		t.Fatalf("Test_ldapSearchResponse(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "여러 항목", errLDAPInvalidCredentials, err)
+	}
+}
+
+// LDAP 서버에서 사용자를 검색하고 비밀번호를 확인하는지 테스트하기 위한 함수
+func Test_ldapAuthenticate(t *testing.T) {
+	addr, ln := startLDAPStubFunc(t, map[string]ldapStubUser{
+		"kim": {
+			DN:       "uid=kim,ou=people,dc=rd101,dc=co,dc=kr",
+			Password: "secret",
+			Attrs: map[string][]string{
+				"cn":         {"김민수"},
+				"department": {"FX"},
+				"memberOf":   {"cn=budget-pm,ou=groups,dc=rd101,dc=co,dc=kr", "cn=everyone,ou=groups,dc=rd101,dc=co,dc=kr"},
+			},
+		},
+	})
+	defer ln.Close()
+	a := AdminSetting{LDAPURL: addr, LDAPBaseDN: "ou=people,dc=rd101,dc=co,dc=kr"}
+	cases := []struct {
+		id   string
+		pw   string
+		want error
+	}{
+		{id: "kim", pw: "secret", want: nil},
+		{id: "kim", pw: "wrong", want: errLDAPInvalidCredentials},
+		{id: "kim", pw: "", want: errLDAPInvalidCredentials},
+		{id: "lee", pw: "secret", want: errLDAPInvalidCredentials},
+	}
+	for _, c := range cases {
+		entry, err := ldapAuthenticateFunc(a, c.id, c.pw)
+		if err != c.want {
+			t.Fatalf("Test_ldapAuthenticate(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.id, c.pw, c.want, err)
+		}
+		if err == nil && (entry.Get("cn") != "김민수" || len(entry.Attributes["memberof"]) != 2) {
+			t.Fatalf("Test_ldapAuthenticate(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.id, "김민수", entry)
+		}
+	}
+
+	if _, err := ldapAuthenticateFunc(AdminSetting{}, "kim", "secret"); err != errLDAPDisabled {
+		t.Fatalf("Test_ldapAuthenticate(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "빈 LDAP 설정", errLDAPDisabled, err)
+	}
+}
+
+// LDAP 그룹에 따라 역할과 액세스 레벨을 정하는지 테스트하기 위한 함수
+func Test_applyLDAPEntry(t *testing.T) {
+	entry := LDAPEntry{
+		DN: "uid=kim,ou=people,dc=rd101,dc=co,dc=kr",
+		Attributes: map[string][]string{
+			"cn":       {"김민수"},
+			"memberof": {"cn=budget-pm,ou=groups,dc=rd101,dc=co,dc=kr", "cn=Budget-Admin,ou=groups,dc=rd101,dc=co,dc=kr"},
+		},
+	}
+	mappings := []LDAPGroupRole{
+		{Group: "cn=budget-pm,ou=groups,dc=rd101,dc=co,dc=kr", Role: "PM", AccessLevel: ManagerLevel},
+		{Group: "budget-admin", Role: "", AccessLevel: AdminLevel},
+	}
+	cases := []struct {
+		user     User
+		mappings []LDAPGroupRole
+		want     User
+	}{
+		{user: User{ID: "kim"}, mappings: mappings, want: User{ID: "kim", Name: "김민수", Team: "LDAP", AccessLevel: AdminLevel}},
+		{user: User{ID: "kim", Team: "FX"}, mappings: mappings[:1], want: User{ID: "kim", Name: "김민수", Team: "FX", Role: "PM", AccessLevel: ManagerLevel}},
+		{user: User{ID: "kim", Role: "PD", AccessLevel: MemberLevel}, mappings: nil, want: User{ID: "kim", Name: "김민수", Team: "LDAP", Role: "PD", AccessLevel: MemberLevel}}, // 일치하는 그룹이 없으면 유지
+	}
+	for _, c := range cases {
+		got := applyLDAPEntryFunc(c.user, entry, c.mappings)
+		if got.Name != c.want.Name || got.Team != c.want.Team || got.Role != c.want.Role || got.AccessLevel != c.want.AccessLevel {
+			t.Fatalf("Test_applyLDAPEntry(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.user, c.mappings, c.want, got)
+		}
+	}
+}
+
+// LDAP 로그인으로 같은 ID의 로컬 계정을 바꾸지 않는지 테스트하기 위한 함수
+func Test_signinLDAPUserLocalAccount(t *testing.T) {
+	local := User{ID: "kim", AccessLevel: MemberLevel, Role: "PD"}
+	entry := LDAPEntry{Attributes: map[string][]string{"memberof": {"cn=budget-admin,ou=groups,dc=rd101,dc=co,dc=kr"}}}
+	mappings := []LDAPGroupRole{{Group: "budget-admin", AccessLevel: AdminLevel}}
+	got, err := signinLDAPUserFunc(nil, local, true, "kim", entry, mappings, "10.0.0.1") // DB에 접근하기 전에 거절해야 한다.
+	if err != errLDAPLocalAccount || got.AccessLevel != local.AccessLevel || got.Role != local.Role {
+		t.Fatalf("Test_signinLDAPUserLocalAccount(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v, %v\n", local, errLDAPLocalAccount, got, err)
+	}
+}
@@ -0,0 +1,150 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 오래된 로그 보관(압축 저장 후 삭제) 관련 스크립트
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// logRetentionCutoffFunc 함수는 보관 기간이 days일일 때 DB에서 지울 로그의 기준 시간을 반환하는 함수이다.
+// 한국 시간 기준으로 days일 전 0시 이전의 로그를 지운다. days가 0 이하이면 빈 시간을 반환한다.
+func logRetentionCutoffFunc(days int, now time.Time) time.Time {
+	if days <= 0 {
+		return time.Time{}
+	}
+	kst := time.FixedZone("KST", 9*60*60)
+	y, m, d := now.In(kst).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, kst).AddDate(0, 0, -days).UTC()
+}
+
+// logArchivePathFunc 함수는 cutoff 이전의 로그를 저장할 파일 경로를 반환하는 함수이다.
+// dir이 비어있으면 ~/.budget-logs 폴더를 사용하고, 파일 이름에는 기준 날짜와 보관한 시간을 넣는다.
+func logArchivePathFunc(dir string, cutoff, now time.Time) (string, error) {
+	if dir == "" {
+		u, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(u.HomeDir, ".budget-logs")
+	}
+	kst := time.FixedZone("KST", 9*60*60)
+	name := fmt.Sprintf("logs-until-%s-%s.jsonl.gz", cutoff.In(kst).Format("20060102"), now.In(kst).Format("20060102150405"))
+	return filepath.Join(dir, name), nil
+}
+
+// logArchive 자료구조는 로그를 한 줄에 하나씩 JSON으로 써서 gzip으로 압축하는 파일이다.
+// 임시 파일에 쓰다가 Close에서 성공적으로 닫혔을 때만 원래 이름으로 바꾸므로, 중간에 실패해도 반쯤 쓰인 파일이 남지 않는다.
+type logArchive struct {
+	path string
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+// newLogArchiveFunc 함수는 path에 로그를 저장할 logArchive를 만드는 함수이다. 폴더가 없으면 만든다.
+func newLogArchiveFunc(path string) (*logArchive, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	return &logArchive{
+		path: path,
+		file: file,
+		gz:   gz,
+		enc:  json.NewEncoder(gz),
+	}, nil
+}
+
+// Write 메소드는 로그 하나를 한 줄로 쓴다.
+func (a *logArchive) Write(l Log) error {
+	return a.enc.Encode(l)
+}
+
+// Close 메소드는 압축을 끝내고 임시 파일을 원래 이름으로 바꾼다.
+func (a *logArchive) Close() error {
+	err := a.gz.Close()
+	if err != nil {
+		a.Abort()
+		return err
+	}
+	err = a.file.Close()
+	if err != nil {
+		os.Remove(a.file.Name())
+		return err
+	}
+	return os.Rename(a.file.Name(), a.path)
+}
+
+// Abort 메소드는 쓰던 임시 파일을 지운다.
+func (a *logArchive) Abort() {
+	a.gz.Close()
+	a.file.Close()
+	os.Remove(a.file.Name())
+}
+
+// archiveOldLogsFunc 함수는 Admin Setting의 보관 기간이 지난 로그를 파일로 저장한 뒤 DB에서 삭제하는 함수이다.
+// 파일 저장에 실패하면 로그를 삭제하지 않는다. 저장한 파일 경로와 삭제한 로그 개수를 반환하고, 보관 기간이 0이거나 지울 로그가 없으면 빈 경로를 반환한다.
+func archiveOldLogsFunc(client *mongo.Client, actor string) (string, int64, error) {
+	adminSetting, err := getAdminSettingFunc(client)
+	if err != nil {
+		return "", 0, err
+	}
+	now := time.Now()
+	cutoff := logRetentionCutoffFunc(adminSetting.LogRetentionDays, now)
+	if cutoff.IsZero() {
+		return "", 0, nil
+	}
+	path, err := logArchivePathFunc(adminSetting.LogArchivePath, cutoff, now)
+	if err != nil {
+		return "", 0, err
+	}
+
+	archive, err := newLogArchiveFunc(path)
+	if err != nil {
+		return "", 0, err
+	}
+	num, err := forEachLogBeforeFunc(client, cutoff, archive.Write)
+	if err != nil {
+		archive.Abort()
+		return "", 0, err
+	}
+	if num == 0 {
+		archive.Abort()
+		return "", 0, nil
+	}
+	err = archive.Close()
+	if err != nil {
+		return "", 0, err
+	}
+
+	deleted, err := rmLogsBeforeFunc(client, cutoff)
+	if err != nil {
+		return path, 0, err
+	}
+	err = addLogsFunc(client, Log{
+		UserID:     actor,
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("%d일이 지난 로그 %d개를 %s 파일에 저장하고 삭제하였습니다.", adminSetting.LogRetentionDays, deleted, path),
+		Action:     LogActionDelete,
+		EntityType: LogEntityLog,
+	})
+	if err != nil {
+		return path, deleted, err
+	}
+	return path, deleted, nil
+}
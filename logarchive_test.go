@@ -0,0 +1,116 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 로그 보관 테스트 스크립트
+
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// 보관 기간에 따라 한국 시간 0시 기준으로 삭제 기준 시간을 구하는지 테스트하기 위한 함수
+func Test_logRetentionCutoff(t *testing.T) {
+	cases := []struct {
+		days int
+		now  time.Time
+		want time.Time
+	}{
+		{days: 0, now: time.Date(2020, 6, 1, 3, 0, 0, 0, time.UTC), want: time.Time{}},
+		{days: -1, now: time.Date(2020, 6, 1, 3, 0, 0, 0, time.UTC), want: time.Time{}},
+		{days: 90, now: time.Date(2020, 6, 1, 3, 0, 0, 0, time.UTC), want: time.Date(2020, 3, 2, 15, 0, 0, 0, time.UTC)},
+		{days: 1, now: time.Date(2020, 5, 31, 18, 0, 0, 0, time.UTC), want: time.Date(2020, 5, 30, 15, 0, 0, 0, time.UTC)}, // 한국 시간으로는 6월 1일 새벽 3시
+	}
+	for _, c := range cases {
+		got := logRetentionCutoffFunc(c.days, c.now)
+		if !got.Equal(c.want) {
+			t.Fatalf("Test_logRetentionCutoff(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.days, c.now, c.want, got)
+		}
+	}
+}
+
+// 로그를 압축 파일에 한 줄씩 저장하고, 실패하면 파일을 남기지 않는지 테스트하기 위한 함수
+func Test_logArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "budget-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cutoff := time.Date(2020, 3, 2, 15, 0, 0, 0, time.UTC)
+	now := time.Date(2020, 6, 1, 3, 0, 0, 0, time.UTC)
+	path, err := logArchivePathFunc(filepath.Join(dir, "archive"), cutoff, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "archive", "logs-until-20200303-20200601120000.jsonl.gz")
+	if path != want {
+		t.Fatalf("Test_logArchive(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", cutoff, want, path)
+	}
+
+	logs := []Log{
+		{UserID: "kim", CreatedAt: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Content: "로그인하였습니다."},
+		{UserID: "lee", CreatedAt: time.Date(2020, 2, 3, 0, 0, 0, 0, time.UTC), Action: LogActionUpdate, EntityType: LogEntityProject, EntityID: "KIJ",
+			Changes: []LogChange{{Field: "Name", Before: "킹덤", After: "킹덤2"}}},
+	}
+	archive, err := newLogArchiveFunc(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range logs {
+		err = archive.Write(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = archive.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Log
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var l Log
+		err = json.Unmarshal(scanner.Bytes(), &l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, l)
+	}
+	if len(got) != len(logs) || got[1].EntityID != "KIJ" || got[1].Changes[0].After != "킹덤2" || !got[0].CreatedAt.Equal(logs[0].CreatedAt) {
+		t.Fatalf("Test_logArchive(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", path, logs, got)
+	}
+
+	// 중간에 그만두면 임시 파일도 남지 않아야 한다.
+	aborted := filepath.Join(dir, "aborted.jsonl.gz")
+	archive, err = newLogArchiveFunc(aborted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = archive.Write(logs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive.Abort()
+	for _, p := range []string{aborted, aborted + ".tmp"} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Fatalf("Test_logArchive(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", p, "파일 없음", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 로그인 보호(시도 제한, 계정 잠금, TOTP) 관련 스크립트
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	LoginFailLimit        = 5                   // 계정을 잠그는 연속 로그인 실패 횟수
+	LoginLockDuration     = 15 * time.Minute    // 계정을 잠그는 시간
+	LoginIPFailLimit      = 20                  // IP별로 LoginIPFailWindow 동안 허용하는 로그인 실패 횟수
+	LoginIPFailWindow     = 15 * time.Minute    // IP별 로그인 실패 횟수를 세는 시간
+	LoginHistoryRetention = 90 * 24 * time.Hour // 로그인 기록을 보관하는 기간
+	TOTPPeriod            = 30                  // TOTP 코드가 바뀌는 시간(초)
+	TOTPDigits            = 6                   // TOTP 코드 자리수
+	TOTPIssuer            = "budget"            // OTP 앱에 표시되는 서비스 이름
+)
+
+// clientIPFunc 함수는 요청을 보낸 IP를 반환하는 함수이다.
+func clientIPFunc(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// isLoginLockedFunc 함수는 사용자 계정이 잠겨있는지 확인하는 함수이다.
+func isLoginLockedFunc(u User, now time.Time) bool {
+	return now.Before(u.LockedUntil)
+}
+
+// recordLoginFailureFunc 함수는 사용자의 로그인 실패 횟수를 늘리고, LoginFailLimit번 연속으로 실패하면 계정을 잠그는 함수이다.
+// 계정을 잠그면 실패 횟수는 0으로 돌아가고 두 번째 반환값이 true가 된다.
+func recordLoginFailureFunc(u User, now time.Time) (User, bool) {
+	u.FailedLogins++
+	if u.FailedLogins < LoginFailLimit {
+		return u, false
+	}
+	u.FailedLogins = 0
+	u.LockedUntil = now.Add(LoginLockDuration)
+	return u, true
+}
+
+// newTOTPSecretFunc 함수는 OTP 앱에 등록할 base32 형식의 TOTP 키를 만드는 함수이다.
+func newTOTPSecretFunc() (string, error) {
+	b := make([]byte, 20)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
+
+// totpURIFunc 함수는 OTP 앱에 직접 입력할 수 있는 otpauth URI를 만드는 함수이다.
+func totpURIFunc(userID, secret string) string {
+	label := url.PathEscape(TOTPIssuer + ":" + userID)
+	return fmt.Sprintf("otpauth://totp/%s?secret=%s&issuer=%s", label, secret, url.QueryEscape(TOTPIssuer))
+}
+
+// totpCodeFunc 함수는 RFC 6238 방식으로 t 시간의 TOTP 코드를 만드는 함수이다.
+func totpCodeFunc(secret string, t time.Time) (string, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(strings.TrimRight(strings.Replace(secret, " ", "", -1), "=")))
+	if err != nil {
+		return "", err
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/TOTPPeriod))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, value%mod), nil
+}
+
+// checkTOTPFunc 함수는 입력한 TOTP 코드가 맞는지 확인하는 함수이다. 시계 차이를 고려해서 앞뒤 한 구간의 코드도 허용한다.
+func checkTOTPFunc(secret, code string, now time.Time) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return false
+	}
+	for _, step := range []int{0, -1, 1} {
+		want, err := totpCodeFunc(secret, now.Add(time.Duration(step*TOTPPeriod)*time.Second))
+		if err != nil {
+			return false
+		}
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return true
+		}
+	}
+	return false
+}
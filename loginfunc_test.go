@@ -0,0 +1,81 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 로그인 보호 관련 테스트 스크립트
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// 연속으로 로그인에 실패하면 계정을 잠그는지 테스트하기 위한 함수
+func Test_recordLoginFailure(t *testing.T) {
+	now := time.Date(2020, 9, 1, 10, 0, 0, 0, time.Local)
+	u := User{ID: "kim"}
+	for i := 1; i < LoginFailLimit; i++ {
+		var locked bool
+		u, locked = recordLoginFailureFunc(u, now)
+		if locked || u.FailedLogins != i || isLoginLockedFunc(u, now) {
+			t.Fatalf("Test_recordLoginFailure(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", i, "잠기지 않음", u)
+		}
+	}
+	u, locked := recordLoginFailureFunc(u, now)
+	if !locked || u.FailedLogins != 0 || !u.LockedUntil.Equal(now.Add(LoginLockDuration)) {
+		t.Fatalf("Test_recordLoginFailure(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", LoginFailLimit, now.Add(LoginLockDuration), u)
+	}
+	cases := []struct {
+		now  time.Time
+		want bool
+	}{
+		{now: now, want: true},
+		{now: now.Add(LoginLockDuration - time.Second), want: true},
+		{now: now.Add(LoginLockDuration), want: false},
+	}
+	for _, c := range cases {
+		got := isLoginLockedFunc(u, c.now)
+		if got != c.want {
+			t.Fatalf("Test_recordLoginFailure(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.now, c.want, got)
+		}
+	}
+}
+
+// RFC 6238 테스트 벡터로 TOTP 코드를 확인하는 함수
+func Test_checkTOTP(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, c := range cases {
+		got, err := totpCodeFunc(secret, time.Unix(c.unix, 0))
+		if err != nil || got != c.want {
+			t.Fatalf("Test_checkTOTP(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v, %v\n", c.unix, c.want, got, err)
+		}
+	}
+
+	now := time.Unix(1111111109, 0)
+	checks := []struct {
+		code string
+		now  time.Time
+		want bool
+	}{
+		{code: "081804", now: now, want: true},
+		{code: " 081804 ", now: now, want: true},
+		{code: "081804", now: now.Add(30 * time.Second), want: true}, // 한 구간 전 코드는 허용
+		{code: "081804", now: now.Add(90 * time.Second), want: false},
+		{code: "000000", now: now, want: false},
+		{code: "", now: now, want: false},
+	}
+	for _, c := range checks {
+		got := checkTOTPFunc(secret, c.code, c.now)
+		if got != c.want {
+			t.Fatalf("Test_checkTOTP(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.code, c.now, c.want, got)
+		}
+	}
+}
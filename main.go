@@ -44,7 +44,14 @@ var (
 	// 프로젝트 관련 플래그
 	flagUpdateProject = flag.Bool("update-project", false, "update project(new struct)")
 
-	flagGenKey = flag.Bool("gen-key", false, "generate AES 256 key file mode")
+	// 클라이언트 관련 플래그
+	flagUpdateClient = flag.Bool("update-client", false, "link producer and director of projects to clients")
+
+	// 로그 관련 플래그
+	flagArchiveLogs = flag.Bool("archive-logs", false, "archive logs older than retention days of admin setting to a gzip file and delete them")
+
+	flagGenKey  = flag.Bool("gen-key", false, "generate AES 256 key file mode")
+	flagGenCert = flag.Bool("gen-cert", false, "generate self-signed TLS certificate mode")
 
 	flagID             = flag.String("id", "", "shotgun id / user id / project id")
 	flagName           = flag.String("name", "", "user name / artitst name / project name / vendor name")
@@ -83,6 +90,16 @@ var (
 	flagCookieAge  = flag.Int("cookieage", 4, "cookie age (hour)")             // MPAA 기준 4시간이다.
 	flagDBID       = flag.String("dbid", "", "mongoDB Authorization ID")       // mongoDB 권한 아이디
 	flagDBPW       = flag.String("dbpw", "", "mongoDB Authorization Password") // mongoDB 권한 비밀번호
+
+	flagCookieSecure   = flag.Bool("cookiesecure", false, "set Secure attribute on cookies. use with HTTPS") // HTTPS로 서비스할 때 켠다.
+	flagCookieSameSite = flag.String("cookiesamesite", "lax", "SameSite attribute of cookies(lax/strict/none)")
+
+	// HTTPS 관련 플래그
+	flagHTTPSPort  = flag.String("https", "", "HTTPS Service Port Number. -http port redirects to HTTPS")
+	flagCertFile   = flag.String("certfile", "", "TLS certificate file path(default ~/.budget-tls/<hostname>.crt)")
+	flagKeyFile    = flag.String("keyfile", "", "TLS private key file path(default ~/.budget-tls/<hostname>.key)")
+	flagCertHosts  = flag.String("certhosts", "", "comma separated host names and IPs of self-signed certificate(default hostname, service IP, localhost)")
+	flagHSTSMaxAge = flag.Int("hstsmaxage", 31536000, "max-age of Strict-Transport-Security header(second). 0 disables HSTS") // 기본값은 1년이다.
 )
 
 func main() {
@@ -96,6 +113,12 @@ func main() {
 		log.Fatal()
 	}
 
+	// 웹 페이지와 알림 메일에서 같이 사용하는 템플릿을 시작할 때 한 번만 로딩한다.
+	TEMPLATES, err = loadTemplatesFunc()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	fmt.Print("Enter DB AuthUsername: ")
@@ -242,6 +265,18 @@ func main() {
 			log.Fatal(errors.New("root 권한이 필요합니다"))
 		}
 		updateProjectCmdFunc()
+	} else if *flagUpdateClient {
+		// root 계정인지 확인
+		if user.Username != "root" {
+			log.Fatal(errors.New("root 권한이 필요합니다"))
+		}
+		updateClientCmdFunc()
+	} else if *flagArchiveLogs {
+		// root 계정인지 확인
+		if user.Username != "root" {
+			log.Fatal(errors.New("root 권한이 필요합니다"))
+		}
+		archiveLogsCmdFunc()
 	} else if *flagGenKey {
 		// root 계정인지 확인
 		if user.Username != "root" {
@@ -262,15 +297,68 @@ func main() {
 			log.Fatal(err)
 		}
 		fmt.Printf("Generated successfully\n")
-	} else if *flagHTTPPort != "" {
+	} else if *flagGenCert {
+		// root 계정인지 확인
+		if user.Username != "root" {
+			log.Fatal(errors.New("root 권한이 필요합니다"))
+		}
+
+		certPath, keyPath, err := tlsFilePathFunc()
+		if err != nil {
+			log.Fatal(err)
+		}
+		// 인증서 파일이 존재하는지 확인
+		existed, err := checkFileExistsFunc(certPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if existed {
+			log.Fatal(errors.New("이미 인증서 파일이 존재합니다"))
+		}
+		hosts, err := certHostsFunc()
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = genSelfSignedCertFunc(hosts, certPath, keyPath, time.Now())
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Generated successfully: %s, %s (%s)\n", certPath, keyPath, strings.Join(hosts, ", "))
+	} else if *flagHTTPPort != "" || *flagHTTPSPort != "" {
+		// HTTPS로 서비스하면 쿠키가 HTTP로 전송되지 않도록 Secure 속성을 넣는다.
+		if *flagHTTPSPort != "" {
+			*flagCookieSecure = true
+			certPath, keyPath, err := tlsFilePathFunc()
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = checkTLSFilesFunc(certPath, keyPath)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		err := checkCookieFlagsFunc()
+		if err != nil {
+			log.Fatal(err)
+		}
 		ip, err := serviceIPFunc()
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		// 로그 페이지의 정렬, 검색에 사용하는 인덱스를 만든다.
+		err = ensureLogIndexesFunc(client)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		serviceFunc() // 서비스 실행
 
-		fmt.Printf("Service start: http://%s\n", ip)
+		if *flagHTTPSPort != "" {
+			fmt.Printf("Service start: https://%s\n", ip)
+		} else {
+			fmt.Printf("Service start: http://%s\n", ip)
+		}
 		webServerFunc()
 	} else {
 		flag.PrintDefaults()
@@ -12,42 +12,22 @@ import (
 	"strings"
 	"time"
 
-	jwt "github.com/dgrijalva/jwt-go"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // getTokenFromHeaderFunc 함수는 쿠키에서 Token 값을 반환한다.
+// 서버 키로 서명된 토큰이어야 하고, DB에 세션이 남아있어야 한다. 세션이 만들어진 뒤 사용자의 액세스 레벨이 바뀌었다면 세션을 삭제하고 다시 로그인하게 한다.
 func getTokenFromHeaderFunc(w http.ResponseWriter, r *http.Request) (Token, error) {
-	// Token을 열기위해서 헤더 쿠키에서 필요한 정보를 불러온다.
-	sessionToken := ""
-	sessionSignkey := ""
-	for _, cookie := range r.Cookies() {
-		if cookie.Name == "SessionToken" {
-			sessionToken = cookie.Value
-			continue
-		}
-		if cookie.Name == "SessionSignKey" {
-			sessionSignkey = cookie.Value
-			continue
-		}
+	key, err := sessionSignKeyFunc()
+	if err != nil {
+		return Token{}, err
 	}
-	tk := Token{}
-	// Signkey로 Token 정보를 연다.
-	token, err := jwt.ParseWithClaims(sessionToken, &tk, func(token *jwt.Token) (interface{}, error) {
-		return []byte(sessionSignkey), nil
-	})
+	tk, err := parseSessionTokenFunc(getSessionTokenFromCookieFunc(r), key)
 	if err != nil {
 		return tk, err
 	}
-	if !token.Valid {
-		return tk, errors.New("Token key is not valid")
-	}
-	if tk.ToolName != "budget" {
-		return tk, errors.New("Token key is not for budget")
-	}
 
 	// mongoDB client 연결
 	credential := options.Credential{
@@ -70,37 +50,28 @@ func getTokenFromHeaderFunc(w http.ResponseWriter, r *http.Request) (Token, erro
 		return tk, err
 	}
 
-	// DB에 저장된 토큰키와 일치하는지 확인
-	user, err := getUserFunc(client, tk.ID)
+	// DB에 세션이 남아있는지 확인
+	session, err := getSessionFunc(client, tk.Id)
 	if err != nil {
 		return tk, err
 	}
-	if user.Token != token.Raw {
-		return tk, errors.New("토큰키가 일치하지 않습니다")
-	}
-	return tk, nil
-}
-
-// getAccessLevelFromHeaderFunc 함수는 rest api 사용시 토큰을 확인하고 access level을 반환하는 함수이다.
-func getAccessLevelFromHeaderFunc(r *http.Request, client *mongo.Client) (AccessLevel, error) {
-	// header에서 token을 가져온다.
-	auth := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
-	if len(auth) != 2 || auth[0] != "Basic" {
-		return GuestLevel, errors.New("Authorization failed")
+	if session.UserID != tk.ID {
+		return tk, errors.New("세션의 사용자가 일치하지 않습니다")
 	}
-	token := auth[1]
-
-	// DB 검색
-	collection := client.Database(*flagDBName).Collection("users")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
 
-	user := User{}
-	err := collection.FindOne(ctx, bson.M{"token": token}).Decode(&user)
+	// 액세스 레벨이 바뀌었다면 세션을 삭제한다.
+	user, err := getUserFunc(client, tk.ID)
 	if err != nil {
-		return GuestLevel, err
+		return tk, err
 	}
-	return user.AccessLevel, nil
+	if user.AccessLevel != tk.AccessLevel {
+		err = rmSessionFunc(client, session.ID)
+		if err != nil {
+			return tk, err
+		}
+		return tk, errors.New("액세스 레벨이 바뀌어 다시 로그인해야 합니다")
+	}
+	return tk, nil
 }
 
 // GetObjectIDfromRequestHeader 미들웨어는 리퀘스트헤더에서 ObjectID를 가지고 온다.
@@ -129,3 +100,132 @@ func GetObjectIDfromRequestHeader(r *http.Request) (string, error) {
 	}
 	return objectID, nil
 }
+
+// contextKey 는 미들웨어가 리퀘스트 context에 값을 넣을 때 사용하는 키 타입이다.
+type contextKey string
+
+// userContextKey 는 permissionMiddlewareFunc에서 확인한 사용자를 넣는 context 키이다.
+const userContextKey contextKey = "user"
+
+// requestUserFunc 함수는 permissionMiddlewareFunc에서 확인한 사용자를 리퀘스트 context에서 가져오는 함수이다.
+func requestUserFunc(r *http.Request) (User, bool) {
+	u, ok := r.Context().Value(userContextKey).(User)
+	return u, ok
+}
+
+// permissionMiddlewareFunc 미들웨어는 URL에 필요한 권한과 프로젝트 허용 여부를 확인한다. 각 핸들러는 액세스 레벨을 따로 확인하지 않는다.
+// 확인한 사용자는 리퀘스트 context에 넣어 핸들러에 넘기므로, restAPI 토큰은 한 번만 확인한다.
+// 권한이 없으면 웹 페이지는 invalidaccess 페이지로 리다이렉트하고, restAPI는 401 에러를 반환한다.
+func permissionMiddlewareFunc(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		permission, needPermission := routePermissions[r.URL.Path]
+		projectKey, isProjectRoute := projectRoutes[r.URL.Path]
+		if !needPermission && !isProjectRoute && !allProjectRoutes[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		isAPI := strings.HasPrefix(r.URL.Path, "/api/")
+
+		// mongoDB client 연결
+		credential := options.Credential{
+			Username: *flagDBID,
+			Password: *flagDBPW,
+		}
+		client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = client.Connect(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer client.Disconnect(ctx)
+		err = client.Ping(ctx, readpref.Primary())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// 로그인한 사용자 또는 restAPI 토큰의 사용자를 가져온다.
+		user, err := getUserFromRequestFunc(w, r, client, isAPI)
+		if err != nil {
+			if isAPI {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			http.Redirect(w, r, "/signin", http.StatusSeeOther)
+			return
+		}
+
+		allowed := true
+		if needPermission {
+			allowed, err = checkPermissionFunc(client, user, permission)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if isProjectRoute && !hasRequestProjectGrantFunc(user, r, projectKey) {
+			allowed = false
+		}
+		if allProjectRoutes[r.URL.Path] && isProjectScopedFunc(user) {
+			allowed = false
+		}
+		if !allowed {
+			if isAPI {
+				http.Error(w, "권한이 없는 계정입니다", http.StatusUnauthorized)
+				return
+			}
+			http.Redirect(w, r, "/invalidaccess", http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userContextKey, user)))
+	})
+}
+
+// hasRequestProjectGrantFunc 함수는 리퀘스트의 key 폼 값에 들어있는 프로젝트를 사용자가 모두 볼 수 있는지 확인하는 함수이다.
+// 핸들러마다 URL 쿼리나 폼에서 값을 가져오므로 같은 키로 들어온 값을 모두 확인하고, 허용된 프로젝트가 정해진 사용자는 프로젝트 ID가 없으면 열 수 없다.
+func hasRequestProjectGrantFunc(u User, r *http.Request, key string) bool {
+	if !isProjectScopedFunc(u) {
+		return true
+	}
+	r.FormValue(key) // r.Form에 URL 쿼리와 폼 값을 모두 넣는다.
+	ids := r.Form[key]
+	if len(ids) == 0 {
+		return false
+	}
+	for _, id := range ids {
+		if !hasProjectGrantFunc(u, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// getUserFromRequestFunc 함수는 restAPI는 Authorization 헤더의 토큰으로, 웹 페이지는 세션 쿠키로 사용자 정보를 가져오는 함수이다.
+// 웹 페이지의 스크립트는 Authorization 헤더 없이 세션 쿠키로 restAPI를 사용하고, 이때는 쿠키로 인증하므로 CSRF 토큰 헤더도 확인한다.
+// permissionMiddlewareFunc에서 이미 확인한 사용자가 리퀘스트 context에 있으면 다시 확인하지 않고 그 사용자를 반환한다.
+func getUserFromRequestFunc(w http.ResponseWriter, r *http.Request, client *mongo.Client, isAPI bool) (User, error) {
+	if u, ok := requestUserFunc(r); ok {
+		return u, nil
+	}
+	if isAPI && r.Header.Get("Authorization") == "" {
+		expected, ok := expectedCSRFTokenFunc(r)
+		if !ok || r.Header.Get(CSRFHeaderName) == "" || !checkCSRFTokenFunc(r, expected) {
+			return User{}, errors.New("Authorization failed")
+		}
+		isAPI = false
+	}
+	if !isAPI {
+		token, err := getTokenFromHeaderFunc(w, r)
+		if err != nil {
+			return User{}, err
+		}
+		return getUserFunc(client, token.ID)
+	}
+	return getUserFromAPITokenFunc(r, client)
+}
@@ -0,0 +1,120 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 미들웨어 테스트 스크립트
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// routeRecorder 자료구조는 registerRoutesFunc에서 등록한 URL을 모으는 테스트용 mux이다.
+type routeRecorder struct {
+	patterns []string
+}
+
+func (m *routeRecorder) Handle(pattern string, handler http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+}
+
+func (m *routeRecorder) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.patterns = append(m.patterns, pattern)
+}
+
+// 웹서버에 등록된 모든 URL이 프로젝트 허용 여부 목록 중 하나에만 들어있는지 테스트하기 위한 함수
+func Test_routeClassification(t *testing.T) {
+	mux := &routeRecorder{}
+	registerRoutesFunc(mux)
+	registered := make(map[string]bool)
+	for _, pattern := range mux.patterns {
+		registered[pattern] = true
+		num := 0
+		if _, ok := projectRoutes[pattern]; ok {
+			num++
+		}
+		if projectHandlerRoutes[pattern] {
+			num++
+		}
+		if allProjectRoutes[pattern] {
+			num++
+		}
+		if nonProjectRoutes[pattern] {
+			num++
+		}
+		if num != 1 {
+			t.Fatalf("Test_routeClassification(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", pattern, 1, num)
+		}
+
+		// 권한 없이 열 수 있는 URL이 아니면 권한이 정해져 있어야 한다.
+		permission, ok := routePermissions[pattern]
+		if ok == openRoutes[pattern] {
+			t.Fatalf("Test_routeClassification(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", pattern, "권한 또는 openRoutes 중 하나", permission)
+		}
+		if ok && !isPermissionFunc(permission) {
+			t.Fatalf("Test_routeClassification(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", pattern, "Permissions에 있는 권한", permission)
+		}
+	}
+
+	// 목록에만 있고 등록되지 않은 URL이 없는지 확인한다.
+	var classified []string
+	for pattern := range projectRoutes {
+		classified = append(classified, pattern)
+	}
+	for _, routes := range []map[string]bool{projectHandlerRoutes, allProjectRoutes, nonProjectRoutes, csrfPreviewRoutes, csrfPostRoutes, openRoutes} {
+		for pattern := range routes {
+			classified = append(classified, pattern)
+		}
+	}
+	for pattern := range routePermissions {
+		classified = append(classified, pattern)
+	}
+	for _, pattern := range classified {
+		if !registered[pattern] {
+			t.Fatalf("Test_routeClassification(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", pattern, "등록된 URL", "등록되지 않은 URL")
+		}
+	}
+}
+
+// isPermissionFunc 함수는 Permissions에 있는 권한 ID인지 확인하는 함수이다.
+func isPermissionFunc(id string) bool {
+	for _, p := range Permissions {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// 리퀘스트의 프로젝트 ID를 모두 확인하는지 테스트하기 위한 함수
+func Test_hasRequestProjectGrant(t *testing.T) {
+	scoped := User{AccessLevel: ManagerLevel, Projects: []string{"ABC"}}
+	cases := []struct {
+		user   User
+		method string
+		target string
+		body   string
+		want   bool
+	}{
+		{user: scoped, method: http.MethodGet, target: "/detail-sm?id=ABC", want: true},
+		{user: scoped, method: http.MethodGet, target: "/detail-sm?id=DEF", want: false},
+		{user: scoped, method: http.MethodGet, target: "/detail-sm", want: false},
+		{user: scoped, method: http.MethodGet, target: "/detail-sm?id=ABC&id=DEF", want: false},
+		{user: scoped, method: http.MethodPost, target: "/uploadshot-submit?id=DEF", body: "id=ABC", want: false}, // 핸들러는 URL 쿼리의 id를 사용한다.
+		{user: scoped, method: http.MethodPost, target: "/bgapproval-submit", body: "id=ABC", want: true},
+		{user: User{AccessLevel: ManagerLevel}, method: http.MethodGet, target: "/detail-sm?id=DEF", want: true},
+		{user: User{AccessLevel: AdminLevel, Projects: []string{"ABC"}}, method: http.MethodGet, target: "/detail-sm", want: true},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+		if c.body != "" {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		got := hasRequestProjectGrantFunc(c.user, r, projectRoutes[r.URL.Path])
+		if got != c.want {
+			t.Fatalf("Test_hasRequestProjectGrant(): 입력 값: %v %v %v, 원하는 값: %v, 얻은 값: %v\n", c.method, c.target, c.body, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 알림(메일) 전송 관련 스크립트
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Notifier 인터페이스는 알림을 전송하는 방식이 구현해야 하는 인터페이스이다.
+type Notifier interface {
+	SendFunc(n Notification) error
+}
+
+// SMTPNotifier 자료구조는 SMTP 서버를 통해 메일로 알림을 전송한다.
+type SMTPNotifier struct {
+	Host     string // SMTP 서버 주소
+	Port     string // SMTP 서버 포트
+	TLS      string // 암호화 방식 ex) none, starttls, tls
+	Username string // 인증 계정, 비어있으면 인증하지 않는다.
+	Password string // 인증 비밀번호
+	From     string // 보내는 사람
+}
+
+// FileNotifier 자료구조는 알림을 파일에 기록한다. Writer가 있으면 Path 대신 Writer에 기록한다.
+type FileNotifier struct {
+	Path   string    // 알림을 기록할 파일 경로
+	Writer io.Writer // 알림을 기록할 Writer ex) os.Stdout
+}
+
+// newNotifierFunc 함수는 admin setting의 알림 설정으로 Notifier를 만드는 함수이다.
+func newNotifierFunc(a AdminSetting) (Notifier, error) {
+	switch a.NotifierType {
+	case NotifierTypeFile:
+		return FileNotifier{Path: a.NotifierFilePath}, nil
+	case NotifierTypeStdout:
+		return FileNotifier{Writer: os.Stdout}, nil
+	}
+
+	// 설정이 비어있으면 기존에 사용하던 그룹웨어 메일 서버를 사용한다.
+	s := SMTPNotifier{
+		Host:     a.SMTPHost,
+		Port:     a.SMTPPort,
+		TLS:      a.SMTPTLS,
+		Username: a.SMTPUsername,
+		From:     a.SMTPFrom,
+	}
+	if s.Host == "" {
+		s.Host = "gw.rd101.co.kr"
+	}
+	if s.Port == "" {
+		s.Port = "25"
+	}
+	if s.TLS == "" {
+		s.TLS = SMTPTLSNone
+	}
+	if s.From == "" {
+		s.From = "BUDGET"
+	}
+	if a.SMTPPassword != "" {
+		password, err := decryptAES256Func(a.SMTPPassword)
+		if err != nil {
+			return nil, err
+		}
+		s.Password = password
+	}
+	return s, nil
+}
+
+// SendFunc 메소드는 SMTP 서버로 메일을 전송하는 함수이다.
+func (s SMTPNotifier) SendFunc(n Notification) error {
+	addr := net.JoinHostPort(s.Host, s.Port)
+	tlsConfig := &tls.Config{ServerName: s.Host}
+
+	var c *smtp.Client
+	if s.TLS == SMTPTLSImplicit {
+		conn, err := tls.Dial("tcp", addr, tlsConfig)
+		if err != nil {
+			return err
+		}
+		c, err = smtp.NewClient(conn, s.Host)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+	} else {
+		var err error
+		c, err = smtp.Dial(addr)
+		if err != nil {
+			return err
+		}
+	}
+	defer c.Close()
+
+	if s.TLS == SMTPTLSStartTLS {
+		err := c.StartTLS(tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.Mail(s.From)
+	if err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		err = c.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+	wc, err := c.Data()
+	if err != nil {
+		return err
+	}
+	_, err = wc.Write(buildMailMessageFunc(s.From, n))
+	if err != nil {
+		wc.Close()
+		return err
+	}
+	err = wc.Close()
+	if err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// SendFunc 메소드는 알림을 파일 또는 Writer에 기록하는 함수이다.
+func (f FileNotifier) SendFunc(n Notification) error {
+	w := f.Writer
+	if w == nil {
+		file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	_, err := fmt.Fprintf(w, "----- %s\nTo: %s\nSubject: %s\n\n%s\n",
+		time.Now().Format(time.RFC3339), strings.Join(n.To, ", "), n.Subject, n.Body)
+	return err
+}
+
+// buildMailMessageFunc 함수는 알림으로 MIME 형식의 메일 메시지를 만드는 함수이다.
+func buildMailMessageFunc(from string, n Notification) []byte {
+	var msg bytes.Buffer
+	msg.WriteString("From: " + from + "\r\n")
+	msg.WriteString("To: " + strings.Join(n.To, ", ") + "\r\n")
+	msg.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", n.Subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n")
+	msg.WriteString("\r\n")
+
+	// 본문은 base64로 인코딩하고 76자마다 줄을 바꾼다.
+	body := base64.StdEncoding.EncodeToString([]byte(n.Body))
+	for len(body) > 76 {
+		msg.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	msg.WriteString(body + "\r\n")
+	return msg.Bytes()
+}
+
+// addNotificationByTemplateFunc 함수는 템플릿으로 메일 알림 내용을 만들어 outbox에 추가하는 함수이다.
+func addNotificationByTemplateFunc(client *mongo.Client, event string, to []string, subject string, templateName string, rcp interface{}) error {
+	// 템플릿은 시작할 때 로딩한 TEMPLATES를 사용한다.
+	var body bytes.Buffer
+	err := TEMPLATES.ExecuteTemplate(&body, templateName, rcp)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	n := Notification{
+		Channel:     NotificationChannelMail,
+		Event:       event,
+		To:          to,
+		Subject:     subject,
+		Body:        body.String(),
+		Status:      NotificationStatusPending,
+		NextTryTime: now,
+		CreatedTime: now,
+	}
+	err = n.CheckErrorFunc()
+	if err != nil {
+		return err
+	}
+	_, err = addNotificationFunc(client, n)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// sendNotificationsFunc 함수는 outbox에 있는 알림을 전송하는 함수이다.
+// 알림은 claimNotificationFunc로 하나씩 가져가므로 여러 서비스에서 동시에 실행해도 같은 알림을 두 번 보내지 않는다.
+// 전송에 실패하면 시도 횟수에 따라 다음 전송 시간을 늦추고, 최대 횟수를 넘기면 실패로 처리한다.
+func sendNotificationsFunc(client *mongo.Client) error {
+	var mailNotifier Notifier
+	var webhookNotifier Notifier
+	for {
+		n, err := claimNotificationFunc(client)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil
+			}
+			return err
+		}
+
+		// 전송할 알림이 있을 때 한 번만 설정을 가져온다.
+		if mailNotifier == nil {
+			adminsetting, err := getAdminSettingFunc(client)
+			if err != nil {
+				return err
+			}
+			mailNotifier, err = newNotifierFunc(adminsetting)
+			if err != nil {
+				return err
+			}
+			webhookNotifier = WebhookNotifier{Payload: adminsetting.WebhookPayload}
+		}
+
+		notifier := mailNotifier
+		if n.Channel == NotificationChannelWebhook {
+			notifier = webhookNotifier
+		}
+		n.Attempts++
+		n.LeaseTime = ""
+		err = notifier.SendFunc(n)
+		if err == nil {
+			n.Status = NotificationStatusSent
+			n.SentTime = time.Now().Format(time.RFC3339)
+			n.LastError = ""
+		} else {
+			log.Printf("알림 전송 실패(%s, %d회): %v", n.Subject, n.Attempts, err)
+			n.LastError = err.Error()
+			if n.Attempts >= NotificationMaxAttempts {
+				n.Status = NotificationStatusFailed
+			} else {
+				// 5분, 10분, 20분, 40분 간격으로 재시도한다.
+				delay := time.Duration(5*math.Pow(2, float64(n.Attempts-1))) * time.Minute
+				n.Status = NotificationStatusPending
+				n.NextTryTime = time.Now().Add(delay).Format(time.RFC3339)
+			}
+		}
+		err = setNotificationFunc(client, n)
+		if err != nil {
+			return err
+		}
+	}
+}
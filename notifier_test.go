@@ -0,0 +1,173 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 알림 전송 테스트 스크립트
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// FileNotifier가 알림 내용을 Writer에 기록하는지 테스트하기 위한 함수
+func Test_fileNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	n := Notification{
+		To:      []string{"aerim.shim", "chaeyun.bae"},
+		Subject: "[BUDGET] 벤더 세금계산서 발행일 알람",
+		Body:    "<p>test</p>",
+	}
+	err := FileNotifier{Writer: &buf}.SendFunc(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"To: aerim.shim, chaeyun.bae", "Subject: " + n.Subject, n.Body} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%q가 기록되지 않았습니다:\n%s", want, buf.String())
+		}
+	}
+}
+
+// 메일 메시지의 헤더와 본문이 올바르게 만들어지는지 테스트하기 위한 함수
+func Test_buildMailMessage(t *testing.T) {
+	n := Notification{
+		To:      []string{"aerim.shim"},
+		Subject: "[BUDGET] 프로젝트 세금계산서 발행일 알람",
+		Body:    strings.Repeat("가나다라마바사", 20),
+	}
+	msg := string(buildMailMessageFunc("BUDGET", n))
+
+	parts := strings.SplitN(msg, "\r\n\r\n", 2)
+	if len(parts) != 2 {
+		t.Fatal("헤더와 본문이 구분되지 않았습니다")
+	}
+	if strings.Contains(parts[0], n.Subject) {
+		t.Error("제목이 인코딩되지 않았습니다")
+	}
+	for _, line := range strings.Split(parts[1], "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("본문 한 줄의 길이가 76자를 넘습니다: %d", len(line))
+		}
+	}
+	body, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(parts[1], "\r\n", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != n.Body {
+		t.Errorf("본문이 다릅니다: %s", body)
+	}
+}
+
+// 구독한 프로젝트의 알림만 받는지 테스트하기 위한 함수
+func Test_subscriptionMatch(t *testing.T) {
+	cases := []struct {
+		subscription Subscription
+		project      string
+		want         bool
+	}{{
+		subscription: Subscription{Event: EventInvoiceToday},
+		project:      "BEE",
+		want:         true, // 프로젝트를 선택하지 않으면 모든 프로젝트
+	}, {
+		subscription: Subscription{Event: EventInvoiceToday, Projects: []string{"BEE", "KIJ"}},
+		project:      "KIJ",
+		want:         true,
+	}, {
+		subscription: Subscription{Event: EventInvoiceToday, Projects: []string{"BEE"}},
+		project:      "KIJ",
+		want:         false,
+	}, {
+		subscription: Subscription{Event: EventMonthClosed, Projects: []string{"BEE"}},
+		project:      "",
+		want:         true, // 프로젝트와 상관없는 알림
+	}}
+	for _, c := range cases {
+		got := c.subscription.MatchFunc(c.project)
+		if got != c.want {
+			t.Fatalf("Test_subscriptionMatch(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", c.subscription, c.project, c.want, got)
+		}
+	}
+}
+
+// webhook으로 payload 템플릿에 맞는 JSON이 POST 되는지 로컬 HTTP 서버로 테스트하기 위한 함수
+func Test_webhookNotifier(t *testing.T) {
+	var got map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("POST 요청이 아닙니다: %s", r.Method)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type이 다릅니다: %s", r.Header.Get("Content-Type"))
+		}
+		err := json.NewDecoder(r.Body).Decode(&got)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	n := Notification{
+		Channel: NotificationChannelWebhook,
+		Event:   EventInvoiceToday,
+		To:      []string{ts.URL},
+		Subject: "[BUDGET] 알람",
+		Body:    "첫째 줄 \"따옴표\"\n둘째 줄",
+	}
+	cases := []struct {
+		payload string
+		want    map[string]string
+	}{{
+		payload: "", // 기본 템플릿
+		want:    map[string]string{"text": n.Body},
+	}, {
+		payload: `{"channel": "budget", "title": {{json .Subject}}, "text": {{json .Text}}}`,
+		want:    map[string]string{"channel": "budget", "title": n.Subject, "text": n.Body},
+	}}
+	for _, c := range cases {
+		got = nil
+		err := WebhookNotifier{Payload: c.payload}.SendFunc(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Test_webhookNotifier(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.payload, c.want, got)
+		}
+	}
+
+	// 2xx가 아닌 응답과 JSON이 아닌 payload는 에러로 처리해야 한다.
+	fail := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer fail.Close()
+	err := WebhookNotifier{}.SendFunc(Notification{To: []string{fail.URL}, Body: "text"})
+	if err == nil {
+		t.Fatal("Test_webhookNotifier(): 500 응답이 에러로 처리되지 않았습니다")
+	}
+	err = WebhookNotifier{Payload: `{"text": {{.Text}}}`}.SendFunc(Notification{To: []string{ts.URL}, Body: "text"})
+	if err == nil {
+		t.Fatal("Test_webhookNotifier(): JSON이 아닌 payload가 에러로 처리되지 않았습니다")
+	}
+}
+
+// 메일 HTML이 채팅 메시지용 텍스트로 바뀌는지 테스트하기 위한 함수
+func Test_htmlToText(t *testing.T) {
+	cases := []struct {
+		html string
+		want string
+	}{{
+		html: "<h3>벤더 세금계산서</h3>\n<p>BEE &amp; KIJ</p><br>----<br/><b>1,000</b>원",
+		want: "벤더 세금계산서\nBEE & KIJ\n1,000원",
+	}}
+	for _, c := range cases {
+		got := htmlToTextFunc(c.html)
+		if got != c.want {
+			t.Fatalf("Test_htmlToText(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.html, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 채팅 webhook 알림 전송 관련 스크립트
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// defaultWebhookPayload 는 payload 템플릿을 설정하지 않았을 때 사용하는 템플릿이다. Slack, Mattermost의 incoming webhook 형식이다.
+const defaultWebhookPayload = `{"text": {{json .Text}}}`
+
+var (
+	regexHTMLLineBreak = regexp.MustCompile(`(?i)<br\s*/?>|</h[1-6]>|</p>|</div>|</li>`) // 줄을 바꿔야 하는 태그
+	regexHTMLTag       = regexp.MustCompile(`<[^>]*>`)                                   // HTML 태그
+)
+
+// WebhookNotifier 자료구조는 채팅 툴의 incoming webhook으로 알림을 전송한다.
+type WebhookNotifier struct {
+	Payload string       // JSON payload 템플릿
+	Client  *http.Client // 요청을 보낼 http client, nil이면 기본 client를 사용한다.
+}
+
+// WebhookMessage 자료구조는 webhook payload 템플릿에 넘겨주는 값을 담는 자료구조이다.
+type WebhookMessage struct {
+	Event   string // 알림 이벤트 ID
+	Subject string // 제목
+	Text    string // 내용
+}
+
+// parseWebhookPayloadFunc 함수는 webhook payload 템플릿을 파싱하는 함수이다. 템플릿에서 json 함수로 문자열을 JSON 문자열로 바꿀 수 있다.
+func parseWebhookPayloadFunc(payload string) (*template.Template, error) {
+	if payload == "" {
+		payload = defaultWebhookPayload
+	}
+	funcs := template.FuncMap{
+		"json": func(s string) (string, error) {
+			b, err := json.Marshal(s)
+			return string(b), err
+		},
+	}
+	return template.New("webhook").Funcs(funcs).Parse(payload)
+}
+
+// SendFunc 메소드는 webhook URL로 알림을 POST 하는 함수이다.
+func (wh WebhookNotifier) SendFunc(n Notification) error {
+	t, err := parseWebhookPayloadFunc(wh.Payload)
+	if err != nil {
+		return err
+	}
+	var payload bytes.Buffer
+	err = t.Execute(&payload, WebhookMessage{
+		Event:   n.Event,
+		Subject: n.Subject,
+		Text:    n.Body,
+	})
+	if err != nil {
+		return err
+	}
+	if !json.Valid(payload.Bytes()) {
+		return fmt.Errorf("webhook payload가 JSON 형식이 아닙니다: %s", payload.String())
+	}
+
+	client := wh.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	for _, url := range n.To {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(payload.Bytes()))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook 전송 실패(%s): %s", url, resp.Status)
+		}
+	}
+	return nil
+}
+
+// htmlToTextFunc 함수는 메일 템플릿으로 만든 HTML을 채팅 메시지로 보낼 수 있도록 텍스트로 바꾸는 함수이다.
+func htmlToTextFunc(s string) string {
+	s = regexHTMLLineBreak.ReplaceAllString(s, "\n")
+	s = regexHTMLTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Trim(line, "-") == "" { // 빈 줄과 구분선은 제외한다.
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// addWebhookNotificationByTemplateFunc 함수는 이벤트에 webhook URL이 설정되어 있으면 템플릿으로 메시지를 만들어 outbox에 추가하는 함수이다.
+func addWebhookNotificationByTemplateFunc(client *mongo.Client, event string, subject string, templateName string, rcp interface{}) error {
+	adminsetting, err := getAdminSettingFunc(client)
+	if err != nil {
+		return err
+	}
+	url := adminsetting.WebhookURLs[event]
+	if url == "" {
+		return nil
+	}
+
+	var body bytes.Buffer
+	err = TEMPLATES.ExecuteTemplate(&body, templateName, rcp)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	n := Notification{
+		Channel:     NotificationChannelWebhook,
+		Event:       event,
+		To:          []string{url},
+		Subject:     subject,
+		Body:        htmlToTextFunc(body.String()),
+		Status:      NotificationStatusPending,
+		NextTryTime: now,
+		CreatedTime: now,
+	}
+	err = n.CheckErrorFunc()
+	if err != nil {
+		return err
+	}
+	_, err = addNotificationFunc(client, n)
+	if err != nil {
+		return err
+	}
+	return nil
+}
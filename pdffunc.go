@@ -0,0 +1,151 @@
+// 프로젝트 결산 프로그램
+//
+// Description : PDF 문서 생성 관련 스크립트
+//
+// 외부 라이브러리 없이 A4 크기의 간단한 PDF 문서를 만든다.
+// 한글은 PDF 뷰어에 포함된 Adobe-Korea1 기본 글꼴(HYGoThic-Medium)을 사용하므로 글꼴 파일을 넣지 않는다.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+)
+
+// PDF 페이지 크기(A4, pt 단위)
+const (
+	PDFPageWidth  = 595.0
+	PDFPageHeight = 842.0
+)
+
+// PDFDocument 자료구조 - 페이지별 그리기 명령을 모아 PDF 파일로 저장한다.
+type PDFDocument struct {
+	pages []*bytes.Buffer // 페이지별 content stream
+}
+
+// newPDFDocumentFunc 함수는 빈 PDF 문서를 만드는 함수이다.
+func newPDFDocumentFunc() *PDFDocument {
+	return &PDFDocument{}
+}
+
+// AddPage 함수는 새 페이지를 추가하는 함수이다. 이후의 그리기 명령은 새 페이지에 들어간다.
+func (d *PDFDocument) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+}
+
+// page 함수는 현재 페이지를 반환하는 함수이다. 페이지가 없으면 추가한다.
+func (d *PDFDocument) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text 함수는 왼쪽 위를 기준으로 한 x, y 위치에 글자를 쓰는 함수이다.
+func (d *PDFDocument) Text(x float64, y float64, size float64, text string) {
+	fmt.Fprintf(d.page(), "BT /F1 %.1f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, PDFPageHeight-y-size, encodePDFTextFunc(text))
+}
+
+// TextRight 함수는 x 위치에 글자의 오른쪽 끝을 맞춰 쓰는 함수이다.
+func (d *PDFDocument) TextRight(x float64, y float64, size float64, text string) {
+	d.Text(x-calPDFTextWidthFunc(text, size), y, size, text)
+}
+
+// TextCenter 함수는 x 위치에 글자의 가운데를 맞춰 쓰는 함수이다.
+func (d *PDFDocument) TextCenter(x float64, y float64, size float64, text string) {
+	d.Text(x-calPDFTextWidthFunc(text, size)/2, y, size, text)
+}
+
+// Line 함수는 두 점을 잇는 선을 그리는 함수이다.
+func (d *PDFDocument) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+	fmt.Fprintf(d.page(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, PDFPageHeight-y1, x2, PDFPageHeight-y2)
+}
+
+// FillRect 함수는 회색조(0: 검정, 1: 흰색)로 채운 사각형을 그리는 함수이다.
+func (d *PDFDocument) FillRect(x float64, y float64, w float64, h float64, gray float64) {
+	fmt.Fprintf(d.page(), "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", gray, x, PDFPageHeight-y-h, w, h)
+}
+
+// WriteTo 함수는 PDF 문서를 w에 쓰는 함수이다.
+func (d *PDFDocument) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	buf := new(bytes.Buffer)
+	var offsets []int
+	addObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// 1: Catalog, 2: Pages, 3~5: 글꼴, 6~: 페이지와 content stream
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	addObject("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := new(bytes.Buffer)
+	for i := range d.pages {
+		fmt.Fprintf(kids, "%d 0 R ", 6+i*2)
+	}
+	addObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(d.pages)))
+	addObject("<< /Type /Font /Subtype /Type0 /BaseFont /HYGoThic-Medium /Encoding /UniKS-UCS2-H /DescendantFonts [4 0 R] >>")
+	addObject("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /HYGoThic-Medium " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Korea1) /Supplement 2 >> " +
+		"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>")
+	addObject("<< /Type /FontDescriptor /FontName /HYGoThic-Medium /Flags 6 /FontBBox [-6 -145 1003 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	for i, content := range d.pages {
+		addObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			PDFPageWidth, PDFPageHeight, 7+i*2))
+		addObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// SaveAs 함수는 PDF 문서를 path 경로에 저장하는 함수이다.
+func (d *PDFDocument) SaveAs(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = d.WriteTo(f)
+	return err
+}
+
+// encodePDFTextFunc 함수는 글자를 UniKS-UCS2-H 인코딩에 맞는 16진수 문자열로 바꾸는 함수이다.
+// UCS2로 나타낼 수 없는 글자는 ?로 바꾼다.
+func encodePDFTextFunc(text string) string {
+	buf := new(bytes.Buffer)
+	for _, r := range text {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(buf, "%04X", r)
+	}
+	return buf.String()
+}
+
+// calPDFTextWidthFunc 함수는 글자 크기가 size일 때 text의 너비를 계산하는 함수이다.
+// 영문, 숫자는 글자 크기의 절반, 그 외의 글자는 글자 크기만큼의 너비를 가진다.
+func calPDFTextWidthFunc(text string, size float64) float64 {
+	width := 0.0
+	for _, r := range text {
+		if r >= 0x20 && r <= 0x7E {
+			width += size / 2
+		} else {
+			width += size
+		}
+	}
+	return width
+}
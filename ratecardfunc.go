@@ -0,0 +1,186 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 단가표 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// checkRateCardPinnedFunc 함수는 예산안에 단가표가 고정되어 있는지 확인하는 함수이다.
+func checkRateCardPinnedFunc(typedata BGTypeData, id string) bool {
+	for _, pinned := range typedata.RateCards {
+		if pinned == id {
+			return true
+		}
+	}
+	return false
+}
+
+// getTasksOfHeadFunc 함수는 예산 팀세팅에서 본부에 속한 태스크 리스트를 가져오는 함수이다.
+func getTasksOfHeadFunc(ts BGTeamSetting, head string) []string {
+	var tasks []string
+	for _, dept := range ts.Departments[head] {
+		for _, part := range dept.Parts {
+			for _, task := range part.Tasks {
+				if !checkStringInListFunc(task, tasks) {
+					tasks = append(tasks, task)
+				}
+			}
+		}
+	}
+	sort.Strings(tasks)
+	return tasks
+}
+
+// getEffectiveRateCardIDsFunc 함수는 오늘 적용되는 본부별 단가표 ID를 가져오는 함수이다. 단가표가 없는 본부는 제외한다.
+func getEffectiveRateCardIDsFunc(client *mongo.Client, ts BGTeamSetting) (map[string]string, error) {
+	today := time.Now().Format("2006-01-02")
+	result := make(map[string]string)
+	for _, head := range ts.Headquarters {
+		rc, err := getEffectiveRateCardFunc(client, head, today)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			return nil, err
+		}
+		result[head] = rc.ID.Hex()
+	}
+	return result, nil
+}
+
+// getTaskDeptFunc 함수는 예산 팀세팅에서 태스크가 속한 본부와 부서를 하나만 찾는 함수이다.
+// 여러 본부나 부서에 속한 태스크는 본부 순서대로 처음 찾은 샷 또는 어셋 관련 부서로 정한다. 찾지 못하면 빈 문자열을 반환한다.
+func getTaskDeptFunc(ts BGTeamSetting, task string, asset bool) (string, string) {
+	heads := append([]string{}, ts.Headquarters...)
+	var others []string
+	for head := range ts.Departments {
+		if !checkStringInListFunc(head, heads) {
+			others = append(others, head)
+		}
+	}
+	sort.Strings(others)
+	heads = append(heads, others...)
+
+	for _, head := range heads {
+		for _, dept := range ts.Departments[head] {
+			if dept.Type != asset {
+				continue
+			}
+			for _, part := range dept.Parts {
+				if checkStringInListFunc(task, part.Tasks) {
+					return head, dept.Name
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// getPinnedRatesFunc 함수는 예산안에 고정된 단가표에서 본부별, 태스크별 1 manday 단가를 가져오는 함수이다.
+func getPinnedRatesFunc(client *mongo.Client, typedata BGTypeData) (map[string]map[string]float64, error) {
+	rates := make(map[string]map[string]float64)
+	for head, id := range typedata.RateCards {
+		rc, err := getRateCardFunc(client, id)
+		if err != nil {
+			return nil, fmt.Errorf("%s 본부의 단가표를 가져올 수 없습니다: %v", head, err)
+		}
+		rates[head] = make(map[string]float64)
+		for task, rate := range rc.Rates {
+			rateInt, err := decryptToIntFunc(rate)
+			if err != nil {
+				return nil, err
+			}
+			rates[head][task] = float64(rateInt)
+		}
+	}
+	return rates, nil
+}
+
+// calTaskCostByRateCardFunc 함수는 태스크별 bid에 예산안에 고정된 단가를 곱해 태스크별 비용을 계산하는 함수이다.
+// 태스크의 단가는 getTaskDeptFunc로 정한 태스크 본부의 단가표에서 가져오고, 단가가 없으면 경고를 남기고 팀 평균 인건비로 계산한다.
+// 예산안에 단가표가 고정되어 있지 않으면 오늘 적용되는 단가표를 고정한다.
+func calTaskCostByRateCardFunc(client *mongo.Client, typedata *BGTypeData, bids map[string]float64, asset bool) (map[string]float64, error) {
+	if len(typedata.RateCards) == 0 {
+		ids, err := getEffectiveRateCardIDsFunc(client, typedata.TeamSetting)
+		if err != nil {
+			return nil, err
+		}
+		typedata.RateCards = ids
+	}
+	rates, err := getPinnedRatesFunc(client, *typedata)
+	if err != nil {
+		return nil, err
+	}
+
+	taskCost := make(map[string]float64)
+	for task, bid := range bids {
+		head, _ := getTaskDeptFunc(typedata.TeamSetting, task, asset)
+		rate, ok := rates[head][task]
+		if !ok {
+			// 단가표에 없는 태스크 때문에 업로드가 실패하지 않도록 단가표를 쓰기 전처럼 팀 평균 인건비로 계산한다.
+			teams := typedata.TeamSetting.Teams[task]
+			if len(teams) == 0 {
+				log.Printf("태스크 %s의 단가가 %s 본부 단가표에 없고 팀세팅에 팀도 없어서 비용을 0으로 계산합니다", task, head)
+				taskCost[task] = 0
+				continue
+			}
+			log.Printf("태스크 %s의 단가가 %s 본부 단가표에 없어서 팀 평균 인건비로 계산합니다", task, head)
+			rate, err = averageWageByTeamsFunc(task, teams)
+			if err != nil {
+				return nil, err
+			}
+			if math.IsNaN(rate) {
+				log.Printf("태스크 %s의 팀에 해당하는 아티스트가 없어서 비용을 0으로 계산합니다", task)
+				rate = 0
+			}
+		}
+		taskCost[task] = math.Round(rate * bid)
+	}
+	return taskCost, nil
+}
+
+// calRateCompareFunc 함수는 단가표의 태스크별 단가와 현재 팀 평균 인건비를 비교하는 함수이다.
+func calRateCompareFunc(rc RateCard, ts BGTeamSetting) ([]RateCompare, error) {
+	var tasks []string
+	for task := range rc.Rates {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+
+	var results []RateCompare
+	for _, task := range tasks {
+		rate, err := decryptToIntFunc(rc.Rates[task])
+		if err != nil {
+			return nil, err
+		}
+		c := RateCompare{
+			Task:  task,
+			Teams: ts.Teams[task],
+			Rate:  rate,
+		}
+		average, err := averageWageByTeamsFunc(task, ts.Teams[task])
+		if err != nil {
+			c.Err = err.Error()
+		} else if math.IsNaN(average) {
+			c.Err = "팀에 해당하는 아티스트가 없습니다"
+		} else {
+			c.Average = int(average)
+			c.Variance = c.Average - c.Rate
+			if c.Rate != 0 {
+				c.VarianceRatio = strconv.FormatFloat(math.Round(float64(c.Variance)/float64(c.Rate)*1000)/10, 'f', 1, 64)
+			}
+		}
+		results = append(results, c)
+	}
+	return results, nil
+}
@@ -0,0 +1,86 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 단가표 테스트 스크립트
+
+package main
+
+import "testing"
+
+// 단가표에 값이 정확히 들어갔는지 확인하는 함수를 테스트하기 위한 함수
+func Test_RateCardCheckError(t *testing.T) {
+	rates := map[string]string{"MM": "encrypted"}
+	cases := []struct {
+		rc   RateCard
+		want bool // 에러 발생 여부
+	}{
+		{rc: RateCard{Headquarter: "VFX", Year: 2021, EffectiveDate: "2021-03-01", Rates: rates}, want: false},
+		{rc: RateCard{Headquarter: "", Year: 2021, EffectiveDate: "2021-03-01", Rates: rates}, want: true},
+		{rc: RateCard{Headquarter: "VFX", Year: 0, EffectiveDate: "2021-03-01", Rates: rates}, want: true},
+		{rc: RateCard{Headquarter: "VFX", Year: 2021, EffectiveDate: "2021/03/01", Rates: rates}, want: true},
+		{rc: RateCard{Headquarter: "VFX", Year: 2021, EffectiveDate: "2020-12-31", Rates: rates}, want: true},
+		{rc: RateCard{Headquarter: "VFX", Year: 2021, EffectiveDate: "2021-03-01"}, want: true},
+	}
+	for _, c := range cases {
+		err := c.rc.CheckErrorFunc()
+		if (err != nil) != c.want {
+			t.Fatalf("Test_RateCardCheckError(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.rc, c.want, err)
+		}
+	}
+}
+
+// 예산 팀세팅에서 본부에 속한 태스크를 가져오는지 테스트하기 위한 함수
+func Test_getTasksOfHead(t *testing.T) {
+	ts := BGTeamSetting{
+		Departments: map[string][]BGDept{
+			"VFX": {{Name: "COMP", Parts: []BGPart{{Name: "Comp", Tasks: []string{"comp", "MM"}}, {Name: "Roto", Tasks: []string{"comp"}}}}},
+		},
+	}
+	cases := []struct {
+		head string
+		want []string
+	}{
+		{head: "VFX", want: []string{"MM", "comp"}},
+		{head: "CM", want: nil},
+	}
+	for _, c := range cases {
+		got := getTasksOfHeadFunc(ts, c.head)
+		if listToStringFunc(got, true) != listToStringFunc(c.want, true) {
+			t.Fatalf("Test_getTasksOfHead(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.head, c.want, got)
+		}
+	}
+}
+
+// 여러 본부에 속한 태스크를 본부 하나의 부서로 정하는지 테스트하기 위한 함수
+func Test_getTaskDept(t *testing.T) {
+	ts := BGTeamSetting{
+		Headquarters: []string{"VFX", "CM"},
+		Departments: map[string][]BGDept{
+			"VFX": {
+				{Name: "Asset", Type: true, Parts: []BGPart{{Name: "Model", Tasks: []string{"model"}}}},
+				{Name: "COMP", Parts: []BGPart{{Name: "Comp", Tasks: []string{"comp"}}}},
+				{Name: "DI", Parts: []BGPart{{Name: "DI", Tasks: []string{"comp"}}}},
+			},
+			"CM": {
+				{Name: "CM", Parts: []BGPart{{Name: "CM", Tasks: []string{"comp", "cm_comp"}}}},
+			},
+		},
+	}
+	cases := []struct {
+		task     string
+		asset    bool
+		wantHead string
+		wantDept string
+	}{
+		{task: "comp", asset: false, wantHead: "VFX", wantDept: "COMP"},
+		{task: "cm_comp", asset: false, wantHead: "CM", wantDept: "CM"},
+		{task: "model", asset: true, wantHead: "VFX", wantDept: "Asset"},
+		{task: "model", asset: false, wantHead: "", wantDept: ""},
+		{task: "FX", asset: false, wantHead: "", wantDept: ""},
+	}
+	for _, c := range cases {
+		head, dept := getTaskDeptFunc(ts, c.task, c.asset)
+		if head != c.wantHead || dept != c.wantDept {
+			t.Fatalf("Test_getTaskDept(): 입력 값: %v, 원하는 값: %v, %v, 얻은 값: %v, %v\n", c.task, c.wantHead, c.wantDept, head, dept)
+		}
+	}
+}
@@ -51,16 +51,7 @@ func handleAPIVFXTeamsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// AccessLevel 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < DefaultLevel {
-		http.Error(w, "권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	adminSetting, err := getAdminSettingFunc(client)
 	if err != nil {
@@ -127,16 +118,7 @@ func handleAPITotalTeamsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// AccessLevel 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < DefaultLevel {
-		http.Error(w, "권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	adminSetting, err := getAdminSettingFunc(client)
 	if err != nil {
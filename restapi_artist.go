@@ -53,16 +53,7 @@ func handleAPIRmArtistFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "삭제 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	err = rmArtistFunc(client, id) // 아티스트 삭제
 	if err != nil {
@@ -70,10 +61,12 @@ func handleAPIRmArtistFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionDelete
+	log.EntityType = LogEntityArtist
+	log.EntityID = id
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("아티스트 ID %s가 삭제되었습니다.", id)
 
@@ -84,16 +84,7 @@ func handleAPIAddArtistCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "추가 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	a := Artist{}
 	a.ID = changeToCMIDFunc(id)
@@ -181,10 +172,12 @@ func handleAPIAddArtistCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionCreate
+	log.EntityType = LogEntityArtist
+	log.EntityID = a.ID
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("CM 아티스트 ID %s가 추가되었습니다.", a.ID)
 
@@ -194,6 +187,20 @@ func handleAPIAddArtistCMFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 급여 보기 권한이 없으면 연봉 정보를 지우고 결과를 보낸다.
+	viewSalary := false
+	user, err := getUserFromRequestFunc(w, r, client, true)
+	if err == nil {
+		viewSalary, err = checkPermissionFunc(client, user, "viewsalary")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if !viewSalary {
+		a = maskArtistsSalaryFunc([]Artist{a})[0]
+	}
+
 	// json으로 결과 전송
 	data, err := json.Marshal(a)
 	if err != nil {
@@ -54,27 +54,25 @@ func handleAPIRmBGProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	err = rmBGProjectFunc(client, id) // 프로젝트 삭제
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "삭제 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
-
-	err = rmBGProjectFunc(client, id) // 프로젝트 삭제
+	err = rmBGRevisionsFunc(client, id) // 프로젝트의 예산안 리비전 삭제
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionDelete
+	log.EntityType = LogEntityBGProject
+	log.EntityID = id
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("예산 프로젝트 %s가 삭제되었습니다.", id)
 
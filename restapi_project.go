@@ -95,10 +95,12 @@ func handleEventSGAPIAddProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionCreate
+	log.EntityType = LogEntityProject
+	log.EntityID = id
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("프로젝트 %s가 추가되었습니다.(ShotgunEvent)", id)
 
@@ -156,16 +158,7 @@ func handleAPIRmProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "삭제 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	err = rmProjectFunc(client, id) // 프로젝트 삭제
 	if err != nil {
@@ -180,10 +173,12 @@ func handleAPIRmProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionDelete
+	log.EntityType = LogEntityProject
+	log.EntityID = id
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("프로젝트 %s가 삭제되었습니다.", id)
 
@@ -247,16 +242,7 @@ func handleMonthlyPurchaseCostFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < ManagerLevel {
-		http.Error(w, "프로젝트 구매비의 읽기 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	project, err := getProjectFunc(client, id)
 	if err != nil {
@@ -332,16 +318,7 @@ func handleAPISetMonthlyPurchaseCostFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < ManagerLevel {
-		http.Error(w, "프로젝트 구매비의 수정 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	purchaseCostNum, err := strconv.Atoi(num)
 	if err != nil {
@@ -392,6 +369,7 @@ func handleAPISetMonthlyPurchaseCostFunc(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	before := snapshotEntityFunc(project)
 	if project.SMMonthlyPurchaseCost == nil { // 비어있다면 초기화를 해준다.
 		project.SMMonthlyPurchaseCost = map[string][]PurchaseCost{}
 	}
@@ -403,6 +381,22 @@ func handleAPISetMonthlyPurchaseCostFunc(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	log := Log{
+		UserID:     apiActorFunc(r),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s의 %s 구매 내역이 수정되었습니다.", id, date),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityProject,
+		EntityID:   id,
+		Changes:    diffEntityFunc(before, project),
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// json으로 결과 전송
 	data, err := json.Marshal(totalExpenses)
 	if err != nil {
@@ -457,16 +451,7 @@ func handleAPIMonthlyPaymentFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < ManagerLevel {
-		http.Error(w, "프로젝트 월별 매출의 읽기 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	project, err := getProjectFunc(client, id)
 	if err != nil {
@@ -542,16 +527,7 @@ func handleAPISetMonthlyPaymentFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < ManagerLevel {
-		http.Error(w, "프로젝트 매출의 수정 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	paymentNum, err := strconv.Atoi(num)
 	if err != nil {
@@ -620,6 +596,7 @@ func handleAPISetMonthlyPaymentFunc(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	before := snapshotEntityFunc(project)
 	if project.SMMonthlyPayment == nil { // 비어있다면 초기화를 해준다.
 		project.SMMonthlyPayment = map[string][]Payment{}
 	}
@@ -631,6 +608,22 @@ func handleAPISetMonthlyPaymentFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	log := Log{
+		UserID:     apiActorFunc(r),
+		CreatedAt:  time.Now(),
+		Content:    fmt.Sprintf("프로젝트 %s의 %s 매출 내역이 수정되었습니다.", id, date),
+		Action:     LogActionUpdate,
+		EntityType: LogEntityProject,
+		EntityID:   id,
+		Changes:    diffEntityFunc(before, project),
+		IP:         clientIPFunc(r),
+	}
+	err = addLogsFunc(client, log)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// json으로 결과 전송
 	data, err := json.Marshal(totalExpenses)
 	if err != nil {
@@ -674,16 +667,7 @@ func handleAPIUpdateProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// AccessLevel 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < ManagerLevel {
-		http.Error(w, "업데이트 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// AdminSetting을 가져온다.
 	adminSetting, err := getAdminSettingFunc(client)
@@ -722,10 +706,11 @@ func handleAPIUpdateProjectsFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionSync
+	log.EntityType = LogEntityProject
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = "샷건에 존재하는 프로젝트들이 업데이트되었습니다."
 
@@ -52,16 +52,7 @@ func handleAPISGArtistFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	a, err := sgGetArtistFunc(id) // Shotgun에서 아티스트 정보를 가져온다.
 	if err != nil {
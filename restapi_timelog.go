@@ -49,16 +49,7 @@ func handleAPICheckMonthlyStatusFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// AccessLevel 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < DefaultLevel {
-		http.Error(w, "권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	y, m, _ := time.Now().Date()
 	ld := time.Now().AddDate(0, -1, 0)
@@ -73,6 +64,20 @@ func handleAPICheckMonthlyStatusFunc(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			err = addLogsFunc(client, Log{
+				UserID:     apiActorFunc(r),
+				CreatedAt:  time.Now(),
+				Content:    fmt.Sprintf("%s 결산 상태가 생성되었습니다.", thisMonsthStatus.Date),
+				Action:     LogActionCreate,
+				EntityType: LogEntityMonthlyStatus,
+				EntityID:   thisMonsthStatus.Date,
+				Changes:    diffEntityFunc(MonthlyStatus{}, thisMonsthStatus),
+				IP:         clientIPFunc(r),
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
 		} else {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -89,6 +94,20 @@ func handleAPICheckMonthlyStatusFunc(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			err = addLogsFunc(client, Log{
+				UserID:     apiActorFunc(r),
+				CreatedAt:  time.Now(),
+				Content:    fmt.Sprintf("%s 결산 상태가 생성되었습니다.", lastMonthStatus.Date),
+				Action:     LogActionCreate,
+				EntityType: LogEntityMonthlyStatus,
+				EntityID:   lastMonthStatus.Date,
+				Changes:    diffEntityFunc(MonthlyStatus{}, lastMonthStatus),
+				IP:         clientIPFunc(r),
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
 		} else {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -154,21 +173,23 @@ func handleAPIUpdateTimelogFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// AccessLevel 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
+	// 권한은 permissionMiddlewareFunc에서 확인한다. 정산 완료된 프로젝트의 타임로그는 월 결산 마감 권한이 있어야 수정할 수 있다.
+	user, err := getUserFromRequestFunc(w, r, client, true)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
 	}
-	if accesslevel < DefaultLevel {
-		http.Error(w, "업데이트 권한이 없는 계정입니다", http.StatusUnauthorized)
+	canEditFinished, err := checkPermissionFunc(client, user, "closemonth")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionUpdate
+	log.EntityType = LogEntityTimelog
+	log.IP = clientIPFunc(r)
 
 	// DB에서 Admin setting 데이터를 가져온다.
 	adminSetting, err := getAdminSettingFunc(client)
@@ -274,13 +295,13 @@ func handleAPIUpdateTimelogFunc(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// 정산 완료된 프로젝트에 타임로그를 작성했을 경우 admin 권한이 아니면 리턴한다.
-	if finishedTimelog != nil && accesslevel < AdminLevel {
+	// 정산 완료된 프로젝트에 타임로그를 작성했을 경우 월 결산 마감 권한이 없으면 리턴한다.
+	if finishedTimelog != nil && !canEditFinished {
 		result.Timelog = finishedTimelog
 		result.InvalidAccess = true
 
 		log.CreatedAt = time.Now()
-		log.Content = "Admin 권한이 아닌 유저가 타임로그를 업데이트하는 중에 정산 완료된 프로젝트에 타임로그가 존재하였습니다."
+		log.Content = "월 결산 마감 권한이 없는 유저가 타임로그를 업데이트하는 중에 정산 완료된 프로젝트에 타임로그가 존재하였습니다."
 
 		err = addLogsFunc(client, log)
 		if err != nil {
@@ -572,6 +593,12 @@ func handleAPIUpdateTimelogFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if updateErr != "" {
+		// 타임로그 업데이트 실패 알림을 구독한 사용자들에게 알린다.
+		err = addTimelogSyncFailedNotificationFunc(client, updateErr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		http.Error(w, updateErr, http.StatusInternalServerError)
 		return
 	}
@@ -659,16 +686,7 @@ func handleAPIRmTimelogByIDFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "삭제 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	idList := stringToListFunc(id, " ")
 	err = rmTimelogByIDFunc(client, idList)
@@ -677,10 +695,11 @@ func handleAPIRmTimelogByIDFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionDelete
+	log.EntityType = LogEntityTimelog
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = "AdminSetting에서 제외할 ID의 타임로그를 삭제했습니다."
 
@@ -739,16 +758,7 @@ func handleAPIRmTimelogByProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "삭제 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	projectList := stringToListFunc(project, " ")
 	err = rmTimelogByProjectFunc(client, projectList)
@@ -757,10 +767,11 @@ func handleAPIRmTimelogByProjectFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionDelete
+	log.EntityType = LogEntityTimelog
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = "AdminSetting에서 제외할 프로젝트의 타임로그를 삭제했습니다."
 
@@ -812,16 +823,7 @@ func handleAPIResetTimelogFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// AccessLevel 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "리셋 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
 
 	// DB에서 Admin setting 데이터를 가져온다.
 	adminSetting, err := getAdminSettingFunc(client)
@@ -902,10 +904,11 @@ func handleAPIResetTimelogFunc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Token 가져오기
-	token, _ := getTokenFromHeaderFunc(w, r)
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionDelete
+	log.EntityType = LogEntityTimelog
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = "AdminSetting에서 타임로그를 리셋했습니다."
 
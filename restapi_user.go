@@ -52,28 +52,25 @@ func handleAPIRmUserFunc(w http.ResponseWriter, r *http.Request) {
 		log.Fatal(err)
 	}
 
-	// Access Level 확인
-	accesslevel, err := getAccessLevelFromHeaderFunc(r, client)
+	// 권한은 permissionMiddlewareFunc에서 확인한다.
+
+	err = rmUserFunc(client, id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if accesslevel < AdminLevel {
-		http.Error(w, "삭제 권한이 없는 계정입니다", http.StatusUnauthorized)
-		return
-	}
-
-	err = rmUserFunc(client, id)
+	err = rmAPITokensFunc(client, id) // 유저의 API 토큰도 삭제한다.
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Token을 가져온다.
-	token, _ := getTokenFromHeaderFunc(w, r)
-
 	log := Log{}
-	log.UserID = token.ID
+	log.UserID = apiActorFunc(r)
+	log.Action = LogActionDelete
+	log.EntityType = LogEntityUser
+	log.EntityID = id
+	log.IP = clientIPFunc(r)
 	log.CreatedAt = time.Now()
 	log.Content = fmt.Sprintf("유저 %s가 삭제되었습니다.", id)
 
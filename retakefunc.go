@@ -0,0 +1,173 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 리테이크 인건비 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// isRetakeTimelogFunc 함수는 타임로그가 리테이크 작업인지 확인하는 함수이다.
+// 태스크 이름에 keywords 중 하나가 포함되거나(대소문자 구분 없음) 타임로그를 작성한 날의 태스크 상태가 statuses 중 하나이면 리테이크이다.
+func isRetakeTimelogFunc(t EpisodeTimelog, keywords []string, statuses []string) bool {
+	task := strings.ToLower(t.Task)
+	for _, k := range keywords {
+		if k != "" && strings.Contains(task, strings.ToLower(k)) {
+			return true
+		}
+	}
+	for _, s := range statuses {
+		if s != "" && strings.EqualFold(t.Status, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// getTaskStatusOnDateFunc 함수는 태스크 상태 변경 기록으로 date(YYYY-MM-DD) 날짜의 태스크 상태를 반환하는 함수이다.
+// 그 날까지 마지막으로 바뀐 상태를 사용하고, 그 날 이후에 처음 바뀌었으면 바뀌기 전 상태를, 변경 기록이 없으면 current를 사용한다.
+func getTaskStatusOnDateFunc(history []SGStatusChange, date string, current string) string {
+	if len(history) == 0 {
+		return current
+	}
+	changes := make([]SGStatusChange, len(history))
+	copy(changes, history)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Time < changes[j].Time
+	})
+	status := changes[0].Old
+	for _, c := range changes {
+		if len(c.Time) < 10 {
+			continue
+		}
+		if c.Time[:10] > date {
+			break
+		}
+		status = c.New
+	}
+	return status
+}
+
+// calBGRetakeRatioFunc 함수는 리테이크 인건비 비율과 예산안 Retake율 기준의 실제 Retake율을 계산하는 함수이다.
+// 예산안의 Retake율은 리테이크를 뺀 인건비에 더하는 비율이므로 실제 Retake율도 리테이크를 뺀 인건비로 나눈다.
+func calBGRetakeRatioFunc(r *BGRetake) {
+	r.ShareRatio = ""
+	r.RetakeRatio = ""
+	if r.LaborCost != 0 {
+		r.ShareRatio = fmt.Sprintf("%.1f", float64(r.RetakeCost)/float64(r.LaborCost)*100)
+	}
+	if base := r.LaborCost - r.RetakeCost; base > 0 {
+		r.RetakeRatio = fmt.Sprintf("%.1f", float64(r.RetakeCost)/float64(base)*100)
+	}
+}
+
+// getBGRetakeWageKeyFunc 함수는 아티스트의 월별 시급을 찾기 위한 키를 반환하는 함수이다.
+func getBGRetakeWageKeyFunc(userID string, year int, month int) string {
+	return fmt.Sprintf("%s/%d/%d", userID, year, month)
+}
+
+// sumBGRetakesFunc 함수는 에피소드별 타임로그와 아티스트의 월별 시급으로 월별 전체 인건비와 리테이크 인건비를 계산하는 함수이다.
+// 월 순서대로 정리한 리스트와 합계를 반환한다. 시급이 없는 아티스트의 타임로그는 시간만 더한다.
+func sumBGRetakesFunc(timelogs []EpisodeTimelog, keywords []string, statuses []string, wages map[string]float64) ([]BGRetake, BGRetake) {
+	type cost struct {
+		hours, retakeHours, labor, retake float64
+	}
+	months := make(map[string]*cost)
+	for _, t := range timelogs {
+		date := fmt.Sprintf("%04d-%02d", t.Year, t.Month)
+		if _, ok := months[date]; !ok {
+			months[date] = &cost{}
+		}
+		duration := math.Round(t.Duration/60*10) / 10
+		c := duration * wages[getBGRetakeWageKeyFunc(t.UserID, t.Year, t.Month)]
+		months[date].hours += duration
+		months[date].labor += c
+		if isRetakeTimelogFunc(t, keywords, statuses) {
+			months[date].retakeHours += duration
+			months[date].retake += c
+		}
+	}
+
+	var dates []string
+	for date := range months {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var results []BGRetake
+	total := BGRetake{}
+	for _, date := range dates {
+		c := months[date]
+		r := BGRetake{
+			Date:        date,
+			Hours:       math.Round(c.hours*10) / 10,
+			RetakeHours: math.Round(c.retakeHours*10) / 10,
+			LaborCost:   int(math.Round(c.labor)),
+			RetakeCost:  int(math.Round(c.retake)),
+		}
+		calBGRetakeRatioFunc(&r)
+		results = append(results, r)
+		total.Hours += r.Hours
+		total.RetakeHours += r.RetakeHours
+		total.LaborCost += r.LaborCost
+		total.RetakeCost += r.RetakeCost
+	}
+	total.Hours = math.Round(total.Hours*10) / 10
+	total.RetakeHours = math.Round(total.RetakeHours*10) / 10
+	calBGRetakeRatioFunc(&total)
+	return results, total
+}
+
+// calBGRetakesFunc 함수는 DB에 저장된 프로젝트의 에피소드별 타임로그로 월별 리테이크 인건비를 계산하는 함수이다.
+func calBGRetakesFunc(client *mongo.Client, project string, adminSetting AdminSetting) ([]BGRetake, BGRetake, error) {
+	timelogs, err := getEpisodeTimelogsFunc(client, project)
+	if err != nil {
+		return nil, BGRetake{}, err
+	}
+
+	wages := make(map[string]float64)
+	artists := make(map[string]Artist)
+	for _, t := range timelogs {
+		key := getBGRetakeWageKeyFunc(t.UserID, t.Year, t.Month)
+		if _, ok := wages[key]; ok {
+			continue
+		}
+		artist, ok := artists[t.UserID]
+		if !ok {
+			artist, err = getArtistFunc(client, t.UserID)
+			if err != nil {
+				if err == mongo.ErrNoDocuments {
+					wages[key] = 0
+					continue
+				}
+				return nil, BGRetake{}, err
+			}
+			artists[t.UserID] = artist
+		}
+		wages[key] = 0
+		if artist.Salary[fmt.Sprintf("%d", t.Year)] != "" {
+			wages[key], err = hourlyWageFunc(artist, t.Year, t.Month) // 시급 계산
+			if err != nil {
+				return nil, BGRetake{}, err
+			}
+		}
+	}
+
+	results, total := sumBGRetakesFunc(timelogs, adminSetting.SGRetakeKeywords, adminSetting.SGRetakeStatuses, wages)
+	return results, total, nil
+}
+
+// calBGRetakeBudgetShareFunc 함수는 예산안 Retake율을 전체 인건비 중 리테이크 인건비 비율(%)로 바꾸는 함수이다.
+// ex) Retake율 10%는 리테이크를 뺀 인건비의 10%를 더한 것이므로 전체 인건비의 약 9.1%이다.
+func calBGRetakeBudgetShareFunc(retakeRatio float64) string {
+	if retakeRatio <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", retakeRatio/(100+retakeRatio)*100)
+}
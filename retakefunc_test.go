@@ -0,0 +1,88 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 리테이크 인건비 테스트 스크립트
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 태스크 이름과 태스크 상태로 리테이크 타임로그를 구분하는지 테스트하기 위한 함수
+func Test_isRetakeTimelog(t *testing.T) {
+	keywords := []string{"retake", "_rt"}
+	statuses := []string{"rtk"}
+	cases := []struct {
+		in   EpisodeTimelog
+		want bool
+	}{
+		{in: EpisodeTimelog{Task: "comp_RT", Status: "ip"}, want: true},
+		{in: EpisodeTimelog{Task: "Retake comp", Status: "ip"}, want: true},
+		{in: EpisodeTimelog{Task: "comp", Status: "RTK"}, want: true},
+		{in: EpisodeTimelog{Task: "comp", Status: "ip"}, want: false},
+		{in: EpisodeTimelog{Task: "art", Status: "fin"}, want: false},
+	}
+	for _, c := range cases {
+		got := isRetakeTimelogFunc(c.in, keywords, statuses)
+		if got != c.want {
+			t.Fatalf("Test_isRetakeTimelog(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.in, c.want, got)
+		}
+	}
+}
+
+// 태스크 상태 변경 기록으로 타임로그 날짜의 태스크 상태를 찾는지 테스트하기 위한 함수
+func Test_getTaskStatusOnDate(t *testing.T) {
+	history := []SGStatusChange{
+		{Time: "2021-03-10T09:00:00+09:00", Old: "rtk", New: "ip"},
+		{Time: "2021-03-02T10:00:00+09:00", Old: "ip", New: "rtk"},
+		{Time: "2021-03-10T18:00:00+09:00", Old: "ip", New: "fin"},
+	}
+	cases := []struct {
+		history []SGStatusChange
+		date    string
+		current string
+		want    string
+	}{
+		{history: history, date: "2021-03-01", current: "fin", want: "ip"}, // 처음 바뀌기 전
+		{history: history, date: "2021-03-02", current: "fin", want: "rtk"},
+		{history: history, date: "2021-03-05", current: "fin", want: "rtk"},
+		{history: history, date: "2021-03-10", current: "fin", want: "fin"}, // 같은 날 여러 번 바뀌면 마지막 상태
+		{history: nil, date: "2021-03-05", current: "rtk", want: "rtk"},     // 변경 기록이 없으면 현재 상태
+	}
+	for _, c := range cases {
+		got := getTaskStatusOnDateFunc(c.history, c.date, c.current)
+		if got != c.want {
+			t.Fatalf("Test_getTaskStatusOnDate(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.history, c.date, c.want, got)
+		}
+	}
+}
+
+// 월별 전체 인건비, 리테이크 인건비와 Retake율을 계산하는지 테스트하기 위한 함수
+func Test_sumBGRetakes(t *testing.T) {
+	timelogs := []EpisodeTimelog{
+		{UserID: "1", Year: 2021, Month: 3, Task: "comp", Duration: 600},
+		{UserID: "1", Year: 2021, Month: 3, Task: "comp_rt", Duration: 120},
+		{UserID: "2", Year: 2021, Month: 3, Task: "mm", Status: "rtk", Duration: 60},
+		{UserID: "1", Year: 2021, Month: 1, Task: "comp", Duration: 60},
+		{UserID: "3", Year: 2021, Month: 1, Task: "comp_rt", Duration: 60}, // 시급이 없는 아티스트
+	}
+	wages := map[string]float64{
+		getBGRetakeWageKeyFunc("1", 2021, 3): 10000,
+		getBGRetakeWageKeyFunc("2", 2021, 3): 20000,
+		getBGRetakeWageKeyFunc("1", 2021, 1): 10000,
+	}
+	want := []BGRetake{
+		{Date: "2021-01", Hours: 2, RetakeHours: 1, LaborCost: 10000, RetakeCost: 0, ShareRatio: "0.0", RetakeRatio: "0.0"},
+		{Date: "2021-03", Hours: 13, RetakeHours: 3, LaborCost: 140000, RetakeCost: 40000, ShareRatio: "28.6", RetakeRatio: "40.0"},
+	}
+	wantTotal := BGRetake{Hours: 15, RetakeHours: 4, LaborCost: 150000, RetakeCost: 40000, ShareRatio: "26.7", RetakeRatio: "36.4"}
+	got, total := sumBGRetakesFunc(timelogs, []string{"_rt"}, []string{"rtk"}, wages)
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(total, wantTotal) {
+		t.Fatalf("Test_sumBGRetakes(): 입력 값: %v, 원하는 값: %v, %v, 얻은 값: %v, %v\n", timelogs, want, wantTotal, got, total)
+	}
+
+	if share := calBGRetakeBudgetShareFunc(10); share != "9.1" {
+		t.Fatalf("Test_sumBGRetakes(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", 10, "9.1", share)
+	}
+}
@@ -0,0 +1,583 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 역할, 권한 관련 스크립트
+
+package main
+
+import "go.mongodb.org/mongo-driver/mongo"
+
+// Permissions 는 기능별 권한 리스트이다. DefaultLevel은 역할을 만들기 전에 각 페이지에서 확인하던 액세스 레벨이다.
+var Permissions = []Permission{
+	{ID: "viewsalary", Name: "급여 보기", DefaultLevel: AdminLevel, Sensitive: true},
+	{ID: "editpayment", Name: "매출, 구매비 수정", DefaultLevel: ManagerLevel},
+	{ID: "managevendor", Name: "벤더 관리", DefaultLevel: ManagerLevel},
+	{ID: "approvebudget", Name: "예산안 승인", DefaultLevel: ManagerLevel},
+	{ID: "manageapproval", Name: "예산안 결재 관리", DefaultLevel: AdminLevel},
+	{ID: "timelogsync", Name: "타임로그 동기화", DefaultLevel: AdminLevel},
+	{ID: "closemonth", Name: "월 결산 마감", DefaultLevel: AdminLevel},
+	{ID: "viewartists", Name: "아티스트 보기", DefaultLevel: AdminLevel},
+	{ID: "viewlaborcost", Name: "인건비 보기", DefaultLevel: AdminLevel},
+	{ID: "viewmain", Name: "메인 페이지 보기", DefaultLevel: DefaultLevel},
+	{ID: "viewtimelog", Name: "타임로그 보기", DefaultLevel: DefaultLevel},
+	{ID: "uploadtimelog", Name: "타임로그 업로드", DefaultLevel: DefaultLevel},
+	{ID: "edittimelog", Name: "타임로그 수정", DefaultLevel: ManagerLevel},
+	{ID: "deletetimelog", Name: "타임로그 삭제", DefaultLevel: AdminLevel},
+	{ID: "editsuptimelog", Name: "SUP 타임로그 수정", DefaultLevel: AdminLevel},
+	{ID: "viewstatus", Name: "결산 현황 보기", DefaultLevel: MemberLevel},
+	{ID: "viewactual", Name: "실제 비용 보기", DefaultLevel: AdminLevel},
+	{ID: "exportdata", Name: "엑셀 내보내기", DefaultLevel: AdminLevel},
+	{ID: "viewprojects", Name: "프로젝트, 벤더, 클라이언트 보기", DefaultLevel: MemberLevel},
+	{ID: "editproject", Name: "결산 프로젝트 수정", DefaultLevel: ManagerLevel},
+	{ID: "editbudget", Name: "예산 프로젝트 수정", DefaultLevel: ManagerLevel},
+	{ID: "deleteproject", Name: "프로젝트 삭제", DefaultLevel: AdminLevel},
+	{ID: "manageclient", Name: "클라이언트 관리", DefaultLevel: ManagerLevel},
+	{ID: "editartists", Name: "아티스트 수정", DefaultLevel: AdminLevel},
+	{ID: "manageratecard", Name: "단가표 관리", DefaultLevel: AdminLevel},
+	{ID: "manageteamsetting", Name: "팀 세팅 관리", DefaultLevel: AdminLevel},
+	{ID: "manageusers", Name: "사용자 관리", DefaultLevel: AdminLevel},
+	{ID: "manageadminsetting", Name: "Admin 설정 관리", DefaultLevel: AdminLevel},
+	{ID: "viewlog", Name: "로그 보기", DefaultLevel: MemberLevel},
+	{ID: "viewinvoice", Name: "세금 계산서 발행 알림 보기", DefaultLevel: AdminLevel},
+}
+
+// routePermissions 는 URL과 필요한 권한 ID이다. 권한은 permissionMiddlewareFunc에서 확인하고, 새 URL은 이 목록이나 openRoutes에 반드시 넣어야 한다.
+var routePermissions = map[string]string{
+	"/edit-artistvfx":                "viewsalary",
+	"/editartistvfx-submit":          "viewsalary",
+	"/editartistvfx-success":         "viewsalary",
+	"/updateartists-vfx":             "viewsalary",
+	"/artistsvfxexcel-download":      "viewsalary",
+	"/artistsvfxexcel-submit":        "viewsalary",
+	"/updateartistsvfx-submit":       "viewsalary",
+	"/updateartistsvfx-success":      "viewsalary",
+	"/edit-artistcm":                 "viewsalary",
+	"/editartistcm-submit":           "viewsalary",
+	"/editartistcm-success":          "viewsalary",
+	"/updateartists-cm":              "viewsalary",
+	"/artistscmexcel-download":       "viewsalary",
+	"/artistscmexcel-submit":         "viewsalary",
+	"/updateartistscm-submit":        "viewsalary",
+	"/updateartistscm-success":       "viewsalary",
+	"/upload-artistsexcel":           "viewsalary",
+	"/api/addartistvfx":              "viewsalary",
+	"/api/addartistcm":               "viewsalary",
+	"/api/setMonthlyPayment":         "editpayment",
+	"/api/setMonthlyPurchaseCost":    "editpayment",
+	"/addvendor-page":                "managevendor",
+	"/addvendor":                     "managevendor",
+	"/addvendor-submit":              "managevendor",
+	"/addvendor-success":             "managevendor",
+	"/edit-vendor":                   "managevendor",
+	"/editvendor-submit":             "managevendor",
+	"/editvendor-success":            "managevendor",
+	"/api/rmvendor":                  "managevendor",
+	"/episode-timelog-sync":          "timelogsync",
+	"/finishedtimelog":               "closemonth",
+	"/finishedtimelog-submit":        "closemonth",
+	"/artists-vfx":                   "viewartists",
+	"/exportartists-vfx":             "viewartists",
+	"/artists-cm":                    "viewartists",
+	"/exportartists-cm":              "viewartists",
+	"/smdetail-laborcost":            "viewlaborcost",
+	"/export-smdetaillaborcost":      "viewlaborcost",
+	"/smtotal-laborcost":             "viewlaborcost",
+	"/export-smtotallaborcost":       "viewlaborcost",
+	"/":                              "viewmain",
+	"/search":                        "viewmain",
+	"/detail-sm":                     "viewmain",
+	"/help":                          "viewmain",
+	"/api/vfxteams":                  "viewmain",
+	"/api/totalteams":                "viewmain",
+	"/timelog-vfx":                   "viewtimelog",
+	"/searchtimelog-vfx":             "viewtimelog",
+	"/timelog-cm":                    "viewtimelog",
+	"/searchtimelog-cm":              "viewtimelog",
+	"/timelog-total":                 "viewtimelog",
+	"/searchtimelog-total":           "viewtimelog",
+	"/api/checkmonthlystatus":        "viewtimelog",
+	"/api/updatetimelog":             "uploadtimelog",
+	"/updatetimelog-vfx":             "edittimelog",
+	"/timelogvfxexcel-download":      "edittimelog",
+	"/upload-timelogvfxexcel":        "edittimelog",
+	"/timelogvfxexcel-submit":        "edittimelog",
+	"/updatetimelogvfx-submit":       "edittimelog",
+	"/updatetimelogvfx-success":      "edittimelog",
+	"/exporttimelog-vfx":             "edittimelog",
+	"/updatetimelog-cm":              "edittimelog",
+	"/timelogcmexcel-download":       "edittimelog",
+	"/upload-timelogcmexcel":         "edittimelog",
+	"/timelogcmexcel-submit":         "edittimelog",
+	"/updatetimelogcm-submit":        "edittimelog",
+	"/updatetimelogcm-success":       "edittimelog",
+	"/exporttimelog-cm":              "edittimelog",
+	"/exporttimelog-total":           "edittimelog",
+	"/api/rmtimelogbyid":             "deletetimelog",
+	"/api/rmtimelogbyproject":        "deletetimelog",
+	"/api/resettimelog":              "deletetimelog",
+	"/timelogs-sup":                  "editsuptimelog",
+	"/editsuptimelogs-submit":        "editsuptimelog",
+	"/editsuptimelogs-success":       "editsuptimelog",
+	"/smpayment-status":              "viewstatus",
+	"/smvendor-status":               "viewstatus",
+	"/smtotal-status":                "viewstatus",
+	"/bg/detail":                     "viewactual",
+	"/bgactual":                      "viewactual",
+	"/exportbgactual":                "viewactual",
+	"/episode-actual":                "viewactual",
+	"/retake":                        "viewactual",
+	"/exportinit":                    "exportdata",
+	"/exportdetailsm":                "exportdata",
+	"/export-smpaymentstatus":        "exportdata",
+	"/export-smvendorstatus":         "exportdata",
+	"/export-smtotalstatus":          "exportdata",
+	"/exportprojects":                "exportdata",
+	"/exportbgprojects":              "exportdata",
+	"/exportdetailshot":              "exportdata",
+	"/exportdetailasset":             "exportdata",
+	"/exportvendors":                 "exportdata",
+	"/projects":                      "viewprojects",
+	"/searchprojects":                "viewprojects",
+	"/bgprojects":                    "viewprojects",
+	"/searchbgprojects":              "viewprojects",
+	"/bgcapacity":                    "viewprojects",
+	"/exportbgcapacity":              "viewprojects",
+	"/shotasset":                     "viewprojects",
+	"/searchshotasset":               "viewprojects",
+	"/detail-shot":                   "viewprojects",
+	"/detail-asset":                  "viewprojects",
+	"/vendors":                       "viewprojects",
+	"/searchvendors":                 "viewprojects",
+	"/clients":                       "viewprojects",
+	"/searchclients":                 "viewprojects",
+	"/client":                        "viewprojects",
+	"/addproject":                    "editproject",
+	"/addproject-submit":             "editproject",
+	"/addproject-success":            "editproject",
+	"/edit-projectsm":                "editproject",
+	"/editprojectsm-submit":          "editproject",
+	"/editprojectsm-success":         "editproject",
+	"/episodes-sm":                   "editproject",
+	"/episodes-sm-submit":            "editproject",
+	"/api/monthlyPurchaseCost":       "editproject",
+	"/api/monthlyPayment":            "editproject",
+	"/api/updateprojects":            "editproject",
+	"/addbgproject":                  "editbudget",
+	"/addbgproject-submit":           "editbudget",
+	"/addbgproject-success":          "editbudget",
+	"/edit-bgproject":                "editbudget",
+	"/editbgproject-submit":          "editbudget",
+	"/editbgproject-success":         "editbudget",
+	"/bgproject-teamsetting":         "editbudget",
+	"/bgproject-teamsetting-submit":  "editbudget",
+	"/bgproject-teamsetting-success": "editbudget",
+	"/bgrevisions":                   "editbudget",
+	"/bgrevision-restore":            "editbudget",
+	"/bgcompare":                     "editbudget",
+	"/bgapproval":                    "editbudget",
+	"/bgapproval-submit":             "editbudget",
+	"/bgepisodes":                    "editbudget",
+	"/bgepisodes-submit":             "editbudget",
+	"/bgquote":                       "editbudget",
+	"/bgquote-submit":                "editbudget",
+	"/uploadshot":                    "editbudget",
+	"/shotexcel-download":            "editbudget",
+	"/upload-shotexcel":              "editbudget",
+	"/shotexcel-submit":              "editbudget",
+	"/uploadshot-submit":             "editbudget",
+	"/uploadshot-success":            "editbudget",
+	"/uploadasset":                   "editbudget",
+	"/assetexcel-download":           "editbudget",
+	"/upload-assetexcel":             "editbudget",
+	"/assetexcel-submit":             "editbudget",
+	"/uploadasset-submit":            "editbudget",
+	"/uploadasset-success":           "editbudget",
+	"/importsgbid":                   "editbudget",
+	"/importsgbid-submit":            "editbudget",
+	"/importsgbid-success":           "editbudget",
+	"/api/rmproject":                 "deleteproject",
+	"/api/rmbgproject":               "deleteproject",
+	"/addclient":                     "manageclient",
+	"/addclient-submit":              "manageclient",
+	"/addclient-success":             "manageclient",
+	"/edit-client":                   "manageclient",
+	"/editclient-submit":             "manageclient",
+	"/editclient-success":            "manageclient",
+	"/api/rmclient":                  "manageclient",
+	"/api/rmartist":                  "editartists",
+	"/api/sgartist":                  "editartists",
+	"/ratecards":                     "manageratecard",
+	"/addratecard":                   "manageratecard",
+	"/addratecard-submit":            "manageratecard",
+	"/addratecard-success":           "manageratecard",
+	"/ratecard-compare":              "manageratecard",
+	"/api/rmratecard":                "manageratecard",
+	"/bgteamsetting":                 "manageteamsetting",
+	"/bgteamsetting-submit":          "manageteamsetting",
+	"/bgteamsetting-success":         "manageteamsetting",
+	"/bgteamsetting-history":         "manageteamsetting",
+	"/bgteamsetting-diff":            "manageteamsetting",
+	"/bgteamsetting-migrate":         "manageteamsetting",
+	"/bgteamsetting-migrate-submit":  "manageteamsetting",
+	"/users":                         "manageusers",
+	"/update-users":                  "manageusers",
+	"/updateusers-success":           "manageusers",
+	"/update-roles":                  "manageusers",
+	"/rmsession":                     "manageusers",
+	"/rmsessions":                    "manageusers",
+	"/unlockuser":                    "manageusers",
+	"/resettotp":                     "manageusers",
+	"/changepassword":                "manageusers",
+	"/changepassword-submit":         "manageusers",
+	"/changepassword-success":        "manageusers",
+	"/api/rmuser":                    "manageusers",
+	"/adminsetting":                  "manageadminsetting",
+	"/adminsetting-submit":           "manageadminsetting",
+	"/adminsetting-success":          "manageadminsetting",
+	"/log":                           "viewlog",
+	"/exportlog":                     "viewlog",
+}
+
+// openRoutes 는 권한 없이 열 수 있는 URL이다. 로그인, 회원가입, 자신의 프로필 페이지와 Shotgun 이벤트 restAPI가 있고, 로그인이 필요한 페이지는 핸들러에서 로그인 여부만 확인한다.
+var openRoutes = map[string]bool{
+	"/assets/":                        true,
+	"/favicon.ico":                    true,
+	"/signup":                         true,
+	"/signup-submit":                  true,
+	"/signup-success":                 true,
+	"/signin":                         true,
+	"/signin-submit":                  true,
+	"/signout":                        true,
+	"/invalidaccess":                  true,
+	"/editprofile":                    true,
+	"/editprofile-submit":             true,
+	"/editprofile-success":            true,
+	"/updatepassword":                 true,
+	"/updatepassword-submit":          true,
+	"/updatepassword-success":         true,
+	"/addapitoken":                    true,
+	"/rmapitoken":                     true,
+	"/enabletotp":                     true,
+	"/disabletotp":                    true,
+	"/api/shotgunevent/humanuser/new": true,
+	"/api/shotgunevent/project/new":   true,
+}
+
+// projectRoutes 는 프로젝트 하나를 보여주거나 수정하는 URL과 프로젝트 ID가 들어있는 폼 키이다. 사용자에게 허용된 프로젝트만 열 수 있다.
+var projectRoutes = map[string]string{
+	"/detail-sm":                     "id",
+	"/bgactual":                      "id",
+	"/episode-actual":                "id",
+	"/episode-timelog-sync":          "id",
+	"/retake":                        "id",
+	"/bg/detail":                     "id",
+	"/edit-projectsm":                "id",
+	"/editprojectsm-submit":          "id",
+	"/editprojectsm-success":         "id",
+	"/episodes-sm":                   "id",
+	"/episodes-sm-submit":            "id",
+	"/edit-bgproject":                "id",
+	"/editbgproject-submit":          "originalid",
+	"/editbgproject-success":         "id",
+	"/bgproject-teamsetting":         "id",
+	"/bgproject-teamsetting-submit":  "id",
+	"/bgproject-teamsetting-success": "id",
+	"/bgrevisions":                   "id",
+	"/bgcompare":                     "id",
+	"/bgapproval":                    "id",
+	"/bgapproval-submit":             "id",
+	"/bgepisodes":                    "id",
+	"/bgepisodes-submit":             "id",
+	"/bgquote":                       "id",
+	"/bgquote-submit":                "id",
+	"/uploadshot":                    "id",
+	"/shotexcel-download":            "id",
+	"/shotexcel-submit":              "id",
+	"/uploadshot-submit":             "id",
+	"/uploadshot-success":            "id",
+	"/detail-shot":                   "id",
+	"/exportdetailshot":              "id",
+	"/uploadasset":                   "id",
+	"/assetexcel-download":           "id",
+	"/assetexcel-submit":             "id",
+	"/uploadasset-submit":            "id",
+	"/uploadasset-success":           "id",
+	"/detail-asset":                  "id",
+	"/exportdetailasset":             "id",
+	"/importsgbid":                   "id",
+	"/importsgbid-submit":            "id",
+	"/importsgbid-success":           "id",
+	"/api/rmproject":                 "id",
+	"/api/rmbgproject":               "id",
+	"/api/rmtimelogbyproject":        "project",
+	"/api/monthlyPayment":            "id",
+	"/api/monthlyPurchaseCost":       "id",
+	"/api/setMonthlyPayment":         "id",
+	"/api/setMonthlyPurchaseCost":    "id",
+}
+
+// projectHandlerRoutes 는 프로젝트 ID가 폼 값에 없어서 핸들러에서 허용 여부를 확인하거나, 허용된 프로젝트만 골라서 보여주는 URL이다.
+var projectHandlerRoutes = map[string]bool{
+	"/":                   true, // 프로젝트 리스트
+	"/search":             true,
+	"/projects":           true,
+	"/searchprojects":     true,
+	"/bgprojects":         true,
+	"/searchbgprojects":   true,
+	"/shotasset":          true,
+	"/searchshotasset":    true,
+	"/exportdetailsm":     true, // 임시 폴더의 엑셀 파일 이름에 프로젝트 ID가 있다.
+	"/exportbgactual":     true,
+	"/bgrevision-restore": true, // 리비전의 프로젝트 ID
+}
+
+// allProjectRoutes 는 여러 프로젝트의 비용을 한번에 보여주거나 수정하는 URL이다. 허용된 프로젝트가 정해진 사용자는 열 수 없다.
+var allProjectRoutes = map[string]bool{
+	"/exportinit":                   true,
+	"/exportprojects":               true,
+	"/exportbgprojects":             true,
+	"/smpayment-status":             true,
+	"/export-smpaymentstatus":       true,
+	"/smvendor-status":              true,
+	"/export-smvendorstatus":        true,
+	"/smtotal-status":               true,
+	"/export-smtotalstatus":         true,
+	"/smdetail-laborcost":           true,
+	"/export-smdetaillaborcost":     true,
+	"/smtotal-laborcost":            true,
+	"/export-smtotallaborcost":      true,
+	"/timelog-vfx":                  true,
+	"/searchtimelog-vfx":            true,
+	"/updatetimelog-vfx":            true,
+	"/timelogvfxexcel-download":     true,
+	"/upload-timelogvfxexcel":       true,
+	"/timelogvfxexcel-submit":       true,
+	"/updatetimelogvfx-submit":      true,
+	"/updatetimelogvfx-success":     true,
+	"/exporttimelog-vfx":            true,
+	"/timelog-cm":                   true,
+	"/searchtimelog-cm":             true,
+	"/updatetimelog-cm":             true,
+	"/timelogcmexcel-download":      true,
+	"/upload-timelogcmexcel":        true,
+	"/timelogcmexcel-submit":        true,
+	"/updatetimelogcm-submit":       true,
+	"/updatetimelogcm-success":      true,
+	"/exporttimelog-cm":             true,
+	"/timelog-total":                true,
+	"/searchtimelog-total":          true,
+	"/exporttimelog-total":          true,
+	"/timelogs-sup":                 true,
+	"/editsuptimelogs-submit":       true,
+	"/editsuptimelogs-success":      true,
+	"/finishedtimelog":              true,
+	"/finishedtimelog-submit":       true,
+	"/ratecards":                    true,
+	"/ratecard-compare":             true,
+	"/bgcapacity":                   true,
+	"/exportbgcapacity":             true,
+	"/bgteamsetting-history":        true,
+	"/bgteamsetting-diff":           true,
+	"/bgteamsetting-migrate":        true,
+	"/bgteamsetting-migrate-submit": true,
+	"/vendors":                      true,
+	"/searchvendors":                true,
+	"/addvendor-page":               true,
+	"/addvendor":                    true,
+	"/addvendor-submit":             true,
+	"/addvendor-success":            true,
+	"/edit-vendor":                  true,
+	"/editvendor-submit":            true,
+	"/editvendor-success":           true,
+	"/exportvendors":                true,
+	"/clients":                      true,
+	"/searchclients":                true,
+	"/client":                       true,
+	"/editclient-submit":            true,
+	"/log":                          true,
+	"/exportlog":                    true,
+	"/api/checkmonthlystatus":       true,
+	"/api/updatetimelog":            true,
+	"/api/rmtimelogbyid":            true,
+	"/api/resettimelog":             true,
+	"/api/updateprojects":           true,
+	"/api/rmvendor":                 true,
+}
+
+// nonProjectRoutes 는 프로젝트와 관계없는 URL이다. 새 URL은 위의 목록 중 하나에 반드시 넣어야 한다.
+var nonProjectRoutes = map[string]bool{
+	"/assets/":                        true,
+	"/favicon.ico":                    true,
+	"/signup":                         true,
+	"/signup-submit":                  true,
+	"/signup-success":                 true,
+	"/signin":                         true,
+	"/signin-submit":                  true,
+	"/signout":                        true,
+	"/invalidaccess":                  true,
+	"/editprofile":                    true,
+	"/editprofile-submit":             true,
+	"/editprofile-success":            true,
+	"/updatepassword":                 true,
+	"/updatepassword-submit":          true,
+	"/updatepassword-success":         true,
+	"/addapitoken":                    true,
+	"/rmapitoken":                     true,
+	"/enabletotp":                     true,
+	"/disabletotp":                    true,
+	"/addratecard":                    true,
+	"/addratecard-submit":             true,
+	"/addratecard-success":            true,
+	"/users":                          true,
+	"/update-users":                   true,
+	"/updateusers-success":            true,
+	"/update-roles":                   true,
+	"/rmsession":                      true,
+	"/rmsessions":                     true,
+	"/unlockuser":                     true,
+	"/resettotp":                      true,
+	"/changepassword":                 true,
+	"/changepassword-submit":          true,
+	"/changepassword-success":         true,
+	"/upload-artistsexcel":            true,
+	"/artists-vfx":                    true,
+	"/edit-artistvfx":                 true,
+	"/editartistvfx-submit":           true,
+	"/editartistvfx-success":          true,
+	"/updateartists-vfx":              true,
+	"/artistsvfxexcel-download":       true,
+	"/artistsvfxexcel-submit":         true,
+	"/updateartistsvfx-submit":        true,
+	"/updateartistsvfx-success":       true,
+	"/exportartists-vfx":              true,
+	"/artists-cm":                     true,
+	"/edit-artistcm":                  true,
+	"/editartistcm-submit":            true,
+	"/editartistcm-success":           true,
+	"/updateartists-cm":               true,
+	"/artistscmexcel-download":        true,
+	"/artistscmexcel-submit":          true,
+	"/updateartistscm-submit":         true,
+	"/updateartistscm-success":        true,
+	"/exportartists-cm":               true,
+	"/addproject":                     true, // 새 프로젝트 추가
+	"/addproject-submit":              true,
+	"/addproject-success":             true,
+	"/addbgproject":                   true,
+	"/addbgproject-submit":            true,
+	"/addbgproject-success":           true,
+	"/upload-shotexcel":               true, // 사용자의 임시 폴더에만 저장한다.
+	"/upload-assetexcel":              true,
+	"/addclient":                      true,
+	"/addclient-submit":               true,
+	"/addclient-success":              true,
+	"/edit-client":                    true,
+	"/editclient-success":             true,
+	"/bgteamsetting":                  true,
+	"/bgteamsetting-submit":           true,
+	"/bgteamsetting-success":          true,
+	"/adminsetting":                   true,
+	"/adminsetting-submit":            true,
+	"/adminsetting-success":           true,
+	"/help":                           true,
+	"/api/rmuser":                     true,
+	"/api/addartistvfx":               true,
+	"/api/addartistcm":                true,
+	"/api/rmartist":                   true,
+	"/api/shotgunevent/humanuser/new": true,
+	"/api/shotgunevent/project/new":   true,
+	"/api/rmclient":                   true,
+	"/api/rmratecard":                 true,
+	"/api/sgartist":                   true,
+	"/api/vfxteams":                   true,
+	"/api/totalteams":                 true,
+}
+
+// getPermissionsFunc 함수는 사용자가 가진 권한 ID 리스트를 반환하는 함수이다.
+// 역할이 없는 사용자는 액세스 레벨에 따라 권한을 갖는다. Admin은 모든 권한을 갖지만, 역할이 있으면 민감한 권한은 역할에 포함되어야 갖는다.
+func getPermissionsFunc(u User, role Role) []string {
+	var results []string
+	for _, p := range Permissions {
+		switch {
+		case u.AccessLevel == AdminLevel && (u.Role == "" || !p.Sensitive):
+			results = append(results, p.ID)
+		case u.Role == "":
+			if u.AccessLevel >= p.DefaultLevel {
+				results = append(results, p.ID)
+			}
+		default:
+			if checkStringInListFunc(p.ID, role.Permissions) {
+				results = append(results, p.ID)
+			}
+		}
+	}
+	return results
+}
+
+// hasPermissionFunc 함수는 사용자가 권한을 가졌는지 확인하는 함수이다.
+func hasPermissionFunc(u User, role Role, permission string) bool {
+	return checkStringInListFunc(permission, getPermissionsFunc(u, role))
+}
+
+// checkPermissionFunc 함수는 DB에서 사용자의 역할을 가져와 권한을 가졌는지 확인하는 함수이다. 역할이 삭제되었으면 권한이 없다.
+func checkPermissionFunc(client *mongo.Client, u User, permission string) (bool, error) {
+	role := Role{}
+	if u.Role != "" {
+		var err error
+		role, err = getRoleFunc(client, u.Role)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return false, err
+		}
+	}
+	return hasPermissionFunc(u, role, permission), nil
+}
+
+// isProjectScopedFunc 함수는 사용자가 허용된 프로젝트만 볼 수 있는지 확인하는 함수이다. Admin과 허용된 프로젝트가 정해지지 않은 사용자는 모든 프로젝트를 볼 수 있다.
+func isProjectScopedFunc(u User) bool {
+	return u.AccessLevel != AdminLevel && len(u.Projects) != 0
+}
+
+// hasProjectGrantFunc 함수는 사용자가 프로젝트를 볼 수 있는지 확인하는 함수이다.
+func hasProjectGrantFunc(u User, project string) bool {
+	if !isProjectScopedFunc(u) {
+		return true
+	}
+	return checkStringInListFunc(project, u.Projects)
+}
+
+// checkProjectGrantFunc 함수는 DB에서 사용자 정보를 가져와 프로젝트를 볼 수 있는지 확인하는 함수이다.
+// 프로젝트 ID가 폼 값에 없어서 permissionMiddlewareFunc에서 확인할 수 없는 핸들러에서 사용한다.
+func checkProjectGrantFunc(client *mongo.Client, userID string, project string) (bool, error) {
+	u, err := getUserFunc(client, userID)
+	if err != nil {
+		return false, err
+	}
+	return hasProjectGrantFunc(u, project), nil
+}
+
+// filterProjectsByGrantFunc 함수는 프로젝트 리스트에서 사용자가 볼 수 있는 프로젝트만 반환하는 함수이다.
+func filterProjectsByGrantFunc(u User, projects []Project) []Project {
+	if !isProjectScopedFunc(u) {
+		return projects
+	}
+	var results []Project
+	for _, p := range projects {
+		if hasProjectGrantFunc(u, p.ID) {
+			results = append(results, p)
+		}
+	}
+	return results
+}
+
+// filterBGProjectsByGrantFunc 함수는 예산 프로젝트 리스트에서 사용자가 볼 수 있는 프로젝트만 반환하는 함수이다.
+func filterBGProjectsByGrantFunc(u User, bgprojects []BGProject) []BGProject {
+	if !isProjectScopedFunc(u) {
+		return bgprojects
+	}
+	var results []BGProject
+	for _, p := range bgprojects {
+		if hasProjectGrantFunc(u, p.ID) {
+			results = append(results, p)
+		}
+	}
+	return results
+}
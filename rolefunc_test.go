@@ -0,0 +1,55 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 역할, 권한 테스트 스크립트
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 사용자의 레벨과 역할에 따라 권한을 계산하는지 테스트하기 위한 함수
+func Test_getPermissions(t *testing.T) {
+	producer := Role{Name: "PD", Permissions: []string{"approvebudget", "viewsalary"}}
+	cases := []struct {
+		user User
+		role Role
+		want []string
+	}{
+		{user: User{AccessLevel: DefaultLevel}, want: []string{"viewmain", "viewtimelog", "uploadtimelog"}},
+		{user: User{AccessLevel: ManagerLevel}, want: []string{"editpayment", "managevendor", "approvebudget", "viewmain", "viewtimelog", "uploadtimelog", "edittimelog", "viewstatus", "viewprojects", "editproject", "editbudget", "manageclient", "viewlog"}},
+		{user: User{AccessLevel: AdminLevel, Role: "PD"}, role: producer, want: []string{"viewsalary", "editpayment", "managevendor", "approvebudget", "manageapproval", "timelogsync", "closemonth", "viewartists", "viewlaborcost", "viewmain", "viewtimelog", "uploadtimelog", "edittimelog", "deletetimelog", "editsuptimelog", "viewstatus", "viewactual", "exportdata", "viewprojects", "editproject", "editbudget", "deleteproject", "manageclient", "editartists", "manageratecard", "manageteamsetting", "manageusers", "manageadminsetting", "viewlog", "viewinvoice"}},
+		{user: User{AccessLevel: ManagerLevel, Role: "PD"}, role: producer, want: []string{"viewsalary", "approvebudget"}},
+		{user: User{AccessLevel: ManagerLevel, Role: "PD"}, role: Role{}, want: nil}, // 삭제된 역할
+		{user: User{AccessLevel: GuestLevel}, want: nil},
+		{user: User{AccessLevel: AdminLevel}, want: []string{"viewsalary", "editpayment", "managevendor", "approvebudget", "manageapproval", "timelogsync", "closemonth", "viewartists", "viewlaborcost", "viewmain", "viewtimelog", "uploadtimelog", "edittimelog", "deletetimelog", "editsuptimelog", "viewstatus", "viewactual", "exportdata", "viewprojects", "editproject", "editbudget", "deleteproject", "manageclient", "editartists", "manageratecard", "manageteamsetting", "manageusers", "manageadminsetting", "viewlog", "viewinvoice"}},
+		{user: User{AccessLevel: AdminLevel, Role: "PM"}, role: Role{Name: "PM"}, want: []string{"editpayment", "managevendor", "approvebudget", "manageapproval", "timelogsync", "closemonth", "viewartists", "viewlaborcost", "viewmain", "viewtimelog", "uploadtimelog", "edittimelog", "deletetimelog", "editsuptimelog", "viewstatus", "viewactual", "exportdata", "viewprojects", "editproject", "editbudget", "deleteproject", "manageclient", "editartists", "manageratecard", "manageteamsetting", "manageusers", "manageadminsetting", "viewlog", "viewinvoice"}}, // 급여 보기는 역할에 있어야 한다.
+	}
+	for _, c := range cases {
+		got := getPermissionsFunc(c.user, c.role)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Test_getPermissions(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.user, c.role, c.want, got)
+		}
+	}
+}
+
+// 허용된 프로젝트만 볼 수 있는지 테스트하기 위한 함수
+func Test_filterProjectsByGrant(t *testing.T) {
+	projects := []Project{{ID: "ABC"}, {ID: "DEF"}, {ID: "GHI"}}
+	cases := []struct {
+		user User
+		want []Project
+	}{
+		{user: User{AccessLevel: MemberLevel}, want: projects},
+		{user: User{AccessLevel: MemberLevel, Projects: []string{"GHI", "ABC"}}, want: []Project{{ID: "ABC"}, {ID: "GHI"}}},
+		{user: User{AccessLevel: MemberLevel, Projects: []string{"XYZ"}}, want: nil},
+		{user: User{AccessLevel: AdminLevel, Projects: []string{"XYZ"}}, want: projects},
+	}
+	for _, c := range cases {
+		got := filterProjectsByGrantFunc(c.user, projects)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Test_filterProjectsByGrant(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.user, c.want, got)
+		}
+	}
+}
@@ -6,9 +6,13 @@ package main
 
 import (
 	"math"
+	"sort"
 	"strconv"
 )
 
+// SalaryMaskTeam 은 팀 정보가 없는 아티스트의 인건비를 합칠 때 사용하는 팀 이름이다.
+const SalaryMaskTeam = "(팀 없음)"
+
 // realMonthlySalaryFunc 함수는 실제 급여를 계산하여 반환하는 함수이다.
 func realMonthlySalaryFunc(salary string, whole int, days int) (float64, error) {
 	if salary == "" {
@@ -49,3 +53,47 @@ func allMonthlySalaryFunc(salary string, count int) (float64, error) {
 
 	return monthlySalary * float64(count), nil
 }
+
+// artistsExcelFileNameFunc 함수는 team(vfx, cm) 아티스트 엑셀 파일 이름을 반환하는 함수이다. 연봉 정보가 없는 파일은 이름 끝에 _nosalary를 붙인다.
+func artistsExcelFileNameFunc(team string, withSalary bool) string {
+	if !withSalary {
+		return team + "_artists_nosalary.xlsx"
+	}
+	return team + "_artists.xlsx"
+}
+
+// maskArtistsSalaryFunc 함수는 급여 보기 권한이 없는 사용자에게 보여주기 위해 아티스트의 연봉 정보를 지우는 함수이다.
+func maskArtistsSalaryFunc(artists []Artist) []Artist {
+	var results []Artist
+	for _, a := range artists {
+		a.Salary = nil
+		a.Changed = false
+		a.ChangedSalary = nil
+		results = append(results, a)
+	}
+	return results
+}
+
+// sumLaborCostByTeamFunc 함수는 아티스트별 프로젝트 인건비를 팀별로 합치는 함수이다.
+// 급여 보기 권한이 없는 사용자에게 보여주기 위해 팀 이름을 ID와 이름으로 갖는 Artist 리스트와 팀별 인건비를 반환한다.
+func sumLaborCostByTeamFunc(artists []Artist, detail map[string]map[string]int) ([]Artist, map[string]map[string]int) {
+	var teams []Artist
+	results := make(map[string]map[string]int)
+	for _, a := range artists {
+		team := a.Team
+		if team == "" {
+			team = SalaryMaskTeam
+		}
+		if _, ok := results[team]; !ok {
+			results[team] = make(map[string]int)
+			teams = append(teams, Artist{ID: team, Name: team, Dept: a.Dept, Team: team})
+		}
+		for project, cost := range detail[a.ID] {
+			results[team][project] += cost
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+	return teams, results
+}
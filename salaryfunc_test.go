@@ -0,0 +1,48 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 급여 관련 테스트 스크립트
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 아티스트별 인건비를 팀별로 합치는지 테스트하기 위한 함수
+func Test_sumLaborCostByTeam(t *testing.T) {
+	artists := []Artist{
+		{ID: "1", Name: "김민수", Dept: "VFX", Team: "FX"},
+		{ID: "2", Name: "이지은", Dept: "VFX", Team: "Comp"},
+		{ID: "3", Name: "박서준", Dept: "VFX", Team: "FX"},
+		{ID: "4", Name: "최유리", Dept: "VFX"},
+	}
+	detail := map[string]map[string]int{
+		"1": {"ABC": 100, "DEF": 50},
+		"2": {"ABC": 30},
+		"3": {"ABC": 20},
+		"4": {"DEF": 10},
+	}
+	wantTeams := []Artist{
+		{ID: SalaryMaskTeam, Name: SalaryMaskTeam, Dept: "VFX", Team: SalaryMaskTeam},
+		{ID: "Comp", Name: "Comp", Dept: "VFX", Team: "Comp"},
+		{ID: "FX", Name: "FX", Dept: "VFX", Team: "FX"},
+	}
+	wantDetail := map[string]map[string]int{
+		"FX":           {"ABC": 120, "DEF": 50},
+		"Comp":         {"ABC": 30},
+		SalaryMaskTeam: {"DEF": 10},
+	}
+	teams, got := sumLaborCostByTeamFunc(artists, detail)
+	if !reflect.DeepEqual(teams, wantTeams) {
+		t.Fatalf("Test_sumLaborCostByTeam(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", artists, wantTeams, teams)
+	}
+	if !reflect.DeepEqual(got, wantDetail) {
+		t.Fatalf("Test_sumLaborCostByTeam(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", detail, wantDetail, got)
+	}
+
+	masked := maskArtistsSalaryFunc([]Artist{{ID: "1", Salary: map[string]string{"2020": "x"}, Changed: true, ChangedSalary: map[string]string{"2020": "y"}}})
+	if masked[0].Salary != nil || masked[0].Changed || masked[0].ChangedSalary != nil {
+		t.Fatalf("Test_sumLaborCostByTeam(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "연봉 정보", "nil", masked[0])
+	}
+}
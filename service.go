@@ -5,11 +5,9 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"log"
-	"net/smtp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -40,6 +38,23 @@ func serviceFunc() {
 		sendMailForVendorFunc()
 	})
 
+	// 매일 오전 10시에 입금 기한이 지난 매출을 확인하여 메일을 보내는 서비스
+	c.AddFunc("0 10 * * *", func() {
+		log.Println("매출 입금 기한 초과 메일 서비스 실행")
+		sendMailForOverdueFunc()
+	})
+
+	// 5분마다 outbox에서 전송하지 못한 알림을 다시 전송하는 서비스
+	c.AddFunc("@every 5m", func() {
+		sendNotificationsServiceFunc()
+	})
+
+	// 매일 새벽 3시에 보관 기간이 지난 로그를 파일로 저장하고 삭제하는 서비스
+	c.AddFunc("0 3 * * *", func() {
+		log.Println("로그 보관 서비스 실행")
+		archiveLogsServiceFunc()
+	})
+
 	c.Start()
 }
 
@@ -86,6 +101,28 @@ func setResinationFunc() {
 		duration := time.Now().Sub(t).Hours() / 24
 		if duration > 0 {
 			err = setArtistFunc(client, artist)
+			if err != nil {
+				log.Print(err)
+				continue
+			}
+			after, err := getArtistFunc(client, artist.ID)
+			if err != nil {
+				log.Print(err)
+				continue
+			}
+			changes := diffEntityFunc(artist, after)
+			if len(changes) == 0 {
+				continue
+			}
+			err = addLogsFunc(client, Log{
+				UserID:     LogServiceActor,
+				CreatedAt:  time.Now(),
+				Content:    fmt.Sprintf("아티스트 ID %s의 퇴사 여부가 업데이트되었습니다.", artist.ID),
+				Action:     LogActionUpdate,
+				EntityType: LogEntityArtist,
+				EntityID:   artist.ID,
+				Changes:    changes,
+			})
 			if err != nil {
 				log.Print(err)
 			}
@@ -103,30 +140,27 @@ func sendMailForVendorFunc() {
 	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
 	if err != nil {
 		log.Print(err)
+		return
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	err = client.Connect(ctx)
 	if err != nil {
 		log.Print(err)
+		return
 	}
 	defer client.Disconnect(ctx)
 	err = client.Ping(ctx, readpref.Primary())
 	if err != nil {
 		log.Print(err)
-	}
-
-	if *flagDBIP != "" {
-		// 입력받은 DB IP의 형식이 맞는지 확인
-		if !regexIPv4.MatchString(*flagDBIP) {
-			log.Print(err)
-		}
+		return
 	}
 
 	// 벤더 비용이 오늘인 벤더 정보를 가져온다.
 	vendors, err := getVendorsByTodayFunc(client)
 	if err != nil {
 		log.Print(err)
+		return
 	}
 	if len(vendors) == 0 { // 벤더 정보가 없는 경우 서비스를 리턴한다.
 		return
@@ -136,56 +170,56 @@ func sendMailForVendorFunc() {
 	adminsetting, err := getAdminSettingFunc(client)
 	if err != nil {
 		log.Print(err)
-	}
-	if len(adminsetting.GWIDs) == 0 { // 메일을 보낼 그룹웨어 ID 정보가 없는 경우 서비스를 리턴한다.
 		return
 	}
 
-	// 메시지 설정
-	from := "BUDGET"
-	to := adminsetting.GWIDs
-
-	smtpHost := "gw.rd101.co.kr"
-	smtpPort := "25"
-
-	var msg []byte
-	var body bytes.Buffer
-
-	// Set Header
-	headerSubject := "Subject: " + encodeRFC2047Func("[BUDGET] 벤더 세금계산서 발행일 알람") + "\r\n"
-	headerFrom := "From: BUDGET\r\n"
-	headerTo := "To: " + listToStringFunc(to, false) + "\r\n"
-	msg = append(msg, []byte(headerSubject+headerFrom+headerTo)...)
+	// admin setting의 그룹웨어 ID와 프로젝트별로 알림을 구독한 사용자에게 메일을 보낸다.
+	var projects []string
+	for _, v := range vendors {
+		if !checkStringInListFunc(v.Project, projects) {
+			projects = append(projects, v.Project)
+		}
+	}
+	groups, err := getRecipientGroupsFunc(client, EventInvoiceToday, projects, adminsetting.GWIDs)
+	if err != nil {
+		log.Print(err)
+		return
+	}
 
-	// Set Body
 	type Recipe struct {
 		Vendors []Vendor
 		Date    string
 	}
-	rcp := Recipe{}
-	rcp.Vendors = vendors
-	rcp.Date = time.Now().Format("2006-01-02") // 오늘 날짜
-
-	mimeHeaders := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-	body.Write([]byte(fmt.Sprintf("%s", mimeHeaders)))
-
-	// 템플릿 로딩
-	vfsTemplate, err := loadTemplatesFunc()
-	if err != nil {
-		log.Fatal(err)
+	// 프로젝트 리스트에 해당하는 벤더 정보로 메일 내용을 만든다.
+	recipeFunc := func(projectIDs []string) Recipe {
+		rcp := Recipe{}
+		rcp.Date = time.Now().Format("2006-01-02") // 오늘 날짜
+		for _, v := range vendors {
+			if checkStringInListFunc(v.Project, projectIDs) {
+				rcp.Vendors = append(rcp.Vendors, v)
+			}
+		}
+		return rcp
+	}
+	subject := "[BUDGET] 벤더 세금계산서 발행일 알람"
+	for _, group := range groups {
+		err = addNotificationByTemplateFunc(client, EventInvoiceToday, group.To, subject, "mail-vendor", recipeFunc(group.Projects))
+		if err != nil {
+			log.Print(err)
+			return
+		}
 	}
-	TEMPLATES = vfsTemplate
-	err = TEMPLATES.ExecuteTemplate(&body, "mail-vendor", rcp)
+
+	// 채팅 webhook으로는 모든 벤더 정보를 보낸다.
+	err = addWebhookNotificationByTemplateFunc(client, EventInvoiceToday, subject, "mail-vendor", recipeFunc(projects))
 	if err != nil {
-		log.Fatal(err)
+		log.Print(err)
 		return
 	}
-	msg = append(msg, body.Bytes()...)
 
-	// 메시지 보내기
-	err = smtp.SendMail(smtpHost+":"+smtpPort, nil, from, to, msg)
+	err = sendNotificationsFunc(client)
 	if err != nil {
-		log.Println(err)
+		log.Print(err)
 	}
 }
 
@@ -199,29 +233,26 @@ func sendMailForProjectFunc() {
 	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
 	if err != nil {
 		log.Print(err)
+		return
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	err = client.Connect(ctx)
 	if err != nil {
 		log.Print(err)
+		return
 	}
 	defer client.Disconnect(ctx)
 	err = client.Ping(ctx, readpref.Primary())
 	if err != nil {
 		log.Print(err)
-	}
-
-	if *flagDBIP != "" {
-		// 입력받은 DB IP의 형식이 맞는지 확인
-		if !regexIPv4.MatchString(*flagDBIP) {
-			log.Print(err)
-		}
+		return
 	}
 
 	projects, err := getProjectsByTodayFunc(client)
 	if err != nil {
 		log.Print(err)
+		return
 	}
 	if len(projects) == 0 { // 프로젝트 정보가 없는 경우 서비스를 리턴한다.
 		return
@@ -231,28 +262,20 @@ func sendMailForProjectFunc() {
 	adminsetting, err := getAdminSettingFunc(client)
 	if err != nil {
 		log.Print(err)
-	}
-	if len(adminsetting.GWIDsForProject) == 0 { // 메일을 보낼 그룹웨어 ID 정보가 없는 경우 서비스를 리턴한다.
 		return
 	}
 
-	// 메시지 설정
-	from := "BUDGET"
-	to := adminsetting.GWIDsForProject
-
-	smtpHost := "gw.rd101.co.kr"
-	smtpPort := "25"
-
-	var msg []byte
-	var body bytes.Buffer
-
-	// Set Header
-	headerSubject := "Subject: " + encodeRFC2047Func("[BUDGET] 프로젝트 세금계산서 발행일 알람") + "\r\n"
-	headerFrom := "From: BUDGET\r\n"
-	headerTo := "To: " + listToStringFunc(to, false) + "\r\n"
-	msg = append(msg, []byte(headerSubject+headerFrom+headerTo)...)
+	// admin setting의 그룹웨어 ID와 프로젝트별로 알림을 구독한 사용자에게 메일을 보낸다.
+	var projectIDs []string
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	groups, err := getRecipientGroupsFunc(client, EventInvoiceToday, projectIDs, adminsetting.GWIDsForProject)
+	if err != nil {
+		log.Print(err)
+		return
+	}
 
-	// Set Body
 	type ProjectInfo struct {
 		Name string   // 프로젝트 이름
 		Type []string // 발행일이 오늘인 매출의 타입
@@ -262,42 +285,237 @@ func sendMailForProjectFunc() {
 		Projects []ProjectInfo
 		Date     string
 	}
-	rcp := Recipe{}
-	rcp.Date = time.Now().Format("2006-01-02") // 오늘 날짜
-	month := dateToMonthFunc(rcp.Date)
+	date := time.Now().Format("2006-01-02") // 오늘 날짜
+	month := dateToMonthFunc(date)
+	// 프로젝트 리스트에 해당하는 프로젝트 정보로 메일 내용을 만든다.
+	recipeFunc := func(ids []string) Recipe {
+		rcp := Recipe{}
+		rcp.Date = date
+		for _, project := range projects {
+			if !checkStringInListFunc(project.ID, ids) {
+				continue
+			}
+			var typeList []string
+			for _, payment := range project.SMMonthlyPayment[month] {
+				if payment.Date == rcp.Date {
+					typeList = append(typeList, payment.Type)
+				}
+			}
+			pi := ProjectInfo{
+				Name: project.Name,
+				Type: typeList,
+			}
+			rcp.Projects = append(rcp.Projects, pi)
+		}
+		return rcp
+	}
+	subject := "[BUDGET] 프로젝트 세금계산서 발행일 알람"
+	for _, group := range groups {
+		err = addNotificationByTemplateFunc(client, EventInvoiceToday, group.To, subject, "mail-project", recipeFunc(group.Projects))
+		if err != nil {
+			log.Print(err)
+			return
+		}
+	}
+
+	// 채팅 webhook으로는 모든 프로젝트 정보를 보낸다.
+	err = addWebhookNotificationByTemplateFunc(client, EventInvoiceToday, subject, "mail-project", recipeFunc(projectIDs))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	err = sendNotificationsFunc(client)
+	if err != nil {
+		log.Print(err)
+	}
+}
+
+// sendMailForOverdueFunc 함수는 발행일이 지났는데 입금되지 않은 매출을 확인하여 구독한 사용자에게 메일을 보내는 함수이다.
+// 입금 기한이 지난 다음날과 그 후 일주일마다 메일을 보낸다.
+func sendMailForOverdueFunc() {
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	projects, err := getAllProjectsFunc(client)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	type OverdueInfo struct {
+		ProjectID string // 프로젝트 ID
+		Name      string // 프로젝트 이름
+		Type      string // 매출 타입
+		Date      string // 세금계산서 발행일
+		Days      int    // 발행일로부터 지난 일수
+	}
+
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	var overdues []OverdueInfo
+	var projectIDs []string
 	for _, project := range projects {
-		var typeList []string
-		for _, payment := range project.SMMonthlyPayment[month] {
-			if payment.Date == rcp.Date {
-				typeList = append(typeList, payment.Type)
+		if project.IsFinished {
+			continue
+		}
+		for _, monthlyPayment := range project.SMMonthlyPayment {
+			for _, payment := range monthlyPayment {
+				if payment.Status || payment.Date == "" {
+					continue
+				}
+				date, err := time.Parse("2006-01-02", payment.Date)
+				if err != nil {
+					continue
+				}
+				days := int(today.Sub(date).Hours() / 24)
+				if days < 1 || (days != 1 && days%7 != 0) {
+					continue
+				}
+				overdues = append(overdues, OverdueInfo{
+					ProjectID: project.ID,
+					Name:      project.Name,
+					Type:      payment.Type,
+					Date:      payment.Date,
+					Days:      days,
+				})
+				if !checkStringInListFunc(project.ID, projectIDs) {
+					projectIDs = append(projectIDs, project.ID)
+				}
 			}
 		}
-		pi := ProjectInfo{
-			Name: project.Name,
-			Type: typeList,
+	}
+	if len(overdues) == 0 { // 입금 기한이 지난 매출이 없는 경우 서비스를 리턴한다.
+		return
+	}
+
+	groups, err := getRecipientGroupsFunc(client, EventPaymentOverdue, projectIDs, nil)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	type Recipe struct {
+		Overdues []OverdueInfo
+		Date     string
+	}
+	// 프로젝트 리스트에 해당하는 매출 정보로 메일 내용을 만든다.
+	recipeFunc := func(ids []string) Recipe {
+		rcp := Recipe{}
+		rcp.Date = time.Now().Format("2006-01-02") // 오늘 날짜
+		for _, o := range overdues {
+			if checkStringInListFunc(o.ProjectID, ids) {
+				rcp.Overdues = append(rcp.Overdues, o)
+			}
+		}
+		return rcp
+	}
+	subject := "[BUDGET] 매출 입금 기한 초과 알람"
+	for _, group := range groups {
+		err = addNotificationByTemplateFunc(client, EventPaymentOverdue, group.To, subject, "mail-overdue", recipeFunc(group.Projects))
+		if err != nil {
+			log.Print(err)
+			return
 		}
-		rcp.Projects = append(rcp.Projects, pi)
 	}
 
-	mimeHeaders := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-	body.Write([]byte(fmt.Sprintf("%s", mimeHeaders)))
+	// 채팅 webhook으로는 모든 매출 정보를 보낸다.
+	err = addWebhookNotificationByTemplateFunc(client, EventPaymentOverdue, subject, "mail-overdue", recipeFunc(projectIDs))
+	if err != nil {
+		log.Print(err)
+		return
+	}
 
-	// 템플릿 로딩
-	vfsTemplate, err := loadTemplatesFunc()
+	err = sendNotificationsFunc(client)
 	if err != nil {
-		log.Fatal(err)
+		log.Print(err)
 	}
-	TEMPLATES = vfsTemplate
-	err = TEMPLATES.ExecuteTemplate(&body, "mail-project", rcp)
+}
+
+// sendNotificationsServiceFunc 함수는 outbox에 남아있는 알림을 다시 전송하는 함수이다.
+func sendNotificationsServiceFunc() {
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	err = sendNotificationsFunc(client)
+	if err != nil {
+		log.Print(err)
+	}
+}
+
+// archiveLogsServiceFunc 함수는 보관 기간이 지난 로그를 파일로 저장하고 DB에서 삭제하는 함수이다.
+func archiveLogsServiceFunc() {
+	// mongoDB client 연결
+	credential := options.Credential{
+		Username: *flagDBID,
+		Password: *flagDBPW,
+	}
+	client, err := mongo.NewClient(options.Client().ApplyURI(*flagMongoDBURI).SetAuth(credential))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = client.Connect(ctx)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer client.Disconnect(ctx)
+	err = client.Ping(ctx, readpref.Primary())
 	if err != nil {
-		log.Fatal(err)
+		log.Print(err)
 		return
 	}
-	msg = append(msg, body.Bytes()...)
 
-	// 메시지 보내기
-	err = smtp.SendMail(smtpHost+":"+smtpPort, nil, from, to, msg)
+	path, num, err := archiveOldLogsFunc(client, LogServiceActor)
 	if err != nil {
-		log.Println(err)
+		log.Print(err)
+		return
+	}
+	if path != "" {
+		log.Printf("로그 %d개를 %s 파일에 저장하고 삭제하였습니다.", num, path)
 	}
 }
@@ -0,0 +1,122 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 로그인 세션 관련 스크립트
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// newSessionIDFunc 함수는 세션 ID로 사용할 임의의 문자열을 만드는 함수이다.
+func newSessionIDFunc() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// signSessionTokenFunc 함수는 세션 정보로 세션 토큰(JWT)을 만들어 서버 키로 서명하는 함수이다.
+func signSessionTokenFunc(s Session, key []byte) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Token{
+		ID:          s.UserID,
+		AccessLevel: s.Level,
+		ToolName:    "budget",
+		StandardClaims: jwt.StandardClaims{
+			Id:        s.ID,
+			IssuedAt:  s.CreatedAt.Unix(),
+			ExpiresAt: s.ExpiresAt.Unix(),
+		},
+	})
+	return token.SignedString(key)
+}
+
+// parseSessionTokenFunc 함수는 세션 토큰(JWT)을 서버 키로 열어 Token을 반환하는 함수이다. 만료 시간과 세션 ID가 없는 토큰은 사용할 수 없다.
+func parseSessionTokenFunc(tokenString string, key []byte) (Token, error) {
+	tk := Token{}
+	token, err := jwt.ParseWithClaims(tokenString, &tk, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("Token signing method is not valid")
+		}
+		return key, nil
+	})
+	if err != nil {
+		return tk, err
+	}
+	if !token.Valid {
+		return tk, errors.New("Token key is not valid")
+	}
+	if tk.ToolName != "budget" {
+		return tk, errors.New("Token key is not for budget")
+	}
+	if tk.ExpiresAt == 0 {
+		return tk, errors.New("Token에 만료 시간이 없습니다")
+	}
+	if tk.Id == "" {
+		return tk, errors.New("Token에 세션 ID가 없습니다")
+	}
+	return tk, nil
+}
+
+// getSessionTokenFromCookieFunc 함수는 쿠키에서 세션 토큰 문자열을 가져오는 함수이다.
+func getSessionTokenFromCookieFunc(r *http.Request) string {
+	cookie, err := r.Cookie("SessionToken")
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// setSessionCookieFunc 함수는 세션 토큰을 쿠키에 저장하는 함수이다. 스크립트에서 읽을 수 없도록 HttpOnly로 저장하고, 예전 방식의 SessionSignKey 쿠키는 삭제한다.
+func setSessionCookieFunc(w http.ResponseWriter, tokenString string, expires time.Time) {
+	http.SetCookie(w, newCookieFunc("SessionToken", tokenString, expires, 0, true))
+	http.SetCookie(w, newCookieFunc("SessionSignKey", "", time.Time{}, -1, true))
+}
+
+// clearSessionCookieFunc 함수는 쿠키에 저장된 세션 토큰을 삭제하는 함수이다.
+func clearSessionCookieFunc(w http.ResponseWriter) {
+	http.SetCookie(w, newCookieFunc("SessionToken", "", time.Time{}, -1, true))
+	http.SetCookie(w, newCookieFunc("SessionSignKey", "", time.Time{}, -1, true))
+}
+
+// startSessionFunc 함수는 사용자의 로그인 세션을 DB에 추가하고 세션 토큰을 쿠키에 저장하는 함수이다.
+func startSessionFunc(client *mongo.Client, w http.ResponseWriter, r *http.Request, u User) error {
+	key, err := sessionSignKeyFunc()
+	if err != nil {
+		return err
+	}
+	id, err := newSessionIDFunc()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	s := Session{
+		ID:        id,
+		UserID:    u.ID,
+		Level:     u.AccessLevel,
+		IP:        clientIPFunc(r),
+		UserAgent: r.UserAgent(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(*flagCookieAge) * time.Hour),
+	}
+	tokenString, err := signSessionTokenFunc(s, key)
+	if err != nil {
+		return err
+	}
+	err = addSessionFunc(client, s)
+	if err != nil {
+		return err
+	}
+	setSessionCookieFunc(w, tokenString, s.ExpiresAt)
+	setCSRFCookieFunc(w, csrfTokenFunc(s.ID, key))
+	return nil
+}
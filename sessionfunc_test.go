@@ -0,0 +1,57 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 로그인 세션 테스트 스크립트
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// 세션 토큰을 서버 키로 서명하고 열 수 있는지 테스트하기 위한 함수
+func Test_parseSessionToken(t *testing.T) {
+	key := []byte("server key")
+	now := time.Now()
+	valid := Session{ID: "abc", UserID: "user", Level: ManagerLevel, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	expired := Session{ID: "abc", UserID: "user", Level: ManagerLevel, CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	noID := Session{UserID: "user", Level: ManagerLevel, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	// 만료 시간이 없는 예전 방식의 토큰
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Token{ID: "user", AccessLevel: ManagerLevel, ToolName: "budget"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		session Session
+		signKey []byte
+		token   string
+		want    bool // 토큰을 열 수 있어야 하는지 여부
+	}{
+		{name: "valid", session: valid, signKey: key, want: true},
+		{name: "expired", session: expired, signKey: key, want: false},
+		{name: "wrong key", session: valid, signKey: []byte("other key"), want: false},
+		{name: "no id", session: noID, signKey: key, want: false},
+		{name: "legacy", token: legacy, want: false},
+	}
+	for _, c := range cases {
+		tokenString := c.token
+		if tokenString == "" {
+			tokenString, err = signSessionTokenFunc(c.session, c.signKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		tk, err := parseSessionTokenFunc(tokenString, key)
+		if (err == nil) != c.want {
+			t.Fatalf("Test_parseSessionToken(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.name, c.want, err)
+		}
+		if c.want && (tk.ID != c.session.UserID || tk.Id != c.session.ID || tk.AccessLevel != c.session.Level) {
+			t.Fatalf("Test_parseSessionToken(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", c.name, c.session, tk)
+		}
+	}
+}
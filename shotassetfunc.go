@@ -0,0 +1,259 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 샷, 어셋 관련 스크립트
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// getBGTasksByTypeFunc 함수는 예산 팀세팅에서 샷 또는 어셋 관련 태스크 리스트를 가져오는 함수이다.
+func getBGTasksByTypeFunc(ts BGTeamSetting, asset bool) []string {
+	var tasks []string
+	for _, deptList := range ts.Departments {
+		for _, dept := range deptList {
+			if dept.Type != asset {
+				continue
+			}
+			for _, part := range dept.Parts {
+				for _, task := range part.Tasks {
+					if !checkStringInListFunc(task, tasks) {
+						tasks = append(tasks, task)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(tasks)
+	return tasks
+}
+
+// calTotalBidFunc 함수는 샷, 어셋 리스트의 태스크별 bid 합계를 계산하는 함수이다.
+func calTotalBidFunc(list []BGShotAsset) map[string]float64 {
+	totalBid := make(map[string]float64)
+	for _, item := range list {
+		for task, bid := range item.Manday {
+			totalBid[task] += bid
+		}
+	}
+	return totalBid
+}
+
+// setLaborCostByBidFunc 함수는 태스크별 bid를 기반으로 예산안의 샷 또는 어셋 관련 부서 비용을 계산하여 업데이트하는 함수이다.
+// 다른 유형의 부서 비용과 매니지먼트 비용은 그대로 유지한다.
+func setLaborCostByBidFunc(client *mongo.Client, typedata *BGTypeData, totalBid map[string]float64, asset bool) error {
+	// 예산안에 고정된 단가표의 단가로 태스크별 비용을 정리한다.
+	taskCost, err := calTaskCostByRateCardFunc(client, typedata, totalBid, asset)
+	if err != nil {
+		return err
+	}
+
+	// 현재 예산안의 팀세팅 정보를 기반으로 본부별 부서 비용을 정리한다.
+	costByHead := make(map[string]map[string]float64)
+	var typeDept []string // 샷 또는 어셋 관련 부서
+	for head, deptList := range typedata.TeamSetting.Departments {
+		costByHead[head] = make(map[string]float64)
+		for _, dept := range deptList {
+			if dept.Type == asset {
+				typeDept = append(typeDept, dept.Name)
+			}
+		}
+	}
+	// 태스크 비용은 단가를 가져온 본부의 부서 하나에만 더한다.
+	for task, cost := range taskCost {
+		head, dept := getTaskDeptFunc(typedata.TeamSetting, task, asset)
+		if head == "" {
+			continue
+		}
+		costByHead[head][dept] += cost
+	}
+
+	// 기존 예산안이 있는지 확인후 비교하여 예산안 비용을 암호화하여 업데이트한다.
+	bglsList := []BGLaborCost{}
+	for head, costByDept := range costByHead {
+		bgls := BGLaborCost{}
+		bgls.Headquarter = head
+		for _, ls := range typedata.LaborCosts {
+			if ls.Headquarter == head { // 기존에 저장된 본부의 비용의 경우
+				bgls = ls
+				break
+			}
+		}
+
+		// 이미 부서별 비용이 있는 경우 -> 같은 유형의 부서 비용만 초기화
+		if bgls.DepartmentCost == nil {
+			bgls.DepartmentCost = make(map[string]string)
+		} else {
+			for dept := range bgls.DepartmentCost {
+				if checkStringInListFunc(dept, typeDept) {
+					delete(bgls.DepartmentCost, dept)
+				}
+			}
+		}
+
+		// 계산된 부서별 비용 암호화하여 저장
+		for dept, cost := range costByDept {
+			encryptedCost, err := encryptAES256Func(strconv.Itoa(int(cost)))
+			if err != nil {
+				return err
+			}
+			bgls.DepartmentCost[dept] = encryptedCost
+		}
+		bglsList = append(bglsList, bgls)
+	}
+	typedata.LaborCosts = bglsList
+	return nil
+}
+
+// setEpisodeCostFunc 함수는 드라마 프로젝트의 샷 리스트를 기반으로 에피소드별 비용을 계산하여 업데이트하는 함수이다.
+func setEpisodeCostFunc(client *mongo.Client, typedata *BGTypeData) error {
+	totalBidByEpisode := make(map[string]map[string]float64)
+	for _, shot := range typedata.ShotList {
+		ep := getShotEpisodeFunc(shot)
+		if totalBidByEpisode[ep] == nil {
+			totalBidByEpisode[ep] = make(map[string]float64)
+		}
+		for task, bid := range shot.Manday {
+			totalBidByEpisode[ep][task] += bid
+		}
+	}
+
+	typedata.EpisodeCost = make(map[string]string)
+	for ep, totalBid := range totalBidByEpisode {
+		taskCost, err := calTaskCostByRateCardFunc(client, typedata, totalBid, false)
+		if err != nil {
+			return err
+		}
+		cost := 0.0
+		for _, c := range taskCost {
+			cost += c
+		}
+		encryptedCost, err := encryptAES256Func(strconv.Itoa(int(cost)))
+		if err != nil {
+			return err
+		}
+		typedata.EpisodeCost[ep] = encryptedCost
+	}
+	return nil
+}
+
+// mapSGTaskFunc 함수는 Shotgun 태스크를 예산 팀세팅의 태스크로 변환하는 함수이다.
+// 태스크 이름이 같으면 그 태스크로, 아니면 파이프라인 스텝이 예산 팀세팅의 태스크별 팀에 포함되는 태스크로 변환한다.
+func mapSGTaskFunc(ts BGTeamSetting, tasks []string, sgTask string, step string) string {
+	for _, task := range tasks {
+		if strings.EqualFold(task, sgTask) {
+			return task
+		}
+	}
+	if step == "" {
+		return ""
+	}
+	for _, task := range tasks {
+		for _, team := range ts.Teams[task] {
+			if strings.EqualFold(team, step) {
+				return task
+			}
+		}
+	}
+	return ""
+}
+
+// sgBidsToShotAssetFunc 함수는 Shotgun에서 가져온 태스크별 bid를 샷, 어셋 리스트로 정리하는 함수이다.
+// 예산 팀세팅의 태스크로 변환하지 못한 Shotgun 태스크는 따로 반환한다.
+func sgBidsToShotAssetFunc(bids []SGTaskBid, ts BGTeamSetting, asset bool) ([]BGShotAsset, []string) {
+	tasks := getBGTasksByTypeFunc(ts, asset)
+	items := make(map[string]*BGShotAsset)
+	var names []string
+	var unmapped []string
+	for _, bid := range bids {
+		if bid.Entity == "" {
+			continue
+		}
+		item, ok := items[bid.Entity]
+		if !ok {
+			item = &BGShotAsset{Name: bid.Entity, Manday: make(map[string]float64)}
+			if asset {
+				item.Class = bid.Class
+			} else {
+				item.Note = bid.Episode
+				item.Episode = bid.Episode
+			}
+			items[bid.Entity] = item
+			names = append(names, bid.Entity)
+		}
+
+		task := mapSGTaskFunc(ts, tasks, bid.Task, bid.Step)
+		if task == "" {
+			name := fmt.Sprintf("%s(%s)", bid.Task, bid.Step)
+			if !checkStringInListFunc(name, unmapped) {
+				unmapped = append(unmapped, name)
+			}
+			continue
+		}
+		if bid.Manday > 0 {
+			item.Manday[task] += bid.Manday
+		}
+	}
+
+	sort.Strings(names)
+	sort.Strings(unmapped)
+	var result []BGShotAsset
+	for _, name := range names {
+		result = append(result, *items[name])
+	}
+	return result, unmapped
+}
+
+// equalMandayFunc 함수는 두 태스크별 bid가 같은지 확인하는 함수이다. bid가 0인 태스크는 없는 것으로 본다.
+func equalMandayFunc(a map[string]float64, b map[string]float64) bool {
+	for task, bid := range a {
+		if b[task] != bid {
+			return false
+		}
+	}
+	for task, bid := range b {
+		if a[task] != bid {
+			return false
+		}
+	}
+	return true
+}
+
+// calBidDiffFunc 함수는 현재 예산안의 샷, 어셋 리스트와 새로운 리스트를 비교하여 바뀐 항목을 반환하는 함수이다.
+func calBidDiffFunc(before []BGShotAsset, after []BGShotAsset) []BGBidDiff {
+	beforeMap := make(map[string]map[string]float64)
+	for _, item := range before {
+		beforeMap[item.Name] = item.Manday
+	}
+	afterMap := make(map[string]map[string]float64)
+	for _, item := range after {
+		afterMap[item.Name] = item.Manday
+	}
+
+	var result []BGBidDiff
+	for _, item := range after {
+		manday, ok := beforeMap[item.Name]
+		if !ok {
+			result = append(result, BGBidDiff{Name: item.Name, Status: "added", After: item.Manday})
+			continue
+		}
+		if !equalMandayFunc(manday, item.Manday) {
+			result = append(result, BGBidDiff{Name: item.Name, Status: "changed", Before: manday, After: item.Manday})
+		}
+	}
+	for _, item := range before {
+		if _, ok := afterMap[item.Name]; !ok {
+			result = append(result, BGBidDiff{Name: item.Name, Status: "removed", Before: item.Manday})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
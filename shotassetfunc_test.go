@@ -0,0 +1,81 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 예산 샷, 어셋 테스트 스크립트
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Shotgun 태스크를 예산 팀세팅의 태스크로 변환하는지 테스트하기 위한 함수
+func Test_mapSGTask(t *testing.T) {
+	ts := BGTeamSetting{
+		Teams: map[string][]string{"MM": {"MatchMove"}, "comp": {"Comp1", "Comp2"}},
+	}
+	tasks := []string{"MM", "comp"}
+	cases := []struct {
+		task string
+		step string
+		want string
+	}{
+		{task: "mm", step: "", want: "MM"},
+		{task: "comp_main", step: "comp2", want: "comp"},
+		{task: "roto", step: "Roto", want: ""},
+		{task: "", step: "", want: ""},
+	}
+	for _, c := range cases {
+		got := mapSGTaskFunc(ts, tasks, c.task, c.step)
+		if got != c.want {
+			t.Fatalf("Test_mapSGTask(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", c.task, c.step, c.want, got)
+		}
+	}
+}
+
+// Shotgun 태스크별 bid를 샷 리스트로 정리하는지 테스트하기 위한 함수
+func Test_sgBidsToShotAsset(t *testing.T) {
+	ts := BGTeamSetting{
+		Departments: map[string][]BGDept{
+			"VFX": {{Name: "COMP", Parts: []BGPart{{Name: "Comp", Tasks: []string{"MM", "comp"}}}}},
+		},
+		Teams: map[string][]string{"MM": {"MatchMove"}, "comp": {"Comp1"}},
+	}
+	bids := []SGTaskBid{
+		{Entity: "s0020_c0010", Episode: "EP01", Task: "comp", Step: "Comp1", Manday: 2},
+		{Entity: "s0010_c0010", Episode: "EP01", Task: "MM", Step: "MatchMove", Manday: 1},
+		{Entity: "s0010_c0010", Episode: "EP01", Task: "comp_fix", Step: "Comp1", Manday: 0.5},
+		{Entity: "s0010_c0010", Episode: "EP01", Task: "roto", Step: "Roto", Manday: 3},
+	}
+	want := []BGShotAsset{
+		{Name: "s0010_c0010", Note: "EP01", Episode: "EP01", Manday: map[string]float64{"MM": 1, "comp": 0.5}},
+		{Name: "s0020_c0010", Note: "EP01", Episode: "EP01", Manday: map[string]float64{"comp": 2}},
+	}
+	got, unmapped := sgBidsToShotAssetFunc(bids, ts, false)
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(unmapped, []string{"roto(Roto)"}) {
+		t.Fatalf("Test_sgBidsToShotAsset(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v %v\n", bids, want, got, unmapped)
+	}
+}
+
+// 현재 bid와 새로운 bid의 차이를 계산하는지 테스트하기 위한 함수
+func Test_calBidDiff(t *testing.T) {
+	before := []BGShotAsset{
+		{Name: "a", Manday: map[string]float64{"MM": 1}},
+		{Name: "b", Manday: map[string]float64{"MM": 1}},
+		{Name: "c", Manday: map[string]float64{"MM": 1, "comp": 0}},
+	}
+	after := []BGShotAsset{
+		{Name: "d", Manday: map[string]float64{"comp": 1}},
+		{Name: "b", Manday: map[string]float64{"MM": 2}},
+		{Name: "c", Manday: map[string]float64{"MM": 1}},
+	}
+	got := calBidDiffFunc(before, after)
+	var status []string
+	for _, d := range got {
+		status = append(status, d.Name+":"+d.Status)
+	}
+	want := []string{"a:removed", "b:changed", "d:added"}
+	if !reflect.DeepEqual(status, want) {
+		t.Fatalf("Test_calBidDiff(): 입력 값: %v %v, 원하는 값: %v, 얻은 값: %v\n", before, after, want, status)
+	}
+}
@@ -786,3 +786,339 @@ func sgGetAllProjectsFunc(excludeProjects []string) ([]Project, error) {
 
 	return result, nil
 }
+
+// sgGetTaskBidsFunc 함수는 Shotgun 프로젝트의 샷 또는 어셋 태스크별 bid를 반환하는 함수이다.
+// bid는 태스크의 sg_bid(manday) 값을 사용하고, 값이 없으면 est_in_mins 값을 8시간 기준 manday로 변환한다.
+func sgGetTaskBidsFunc(project string, asset bool) ([]SGTaskBid, error) {
+	token := accessTokensFunc()
+
+	headers := map[string][]string{
+		"Content-Type":  []string{"application/vnd+shotgun.api3_array+json"},
+		"Accept":        []string{"application/json"},
+		"Authorization": []string{token},
+	}
+
+	entityType := "Shot"
+	extraFields := `"entity.Shot.code", "entity.Shot.sg_episode"`
+	if asset {
+		entityType = "Asset"
+		extraFields = `"entity.Asset.code", "entity.Asset.sg_asset_type"`
+	}
+	projectName, _ := json.Marshal(project)
+
+	jsonReq := fmt.Sprintf(`
+	{
+		"filters": [
+			["project.Project.name", "is", %s],
+			["entity", "type_is", "%s"]
+		],
+		"fields": ["content", "est_in_mins", "sg_bid", "step.Step.code", %s],
+		"sort": "id"
+	}
+	`, projectName, entityType, extraFields)
+
+	type Attribute struct {
+		Content    string          `json:"content" bson:"content"`
+		EstInMins  float64         `json:"est_in_mins" bson:"est_in_mins"`
+		Bid        float64         `json:"sg_bid" bson:"sg_bid"`
+		Step       string          `json:"step.Step.code" bson:"step.Step.code"`
+		ShotCode   string          `json:"entity.Shot.code" bson:"entity.Shot.code"`
+		Episode    json.RawMessage `json:"entity.Shot.sg_episode" bson:"entity.Shot.sg_episode"` // 텍스트 필드 또는 Episode 엔티티
+		AssetCode  string          `json:"entity.Asset.code" bson:"entity.Asset.code"`
+		AssetClass string          `json:"entity.Asset.sg_asset_type" bson:"entity.Asset.sg_asset_type"`
+	}
+
+	type TaskJSON struct {
+		Type       string    `json:"type" bson:"type"`
+		Attributes Attribute `json:"attributes" bson:"attributes"`
+		ID         int       `json:"id" bson:"id"`
+	}
+
+	type Recipe struct {
+		Data []TaskJSON `json:"data" bson:"data"`
+	}
+
+	var result []SGTaskBid
+	pageSize := 500
+	for page := 1; ; page++ {
+		data := bytes.NewBuffer([]byte(jsonReq))
+		req, err := http.NewRequest("POST", fmt.Sprintf("https://road101.shotgunstudio.com/api/v1/entity/tasks/_search?page[size]=%d&page[number]=%d", pageSize, page), data)
+		if err != nil {
+			return nil, err
+		}
+		req.Header = headers
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Shotgun에서 태스크를 가져오지 못했습니다(%s): %s", resp.Status, string(body))
+		}
+
+		var rcp Recipe
+		err = json.Unmarshal(body, &rcp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rcp.Data {
+			b := SGTaskBid{
+				Task:   r.Attributes.Content,
+				Step:   r.Attributes.Step,
+				Manday: r.Attributes.Bid,
+			}
+			if b.Manday == 0 {
+				b.Manday = r.Attributes.EstInMins / (8 * 60)
+			}
+			if asset {
+				b.Entity = r.Attributes.AssetCode
+				b.Class = r.Attributes.AssetClass
+			} else {
+				b.Entity = r.Attributes.ShotCode
+				b.Episode = sgEntityNameFunc(r.Attributes.Episode)
+			}
+			result = append(result, b)
+		}
+
+		if len(rcp.Data) < pageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// sgEntityNameFunc 함수는 Shotgun 필드 값이 텍스트이면 그대로, 엔티티이면 엔티티 이름을 반환하는 함수이다.
+func sgEntityNameFunc(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return strings.TrimSpace(text)
+	}
+	var entity struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(raw, &entity) == nil {
+		return strings.TrimSpace(entity.Name)
+	}
+	return ""
+}
+
+// sgGetTaskStatusHistoryFunc 함수는 Shotgun 이벤트 로그에서 프로젝트 태스크의 상태 변경 기록을 가져와 태스크 ID별로 반환하는 함수이다.
+func sgGetTaskStatusHistoryFunc(project string) (map[int][]SGStatusChange, error) {
+	token := accessTokensFunc()
+
+	headers := map[string][]string{
+		"Content-Type":  []string{"application/vnd+shotgun.api3_array+json"},
+		"Accept":        []string{"application/json"},
+		"Authorization": []string{token},
+	}
+
+	projectName, _ := json.Marshal(project)
+	jsonReq := fmt.Sprintf(`
+	{
+		"filters": [
+			["project.Project.name", "is", %s],
+			["event_type", "is", "Shotgun_Task_Change"],
+			["attribute_name", "is", "sg_status_list"]
+		],
+		"fields": ["created_at", "meta"],
+		"sort": "id",
+		"options": {
+			"include_archived_projects": true
+		}
+	}
+	`, projectName)
+
+	type Meta struct {
+		EntityID int    `json:"entity_id" bson:"entity_id"`
+		OldValue string `json:"old_value" bson:"old_value"`
+		NewValue string `json:"new_value" bson:"new_value"`
+	}
+
+	type Attribute struct {
+		CreatedAt string `json:"created_at" bson:"created_at"`
+		Meta      Meta   `json:"meta" bson:"meta"`
+	}
+
+	type EventJSON struct {
+		Type       string    `json:"type" bson:"type"`
+		Attributes Attribute `json:"attributes" bson:"attributes"`
+		ID         int       `json:"id" bson:"id"`
+	}
+
+	type Recipe struct {
+		Data []EventJSON `json:"data" bson:"data"`
+	}
+
+	result := make(map[int][]SGStatusChange)
+	pageSize := 500
+	for page := 1; ; page++ {
+		data := bytes.NewBuffer([]byte(jsonReq))
+		req, err := http.NewRequest("POST", fmt.Sprintf("https://road101.shotgunstudio.com/api/v1/entity/event_log_entry/_search?page[size]=%d&page[number]=%d", pageSize, page), data)
+		if err != nil {
+			return nil, err
+		}
+		req.Header = headers
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Shotgun에서 태스크 상태 변경 기록을 가져오지 못했습니다(%s): %s", resp.Status, string(body))
+		}
+
+		var rcp Recipe
+		err = json.Unmarshal(body, &rcp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rcp.Data {
+			// 타임로그 날짜와 비교할 수 있도록 로컬 시간으로 바꾼다.
+			createdAt, err := time.Parse(time.RFC3339, r.Attributes.CreatedAt)
+			if err != nil {
+				return nil, err
+			}
+			result[r.Attributes.Meta.EntityID] = append(result[r.Attributes.Meta.EntityID], SGStatusChange{
+				Time: createdAt.Local().Format(time.RFC3339),
+				Old:  r.Attributes.Meta.OldValue,
+				New:  r.Attributes.Meta.NewValue,
+			})
+		}
+
+		if len(rcp.Data) < pageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// sgGetEpisodeTimelogsFunc 함수는 Shotgun 프로젝트의 타임로그를 아티스트, 월, 에피소드, 태스크, 태스크 상태별로 합쳐서 반환하는 함수이다.
+// 타임로그가 작성된 태스크의 샷을 shotEpisodes에서 찾아 에피소드를 정하고, 찾지 못하면 에피소드는 빈 문자열이다.
+// 태스크 상태는 statusHistory로 찾은 타임로그 날짜의 상태이다.
+func sgGetEpisodeTimelogsFunc(project string, shotEpisodes map[string]string, statusHistory map[int][]SGStatusChange) ([]EpisodeTimelog, error) {
+	token := accessTokensFunc()
+
+	headers := map[string][]string{
+		"Content-Type":  []string{"application/vnd+shotgun.api3_array+json"},
+		"Accept":        []string{"application/json"},
+		"Authorization": []string{token},
+	}
+
+	projectName, _ := json.Marshal(project)
+	jsonReq := fmt.Sprintf(`
+	{
+		"filters": [
+			["project.Project.name", "is", %s]
+		],
+		"fields": ["date", "duration", "user.HumanUser.id", "entity.Task.id", "entity.Task.content", "entity.Task.sg_status_list", "entity.Task.entity"],
+		"sort": "id",
+		"options": {
+			"include_archived_projects": true
+		}
+	}
+	`, projectName)
+
+	type Attribute struct {
+		Date       string          `json:"date" bson:"date"`
+		Duration   float64         `json:"duration" bson:"duration"`
+		UserID     int             `json:"user.HumanUser.id" bson:"user.HumanUser.id"`
+		TaskID     int             `json:"entity.Task.id" bson:"entity.Task.id"`
+		TaskName   string          `json:"entity.Task.content" bson:"entity.Task.content"`
+		TaskStatus string          `json:"entity.Task.sg_status_list" bson:"entity.Task.sg_status_list"`
+		Entity     json.RawMessage `json:"entity.Task.entity" bson:"entity.Task.entity"` // 태스크가 연결된 샷, 어셋
+	}
+
+	type TimelogJSON struct {
+		Type       string    `json:"type" bson:"type"`
+		Attributes Attribute `json:"attributes" bson:"attributes"`
+		ID         int       `json:"id" bson:"id"`
+	}
+
+	type Recipe struct {
+		Data []TimelogJSON `json:"data" bson:"data"`
+	}
+
+	var result []EpisodeTimelog
+	index := make(map[string]int) // 아티스트, 월, 에피소드, 태스크, 태스크 상태별 result의 인덱스
+	pageSize := 500
+	for page := 1; ; page++ {
+		data := bytes.NewBuffer([]byte(jsonReq))
+		req, err := http.NewRequest("POST", fmt.Sprintf("https://road101.shotgunstudio.com/api/v1/entity/time_log/_search?page[size]=%d&page[number]=%d", pageSize, page), data)
+		if err != nil {
+			return nil, err
+		}
+		req.Header = headers
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Shotgun에서 타임로그를 가져오지 못했습니다(%s): %s", resp.Status, string(body))
+		}
+
+		var rcp Recipe
+		err = json.Unmarshal(body, &rcp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rcp.Data {
+			date := strings.Split(r.Attributes.Date, "-")
+			if len(date) < 2 {
+				continue
+			}
+			year, err := strconv.Atoi(date[0])
+			if err != nil {
+				return nil, err
+			}
+			month, err := strconv.Atoi(date[1])
+			if err != nil {
+				return nil, err
+			}
+
+			t := EpisodeTimelog{
+				UserID:   strconv.Itoa(r.Attributes.UserID),
+				Year:     year,
+				Month:    month,
+				Project:  strings.ToUpper(project),
+				Episode:  shotEpisodes[sgEntityNameFunc(r.Attributes.Entity)],
+				Task:     r.Attributes.TaskName,
+				Status:   getTaskStatusOnDateFunc(statusHistory[r.Attributes.TaskID], r.Attributes.Date, r.Attributes.TaskStatus),
+				Duration: r.Attributes.Duration,
+			}
+			key := fmt.Sprintf("%s/%d/%d/%s/%s/%s", t.UserID, t.Year, t.Month, t.Episode, t.Task, t.Status)
+			if i, ok := index[key]; ok {
+				result[i].Duration += t.Duration
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, t)
+		}
+
+		if len(rcp.Data) < pageSize {
+			break
+		}
+	}
+	return result, nil
+}
@@ -6,6 +6,9 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -21,6 +24,19 @@ type Timelog struct {
 	Duration float64 `json:"duration" bson:"duration"` // 타임로그 시간
 }
 
+// EpisodeTimelog 자료구조는 프로젝트의 타임로그를 에피소드, 태스크, 태스크 상태별로 나누어 담을 때 사용하는 자료구조이다.
+// 드라마 프로젝트의 에피소드별 실제 인건비와 프로젝트의 리테이크 인건비를 계산할 때 같이 사용한다.
+type EpisodeTimelog struct {
+	UserID   string  `json:"userid" bson:"userid"`     // 아티스트의 Shotgun ID
+	Year     int     `json:"year" bson:"year"`         // 연도
+	Month    int     `json:"month" bson:"month"`       // 월
+	Project  string  `json:"project" bson:"project"`   // 프로젝트
+	Episode  string  `json:"episode" bson:"episode"`   // 에피소드, 샷이 에피소드에 연결되지 않았으면 빈 문자열
+	Task     string  `json:"task" bson:"task"`         // Shotgun 태스크 이름
+	Status   string  `json:"status" bson:"status"`     // 타임로그를 작성한 날의 Shotgun 태스크 상태 코드 ex) ip, rtk
+	Duration float64 `json:"duration" bson:"duration"` // 타임로그 시간
+}
+
 // FinishedTimelogStatus 자료구조는 정산 완료된 프로젝트에 타임로그를 작성했을 경우 ETC로 처리할지의 여부를 담는 자료구조이다.
 type FinishedTimelogStatus struct {
 	Year        int                `json:"year" bson:"year"`               // 연도
@@ -33,10 +49,11 @@ type FinishedTimelogStatus struct {
 // Artist 자료구조
 type Artist struct {
 	// 아티스트 기본 정보
-	ID   string // ID(VFX : Shotgun ID, CM : 1부터 시작)
-	Name string // 이름
-	Dept string // 부서
-	Team string // 팀
+	ID          string            // ID(VFX : Shotgun ID, CM : 1부터 시작)
+	Name        string            // 이름
+	Dept        string            // 부서
+	Team        string            // 팀
+	TeamHistory map[string]string // 팀이 바뀐 날짜별 이전 팀 {"2020-03-15": "FX"}, 2020-03-14까지 FX 팀이었다.
 
 	// 아티스트 입사 및 퇴사 정보
 	StartDay   string // 입사일 2020-01-01
@@ -80,6 +97,9 @@ type Project struct {
 	SMEndDate    string    // 결산시 작업 마감일
 	DirectorName string    // 감독 이름
 	ProducerName string    // 제작사 이름
+	DirectorID   string    // 감독 Client ID
+	ProducerID   string    // 제작사 Client ID
+	BGProjectID  string    // 연결된 예산 프로젝트 ID
 
 	IsFinished   bool   // 정산 완료 여부(이미 정산 완료된 프로젝트를 추가할 때 true)
 	TotalAmount  string // 정산 완료된 프로젝트의 총 내부 비용(이미 정산 완료된 프로젝트를 추가할 때 입력하는 내부 비용)
@@ -94,8 +114,19 @@ type Project struct {
 	SMDifference          string                    // 경영관리실에서 입력하는 차액(퇴직금, 감가상각비, 공통 노무비, 공통 경비 등)
 
 	// 프로젝트 부가 정보
-	ContractCuts int // 프로젝트 계약 컷수
-	WorkingCuts  int // 프로젝트 작업 컷수
+	ContractCuts  int       // 프로젝트 계약 컷수
+	WorkingCuts   int       // 프로젝트 작업 컷수
+	CompletedCuts int       // 프로젝트 완료 컷수
+	Episodes      []Episode // 드라마 프로젝트의 에피소드 정보
+}
+
+// Episode 자료구조는 드라마 프로젝트의 에피소드 정보를 담을 때 사용하는 자료구조이다.
+type Episode struct {
+	Name         string // 에피소드 이름 ex) EP01
+	AirDate      string // 방영일 ex) 2021-03-01
+	DeliveryDate string // 납품일 ex) 2021-02-25
+	Cuts         int    // 에피소드 컷수
+	Budget       string // 에피소드 예산
 }
 
 // Payment 자료구조는 프로젝트의 매출 정보를 담을 때 사용하는 자료구조이다.
@@ -158,6 +189,8 @@ type AdminSetting struct {
 	SGUpdatedTime     string   `json:"sgupdatedtime" bson:"sgupdatedtime"`         // Shotgun에서 타임로그 데이터가 업데이트된 시간
 	SGExcludeID       []string `json:"sgexcludeid" bson:"sgexcludeid"`             // Shotgun에서 타임로그를 가져올 때 제외할 아티스트 ID(ex. 90)
 	SGExcludeProjects []string `json:"sgexcludeprojects" bson:"sgexcludeprojects"` // Shotgun에서 타임로그를 가져올 때 제외할 프로젝트 리스트(ex. td2)
+	SGRetakeKeywords  []string `json:"sgretakekeywords" bson:"sgretakekeywords"`   // 태스크 이름에 포함되면 리테이크로 처리할 단어 리스트(ex. retake _rt)
+	SGRetakeStatuses  []string `json:"sgretakestatuses" bson:"sgretakestatuses"`   // 리테이크로 처리할 Shotgun 태스크 상태 코드 리스트(ex. rtk)
 
 	// Project
 	ProjectStatus []Status `json:"projectstatus" bson:"projectstatus"` // 프로젝트 상태 리스트
@@ -170,12 +203,45 @@ type AdminSetting struct {
 	GWIDs           []string `json:"gwids" bson:"gwids"`                     // 벤더 발행일에 메일을 전송할 그룹웨어 ID
 	GWIDsForProject []string `json:"gwidsforproject" bson:"gwidsforproject"` // 프로젝트 발행일에 메일을 전송할 그룹웨어 ID
 
+	// 알림(Notification)
+	NotifierType     string `json:"notifiertype" bson:"notifiertype"`         // 알림을 보낼 방식 ex) smtp, file, stdout
+	NotifierFilePath string `json:"notifierfilepath" bson:"notifierfilepath"` // NotifierType이 file일 때 알림을 기록할 파일 경로
+	SMTPHost         string `json:"smtphost" bson:"smtphost"`                 // SMTP 서버 주소
+	SMTPPort         string `json:"smtpport" bson:"smtpport"`                 // SMTP 서버 포트
+	SMTPTLS          string `json:"smtptls" bson:"smtptls"`                   // SMTP 암호화 방식 ex) none, starttls, tls
+	SMTPUsername     string `json:"smtpusername" bson:"smtpusername"`         // SMTP 인증 계정
+	SMTPPassword     string `json:"smtppassword" bson:"smtppassword"`         // SMTP 인증 비밀번호(암호화)
+	SMTPFrom         string `json:"smtpfrom" bson:"smtpfrom"`                 // 메일 보내는 사람
+
+	// Webhook
+	WebhookURLs    map[string]string `json:"webhookurls" bson:"webhookurls"`       // 알림 이벤트별로 메시지를 보낼 webhook URL, 비어있으면 보내지 않는다.
+	WebhookPayload string            `json:"webhookpayload" bson:"webhookpayload"` // webhook으로 보낼 JSON payload 템플릿, 비어있으면 {"text": ...} 형식으로 보낸다.
+
+	// LDAP
+	LDAPURL          string          `json:"ldapurl" bson:"ldapurl"`                   // LDAP 서버 주소 ex) ldap://gw.rd101.co.kr:389, ldaps://gw.rd101.co.kr:636, 비어있으면 LDAP 로그인을 사용하지 않는다.
+	LDAPBaseDN       string          `json:"ldapbasedn" bson:"ldapbasedn"`             // 사용자를 검색할 Base DN ex) ou=people,dc=rd101,dc=co,dc=kr
+	LDAPUserAttr     string          `json:"ldapuserattr" bson:"ldapuserattr"`         // 로그인 ID와 비교할 속성, 비어있으면 uid를 사용한다.
+	LDAPBindDN       string          `json:"ldapbinddn" bson:"ldapbinddn"`             // 사용자를 검색할 때 사용할 계정 DN, 비어있으면 익명으로 검색한다.
+	LDAPBindPassword string          `json:"ldapbindpassword" bson:"ldapbindpassword"` // 사용자를 검색할 때 사용할 계정 비밀번호(암호화)
+	LDAPGroupRoles   []LDAPGroupRole `json:"ldapgrouproles" bson:"ldapgrouproles"`     // LDAP 그룹별 역할, 액세스 레벨
+
+	// 로그(Log)
+	LogRetentionDays int    `json:"logretentiondays" bson:"logretentiondays"` // 로그를 DB에 보관하는 기간(일), 0이면 삭제하지 않는다.
+	LogArchivePath   string `json:"logarchivepath" bson:"logarchivepath"`     // 보관 기간이 지난 로그를 압축해서 저장할 폴더, 비어있으면 ~/.budget-logs에 저장한다.
+
 	// 예산(Budget)
 	BGSupervisorTeams []string `json:"bgsupervisorteams" bson:"bgsupervisorteams"` // 예산안 및 예산 관련 팀 세팅에서 사용될 슈퍼바이저 Team 리스트
 	BGProductionTeams []string `json:"bgproductionteams" bson:"bgproductionteams"` // 예산안 및 예산 관련 팀 세팅에서 사용될 프로덕션 Team 리스트
 	BGManagementTeams []string `json:"bgmanagementteams" bson:"bgmanagementteams"` // 예산안 및 예산 관련 팀 세팅에서 사용될 매니지먼트 Team 리스트
 }
 
+// LDAPGroupRole 자료구조는 LDAP 그룹에 속한 사용자에게 줄 역할과 액세스 레벨 정보를 담는 자료구조이다.
+type LDAPGroupRole struct {
+	Group       string      `json:"group" bson:"group"`             // 그룹 DN 또는 CN
+	Role        string      `json:"role" bson:"role"`               // 역할 이름, 비어있으면 액세스 레벨에 따라 권한을 준다.
+	AccessLevel AccessLevel `json:"accesslevel" bson:"accesslevel"` // 액세스 레벨
+}
+
 // MonthlyStatus 자료구조
 type MonthlyStatus struct {
 	Date   string `json:"date" bson:"date"`     // 2020-09
@@ -184,9 +250,38 @@ type MonthlyStatus struct {
 
 // Log 자료구조
 type Log struct {
-	UserID    string    `json:"userid" bson:"userid"`         // 유저 ID
-	CreatedAt time.Time `json:"created_at" bson:"created_at"` // 로그가 생성된 시간
-	Content   string    `json:"content" bson:"content"`       // 로그 내용
+	UserID     string      `json:"userid" bson:"userid"`         // 유저 ID, CLI 명령으로 남긴 로그는 cli:<OS 계정>
+	CreatedAt  time.Time   `json:"created_at" bson:"created_at"` // 로그가 생성된 시간
+	Content    string      `json:"content" bson:"content"`       // 로그 내용
+	Action     string      `json:"action" bson:"action"`         // 동작: create, update, delete, view, export, import, sync, login
+	EntityType string      `json:"entitytype" bson:"entitytype"` // 대상 종류: project, artist, timelog ...
+	EntityID   string      `json:"entityid" bson:"entityid"`     // 대상 ID
+	Changes    []LogChange `json:"changes" bson:"changes"`       // 수정 전후 값이 바뀐 항목
+	IP         string      `json:"ip" bson:"ip"`                 // 요청을 보낸 IP
+}
+
+// LogChange 자료구조는 수정 로그에서 값이 바뀐 항목 하나를 담는 자료구조이다. 암호화된 값은 가려서 저장한다.
+type LogChange struct {
+	Field  string `json:"field" bson:"field"`   // 항목 이름, 예) Payment[2021-03].Expenses
+	Before string `json:"before" bson:"before"` // 수정 전 값
+	After  string `json:"after" bson:"after"`   // 수정 후 값
+}
+
+// LogFilter 자료구조는 로그를 검색할 때 사용하는 조건이다. 비어있는 조건은 사용하지 않는다.
+type LogFilter struct {
+	UserID     string    // 유저 ID
+	EntityType string    // 대상 종류
+	EntityID   string    // 대상 ID
+	Action     string    // 동작
+	Keyword    string    // 로그 내용, 유저 ID, 대상 ID, 바뀐 항목에서 찾을 검색어. 띄어쓰기로 구분한 단어가 모두 들어간 로그를 찾는다.
+	Start      time.Time // 이 시간 이후의 로그
+	End        time.Time // 이 시간 이전의 로그
+}
+
+// LogOption 자료구조는 로그 페이지의 검색 항목(동작, 대상 종류)을 담는 자료구조이다.
+type LogOption struct {
+	ID   string // DB에 저장되는 값
+	Name string // 페이지에 보여주는 이름
 }
 
 // CheckErrorFunc 메소드는 Artist 자료구조에 값이 정확히 들어갔는지 확인하는 함수이다.
@@ -315,6 +410,29 @@ func (a AdminSetting) CheckErrorFunc() error {
 			return errors.New("기타 프로젝트에는 영문(대문자), 숫자, 특수문자(_)만 입력 가능합니다")
 		}
 	}
+	if a.NotifierType != "" && a.NotifierType != NotifierTypeSMTP && a.NotifierType != NotifierTypeFile && a.NotifierType != NotifierTypeStdout {
+		return errors.New("알림 방식은 smtp, file, stdout만 가능합니다")
+	}
+	if a.NotifierType == NotifierTypeFile && a.NotifierFilePath == "" {
+		return errors.New("알림을 기록할 파일 경로를 입력해주세요")
+	}
+	if a.SMTPPort != "" && !regexDigit.MatchString(a.SMTPPort) {
+		return errors.New("SMTP 포트는 숫자만 가능합니다")
+	}
+	if a.SMTPTLS != "" && a.SMTPTLS != SMTPTLSNone && a.SMTPTLS != SMTPTLSStartTLS && a.SMTPTLS != SMTPTLSImplicit {
+		return errors.New("SMTP 암호화 방식은 none, starttls, tls만 가능합니다")
+	}
+	for event, url := range a.WebhookURLs {
+		if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return fmt.Errorf("%s 이벤트의 webhook URL은 http:// 또는 https://로 시작해야 합니다", event)
+		}
+	}
+	if a.WebhookPayload != "" {
+		_, err := parseWebhookPayloadFunc(a.WebhookPayload)
+		if err != nil {
+			return fmt.Errorf("webhook payload 템플릿이 올바르지 않습니다: %v", err)
+		}
+	}
 	for _, tp := range a.TaskProjects {
 		if !regexProject.MatchString(tp) {
 			return errors.New("태스크로 구분할 프로젝트에는 영문(대문자), 숫자, 특수문자(_)만 입력 가능합니다")
@@ -325,6 +443,25 @@ func (a AdminSetting) CheckErrorFunc() error {
 			return errors.New("수퍼바이저의 ID는 숫자만 가능합니다")
 		}
 	}
+	if a.LDAPURL != "" {
+		if !strings.HasPrefix(a.LDAPURL, "ldap://") && !strings.HasPrefix(a.LDAPURL, "ldaps://") {
+			return errors.New("LDAP 서버 주소는 ldap:// 또는 ldaps://로 시작해야 합니다")
+		}
+		if a.LDAPBaseDN == "" {
+			return errors.New("LDAP Base DN을 입력해주세요")
+		}
+	}
+	for _, g := range a.LDAPGroupRoles {
+		if g.AccessLevel < GuestLevel || g.AccessLevel > AdminLevel {
+			return fmt.Errorf("LDAP 그룹 %s의 액세스 레벨이 올바르지 않습니다", g.Group)
+		}
+	}
+	if a.LogRetentionDays < 0 {
+		return errors.New("로그 보관 기간은 0 이상의 숫자만 가능합니다")
+	}
+	if a.LogArchivePath != "" && !filepath.IsAbs(a.LogArchivePath) {
+		return errors.New("로그를 저장할 폴더는 절대 경로로 입력해주세요")
+	}
 	return nil
 }
 
@@ -13,11 +13,46 @@ type BGTeamSetting struct {
 	// default
 	ID          string `json:"id" bson:"id"`                   // DB에서 값을 가지고 오기 위한 ID(setting.bgteam)
 	UpdatedTime string `json:"updatedtime" bson:"updatedtime"` // 마지막으로 업데이트된 시간
+	Version     int    `json:"version" bson:"version"`         // 팀세팅 버전, 버전 관리 전에 저장된 팀세팅은 0
 
 	Headquarters []string               `json:"headquarters" bson:"headquarters"` // 본부 ex) [VFX, CM]
 	Departments  map[string][]BGDept    `json:"departments" bson:"departments"`   // 본부별 부서 ex) VFX:[pre-production, Asset, 3D+FX, COMP, SUP+PROD], CM:[CM]
 	Controls     map[string][]BGControl `json:"controls" bson:"controls"`         // 본부별 해당 Supervisor, Production, Management 자료구조
 	Teams        map[string][]string    `json:"teams" bson:"teams"`               // 태스크별 해당 팀 ex) texture:Asset & Lookdev, model:Asset & Lookdev, MM:MatchMove, Ani:Animation, CM_Matte:cm_Matte
+	Approvers    map[string]BGApprover  `json:"approvers" bson:"approvers"`       // 본부별 예산안 결재자 ex) VFX:{Production:[kim], Management:[lee]}
+}
+
+// BGTeamSettingVersion 자료구조 - 팀세팅이 바뀔 때마다 저장하는 팀세팅 버전
+type BGTeamSettingVersion struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`        // 버전을 구분하기 위한 ID
+	Version     int                `json:"version" bson:"version"`         // 팀세팅 버전 ex) 1, 2, 3 ...
+	TeamSetting BGTeamSetting      `json:"teamsetting" bson:"teamsetting"` // 저장 당시 팀세팅
+	Note        string             `json:"note" bson:"note"`               // 변경 사유
+	UserID      string             `json:"userid" bson:"userid"`           // 저장한 사용자 ID
+	CreatedTime string             `json:"createdtime" bson:"createdtime"` // 저장 시간
+}
+
+// BGTeamSettingDiff 자료구조 - 두 팀세팅 버전의 차이 항목
+type BGTeamSettingDiff struct {
+	Section string // 구분 ex) 본부, 부서, 태스크, 태스크별 팀, 컨트롤 팀, 결재자
+	Item    string // 항목 ex) VFX/3D+FX, FX
+	Before  string // 이전 버전 값, 없으면 빈 문자열
+	After   string // 이후 버전 값, 없으면 빈 문자열
+}
+
+// BGMigrationTarget 자료구조 - 팀세팅 마이그레이션 대상 예산안
+type BGMigrationTarget struct {
+	ProjectID   string // 예산 프로젝트 ID
+	ProjectName string // 예산 프로젝트 이름
+	BGType      string // 예산안 이름
+	Status      string // 결재 상태
+	Editable    bool   // 수정 가능 여부
+}
+
+// BGApprover 자료구조 - 본부별 예산안 결재자
+type BGApprover struct {
+	Production []string `json:"production" bson:"production"` // 프로덕션 헤드 결재자 ID 리스트
+	Management []string `json:"management" bson:"management"` // 매니지먼트 결재자 ID 리스트
 }
 
 // BGPart 자료구조 - 본부별 부서세팅에 관련된 Part 자료구조
@@ -55,8 +90,9 @@ type BGProject struct {
 	Type      string // 영화인지 드라마인지 타입 ex) 영화:movie, 드라마:drama
 
 	// 프로젝트 부가 정보
-	DirectorName string // 감독 이름
-	ProducerName string // 제작사 이름
+	DirectorName string    // 감독 이름
+	ProducerName string    // 제작사 이름
+	Episodes     []Episode // 드라마 프로젝트의 에피소드 정보
 
 	// 예산 프로젝트 관련 정보
 	Status   bool                  // true: 계약 완료, false: 사전 검토 or string "계약 완료", "사전 검토"
@@ -83,6 +119,9 @@ type BGTypeData struct {
 	ProgressRatio float64 // 진행비율
 	VendorRatio   float64 // 외주비율
 
+	// 단가표 정보
+	RateCards map[string]string // 예산안 생성 당시 고정된 본부별 단가표 ID ex) VFX: 60a1..., CM: 60a2...
+
 	// 비용 정보
 	LaborCosts  []BGLaborCost     // 예산안 비용
 	EpisodeCost map[string]string // 에피소드별 비용 ex) EP01: 100000, EP02: 200000 ...
@@ -95,6 +134,10 @@ type BGTypeData struct {
 	// 샷, 어셋 정보
 	ShotList  []BGShotAsset // 샷 정보
 	AssetList []BGShotAsset // 어셋 정보
+
+	// 결재 정보
+	ApprovalStatus string       // 결재 상태 ex) draft, submitted, headapproved, approved, locked. 빈 문자열은 draft
+	Approvals      []BGApproval // 결재 요청, 승인, 반려, 코멘트 기록
 }
 
 // BGLaborCost 예산안 비용 자료구조
@@ -119,7 +162,210 @@ type BGShotAsset struct {
 	Manday map[string]float64 // 태스크별 bid ex) {"MM":1, "Comp":0.5}
 	Note   string             // 노트 정보 ex) Asset: 번개섬 야자수 해변, 번개섬 봉우리, Shot(drama): EP01, EP02 ...
 
+	// Shot 자료
+	Episode string // 샷이 속한 에피소드 ex) EP01
+
 	// Asset 자료
 	Class string // 어셋 분류 ex) Asset, Concept
 	Shot  int    // 어셋의 샷 개수
 }
+
+// SGTaskBid 자료구조 - Shotgun에서 가져온 샷, 어셋의 태스크별 bid
+type SGTaskBid struct {
+	Entity  string  // 샷, 어셋 이름 ex) s0010_c0010
+	Episode string  // 샷의 에피소드 ex) EP01
+	Class   string  // 어셋 분류 ex) Character, Prop
+	Task    string  // Shotgun 태스크 이름 ex) comp
+	Step    string  // Shotgun 파이프라인 스텝 ex) Comp
+	Manday  float64 // bid(manday)
+}
+
+// SGStatusChange 자료구조 - Shotgun 이벤트 로그에서 가져온 태스크 상태 변경 기록
+type SGStatusChange struct {
+	Time string // 상태가 바뀐 시간(로컬 시간, RFC3339) ex) 2021-03-02T10:00:00+09:00
+	Old  string // 바뀌기 전 상태 코드 ex) ip
+	New  string // 바뀐 상태 코드 ex) rtk
+}
+
+// BGBidDiff 자료구조 - 현재 예산안의 bid와 새로 가져온 bid의 차이
+type BGBidDiff struct {
+	Name   string             // 샷, 어셋 이름
+	Status string             // 변경 상태 ex) added, removed, changed
+	Before map[string]float64 // 현재 예산안의 태스크별 bid
+	After  map[string]float64 // 새로 가져온 태스크별 bid
+}
+
+// BGRevision 자료구조 - 예산안이 저장될 때마다 남기는 수정할 수 없는 리비전
+type BGRevision struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`        // 리비전을 구분하기 위한 ID
+	ProjectID   string             `json:"projectid" bson:"projectid"`     // 예산 프로젝트 ID
+	BGType      string             `json:"bgtype" bson:"bgtype"`           // 저장 당시 예산안 이름
+	TypeID      string             `json:"typeid" bson:"typeid"`           // 예산안 고유 ID(BGTypeData.ID)
+	Revision    int                `json:"revision" bson:"revision"`       // 예산안별 리비전 번호 ex) 1, 2, 3 ...
+	TypeData    BGTypeData         `json:"typedata" bson:"typedata"`       // 저장 당시 예산안 데이터
+	Action      string             `json:"action" bson:"action"`           // 리비전이 생긴 작업 ex) 예산 프로젝트 수정, 샷 업로드
+	UserID      string             `json:"userid" bson:"userid"`           // 저장한 사용자 ID
+	CreatedTime string             `json:"createdtime" bson:"createdtime"` // 저장 시간
+}
+
+// BGCompareRow 자료구조 - 두 예산안 비교 항목
+type BGCompareRow struct {
+	Section string  // 구분 ex) 계약, 비율, 인건비, Manday
+	Item    string  // 항목 ex) 계약 결정액, VFX 3D+FX, MM
+	A       string  // 기준 예산안 값
+	B       string  // 비교 예산안 값
+	Delta   string  // 차이(B - A)
+	Diff    float64 // 차이 값
+}
+
+// 예산안 결재 상태
+const (
+	BGApprovalDraft        = "draft"        // 작성중
+	BGApprovalSubmitted    = "submitted"    // 결재 요청, 프로덕션 헤드 결재 대기
+	BGApprovalHeadApproved = "headapproved" // 프로덕션 헤드 승인, 매니지먼트 결재 대기
+	BGApprovalApproved     = "approved"     // 매니지먼트 승인
+	BGApprovalLocked       = "locked"       // 확정
+)
+
+// 예산안 결재 작업
+const (
+	BGApprovalActionSubmit   = "submit"   // 결재 요청
+	BGApprovalActionApprove  = "approve"  // 승인
+	BGApprovalActionReject   = "reject"   // 반려
+	BGApprovalActionWithdraw = "withdraw" // 결재 요청 취소
+	BGApprovalActionLock     = "lock"     // 확정
+	BGApprovalActionReopen   = "reopen"   // 승인된 예산안을 다시 작성중으로 변경
+	BGApprovalActionComment  = "comment"  // 코멘트
+)
+
+// BGApproval 자료구조 - 예산안 결재 기록
+type BGApproval struct {
+	Action      string // 결재 작업 ex) submit, approve, reject, comment
+	Stage       string // 작업 당시 결재 상태 ex) submitted, headapproved
+	Headquarter string // 승인, 반려한 결재자의 본부 ex) VFX
+	UserID      string // 작업한 사용자 ID
+	Comment     string // 코멘트
+	CreatedTime string // 작업 시간
+}
+
+// 예산 대비 실제 비용 비교에서 사용하는 본부, 부서 이름
+const (
+	BGDeptManagement = "Management" // 슈퍼바이저, 프로덕션, 매니지먼트 팀
+	BGDeptETC        = "ETC"        // 예산 팀세팅에 없는 팀
+	BGHeadVendor     = "외주비"        // 외주비
+	BGHeadProgress   = "진행비"        // 진행비
+	BGHeadTotal      = "합계"         // 합계
+)
+
+// BGActual 자료구조 - 예산 대비 실제 비용 비교 항목
+type BGActual struct {
+	Headquarter   string // 본부명 ex) VFX, CM, 외주비, 진행비
+	Department    string // 부서명 ex) 3D+FX, COMP, Management
+	Budget        int    // 예산
+	Actual        int    // 실제 비용
+	Variance      int    // 차이(실제 비용 - 예산)
+	VarianceRatio string // 예산 대비 차이 비율(%), 예산이 없으면 빈 문자열
+}
+
+// BGEpisodeActual 자료구조 - 드라마 프로젝트의 에피소드별 예산 대비 실제 비용
+type BGEpisodeActual struct {
+	Episode Episode  // 에피소드 정보
+	Shots   int      // 에피소드에 연결된 샷 개수
+	Cost    BGActual // 에피소드 예산과 실제 인건비
+}
+
+// BGCapacity 자료구조 - 팀별 월별 필요 인력과 가용 인력
+type BGCapacity struct {
+	Team      string             // 팀 이름, CM 팀은 CM_ 으로 시작한다. ex) MatchMove, CM_Matte
+	Projects  []string           // 인력이 필요한 예산 프로젝트 ID 리스트
+	Demand    map[string]float64 // 월별 필요 manday ex) 2021-03: 42.5
+	Headcount map[string]float64 // 월별 인원(입사, 퇴사일을 반영한 인원) ex) 2021-03: 3.5
+	Capacity  map[string]float64 // 월별 가용 manday(인원 * 근무일수)
+	Balance   map[string]float64 // 월별 여유 manday(가용 - 필요), 음수이면 인력 부족
+	Ratio     map[string]float64 // 월별 배정률(%)(필요 / 가용 * 100), 가용 인력이 없으면 0
+}
+
+// BGForecast 자료구조 - 진행중인 프로젝트의 실제 비용 소진율로 예측한 최종 비용(EAC)
+type BGForecast struct {
+	// 예산 정보
+	Decision       int     // 계약 결정액
+	BudgetLabor    int     // 예산안 인건비
+	BudgetExpense  int     // 예산안 진행비(계약 결정액 * 진행비율)
+	TotalMandays   float64 // 예산안 샷, 어셋 bid 합계
+	WorkingCuts    int     // 작업 컷수
+	CompletedCuts  int     // 완료 컷수
+	VendorContract int     // 외주 계약 금액 합계
+
+	// 현재까지의 실제 비용
+	ActualLabor   int // 실제 인건비
+	ActualExpense int // 실제 진행비 + 구매비
+	VendorPaid    int // 지출된 외주비
+
+	// 예측 정보
+	CompleteRatio    float64 // 완료 컷수 / 작업 컷수
+	BurnRate         int     // 완료된 bid 1 manday당 실제 인건비
+	RemainingMandays float64 // 남은 bid
+	RemainingLabor   int     // 남은 인건비 예측
+	RemainingExpense int     // 남은 진행비 예측
+	RemainingVendor  int     // 지출 예정인 외주비
+	EAC              int     // 최종 비용 예측(Estimate At Completion)
+	Margin           int     // 예상 수익(계약 결정액 - EAC)
+	MarginRatio      string  // 예상 수익률(%), 계약 결정액이 없으면 빈 문자열
+	OverBudget       bool    // EAC가 계약 결정액을 넘는지 여부
+}
+
+// BGQuote 자료구조 - 예산안으로 만든 클라이언트 제출용 견적서
+type BGQuote struct {
+	// 표지 정보
+	ProjectID    string // 예산 프로젝트 ID
+	ProjectName  string // 예산 프로젝트 이름
+	BGType       string // 견적서를 만든 예산안 이름
+	Type         string // 영화, 드라마 ex) movie, drama
+	ProducerName string // 제작사 이름
+	DirectorName string // 감독 이름
+	QuoteDate    string // 견적일 ex) 2021-03-02
+	ValidDate    string // 견적 유효기간 ex) 2021-04-01
+	StartDate    string // 예상 작업 시작월
+	EndDate      string // 예상 작업 마감월
+	Writer       string // 견적서 작성자 ID
+
+	// 작업 범위
+	ContractCuts int     // 계약 컷수
+	Shots        int     // 샷 개수
+	Assets       int     // 어셋 개수
+	Mandays      float64 // 샷, 어셋 bid 합계
+
+	// 금액 정보
+	Total      int           // 견적 금액(계약 결정액, 없으면 제안 견적)
+	GroupTitle string        // 항목 구분 이름 ex) 에피소드, 시퀀스
+	Items      []BGQuoteItem // 에피소드 또는 시퀀스별 금액
+	Terms      []string      // 견적 조건
+
+	// 내부 비용 정보. HideMargin이 true이면 문서에 넣지 않는다.
+	HideMargin   bool   // 내부 비용과 수익을 숨길지 여부
+	LaborCost    int    // 예산안 인건비
+	Expense      int    // 예산안 진행비(견적 금액 * 진행비율)
+	InternalCost int    // 인건비 + 진행비
+	Margin       int    // 견적 금액 - 내부 비용
+	MarginRatio  string // 수익률(%), 견적 금액이 없으면 빈 문자열
+}
+
+// BGQuoteItem 자료구조 - 견적서의 에피소드 또는 시퀀스별 금액
+type BGQuoteItem struct {
+	Name    string  // 에피소드 또는 시퀀스 이름 ex) EP01, s0010, 어셋
+	Cuts    int     // 샷 개수
+	Mandays float64 // bid 합계
+	Price   int     // 견적 금액
+	Cost    int     // 내부 인건비
+}
+
+// BGRetake 자료구조 - 월별 전체 인건비와 리테이크 인건비
+type BGRetake struct {
+	Date        string  // 연월 ex) 2021-03, 합계는 빈 문자열
+	Hours       float64 // 전체 타임로그 시간
+	RetakeHours float64 // 리테이크 타임로그 시간
+	LaborCost   int     // 전체 인건비
+	RetakeCost  int     // 리테이크 인건비
+	ShareRatio  string  // 전체 인건비 중 리테이크 인건비 비율(%), 전체 인건비가 없으면 빈 문자열
+	RetakeRatio string  // 예산안 Retake율과 같은 기준의 실제 Retake율(%)(리테이크 인건비 / 리테이크를 뺀 인건비 * 100)
+}
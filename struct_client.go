@@ -0,0 +1,62 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 클라이언트(제작사, 감독) 관련 자료구조 스크립트
+
+package main
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 클라이언트 타입
+const (
+	ClientTypeProducer = "producer" // 제작사
+	ClientTypeDirector = "director" // 감독
+)
+
+// Client 자료구조는 제작사와 감독 정보를 담을 때 사용하는 자료구조이다.
+type Client struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`        // 클라이언트를 구분하기 위한 ID
+	Type        string             `json:"type" bson:"type"`               // 클라이언트 타입 ex) producer, director
+	Name        string             `json:"name" bson:"name"`               // 제작사 이름 또는 감독 이름
+	Contacts    []ClientContact    `json:"contacts" bson:"contacts"`       // 담당자 연락처 리스트
+	Note        string             `json:"note" bson:"note"`               // 메모
+	UpdatedTime string             `json:"updatedtime" bson:"updatedtime"` // 마지막으로 업데이트된 시간
+}
+
+// ClientContact 자료구조는 클라이언트의 담당자 연락처 정보를 담을 때 사용하는 자료구조이다.
+type ClientContact struct {
+	Name     string `json:"name" bson:"name"`         // 담당자 이름
+	Position string `json:"position" bson:"position"` // 직책
+	Phone    string `json:"phone" bson:"phone"`       // 전화번호
+	Email    string `json:"email" bson:"email"`       // 이메일
+}
+
+// ClientSummary 자료구조는 클라이언트별 계약 및 매출 합계를 담을 때 사용하는 자료구조이다.
+type ClientSummary struct {
+	Client      Client    // 클라이언트 정보
+	Projects    []Project // 클라이언트와 연결된 결산 프로젝트 리스트
+	Contract    int       // 총 계약금액
+	Received    int       // 입금 완료된 매출
+	Outstanding int       // 발행되었지만 아직 입금되지 않은 매출
+	Overdue     int       // 발행일이 지났는데 입금되지 않은 매출
+	Profit      int       // 수익
+}
+
+// CheckErrorFunc 메소드는 Client 자료구조에 값이 정확히 들어갔는지 확인하는 함수이다.
+func (c Client) CheckErrorFunc() error {
+	if c.Type != ClientTypeProducer && c.Type != ClientTypeDirector {
+		return errors.New("클라이언트 타입은 producer, director만 가능합니다")
+	}
+	if c.Name == "" {
+		return errors.New("이름을 입력해주세요")
+	}
+	for _, contact := range c.Contacts {
+		if contact.Email != "" && !regexEmail.MatchString(contact.Email) {
+			return errors.New("이메일 형식이 올바르지 않습니다")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 알림 관련 자료구조 스크립트
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 알림을 보낼 방식
+const (
+	NotifierTypeSMTP   = "smtp"   // SMTP 서버로 메일 전송
+	NotifierTypeFile   = "file"   // 파일에 기록
+	NotifierTypeStdout = "stdout" // 표준 출력에 기록
+)
+
+// SMTP 암호화 방식
+const (
+	SMTPTLSNone     = "none"     // 암호화하지 않음
+	SMTPTLSStartTLS = "starttls" // STARTTLS
+	SMTPTLSImplicit = "tls"      // 처음부터 TLS로 연결
+)
+
+// 알림을 보낼 채널
+const (
+	NotificationChannelMail    = "mail"    // 메일
+	NotificationChannelWebhook = "webhook" // 채팅 webhook
+)
+
+// 알림 전송 상태
+const (
+	NotificationStatusPending = "pending" // 전송 대기
+	NotificationStatusSending = "sending" // 전송중, 한 곳에서만 전송하도록 전송할 때 이 상태로 가져간다
+	NotificationStatusSent    = "sent"    // 전송 완료
+	NotificationStatusFailed  = "failed"  // 최대 재시도 횟수를 넘겨 전송 실패
+)
+
+// 사용자가 구독할 수 있는 알림 이벤트
+const (
+	EventInvoiceToday      = "invoicetoday"      // 세금계산서 발행일
+	EventPaymentOverdue    = "paymentoverdue"    // 매출 입금 기한 초과
+	EventMonthClosed       = "monthclosed"       // 월별 결산 완료
+	EventTimelogSyncFailed = "timelogsyncfailed" // 타임로그 업데이트 실패
+	EventBudgetChanged     = "budgetchanged"     // 예산 변경
+	EventBudgetApproval    = "budgetapproval"    // 예산안 결재
+)
+
+// NotificationEvent 자료구조는 사용자가 구독할 수 있는 알림 이벤트 정보를 담는 자료구조이다.
+type NotificationEvent struct {
+	ID        string // 이벤트 ID
+	Name      string // 프로필 페이지에 보여줄 이름
+	ByProject bool   // 프로젝트별로 구독할 수 있는 이벤트인지 여부
+}
+
+// NotificationEvents 는 사용자가 구독할 수 있는 알림 이벤트 리스트이다.
+var NotificationEvents = []NotificationEvent{
+	{ID: EventInvoiceToday, Name: "세금계산서 발행일", ByProject: true},
+	{ID: EventPaymentOverdue, Name: "매출 입금 기한 초과", ByProject: true},
+	{ID: EventMonthClosed, Name: "월별 결산 완료", ByProject: false},
+	{ID: EventTimelogSyncFailed, Name: "타임로그 업데이트 실패", ByProject: false},
+	{ID: EventBudgetChanged, Name: "예산 변경", ByProject: true},
+	{ID: EventBudgetApproval, Name: "예산안 결재", ByProject: true},
+}
+
+// NotificationMaxAttempts 는 알림 전송을 시도할 최대 횟수이다.
+const NotificationMaxAttempts = 5
+
+// NotificationLeaseDuration 은 전송중인 알림을 다른 곳에서 가져가지 못하는 시간이다. 이 시간이 지나도 전송중이면 전송이 중단된 것으로 보고 다시 전송한다.
+const NotificationLeaseDuration = 10 * time.Minute
+
+// Notification 자료구조는 전송할 알림을 outbox에 저장할 때 사용하는 자료구조이다.
+type Notification struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`        // 알림을 구분하기 위한 ID
+	Channel     string             `json:"channel" bson:"channel"`         // 알림을 보낼 채널 ex) mail, webhook. 비어있으면 mail
+	Event       string             `json:"event" bson:"event"`             // 알림 이벤트 ID
+	To          []string           `json:"to" bson:"to"`                   // 받는 사람 리스트, webhook 채널이면 webhook URL 리스트
+	Subject     string             `json:"subject" bson:"subject"`         // 제목
+	Body        string             `json:"body" bson:"body"`               // 내용, mail 채널이면 HTML, webhook 채널이면 텍스트
+	Status      string             `json:"status" bson:"status"`           // 전송 상태 ex) pending, sent, failed
+	Attempts    int                `json:"attempts" bson:"attempts"`       // 전송을 시도한 횟수
+	LastError   string             `json:"lasterror" bson:"lasterror"`     // 마지막으로 전송에 실패한 이유
+	NextTryTime string             `json:"nexttrytime" bson:"nexttrytime"` // 다음 전송을 시도할 시간(RFC3339)
+	LeaseTime   string             `json:"leasetime" bson:"leasetime"`     // 전송중 상태가 끝나는 시간(RFC3339)
+	CreatedTime string             `json:"createdtime" bson:"createdtime"` // 알림이 생성된 시간(RFC3339)
+	SentTime    string             `json:"senttime" bson:"senttime"`       // 알림이 전송된 시간(RFC3339)
+}
+
+// CheckErrorFunc 메소드는 Notification 자료구조에 값이 정확히 들어갔는지 확인하는 함수이다.
+func (n Notification) CheckErrorFunc() error {
+	if len(n.To) == 0 {
+		return errors.New("알림을 받을 사람이 없습니다")
+	}
+	if n.Subject == "" {
+		return errors.New("알림 제목이 없습니다")
+	}
+	return nil
+}
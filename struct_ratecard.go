@@ -0,0 +1,58 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 단가표 관련 자료구조 스크립트
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RateCard 자료구조 - 예산안의 인건비를 계산할 때 사용하는 본부별 태스크 1 manday 단가표
+type RateCard struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`            // 단가표를 구분하기 위한 ID
+	Headquarter   string             `json:"headquarter" bson:"headquarter"`     // 본부 ex) VFX, CM
+	Year          int                `json:"year" bson:"year"`                   // 적용 연도 ex) 2021
+	Version       int                `json:"version" bson:"version"`             // 본부, 연도별 버전 ex) 1, 2, 3 ...
+	EffectiveDate string             `json:"effectivedate" bson:"effectivedate"` // 적용 시작일 ex) 2021-01-01
+	Rates         map[string]string  `json:"rates" bson:"rates"`                 // 태스크별 1 manday 단가(암호화) ex) MM: 150000, Comp: 200000
+	Note          string             `json:"note" bson:"note"`                   // 메모
+	UserID        string             `json:"userid" bson:"userid"`               // 단가표를 등록한 사용자 ID
+	CreatedTime   string             `json:"createdtime" bson:"createdtime"`     // 등록 시간
+}
+
+// RateCompare 자료구조 - 단가표 단가와 현재 팀 평균 인건비 비교 항목
+type RateCompare struct {
+	Task          string   // 태스크
+	Teams         []string // 태스크에 해당하는 팀
+	Rate          int      // 단가표 단가
+	Average       int      // 현재 팀 평균 인건비
+	Variance      int      // 차이(평균 인건비 - 단가)
+	VarianceRatio string   // 단가 대비 차이 비율(%)
+	Err           string   // 평균 인건비를 계산하지 못한 경우 에러 메시지
+}
+
+// CheckErrorFunc 메소드는 RateCard 자료구조에 값이 정확히 들어갔는지 확인하는 함수이다.
+func (rc RateCard) CheckErrorFunc() error {
+	if rc.Headquarter == "" {
+		return errors.New("본부를 선택해주세요")
+	}
+	if rc.Year < 2000 {
+		return fmt.Errorf("적용 연도가 올바르지 않습니다: %d", rc.Year)
+	}
+	effectiveDate, err := time.Parse("2006-01-02", rc.EffectiveDate)
+	if err != nil {
+		return fmt.Errorf("적용 시작일이 올바르지 않습니다: %s", rc.EffectiveDate)
+	}
+	if effectiveDate.Year() != rc.Year {
+		return errors.New("적용 시작일은 적용 연도에 포함되어야 합니다")
+	}
+	if len(rc.Rates) == 0 {
+		return errors.New("태스크 단가를 하나 이상 입력해주세요")
+	}
+	return nil
+}
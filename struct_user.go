@@ -6,6 +6,7 @@ package main
 
 import (
 	"errors"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 )
@@ -31,13 +32,89 @@ const (
 
 // User 사용자 자료구조이다.
 type User struct {
-	ID          string      `json:"id" bson:"id"`                   // 사용자 ID
-	Password    string      `json:"password" bson:"password"`       // 암호화된 비밀번호
-	Name        string      `json:"name" bson:"name"`               // 사용자 이름
-	Team        string      `json:"team" bson:"team"`               // 사용자 팀
-	Token       string      `json:"token" bson:"token"`             // JWT 토큰
-	SignKey     string      `json:"signkey" bson:"signkey"`         // JWT 토큰을 만들 때 사용하는 SignKey
-	AccessLevel AccessLevel `json:"accesslevel" bson:"accesslevel"` // 액세스 레벨
+	ID            string         `json:"id" bson:"id"`                       // 사용자 ID
+	Password      string         `json:"password" bson:"password"`           // 암호화된 비밀번호
+	Name          string         `json:"name" bson:"name"`                   // 사용자 이름
+	Team          string         `json:"team" bson:"team"`                   // 사용자 팀
+	Token         string         `json:"token" bson:"token"`                 // JWT 토큰
+	SignKey       string         `json:"signkey" bson:"signkey"`             // JWT 토큰을 만들 때 사용하는 SignKey
+	AccessLevel   AccessLevel    `json:"accesslevel" bson:"accesslevel"`     // 액세스 레벨
+	Subscriptions []Subscription `json:"subscriptions" bson:"subscriptions"` // 구독한 알림 리스트
+	Role          string         `json:"role" bson:"role"`                   // 역할 이름, 비어있으면 액세스 레벨에 따라 권한을 준다.
+	Projects      []string       `json:"projects" bson:"projects"`           // 볼 수 있는 프로젝트 ID 리스트, 비어있으면 모든 프로젝트
+	Source        string         `json:"source" bson:"source"`               // 계정을 만든 곳, 비어있으면 회원가입한 계정이고 ldap이면 LDAP 로그인으로 만든 계정이다.
+	FailedLogins  int            `json:"failedlogins" bson:"failedlogins"`   // 마지막으로 로그인에 성공한 뒤 연속으로 실패한 횟수
+	LockedUntil   time.Time      `json:"lockeduntil" bson:"lockeduntil"`     // 계정 잠금이 풀리는 시간, 이 시간 전에는 로그인할 수 없다.
+	TOTPSecret    string         `json:"-" bson:"totpsecret"`                // 암호화된 TOTP(2단계 인증) 키
+	TOTPEnabled   bool           `json:"totpenabled" bson:"totpenabled"`     // 로그인할 때 TOTP 코드를 확인하는지 여부
+}
+
+// Role 자료구조는 권한들을 묶은 역할 정보를 담는 자료구조이다.
+type Role struct {
+	Name        string   `json:"name" bson:"name"`               // 역할 이름
+	Permissions []string `json:"permissions" bson:"permissions"` // 권한 ID 리스트
+}
+
+// Permission 자료구조는 기능별 권한 정보를 담는 자료구조이다.
+type Permission struct {
+	ID           string      // 권한 ID
+	Name         string      // 권한 이름
+	DefaultLevel AccessLevel // 역할이 없는 사용자가 이 권한을 갖는 최소 액세스 레벨
+	Sensitive    bool        // 역할이 있는 Admin도 역할에 포함되어야 갖는 권한인지 여부
+}
+
+// Session 자료구조는 로그인 세션 정보를 담는 자료구조이다. 세션 토큰(JWT)의 jti와 ID가 같고, DB에서 지우면 로그아웃된다.
+type Session struct {
+	ID        string      `json:"id" bson:"id"`               // 세션 ID(JWT jti)
+	UserID    string      `json:"userid" bson:"userid"`       // 사용자 ID
+	Level     AccessLevel `json:"level" bson:"level"`         // 로그인할 때의 액세스 레벨
+	IP        string      `json:"ip" bson:"ip"`               // 로그인한 IP
+	UserAgent string      `json:"useragent" bson:"useragent"` // 로그인한 브라우저 정보
+	CreatedAt time.Time   `json:"createdat" bson:"createdat"` // 로그인한 시간
+	ExpiresAt time.Time   `json:"expiresat" bson:"expiresat"` // 세션이 만료되는 시간
+}
+
+// LoginHistory 자료구조는 로그인 시도 기록을 담는 자료구조이다.
+type LoginHistory struct {
+	UserID    string    `json:"userid" bson:"userid"`       // 입력한 사용자 ID
+	IP        string    `json:"ip" bson:"ip"`               // 로그인을 시도한 IP
+	UserAgent string    `json:"useragent" bson:"useragent"` // 로그인을 시도한 브라우저 정보
+	Success   bool      `json:"success" bson:"success"`     // 로그인 성공 여부
+	Reason    string    `json:"reason" bson:"reason"`       // 로그인에 실패한 이유
+	CreatedAt time.Time `json:"createdat" bson:"createdat"` // 로그인을 시도한 시간
+}
+
+// APIToken 자료구조는 restAPI에 사용하는 개인 API 토큰 정보를 담는 자료구조이다. 토큰 원문은 만들 때 한 번만 보여주고 해시값만 저장한다.
+type APIToken struct {
+	ID         string    `json:"id" bson:"id"`                 // 토큰 ID
+	UserID     string    `json:"userid" bson:"userid"`         // 사용자 ID
+	Name       string    `json:"name" bson:"name"`             // 토큰 이름
+	Hash       string    `json:"-" bson:"hash"`                // 토큰 원문의 SHA-256 해시값
+	Prefix     string    `json:"prefix" bson:"prefix"`         // 토큰을 구분하기 위해 보여주는 토큰 앞부분
+	Scopes     []string  `json:"scopes" bson:"scopes"`         // 사용할 수 있는 API 범위 ID 리스트
+	CreatedAt  time.Time `json:"createdat" bson:"createdat"`   // 만든 시간
+	ExpiresAt  time.Time `json:"expiresat" bson:"expiresat"`   // 만료 시간, 비어있으면 만료되지 않는다.
+	LastUsedAt time.Time `json:"lastusedat" bson:"lastusedat"` // 마지막으로 사용한 시간
+}
+
+// APIScope 자료구조는 API 토큰으로 사용할 수 있는 API 범위 정보를 담는 자료구조이다.
+type APIScope struct {
+	ID   string // 범위 ID
+	Name string // 범위 이름
+}
+
+// Subscription 자료구조는 사용자가 구독한 알림 이벤트 정보를 담는 자료구조이다.
+type Subscription struct {
+	Event    string   `json:"event" bson:"event"`       // 알림 이벤트 ID
+	Projects []string `json:"projects" bson:"projects"` // 알림을 받을 프로젝트 ID 리스트, 비어있으면 모든 프로젝트
+}
+
+// MatchFunc 메소드는 프로젝트의 알림을 받아야 하는지 확인하는 함수이다. project가 빈 문자열이면 프로젝트와 상관없는 알림이다.
+func (s Subscription) MatchFunc(project string) bool {
+	if project == "" || len(s.Projects) == 0 {
+		return true
+	}
+	return checkStringInListFunc(project, s.Projects)
 }
 
 // CheckError 함수는 User 자료구조에 값이 정확히 들어갔는지 확인하는 함수이다.
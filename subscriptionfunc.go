@@ -0,0 +1,184 @@
+// 프로젝트 결산 프로그램
+//
+// Description : 알림 구독 관련 스크립트
+
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RecipientGroup 자료구조는 같은 프로젝트들의 알림을 받는 사람들을 묶을 때 사용하는 자료구조이다.
+type RecipientGroup struct {
+	To       []string // 받는 사람 리스트
+	Projects []string // 알림을 받을 프로젝트 ID 리스트
+}
+
+// getSubscriptionFunc 함수는 사용자가 구독한 알림 중에서 이벤트에 해당하는 구독 정보를 반환하는 함수이다.
+func getSubscriptionFunc(u User, event string) (Subscription, bool) {
+	for _, s := range u.Subscriptions {
+		if s.Event == event {
+			return s, true
+		}
+	}
+	return Subscription{}, false
+}
+
+// getSubscribersFunc 함수는 이벤트를 구독한 사용자 중에서 프로젝트의 알림을 받아야 하는 사용자 ID를 반환하는 함수이다.
+// project가 빈 문자열이면 프로젝트와 상관없이 이벤트를 구독한 모든 사용자를 반환한다.
+func getSubscribersFunc(client *mongo.Client, event string, project string) ([]string, error) {
+	users, err := getUsersBySubscriptionFunc(client, event)
+	if err != nil {
+		return nil, err
+	}
+	var subscribers []string
+	for _, u := range users {
+		viewStatus, err := checkPermissionFunc(client, u, "viewstatus")
+		if err != nil {
+			return nil, err
+		}
+		if !viewStatus { // 결산 현황을 볼 수 없는 사용자에게는 알림을 보내지 않는다.
+			continue
+		}
+		s, ok := getSubscriptionFunc(u, event)
+		if !ok || !s.MatchFunc(project) {
+			continue
+		}
+		subscribers = append(subscribers, u.ID)
+	}
+	return subscribers, nil
+}
+
+// getRecipientGroupsFunc 함수는 여러 프로젝트에 대한 알림을 받을 사람들을 받을 프로젝트가 같은 사람끼리 묶어서 반환하는 함수이다.
+// baseTo에 있는 사람들은 모든 프로젝트의 알림을 받는다.
+func getRecipientGroupsFunc(client *mongo.Client, event string, projects []string, baseTo []string) ([]RecipientGroup, error) {
+	var groups []RecipientGroup
+	if len(baseTo) != 0 {
+		groups = append(groups, RecipientGroup{To: baseTo, Projects: projects})
+	}
+
+	users, err := getUsersBySubscriptionFunc(client, event)
+	if err != nil {
+		return nil, err
+	}
+	groupMap := make(map[string]*RecipientGroup)
+	for _, u := range users {
+		viewStatus, err := checkPermissionFunc(client, u, "viewstatus")
+		if err != nil {
+			return nil, err
+		}
+		if !viewStatus { // 결산 현황을 볼 수 없는 사용자에게는 알림을 보내지 않는다.
+			continue
+		}
+		if checkStringInListFunc(u.ID, baseTo) { // 이미 모든 프로젝트의 알림을 받는 사용자
+			continue
+		}
+		s, ok := getSubscriptionFunc(u, event)
+		if !ok {
+			continue
+		}
+		var matched []string
+		for _, p := range projects {
+			if s.MatchFunc(p) {
+				matched = append(matched, p)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		key := strings.Join(matched, ",")
+		if _, ok := groupMap[key]; !ok {
+			groupMap[key] = &RecipientGroup{Projects: matched}
+		}
+		groupMap[key].To = append(groupMap[key].To, u.ID)
+	}
+
+	// 항상 같은 순서로 알림이 추가되도록 정렬한다.
+	var keys []string
+	for key := range groupMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		groups = append(groups, *groupMap[key])
+	}
+	return groups, nil
+}
+
+// addEventNotificationFunc 함수는 이벤트를 구독한 사용자들과 채팅 webhook으로 보낼 알림을 outbox에 추가하는 함수이다.
+// 알림은 서비스에서 주기적으로 전송된다.
+func addEventNotificationFunc(client *mongo.Client, event string, project string, subject string, templateName string, rcp interface{}) error {
+	// 채팅 webhook은 구독과 상관없이 admin setting에 설정된 이벤트면 보낸다.
+	err := addWebhookNotificationByTemplateFunc(client, event, subject, templateName, rcp)
+	if err != nil {
+		return err
+	}
+
+	to, err := getSubscribersFunc(client, event, project)
+	if err != nil {
+		return err
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	return addNotificationByTemplateFunc(client, event, to, subject, templateName, rcp)
+}
+
+// setMonthlyStatusAndNotifyFunc 함수는 결산의 월별 상태를 저장하고, 결산이 완료되었으면 구독한 사용자들에게 알림을 추가하는 함수이다.
+func setMonthlyStatusAndNotifyFunc(client *mongo.Client, ms MonthlyStatus) error {
+	before, err := getMonthlyStatusFunc(client, ms.Date)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	err = setMonthlyStatusFunc(client, ms)
+	if err != nil {
+		return err
+	}
+	if before.Status || !ms.Status { // 결산 완료로 바뀐 경우에만 알림을 보낸다.
+		return nil
+	}
+
+	type Recipe struct {
+		Date string // 결산이 완료된 달 ex) 2020-09
+	}
+	rcp := Recipe{
+		Date: ms.Date,
+	}
+	return addEventNotificationFunc(client, EventMonthClosed, "", "[BUDGET] "+ms.Date+" 결산 완료 알람", "mail-monthclosed", rcp)
+}
+
+// addTimelogSyncFailedNotificationFunc 함수는 타임로그 업데이트가 실패했을 때 구독한 사용자들에게 알림을 추가하는 함수이다.
+func addTimelogSyncFailedNotificationFunc(client *mongo.Client, reason string) error {
+	type Recipe struct {
+		Time   string // 실패한 시간
+		Reason string // 실패한 이유
+	}
+	rcp := Recipe{
+		Time:   time.Now().Format("2006-01-02 15:04"),
+		Reason: reason,
+	}
+	return addEventNotificationFunc(client, EventTimelogSyncFailed, "", "[BUDGET] 타임로그 업데이트 실패 알람", "mail-timelogsyncfailed", rcp)
+}
+
+// addBudgetChangedNotificationFunc 함수는 예산 프로젝트가 변경되었을 때 구독한 사용자들에게 알림을 추가하는 함수이다.
+func addBudgetChangedNotificationFunc(client *mongo.Client, bgp BGProject, userID string, content string) error {
+	type Recipe struct {
+		ID      string // 예산 프로젝트 ID
+		Name    string // 예산 프로젝트 이름
+		UserID  string // 변경한 사용자 ID
+		Content string // 변경 내용
+		Time    string // 변경한 시간
+	}
+	rcp := Recipe{
+		ID:      bgp.ID,
+		Name:    bgp.Name,
+		UserID:  userID,
+		Content: content,
+		Time:    time.Now().Format("2006-01-02 15:04"),
+	}
+	return addEventNotificationFunc(client, EventBudgetChanged, bgp.ID, "[BUDGET] "+bgp.Name+" 예산 변경 알람", "mail-budgetchanged", rcp)
+}
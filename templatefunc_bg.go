@@ -55,3 +55,18 @@ func calNegoRatioFunc(typedata BGTypeData) string {
 
 	return strconv.FormatFloat(negoRatio, 'f', -1, 64)
 }
+
+// getBGApprovalStatusNameFunc 함수는 예산안의 결재 상태를 화면에 보여줄 이름으로 반환하는 함수이다.
+func getBGApprovalStatusNameFunc(typedata BGTypeData) string {
+	switch getBGApprovalStatusFunc(typedata) {
+	case BGApprovalSubmitted:
+		return "결재 요청"
+	case BGApprovalHeadApproved:
+		return "프로덕션 승인"
+	case BGApprovalApproved:
+		return "최종 승인"
+	case BGApprovalLocked:
+		return "확정"
+	}
+	return "작성중"
+}
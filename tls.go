@@ -0,0 +1,181 @@
+// 프로젝트 결산 프로그램
+//
+// Description : HTTPS(TLS) 관련 스크립트
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"path"
+	"strings"
+	"time"
+)
+
+// SelfSignedCertDuration 은 직접 만든 인증서의 유효 기간이다. 브라우저가 허용하는 최대 기간인 825일을 넘지 않는다.
+const SelfSignedCertDuration = 825 * 24 * time.Hour
+
+// tlsFilePathFunc 함수는 인증서 파일과 개인 키 파일 경로를 반환하는 함수이다.
+// -certfile, -keyfile을 입력하지 않으면 ~/.budget-tls/<hostname>.crt, .key를 사용한다.
+// gen-key 모드가 ~/.budget 폴더의 파일을 모두 지우기 때문에 다른 폴더를 사용한다.
+func tlsFilePathFunc() (string, string, error) {
+	certPath := *flagCertFile
+	keyPath := *flagKeyFile
+	if certPath != "" && keyPath != "" {
+		return certPath, keyPath, nil
+	}
+	user, err := user.Current()
+	if err != nil {
+		return "", "", err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", "", err
+	}
+	if certPath == "" {
+		certPath = user.HomeDir + "/.budget-tls/" + hostname + ".crt"
+	}
+	if keyPath == "" {
+		keyPath = user.HomeDir + "/.budget-tls/" + hostname + ".key"
+	}
+	return certPath, keyPath, nil
+}
+
+// certHostsFunc 함수는 직접 만드는 인증서에 넣을 호스트 이름과 IP 리스트를 반환하는 함수이다.
+// -certhosts를 입력하지 않으면 서버의 hostname, 서비스 IP, localhost를 넣는다.
+func certHostsFunc() ([]string, error) {
+	if *flagCertHosts != "" {
+		var hosts []string
+		for _, h := range strings.Split(*flagCertHosts, ",") {
+			if strings.TrimSpace(h) != "" {
+				hosts = append(hosts, strings.TrimSpace(h))
+			}
+		}
+		return hosts, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	ip, err := serviceIPFunc()
+	if err != nil {
+		return nil, err
+	}
+	return []string{hostname, ip, "localhost", "127.0.0.1"}, nil
+}
+
+// genSelfSignedCertFunc 함수는 사내에서 사용할 자체 서명 인증서와 개인 키를 만들어 파일로 저장하는 함수이다.
+func genSelfSignedCertFunc(hosts []string, certPath, keyPath string, now time.Time) error {
+	if len(hosts) == 0 {
+		return errors.New("인증서에 넣을 호스트를 입력해주세요")
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"budget"}, CommonName: hosts[0]},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(SelfSignedCertDuration),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range []string{certPath, keyPath} {
+		err = createFolderFunc(path.Dir(p))
+		if err != nil {
+			return err
+		}
+	}
+	err = ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	if err != nil {
+		return err
+	}
+	// 개인 키는 소유자만 읽을 수 있게 저장한다.
+	err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkTLSFilesFunc 함수는 HTTPS 서비스에 사용할 인증서와 개인 키를 읽을 수 있는지 확인하는 함수이다.
+func checkTLSFilesFunc(certPath, keyPath string) error {
+	_, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return fmt.Errorf("인증서를 읽을 수 없습니다. -certfile, -keyfile을 확인하거나 -gen-cert로 인증서를 만들어주세요: %v", err)
+	}
+	return nil
+}
+
+// httpsURLFunc 함수는 HTTP 요청을 HTTPS 포트의 같은 주소로 바꾸는 함수이다. HTTPS 포트가 443이면 포트를 생략한다.
+func httpsURLFunc(r *http.Request, httpsPort string) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = h
+	}
+	if strings.Contains(host, ":") { // IPv6 주소
+		host = "[" + host + "]"
+	}
+	_, port, err := net.SplitHostPort(httpsPort)
+	if err != nil {
+		port = strings.TrimPrefix(httpsPort, ":")
+	}
+	if port != "" && port != "443" {
+		host = host + ":" + port
+	}
+	return "https://" + host + r.URL.RequestURI()
+}
+
+// httpsRedirectHandlerFunc 함수는 모든 HTTP 요청을 HTTPS 주소로 리다이렉트하는 핸들러를 반환하는 함수이다.
+// POST 요청도 메소드가 유지되도록 308 코드를 사용한다.
+func httpsRedirectHandlerFunc(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, httpsURLFunc(r, httpsPort), http.StatusPermanentRedirect)
+	})
+}
+
+// hstsMiddlewareFunc 미들웨어는 HTTPS 응답에 Strict-Transport-Security 헤더를 넣어 브라우저가 HTTPS로만 접속하게 한다.
+func hstsMiddlewareFunc(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && *flagHSTSMaxAge > 0 {
+			w.Header().Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d", *flagHSTSMaxAge))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+// 프로젝트 결산 프로그램
+//
+// Description : HTTPS(TLS) 테스트 스크립트
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// HTTP 요청을 HTTPS 주소로 바꾸는지 테스트하기 위한 함수
+func Test_httpsURL(t *testing.T) {
+	cases := []struct {
+		url  string
+		port string
+		want string
+	}{
+		{url: "http://budget.rd101.co.kr/signin", port: ":443", want: "https://budget.rd101.co.kr/signin"},
+		{url: "http://budget.rd101.co.kr:80/detail-sm?id=ABC&type=sm", port: ":443", want: "https://budget.rd101.co.kr/detail-sm?id=ABC&type=sm"},
+		{url: "http://10.20.30.192:8080/", port: ":8443", want: "https://10.20.30.192:8443/"},
+		{url: "http://[::1]:80/", port: "0.0.0.0:8443", want: "https://[::1]:8443/"},
+	}
+	for _, c := range cases {
+		got := httpsURLFunc(httptest.NewRequest("GET", c.url, nil), c.port)
+		if got != c.want {
+			t.Fatalf("Test_httpsURL(): 입력 값: %v, %v, 원하는 값: %v, 얻은 값: %v\n", c.url, c.port, c.want, got)
+		}
+	}
+}
+
+// 자체 서명 인증서를 만들고 읽을 수 있는지 테스트하기 위한 함수
+func Test_genSelfSignedCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "budget-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certPath := filepath.Join(dir, "tls", "budget.crt")
+	keyPath := filepath.Join(dir, "tls", "budget.key")
+	now := time.Now()
+
+	err = genSelfSignedCertFunc([]string{"budget.rd101.co.kr", "10.20.30.192"}, certPath, keyPath, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = checkTLSFilesFunc(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("Test_genSelfSignedCert(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", certPath, nil, err)
+	}
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"budget.rd101.co.kr", "10.20.30.192"} {
+		if err := cert.VerifyHostname(host); err != nil {
+			t.Fatalf("Test_genSelfSignedCert(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", host, nil, err)
+		}
+	}
+	if !cert.NotAfter.After(now.Add(SelfSignedCertDuration - time.Minute)) {
+		t.Fatalf("Test_genSelfSignedCert(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "NotAfter", now.Add(SelfSignedCertDuration), cert.NotAfter)
+	}
+	info, err := os.Stat(keyPath)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("Test_genSelfSignedCert(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v, %v\n", keyPath, os.FileMode(0600), info, err)
+	}
+
+	if err := genSelfSignedCertFunc(nil, certPath, keyPath, now); err == nil {
+		t.Fatalf("Test_genSelfSignedCert(): 입력 값: %v, 원하는 값: %v, 얻은 값: %v\n", "빈 호스트", "에러", err)
+	}
+}